@@ -0,0 +1,192 @@
+package betterjson
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// ErrEmptyArray is returned by MinFloat64, MaxFloat64 and AvgFloat64 when there
+// are no numbers to aggregate
+var ErrEmptyArray = errors.New("array has no numbers to aggregate")
+
+// AggregateOptions configures AggregateWithOptions
+type AggregateOptions struct {
+	// SkipNonNumeric leaves out items where the path is missing or isn't a
+	// number, instead of failing with an IndexErrors
+	SkipNonNumeric bool
+}
+
+// Aggregates are the statistics of the numbers of an array
+type Aggregates struct {
+	// Count is the number of numbers aggregated, Skipped the items left out
+	Count   int
+	Skipped int
+	Sum     float64
+	Min     float64
+	Max     float64
+	Avg     float64
+}
+
+// SumFloat64 sums the j array of numbers, or the numbers at path in each item:
+//    total, err := orders.SumFloat64("total")
+//
+// items where the path is missing or isn't a number fail with an IndexErrors
+// naming them. the sum of an empty array is 0
+func (j *Json) SumFloat64(path ...string) (float64, error) {
+	aggregates, err := j.AggregateWithOptions(AggregateOptions{}, path...)
+	return aggregates.Sum, err
+}
+
+// MinFloat64 is SumFloat64 returning the smallest number, ErrEmptyArray for an
+// empty array
+func (j *Json) MinFloat64(path ...string) (float64, error) {
+	aggregates, err := j.nonEmptyAggregates(path)
+	return aggregates.Min, err
+}
+
+// MaxFloat64 is MinFloat64 returning the largest number
+func (j *Json) MaxFloat64(path ...string) (float64, error) {
+	aggregates, err := j.nonEmptyAggregates(path)
+	return aggregates.Max, err
+}
+
+// AvgFloat64 is MinFloat64 returning the mean
+func (j *Json) AvgFloat64(path ...string) (float64, error) {
+	aggregates, err := j.nonEmptyAggregates(path)
+	return aggregates.Avg, err
+}
+
+func (j *Json) nonEmptyAggregates(path []string) (Aggregates, error) {
+	aggregates, err := j.AggregateWithOptions(AggregateOptions{}, path...)
+	if err == nil && aggregates.Count == 0 {
+		err = ErrEmptyArray
+	}
+	return aggregates, err
+}
+
+// AggregateWithOptions computes all of SumFloat64, MinFloat64, MaxFloat64 and
+// AvgFloat64 in one pass, configured by opts. when no numbers are aggregated
+// the result is all zero without an error
+func (j *Json) AggregateWithOptions(opts AggregateOptions, path ...string) (Aggregates, error) {
+	aggregates := Aggregates{}
+	err := j.pluckEach(path, func(idx int, value *Json) error {
+		f, err := value.value.Float64()
+		if err != nil {
+			if opts.SkipNonNumeric {
+				aggregates.Skipped++
+				return nil
+			}
+			return errors.Errorf("%s is not a number", kindName(value.value.Interface()))
+		}
+		if aggregates.Count == 0 || f < aggregates.Min {
+			aggregates.Min = f
+		}
+		if aggregates.Count == 0 || f > aggregates.Max {
+			aggregates.Max = f
+		}
+		aggregates.Count++
+		aggregates.Sum += f
+		return nil
+	}, func(length int) {})
+	if indexErrs, ok := err.(IndexErrors); ok && opts.SkipNonNumeric {
+		// only missing paths are left
+		aggregates.Skipped += len(indexErrs)
+		err = nil
+	}
+	if err != nil {
+		return Aggregates{}, err
+	}
+	if aggregates.Count > 0 {
+		aggregates.Avg = aggregates.Sum / float64(aggregates.Count)
+	}
+	return aggregates, nil
+}
+
+// SumInt64 is SumFloat64 for integers, summing json.Number values exactly
+// instead of through float64. numbers with a fraction are reported like non
+// numbers, and so is a sum overflowing int64
+func (j *Json) SumInt64(path ...string) (int64, error) {
+	var sum int64
+	err := j.pluckEach(path, func(idx int, value *Json) error {
+		i, err := integerValue(value)
+		if err != nil {
+			return err
+		}
+		if (i > 0 && sum > math.MaxInt64-i) || (i < 0 && sum < math.MinInt64-i) {
+			return errors.New("sum overflows int64")
+		}
+		sum += i
+		return nil
+	}, func(length int) {})
+	if err != nil {
+		return 0, err
+	}
+	return sum, nil
+}
+
+// CountOptions configures CountValuesWithOptions
+type CountOptions struct {
+	// SkipNull leaves out null values and missing paths instead of counting
+	// them under "null"
+	SkipNull bool
+}
+
+// CountValues counts the scalars of the j array, or the scalars at path in
+// each item, into an object mapping each value stringified like ToStringMap
+// does to its count. null and missing paths count under "null". items holding
+// an object or array at path fail with an IndexErrors:
+//    statuses, err := results.CountValues("status") // {"done":12,"failed":1}
+func (j *Json) CountValues(path ...string) (*Json, error) {
+	return j.CountValuesWithOptions(CountOptions{}, path...)
+}
+
+// CountValuesWithOptions is CountValues configured by opts
+func (j *Json) CountValuesWithOptions(opts CountOptions, path ...string) (*Json, error) {
+	counts := make(map[string]interface{})
+	add := func(bucket string) {
+		count, _ := counts[bucket].(int)
+		counts[bucket] = count + 1
+	}
+	items, err := j.Array()
+	if err != nil {
+		return NewEmpty(), err
+	}
+	errs := make(IndexErrors, 0)
+	for idx, item := range items {
+		value, ok := valueAtBranch(item, path)
+		value = unwrapRaw(value)
+		if !ok || value == nil {
+			if !opts.SkipNull {
+				add("null")
+			}
+			continue
+		}
+		bucket, err := scalarString(value)
+		if err != nil {
+			errs = append(errs, &IndexError{Index: idx, Err: err})
+			continue
+		}
+		add(bucket)
+	}
+	if len(errs) > 0 {
+		return NewEmpty(), errs
+	}
+	return wrapRaw(counts), nil
+}
+
+// CountBy is CountValues counting the bucket fn computes for each item of the
+// j array
+func (j *Json) CountBy(fn func(item *Json) string) (*Json, error) {
+	items, err := j.Array()
+	if err != nil {
+		return NewEmpty(), err
+	}
+	counts := make(map[string]interface{})
+	for _, item := range items {
+		bucket := fn(wrapRaw(item))
+		count, _ := counts[bucket].(int)
+		counts[bucket] = count + 1
+	}
+	return wrapRaw(counts), nil
+}
@@ -0,0 +1,98 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_SumFloat64(t *testing.T) {
+	numbers, _ := Parse([]byte(`[1,2.5,-0.5,4]`))
+	sum, err := numbers.SumFloat64()
+	assert.True(t, err == nil && sum == 7)
+	min, err := numbers.MinFloat64()
+	assert.True(t, err == nil && min == -0.5)
+	max, err := numbers.MaxFloat64()
+	assert.True(t, err == nil && max == 4)
+	avg, err := numbers.AvgFloat64()
+	assert.True(t, err == nil && avg == 1.75)
+
+	orders, _ := Parse([]byte(`[{"total":10},{"total":2.5},{"total":"3"},{"id":4}]`))
+	_, err = orders.SumFloat64("total")
+	assert.True(t, err != nil && err.Error() == "item 2: string is not a number; item 3: path total is missing")
+	aggregates, err := orders.AggregateWithOptions(AggregateOptions{SkipNonNumeric: true}, "total")
+	assert.True(t, err == nil)
+	assert.Equal(t, Aggregates{Count: 2, Skipped: 2, Sum: 12.5, Min: 2.5, Max: 10, Avg: 6.25}, aggregates)
+	_, err = NewJSONObject().SumFloat64()
+	assert.True(t, err != nil)
+}
+
+func TestJson_AggregateEmpty(t *testing.T) {
+	empty := NewJSONArray()
+	sum, err := empty.SumFloat64()
+	assert.True(t, err == nil && sum == 0)
+	_, err = empty.MinFloat64()
+	assert.True(t, err == ErrEmptyArray)
+	_, err = empty.MaxFloat64()
+	assert.True(t, err == ErrEmptyArray)
+	_, err = empty.AvgFloat64()
+	assert.True(t, err == ErrEmptyArray)
+	i, err := empty.SumInt64()
+	assert.True(t, err == nil && i == 0)
+
+	strings, _ := Parse([]byte(`["a",null]`))
+	aggregates, err := strings.AggregateWithOptions(AggregateOptions{SkipNonNumeric: true})
+	assert.True(t, err == nil && aggregates.Count == 0 && aggregates.Skipped == 2)
+}
+
+func TestJson_SumInt64(t *testing.T) {
+	big, _ := Parse([]byte(`[{"n":9007199254740993},{"n":1},{"n":2.0}]`))
+	sum, err := big.SumInt64("n")
+	assert.True(t, err == nil && sum == 9007199254740996)
+	floatSum, _ := big.SumFloat64("n")
+	assert.True(t, int64(floatSum) != sum)
+
+	mixed, _ := Parse([]byte(`[1,2.5,"x"]`))
+	_, err = mixed.SumInt64()
+	assert.True(t, err != nil && err.Error() == "item 1: number 2.5 is not an integer; item 2: string is not a number")
+	overflow, _ := Parse([]byte(`[9223372036854775807,1]`))
+	_, err = overflow.SumInt64()
+	assert.True(t, err != nil && err.Error() == "item 1: sum overflows int64")
+}
+
+func TestJson_CountValues(t *testing.T) {
+	results, _ := Parse([]byte(`[{"status":"done"},{"status":"failed"},{"status":"done"},{"status":null},{"id":5},{"status":200},{"status":true}]`))
+	counts, err := results.CountValues("status")
+	assert.True(t, err == nil)
+	encoded, _ := counts.EncodeSorted()
+	assert.True(t, string(encoded) == `{"200":1,"done":2,"failed":1,"null":2,"true":1}`)
+	counts, err = results.CountValuesWithOptions(CountOptions{SkipNull: true}, "status")
+	assert.True(t, err == nil)
+	encoded, _ = counts.EncodeSorted()
+	assert.True(t, string(encoded) == `{"200":1,"done":2,"failed":1,"true":1}`)
+
+	plain, _ := Parse([]byte(`["a","b","a",1,1.0]`))
+	counts, _ = plain.CountValues()
+	encoded, _ = counts.EncodeSorted()
+	assert.True(t, string(encoded) == `{"1":1,"1.0":1,"a":2,"b":1}`)
+
+	nested, _ := Parse([]byte(`[{"s":{"x":1}},{"s":"a"}]`))
+	_, err = nested.CountValues("s")
+	assert.True(t, err != nil && err.Error() == "item 0: object can't be converted to string")
+	_, err = NewJSONObject().CountValues()
+	assert.True(t, err != nil)
+}
+
+func TestJson_CountBy(t *testing.T) {
+	results, _ := Parse([]byte(`[{"ms":5},{"ms":150},{"ms":20},{"ms":900}]`))
+	counts, err := results.CountBy(func(item *Json) string {
+		if item.Get("ms").MustInt() < 100 {
+			return "fast"
+		}
+		return "slow"
+	})
+	assert.True(t, err == nil)
+	encoded, _ := counts.EncodeSorted()
+	assert.True(t, string(encoded) == `{"fast":2,"slow":2}`)
+	_, err = NewEmpty().CountBy(func(item *Json) string { return "" })
+	assert.True(t, err != nil)
+}
@@ -0,0 +1,157 @@
+package betterjson
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// arrayItem is a matched item of the arrays of DiffArraysSmart, by its index
+// in a and in b
+type arrayItem struct {
+	source int
+	target int
+}
+
+// DiffArraysSmart returns an RFC 6902 patch turning the array a into the array
+// b, which ApplyPatch can apply to a. items are matched by the value of their
+// identityKey member, or by their whole value when identityKey is "", so an
+// item inserted at the front is one add instead of a replace of every item
+// after it. items of a that b has no match for are removed, the ones only b
+// has are added, and the fewest matched items are moved to reach the order of
+// b. matched items that differ otherwise are replaced. the paths are relative
+// to the arrays:
+//    patch, err := betterjson.DiffArraysSmart(before.Get("users"), after.Get("users"), "id")
+func DiffArraysSmart(a, b *Json, identityKey string) (*Json, error) {
+	left, err := a.Array()
+	if err != nil {
+		return NewEmpty(), errors.Wrap(err, "a is not an array")
+	}
+	right, err := b.Array()
+	if err != nil {
+		return NewEmpty(), errors.Wrap(err, "b is not an array")
+	}
+	leftIDs, err := arrayIdentities(left, identityKey, "a")
+	if err != nil {
+		return NewEmpty(), err
+	}
+	rightIDs, err := arrayIdentities(right, identityKey, "b")
+	if err != nil {
+		return NewEmpty(), err
+	}
+	// repeated identities are matched occurrence by occurrence
+	pending := make(map[string][]int, len(right))
+	for idx, id := range rightIDs {
+		pending[id] = append(pending[id], idx)
+	}
+	operations := make([]interface{}, 0)
+	items := make([]arrayItem, 0, len(left))
+	matched := make([]bool, len(right))
+	removed := make([]int, 0)
+	for idx, id := range leftIDs {
+		targets := pending[id]
+		if len(targets) == 0 {
+			removed = append(removed, idx)
+			continue
+		}
+		items = append(items, arrayItem{source: idx, target: targets[0]})
+		matched[targets[0]] = true
+		pending[id] = targets[1:]
+	}
+	// removing from the back keeps the indexes of the items before valid
+	for idx := len(removed) - 1; idx >= 0; idx-- {
+		operations = append(operations, patchOperation{op: "remove", path: []string{strconv.Itoa(removed[idx])}}.encode())
+	}
+	stable := stableItems(items)
+	moving := make([]int, 0, len(items))
+	for _, item := range items {
+		if !stable[item.target] {
+			moving = append(moving, item.target)
+		}
+	}
+	sort.Ints(moving)
+	// a moved item goes right after the item with the next smaller index in b,
+	// which already is in place, so the items end up in the order of b
+	for _, target := range moving {
+		from := 0
+		for items[from].target != target {
+			from++
+		}
+		item := items[from]
+		items = append(items[:from], items[from+1:]...)
+		to, preceding := 0, -1
+		for idx, other := range items {
+			if other.target < target && other.target > preceding {
+				to, preceding = idx+1, other.target
+			}
+		}
+		items = append(items, arrayItem{})
+		copy(items[to+1:], items[to:])
+		items[to] = item
+		if from != to {
+			operations = append(operations, patchOperation{op: "move", from: []string{strconv.Itoa(from)}, path: []string{strconv.Itoa(to)}}.encode())
+		}
+	}
+	// with every item before it in place, an added item goes to its index in b
+	for idx, item := range right {
+		if !matched[idx] {
+			operations = append(operations, patchOperation{op: "add", path: []string{strconv.Itoa(idx)}, value: item}.encode())
+		}
+	}
+	for _, item := range items {
+		if !digestEqual(left[item.source], right[item.target]) {
+			operations = append(operations, patchOperation{op: "replace", path: []string{strconv.Itoa(item.target)}, value: right[item.target]}.encode())
+		}
+	}
+	return wrapRaw(operations), nil
+}
+
+// arrayIdentities returns the digests the items of array are matched by
+func arrayIdentities(array []interface{}, identityKey string, name string) ([]string, error) {
+	ids := make([]string, len(array))
+	for idx, item := range array {
+		if identityKey != "" {
+			object, ok := unwrapRaw(item).(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("item %d of %s is %s, not an object", idx, name, kindName(unwrapRaw(item)))
+			}
+			if item, ok = object[identityKey]; !ok {
+				return nil, errors.Errorf("item %d of %s has no %q member", idx, name, identityKey)
+			}
+		}
+		digest, err := leafDigest(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "item %d of %s", idx, name)
+		}
+		ids[idx] = string(digest)
+	}
+	return ids, nil
+}
+
+// stableItems returns the targets of a longest run of items already in the
+// order of b, the ones that don't have to move
+func stableItems(items []arrayItem) map[int]bool {
+	// tails[n] is the index of the item ending the best run of length n+1
+	tails := make([]int, 0, len(items))
+	previous := make([]int, len(items))
+	for idx, item := range items {
+		n := sort.Search(len(tails), func(i int) bool { return items[tails[i]].target >= item.target })
+		previous[idx] = -1
+		if n > 0 {
+			previous[idx] = tails[n-1]
+		}
+		if n == len(tails) {
+			tails = append(tails, idx)
+		} else {
+			tails[n] = idx
+		}
+	}
+	stable := make(map[int]bool, len(tails))
+	if len(tails) > 0 {
+		for idx := tails[len(tails)-1]; idx >= 0; idx = previous[idx] {
+			stable[items[idx].target] = true
+		}
+	}
+	return stable
+}
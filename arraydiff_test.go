@@ -0,0 +1,100 @@
+package betterjson
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func applyArrayPatch(t *testing.T, a *Json, patch *Json) *Json {
+	patched := wrapRaw(deepCopyRaw(a.Interface()))
+	err := patched.ApplyPatch(patch)
+	assert.True(t, err == nil)
+	return patched
+}
+
+func TestDiffArraysSmartInsertAtFront(t *testing.T) {
+	a := NewJSONArray()
+	for i := 0; i < 1000; i++ {
+		a.TryAdd(map[string]interface{}{"id": i, "name": "n" + strconv.Itoa(i)})
+	}
+	b := NewJSONArrayFrom(map[string]interface{}{"id": -1, "name": "new"})
+	items, _ := a.Array()
+	for _, item := range items {
+		b.TryAdd(item)
+	}
+	patch, err := DiffArraysSmart(a, b, "id")
+	assert.True(t, err == nil)
+	assert.Equal(t, `[{"op":"add","path":"/0","value":{"id":-1,"name":"new"}}]`, patch.EncodeToStringOrDefault(""))
+	patch, _ = DiffArraysSmart(a, b, "")
+	assert.Equal(t, 1, len(patch.MustArray()))
+}
+
+func TestDiffArraysSmartOperations(t *testing.T) {
+	a, _ := Parse([]byte(`[{"id":1,"v":"a"},{"id":2,"v":"b"},{"id":3,"v":"c"},{"id":4,"v":"d"}]`))
+	b, _ := Parse([]byte(`[{"id":4,"v":"d"},{"id":1,"v":"a"},{"id":3,"v":"C"},{"id":5,"v":"e"}]`))
+	patch, err := DiffArraysSmart(a, b, "id")
+	assert.True(t, err == nil)
+	println(patch.EncodeToStringOrDefault(""))
+	assert.Equal(t, `[{"op":"remove","path":"/1"},{"from":"/2","op":"move","path":"/0"},{"op":"add","path":"/3","value":{"id":5,"v":"e"}},{"op":"replace","path":"/2","value":{"id":3,"v":"C"}}]`, patch.EncodeToStringOrDefault(""))
+	assert.True(t, applyArrayPatch(t, a, patch).IsSameJSONWith(b))
+
+	same, _ := DiffArraysSmart(a, a, "id")
+	assert.Equal(t, "[]", same.EncodeToStringOrDefault(""))
+	_, err = DiffArraysSmart(a, NewJSONArrayFrom(1), "id")
+	assert.Equal(t, "item 0 of b is number, not an object", err.Error())
+	_, err = DiffArraysSmart(a, NewJSONArrayFrom(map[string]interface{}{"key": 1}), "id")
+	assert.Equal(t, `item 0 of b has no "id" member`, err.Error())
+	_, err = DiffArraysSmart(NewJSONObject(), a, "")
+	assert.True(t, err != nil)
+}
+
+func TestDiffArraysSmartDuplicates(t *testing.T) {
+	a := NewJSONArrayFrom(1, 2, 1, 3, 1)
+	b := NewJSONArrayFrom(3, 1, 1, 2, 4)
+	patch, err := DiffArraysSmart(a, b, "")
+	assert.True(t, err == nil)
+	assert.True(t, applyArrayPatch(t, a, patch).IsSameJSONWith(b))
+
+	// a rotation moves one item only
+	patch, _ = DiffArraysSmart(NewJSONArrayFrom(1, 2, 3, 4, 5), NewJSONArrayFrom(2, 3, 4, 5, 1), "")
+	assert.Equal(t, `[{"from":"/0","op":"move","path":"/4"}]`, patch.EncodeToStringOrDefault(""))
+}
+
+// randomized permutations and edits must always patch a into b
+func TestDiffArraysSmartProperty(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	for round := 0; round < 500; round++ {
+		n := random.Intn(12)
+		a := NewJSONArray()
+		for i := 0; i < n; i++ {
+			a.TryAdd(map[string]interface{}{"id": i, "v": random.Intn(3)})
+		}
+		items := deepCopyRaw(a.Interface()).([]interface{})
+		random.Shuffle(len(items), func(x, y int) { items[x], items[y] = items[y], items[x] })
+		edited := make([]interface{}, 0, len(items)+3)
+		for _, item := range items {
+			switch random.Intn(6) {
+			case 0:
+				// removed
+			case 1:
+				item.(map[string]interface{})["v"] = random.Intn(3)
+				edited = append(edited, item)
+			case 2:
+				edited = append(edited, map[string]interface{}{"id": 100 + random.Intn(1000), "v": 0}, item)
+			default:
+				edited = append(edited, item)
+			}
+		}
+		b := wrapRaw(edited)
+		for _, identityKey := range []string{"id", ""} {
+			patch, err := DiffArraysSmart(a, b, identityKey)
+			assert.True(t, err == nil)
+			patched := applyArrayPatch(t, a, patch)
+			if !patched.IsSameJSONWith(b) {
+				t.Fatalf("round %d identity %q: %s patched by %s is %s, want %s", round, identityKey, a.EncodeToStringOrDefault(""), patch.EncodeToStringOrDefault(""), patched.EncodeToStringOrDefault(""), b.EncodeToStringOrDefault(""))
+			}
+		}
+	}
+}
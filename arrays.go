@@ -0,0 +1,323 @@
+package betterjson
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Zip pairs the items of two arrays of equal length through fn into a new
+// array. fn gets wrappers of the items and may return a *Json or plain value:
+//    Zip(ids, details, func(id, detail *Json) interface{} {
+//        return NewJSONObject().Set("id", id).Set("detail", detail)
+//    })
+func Zip(a, b *Json, fn func(x, y *Json) interface{}) (*Json, error) {
+	left, err := a.Array()
+	if err != nil {
+		return NewEmpty(), errors.Wrap(err, "zip first argument")
+	}
+	right, err := b.Array()
+	if err != nil {
+		return NewEmpty(), errors.Wrap(err, "zip second argument")
+	}
+	if len(left) != len(right) {
+		return NewEmpty(), errors.Errorf("can't zip arrays of length %d and %d", len(left), len(right))
+	}
+	result := make([]interface{}, len(left))
+	for idx := range left {
+		result[idx] = unwrapRaw(fn(wrapRaw(left[idx]), wrapRaw(right[idx])))
+	}
+	return wrapRaw(result), nil
+}
+
+// Unzip splits the j array of objects into one array per key, turning rows
+// into columns. items missing a key, or that aren't objects, contribute null
+// to that column. the values are copies
+func (j *Json) Unzip(keys ...string) (map[string]*Json, error) {
+	items, err := j.Array()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string][]interface{}, len(keys))
+	for _, key := range keys {
+		columns[key] = make([]interface{}, len(items))
+	}
+	for idx, item := range items {
+		object, isObject := unwrapRaw(item).(map[string]interface{})
+		if !isObject {
+			continue
+		}
+		for _, key := range keys {
+			columns[key][idx] = deepCopyRaw(object[key])
+		}
+	}
+	result := make(map[string]*Json, len(keys))
+	for key, column := range columns {
+		result[key] = wrapRaw(column)
+	}
+	return result, nil
+}
+
+// Children returns a wrapper of every item of the j array. the wrappers alias
+// the items like GetIndex does: Set on an object item, or SetValue and TryAdd
+// on any item, change j itself
+func (j *Json) Children() ([]*Json, error) {
+	items, err := j.Array()
+	if err != nil {
+		return []*Json{}, err
+	}
+	children := make([]*Json, len(items))
+	for idx, item := range items {
+		children[idx] = newChild(j, strconv.Itoa(idx), item)
+	}
+	return children, nil
+}
+
+// MapChildren calls fn with the aliasing wrapper of every item of the j array,
+// like Children without building the slice. it stops at the first error fn
+// returns. items added to j by fn are not visited
+func (j *Json) MapChildren(fn func(i int, child *Json) error) error {
+	items, err := j.Array()
+	if err != nil {
+		return err
+	}
+	for idx, item := range items {
+		if err := fn(idx, newChild(j, strconv.Itoa(idx), item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConcatOptions configures ConcatArraysWithOptions
+type ConcatOptions struct {
+	// IncludeNonArrays adds a non-array input as a single item instead of
+	// leaving it out
+	IncludeNonArrays bool
+}
+
+// ConcatArrays returns a new array with the items of j followed by the items
+// of each of others. the items are copies, so the result shares nothing with
+// its inputs. non-array inputs, j included, are left out
+func (j *Json) ConcatArrays(others ...*Json) *Json {
+	return j.ConcatArraysWithOptions(ConcatOptions{}, others...)
+}
+
+// ConcatArraysWithOptions is ConcatArrays configured by opts
+func (j *Json) ConcatArraysWithOptions(opts ConcatOptions, others ...*Json) *Json {
+	result, _ := concatArrays(append([]*Json{j}, others...), func(idx int, input *Json) (bool, error) {
+		return opts.IncludeNonArrays && !input.IsEmpty(), nil
+	})
+	return result
+}
+
+// ConcatArraysE is ConcatArrays failing when j or one of others isn't an array
+func (j *Json) ConcatArraysE(others ...*Json) (*Json, error) {
+	return concatArrays(append([]*Json{j}, others...), func(idx int, input *Json) (bool, error) {
+		if idx == 0 {
+			return false, errors.Errorf("can't concat %s receiver", inputKind(input))
+		}
+		return false, errors.Errorf("can't concat %s argument %d", inputKind(input), idx-1)
+	})
+}
+
+func inputKind(input *Json) string {
+	if input.IsEmpty() {
+		return "empty json"
+	}
+	return kindName(input.Interface())
+}
+
+// concatArrays copies the items of inputs into a new array. nonArray decides
+// whether a non-array input is added as one item, or fails the concat
+func concatArrays(inputs []*Json, nonArray func(idx int, input *Json) (bool, error)) (*Json, error) {
+	size := 0
+	for _, input := range inputs {
+		size += input.ArrayLength()
+	}
+	result := make([]interface{}, 0, size)
+	for idx, input := range inputs {
+		items, err := input.Array()
+		if err != nil {
+			include, err := nonArray(idx, input)
+			if err != nil {
+				return NewEmpty(), err
+			}
+			if include {
+				result = append(result, deepCopyRaw(input))
+			}
+			continue
+		}
+		for _, item := range items {
+			result = append(result, deepCopyRaw(item))
+		}
+	}
+	return wrapRaw(result), nil
+}
+
+// ExtendArray appends copies of the items of other to the j array in place,
+// in one step instead of a TryAdd per item. it does nothing when j or other
+// isn't an array
+func (j *Json) ExtendArray(other *Json) *Json {
+	items, err := j.Array()
+	if err != nil {
+		return j
+	}
+	added, err := other.Array()
+	if err != nil || len(added) == 0 {
+		return j
+	}
+	extended := make([]interface{}, len(items), len(items)+len(added))
+	copy(extended, items)
+	for _, item := range added {
+		extended = append(extended, deepCopyRaw(item))
+	}
+	appended := []string{"-"}
+	prior := j.prior(appended)
+	j.value.SetPath([]string{}, extended)
+	j.writeBack()
+	for _, item := range extended[len(items):] {
+		j.changed(patchOperation{op: "add", path: appended, value: item}, prior)
+	}
+	return j
+}
+
+// PrependArray inserts vals at the front of the j array in place, in their
+// order, in one step. like TryAdd a `*Json` value is deep copied. it does nothing
+// when j isn't an array
+func (j *Json) PrependArray(vals ...interface{}) *Json {
+	j.insertItems(0, vals)
+	return j
+}
+
+// InsertAt inserts val before the item at index of the j array, shifting the
+// items after it. index 0 prepends and the length of the array appends. it
+// does nothing when j isn't an array or index is out of range, see InsertAtE
+func (j *Json) InsertAt(index int, val interface{}) *Json {
+	j.insertItems(index, []interface{}{val})
+	return j
+}
+
+// InsertAtE is InsertAt failing when j isn't an array or index is out of
+// range, with an IndexRangeError
+func (j *Json) InsertAtE(index int, val interface{}) error {
+	return j.insertItems(index, []interface{}{val})
+}
+
+func (j *Json) insertItems(index int, vals []interface{}) error {
+	items, err := j.Array()
+	if err != nil {
+		return errors.Errorf("can't insert into %s", inputKind(j))
+	}
+	if index < 0 || index > len(items) {
+		return &IndexRangeError{Index: index, Length: len(items)}
+	}
+	if len(vals) == 0 {
+		return nil
+	}
+	inserted := make([]interface{}, 0, len(items)+len(vals))
+	inserted = append(inserted, items[:index]...)
+	for _, val := range vals {
+		inserted = append(inserted, storedValue(val))
+	}
+	inserted = append(inserted, items[index:]...)
+	prior := j.prior([]string{"-"})
+	j.value.SetPath([]string{}, inserted)
+	j.writeBack()
+	for idx := index; idx < index+len(vals); idx++ {
+		j.inserted(patchOperation{op: "add", path: []string{strconv.Itoa(idx)}, value: inserted[idx]}, prior)
+	}
+	return nil
+}
+
+// CoerceStringArray is StringArray stringifying numbers and bools as their json
+// text instead of failing on them. object and array items still fail
+func (j *Json) CoerceStringArray() ([]string, error) {
+	if j.IsEmpty() {
+		return nil, errors.New("empty json parse to []string failed")
+	}
+	return j.stringArray(true)
+}
+
+func (j *Json) stringArray(coerce bool) ([]string, error) {
+	var result []string
+	err := j.pluckEach([]string{}, func(idx int, value *Json) error {
+		item := value.value.Interface()
+		switch s := item.(type) {
+		case nil:
+			return nil
+		case string:
+			result[idx] = s
+			return nil
+		}
+		if !coerce {
+			return errors.Errorf("%s is not a string", kindName(item))
+		}
+		s, err := scalarString(item)
+		result[idx] = s
+		return err
+	}, func(length int) {
+		result = make([]string, length)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// IntArray converts an array of numbers without a fraction to []int. like
+// StringArray the error is an IndexErrors naming every failing item
+func (j *Json) IntArray() ([]int, error) {
+	if j.IsEmpty() {
+		return nil, errors.New("empty json parse to []int failed")
+	}
+	result, err := j.PluckInts()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Float64Array is IntArray for []float64
+func (j *Json) Float64Array() ([]float64, error) {
+	if j.IsEmpty() {
+		return nil, errors.New("empty json parse to []float64 failed")
+	}
+	var result []float64
+	err := j.pluckEach([]string{}, func(idx int, value *Json) error {
+		f, err := value.value.Float64()
+		if err != nil {
+			return errors.Errorf("%s is not a number", kindName(value.value.Interface()))
+		}
+		result[idx] = f
+		return nil
+	}, func(length int) {
+		result = make([]float64, length)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BoolArray is IntArray for []bool
+func (j *Json) BoolArray() ([]bool, error) {
+	if j.IsEmpty() {
+		return nil, errors.New("empty json parse to []bool failed")
+	}
+	var result []bool
+	err := j.pluckEach([]string{}, func(idx int, value *Json) error {
+		b, err := value.value.Bool()
+		if err != nil {
+			return errors.Errorf("%s is not a bool", kindName(value.value.Interface()))
+		}
+		result[idx] = b
+		return nil
+	}, func(length int) {
+		result = make([]bool, length)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,306 @@
+package betterjson
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZip(t *testing.T) {
+	ids := NewJSONArray().TryAdd(1).TryAdd(2)
+	details := NewJSONArray().TryAdd(NewJSONObject().Set("name", "a")).TryAdd(NewJSONObject().Set("name", "b"))
+	zipped, err := Zip(ids, details, func(id, detail *Json) interface{} {
+		return NewJSONObject().Set("id", id).Set("name", detail.Get("name"))
+	})
+	assert.True(t, err == nil)
+	assert.True(t, zipped.EncodeToStringOrDefault("") == `[{"id":1,"name":"a"},{"id":2,"name":"b"}]`)
+	sums, err := Zip(ids, ids, func(x, y *Json) interface{} {
+		return x.MustInt() + y.MustInt()
+	})
+	assert.True(t, err == nil)
+	assert.True(t, sums.EncodeToStringOrDefault("") == `[2,4]`)
+	empty, err := Zip(NewJSONArray(), NewJSONArray(), func(x, y *Json) interface{} { return nil })
+	assert.True(t, err == nil)
+	assert.True(t, empty.EncodeToStringOrDefault("") == `[]`)
+	_, err = Zip(ids, NewJSONArray().TryAdd(1), func(x, y *Json) interface{} { return nil })
+	assert.True(t, err != nil)
+	assert.True(t, err.Error() == "can't zip arrays of length 2 and 1")
+	_, err = Zip(ids, NewJSONObject(), func(x, y *Json) interface{} { return nil })
+	assert.True(t, err != nil)
+}
+
+func TestJson_Unzip(t *testing.T) {
+	rows, err := Parse([]byte(`[{"x":1,"y":"a"},{"x":2},{"y":"c","z":true},3]`))
+	assert.True(t, err == nil)
+	columns, err := rows.Unzip("x", "y")
+	assert.True(t, err == nil)
+	assert.True(t, len(columns) == 2)
+	assert.True(t, columns["x"].EncodeToStringOrDefault("") == `[1,2,null,null]`)
+	assert.True(t, columns["y"].EncodeToStringOrDefault("") == `["a",null,"c",null]`)
+	emptyColumns, err := NewJSONArray().Unzip("x")
+	assert.True(t, err == nil)
+	assert.True(t, emptyColumns["x"].EncodeToStringOrDefault("") == `[]`)
+	_, err = NewJSONObject().Unzip("x")
+	assert.True(t, err != nil)
+}
+
+func TestNewJSONArrayConformance(t *testing.T) {
+	cases := []struct {
+		text  string
+		items []interface{}
+	}{
+		{`[]`, []interface{}{}},
+		{`[1,2,3]`, []interface{}{1, 2, 3}},
+		{`[null,true,false]`, []interface{}{nil, true, false}},
+		{`["a","<b>","c\"d","ü"]`, []interface{}{"a", "<b>", `c"d`, "ü"}},
+		{`[1.5,-0.25,1e+21]`, []interface{}{1.5, -0.25, 1e21}},
+		{`[{"b":1,"a":[2]},{}]`, []interface{}{NewJSONObject().Set("b", 1).Set("a", NewJSONArrayFrom(2)), NewJSONObject()}},
+		{`[[],[[]],[1,[2]]]`, []interface{}{NewJSONArray(), NewJSONArrayFrom(NewJSONArray()), NewJSONArrayFrom(1, NewJSONArrayFrom(2))}},
+		{`[null,{"a":null}]`, []interface{}{NewEmpty(), map[string]interface{}{"a": nil}}},
+	}
+	for _, c := range cases {
+		parsed, err := Parse([]byte(c.text))
+		assert.True(t, err == nil)
+		added := NewJSONArray()
+		indexed := NewJSONArray()
+		for range c.items {
+			indexed.TryAdd(nil)
+		}
+		for idx, item := range c.items {
+			added.TryAdd(item)
+			indexed.SetIndex(idx, item)
+		}
+		expected, _ := parsed.EncodeSorted()
+		for _, built := range []*Json{NewJSONArrayFrom(c.items...), added, indexed} {
+			encoded, err := built.EncodeSorted()
+			assert.True(t, err == nil)
+			assert.Equal(t, string(expected), string(encoded))
+			assert.True(t, built.DigestJSONForEqual() == parsed.DigestJSONForEqual())
+			assert.True(t, built.IsSameJSONWith(parsed))
+			assert.True(t, built.ArrayLength() == parsed.ArrayLength())
+			items, err := built.Array()
+			assert.True(t, err == nil)
+			assert.True(t, len(items) == len(c.items))
+			for idx := range items {
+				item, parsedItem := built.GetIndex(idx), parsed.GetIndex(idx)
+				assert.True(t, item.DigestJSONForEqual() == parsedItem.DigestJSONForEqual())
+				_, mapErr := item.Map()
+				_, parsedMapErr := parsedItem.Map()
+				assert.True(t, (mapErr == nil) == (parsedMapErr == nil))
+				_, arrayErr := item.Array()
+				_, parsedArrayErr := parsedItem.Array()
+				assert.True(t, (arrayErr == nil) == (parsedArrayErr == nil))
+			}
+		}
+	}
+}
+
+func TestJson_TryAddCopiesJson(t *testing.T) {
+	item := NewJSONObject().Set("a", 1)
+	array := NewJSONArray().TryAdd(item)
+	item.Set("a", 2)
+	assert.True(t, array.GetIndex(0).Get("a").MustInt() == 1)
+	assert.True(t, NewJSONArray().GetIndex(0).IsNullJson())
+}
+
+func TestJson_Children(t *testing.T) {
+	a, _ := Parse([]byte(`{"users":[{"name":"a"},{"name":"b"},3]}`))
+	users := a.Get("users")
+	children, err := users.Children()
+	assert.True(t, err == nil)
+	assert.True(t, len(children) == 3)
+	children[0].Set("admin", true)
+	children[2].SetValue("three")
+	assert.True(t, a.DigestJSONForEqual() == `{"users":[{"admin":true,"name":"a"},{"name":"b"},"three"]}`)
+
+	children, err = a.Children()
+	assert.True(t, err != nil)
+	assert.True(t, len(children) == 0)
+}
+
+func TestJson_MapChildren(t *testing.T) {
+	a := NewJSONArrayFrom(NewJSONObject().Set("n", 1), NewJSONObject().Set("n", 2), NewJSONObject().Set("n", 3))
+	sum := 0
+	err := a.MapChildren(func(i int, child *Json) error {
+		sum += child.Get("n").MustInt()
+		child.Set("i", i)
+		return nil
+	})
+	assert.True(t, err == nil)
+	assert.True(t, sum == 6)
+	assert.True(t, a.GetIndex(2).Get("i").MustInt() == 2)
+
+	visited := 0
+	err = a.MapChildren(func(i int, child *Json) error {
+		visited++
+		if i == 1 {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	assert.True(t, err != nil && err.Error() == "stop")
+	assert.True(t, visited == 2)
+	assert.True(t, NewJSONObject().MapChildren(func(i int, child *Json) error { return nil }) != nil)
+}
+
+func TestJson_ConcatArrays(t *testing.T) {
+	a := NewJSONArrayFrom(1, NewJSONObject().Set("k", "v"))
+	b := NewJSONArrayFrom(2, 3)
+	scalar := NewJSONObject().Set("x", 1)
+	result := a.ConcatArrays(b, scalar, NewEmpty(), NewJSONArray())
+	assert.True(t, result.EncodeToStringOrDefault("") == `[1,{"k":"v"},2,3]`)
+	result.GetIndex(1).Set("k", "changed")
+	assert.True(t, a.GetIndex(1).Get("k").MustString() == "v")
+	assert.True(t, a.ArrayLength() == 2 && b.ArrayLength() == 2)
+
+	included := a.ConcatArraysWithOptions(ConcatOptions{IncludeNonArrays: true}, scalar, NewEmpty())
+	assert.True(t, included.EncodeToStringOrDefault("") == `[1,{"k":"v"},{"x":1}]`)
+
+	_, err := a.ConcatArraysE(b, scalar)
+	assert.True(t, err != nil && err.Error() == "can't concat object argument 1")
+	_, err = scalar.ConcatArraysE(b)
+	assert.True(t, err != nil && err.Error() == "can't concat object receiver")
+	result, err = a.ConcatArraysE(b)
+	assert.True(t, err == nil && result.ArrayLength() == 4)
+}
+
+func TestJson_ExtendArray(t *testing.T) {
+	doc, _ := Parse([]byte(`{"items":[1]}`))
+	items := doc.Get("items")
+	other := NewJSONArrayFrom(NewJSONObject().Set("k", "v"), 3)
+	items.ExtendArray(other).ExtendArray(NewJSONObject())
+	assert.True(t, doc.DigestJSONForEqual() == `{"items":[1,{"k":"v"},3]}`)
+	other.GetIndex(0).Set("k", "changed")
+	assert.True(t, doc.GetDottedPath("items.1.k").MustString() == "v")
+	assert.True(t, other.ArrayLength() == 2)
+	assert.True(t, NewJSONObject().ExtendArray(other).DigestJSONForEqual() == `{}`)
+}
+
+func TestJson_StringArrayReportsItems(t *testing.T) {
+	for _, source := range []string{`[1,"b","c"]`, `["a",{"x":1},"c"]`, `["a","b",true]`} {
+		list, _ := Parse([]byte(source))
+		result, err := list.StringArray()
+		assert.True(t, result == nil && err != nil)
+		indexErrs, ok := err.(IndexErrors)
+		assert.True(t, ok && len(indexErrs) == 1)
+		println(err.Error())
+	}
+	list, _ := Parse([]byte(`["a",null,2,"d",false]`))
+	_, err := list.StringArray()
+	assert.True(t, err != nil && err.Error() == "item 2: number is not a string; item 4: bool is not a string")
+	coerced, err := list.CoerceStringArray()
+	assert.True(t, err == nil)
+	assert.Equal(t, []string{"a", "", "2", "d", "false"}, coerced)
+	nested, _ := Parse([]byte(`["a",[1]]`))
+	_, err = nested.CoerceStringArray()
+	assert.True(t, err != nil && err.Error() == "item 1: array can't be converted to string")
+
+	plain, _ := Parse([]byte(`["a",null,"c"]`))
+	result, err := plain.StringArray()
+	assert.True(t, err == nil)
+	assert.Equal(t, []string{"a", "", "c"}, result)
+	_, err = NewEmpty().StringArray()
+	assert.True(t, err != nil)
+	_, err = NewJSONObject().StringArray()
+	assert.True(t, err != nil)
+}
+
+func TestJson_TypedArrays(t *testing.T) {
+	numbers, _ := Parse([]byte(`[1,2.0,3]`))
+	ints, err := numbers.IntArray()
+	assert.True(t, err == nil)
+	assert.Equal(t, []int{1, 2, 3}, ints)
+	floats, err := numbers.Float64Array()
+	assert.True(t, err == nil)
+	assert.Equal(t, []float64{1, 2, 3}, floats)
+
+	for _, source := range []string{`["1",2,3]`, `[1,2.5,3]`, `[1,2,null]`} {
+		list, _ := Parse([]byte(source))
+		ints, err = list.IntArray()
+		assert.True(t, ints == nil && err != nil)
+		println(err.Error())
+	}
+	for _, list := range []*Json{NewJSONArray().TryAdd(1.5).TryAdd(2), NewJSONArray().TryAdd(uint64(math.MaxUint64))} {
+		ints, err = list.IntArray()
+		assert.True(t, ints == nil && err != nil)
+		println(err.Error())
+	}
+	mixed, _ := Parse([]byte(`[1,"x",2,{}]`))
+	_, err = mixed.Float64Array()
+	assert.True(t, err != nil && err.Error() == "item 1: string is not a number; item 3: object is not a number")
+
+	flags, _ := Parse([]byte(`[true,false]`))
+	bools, err := flags.BoolArray()
+	assert.True(t, err == nil)
+	assert.Equal(t, []bool{true, false}, bools)
+	badFlags, _ := Parse([]byte(`["true",false,1]`))
+	_, err = badFlags.BoolArray()
+	assert.True(t, err != nil && err.Error() == "item 0: string is not a bool; item 2: number is not a bool")
+	_, err = NewEmpty().BoolArray()
+	assert.True(t, err != nil)
+}
+
+func TestJson_PrependArray(t *testing.T) {
+	empty := NewJSONArray()
+	empty.PrependArray(1, "two")
+	assert.Equal(t, `[1,"two"]`, empty.EncodeToStringOrDefault(""))
+
+	js := Obj("list", Arr(3, 4))
+	item := Obj("a", 1)
+	js.Get("list").PrependArray(item, nil).PrependArray()
+	item.Set("a", 2)
+	assert.Equal(t, `{"list":[{"a":1},null,3,4]}`, js.EncodeToStringOrDefault(""))
+
+	object := Obj("a", 1)
+	object.PrependArray(1)
+	assert.Equal(t, `{"a":1}`, object.EncodeToStringOrDefault(""))
+}
+
+func TestJson_InsertAt(t *testing.T) {
+	js := Obj("list", Arr())
+	list := js.Get("list")
+	list.InsertAt(0, "b").InsertAt(0, "a").InsertAt(2, "d").InsertAt(2, Arr("c"))
+	assert.Equal(t, `{"list":["a","b",["c"],"d"]}`, js.EncodeToStringOrDefault(""))
+	list.InsertAt(5, "x").InsertAt(-1, "x")
+	assert.Equal(t, 4, list.ArrayLength())
+
+	err := list.InsertAtE(5, "x")
+	assert.Equal(t, "index 5 out of range of array of length 4", err.Error())
+	assert.True(t, list.InsertAtE(4, "e") == nil)
+	assert.Equal(t, "e", js.GetPath("list").GetIndex(4).MustString())
+	err = js.InsertAtE(0, 1)
+	assert.Equal(t, "can't insert into object", err.Error())
+	err = NewEmpty().InsertAtE(0, 1)
+	assert.Equal(t, "can't insert into empty json", err.Error())
+}
+
+func TestJson_InsertAtRecorded(t *testing.T) {
+	js := Obj("list", Arr(1, 4))
+	js.StartRecording()
+	js.Get("list").PrependArray(0).InsertAt(2, 2).InsertAt(3, 3)
+	patch, _ := js.StopRecording()
+	replayed := Obj("list", Arr(1, 4))
+	assert.True(t, replayed.ApplyPatch(patch) == nil)
+	assert.Equal(t, `{"list":[0,1,2,3,4]}`, replayed.EncodeToStringOrDefault(""))
+	assert.True(t, js.IsSameJSONWith(replayed))
+}
+
+func TestJson_InsertAtObserved(t *testing.T) {
+	js := Obj("list", Arr(1, 4))
+	events := make([]ChangeEvent, 0)
+	js.OnChange(func(event ChangeEvent) { events = append(events, event) })
+	js.Get("list").InsertAt(1, 2).PrependArray(-1, 0).SetIndex(0, -2)
+	assert.Equal(t, 4, len(events))
+	for idx, expected := range [][]string{{"list", "1"}, {"list", "0"}, {"list", "1"}} {
+		assert.True(t, events[idx].Op == ChangeInsert)
+		assert.Equal(t, expected, events[idx].Path)
+		assert.True(t, events[idx].OldValue.IsEmpty())
+	}
+	assert.True(t, events[1].NewValue.MustInt() == -1)
+	assert.True(t, events[2].NewValue.MustInt() == 0)
+	// a replaced item is still reported as a set, with the item it replaced
+	assert.True(t, events[3].Op == ChangeSet)
+	assert.True(t, events[3].OldValue.MustInt() == -1)
+	assert.Equal(t, `{"list":[-2,0,1,2,4]}`, js.EncodeToStringOrDefault(""))
+}
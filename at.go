@@ -0,0 +1,95 @@
+package betterjson
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// At resolves segments, string keys and int indexes mixed, and never panics:
+//    email := js.At("users", 0, "email").MustString("")
+//
+// the result is never nil. it is empty when anything along the way fails, a
+// missing key, an index out of range, a segment of another type or a nil or
+// empty receiver, and Err then says why. like Get the result is linked to j,
+// and At on an empty result keeps its Err
+func (j *Json) At(segments ...interface{}) *Json {
+	if j == nil {
+		return failedAt(nil, errors.New("nil json"))
+	}
+	if j.IsEmpty() {
+		err := j.err
+		if err == nil {
+			err = errors.New("empty json")
+		}
+		return failedAt(j, err)
+	}
+	current := j
+	for idx, segment := range segments {
+		node := current.value.Interface()
+		key, item, err := atSegment(node, segment)
+		if err != nil {
+			return failedAt(j, errors.Wrapf(err, "segment %d", idx))
+		}
+		current = newChild(current, key, item)
+	}
+	return current
+}
+
+// Err returns why At returned an empty Json, nil otherwise
+func (j *Json) Err() error {
+	if j == nil {
+		return errors.New("nil json")
+	}
+	return j.err
+}
+
+func failedAt(j *Json, err error) *Json {
+	result := NewEmpty()
+	if j != nil {
+		result.settings = j.settings
+	}
+	result.err = err
+	return result
+}
+
+func atSegment(node interface{}, segment interface{}) (string, interface{}, error) {
+	var index int
+	switch value := segment.(type) {
+	case string:
+		switch container := unwrapRaw(node).(type) {
+		case map[string]interface{}:
+			item, ok := container[value]
+			if !ok {
+				return "", nil, errors.Errorf("key %q is missing", value)
+			}
+			return value, item, nil
+		case []interface{}:
+			idx, ok := arrayIndexSegment(value)
+			if !ok {
+				return "", nil, errors.Errorf("key %q of array is not an index", value)
+			}
+			index = idx
+		default:
+			return "", nil, errors.Errorf("key %q of %s", value, kindName(container))
+		}
+	case int:
+		index = value
+	case int64:
+		index = int(value)
+	case int32:
+		index = int(value)
+	case uint:
+		index = int(value)
+	default:
+		return "", nil, errors.Errorf("unsupported segment type %T", segment)
+	}
+	container, ok := unwrapRaw(node).([]interface{})
+	if !ok {
+		return "", nil, errors.Errorf("index %d of %s", index, kindName(node))
+	}
+	if index < 0 || index >= len(container) {
+		return "", nil, errors.Errorf("index %d out of range of array of length %d", index, len(container))
+	}
+	return strconv.Itoa(index), container[index], nil
+}
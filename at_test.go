@@ -0,0 +1,93 @@
+package betterjson
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_At(t *testing.T) {
+	js, _ := Parse([]byte(`{"users":[{"email":"a@b.c","tags":["x"]},null],"count":2}`))
+	assert.True(t, js.At("users", 0, "email").MustString() == "a@b.c")
+	assert.True(t, js.At("users", "0", "tags", int64(0)).MustString() == "x")
+	assert.True(t, js.At().IsSameJSONWith(js))
+	assert.True(t, js.At("users", 1).IsNull())
+	assert.True(t, js.At("users", 0).Err() == nil)
+
+	for _, segments := range [][]interface{}{
+		{"missing"}, {"users", 2}, {"users", -1}, {"users", "x"}, {"count", "x"}, {"count", 0},
+		{"users", 1, "email"}, {"users", 0.5}, {nil}, {"users", 0, "email", 0},
+	} {
+		result := js.At(segments...)
+		assert.True(t, result != nil && result.IsEmpty(), segments)
+		assert.True(t, result.Err() != nil, segments)
+		println(result.Err().Error())
+	}
+	missing := js.At("users", 5)
+	assert.True(t, missing.At("email").Err().Error() == missing.Err().Error())
+	var nilJson *Json
+	assert.True(t, nilJson.At("a").IsEmpty() && nilJson.At("a").Err() != nil)
+	assert.True(t, NewEmpty().At("a").Err() != nil)
+
+	// the result aliases j like Get
+	js.At("users", 0).Set("email", "changed")
+	assert.True(t, js.GetDottedPath("users.0.email").MustString() == "changed")
+	js.At("users", 0, "tags").TryAdd("y")
+	assert.True(t, js.GetDottedPath("users.0.tags.1").MustString() == "y")
+}
+
+func randomAtDocument(r *rand.Rand, depth int) interface{} {
+	switch kind := r.Intn(7); {
+	case depth > 3 || kind == 0:
+		return nil
+	case kind == 1:
+		return "s"
+	case kind == 2:
+		return r.Float64()
+	case kind == 3:
+		return r.Intn(2) == 0
+	case kind == 4:
+		items := make([]interface{}, r.Intn(4))
+		for idx := range items {
+			items[idx] = randomAtDocument(r, depth+1)
+		}
+		return items
+	default:
+		object := make(map[string]interface{})
+		for _, key := range []string{"a", "b", "0"}[:r.Intn(4)] {
+			object[key] = randomAtDocument(r, depth+1)
+		}
+		return object
+	}
+}
+
+func TestJson_AtNeverPanics(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	candidates := []interface{}{"a", "b", "0", "1", "-1", "", 0, 1, 2, -1, int64(1), uint(0), 1.5, nil, []string{"a"}, true}
+	for i := 0; i < 2000; i++ {
+		var doc *Json
+		switch i % 10 {
+		case 0:
+			doc = NewEmpty()
+		case 1:
+			doc = nil
+		default:
+			doc = wrapRaw(randomAtDocument(r, 0))
+		}
+		segments := make([]interface{}, r.Intn(6))
+		for idx := range segments {
+			segments[idx] = candidates[r.Intn(len(candidates))]
+		}
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					t.Fatalf("At(%v) panicked: %v", segments, recovered)
+				}
+			}()
+			result := doc.At(segments...)
+			assert.True(t, result != nil)
+			assert.True(t, result.IsEmpty() == (result.Err() != nil))
+			result.At(segments...)
+		}()
+	}
+}
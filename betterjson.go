@@ -3,15 +3,38 @@ package betterjson
 import (
 	"github.com/bitly/go-simplejson"
 	"github.com/pkg/errors"
-	"log"
-	"encoding/json"
 	"bytes"
-	"sort"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
 )
 
 // Json is immutable type when it's empty
 type Json struct {
 	value *simplejson.Json
+	// parent is the container wrapper this one was derived from, when the
+	// value has to be written back into it after being replaced
+	parent *Json
+	parentKey string
+	// snapshots is the stack of states saved by Snapshot
+	snapshots []snapshot
+	// recorder is set between StartRecording and StopRecording
+	recorder *recorder
+	// observers are the callbacks registered with OnChange
+	observers []*observer
+	// settings are the Options of the document, shared by its wrappers
+	settings *documentSettings
+	// err is why an empty Json returned by At is empty
+	err error
+	// trace is the AccessTrace of Traced, shared by the derived wrappers
+	trace *AccessTrace
+	// lazy is set on ParseLazy documents, until all their values are parsed
+	lazy bool
+	// pool is the pool of AcquireObject or AcquireArray the value came from,
+	// released is set once it went back there
+	pool     *sync.Pool
+	released bool
 }
 
 type jsonWithItemKeyValue struct {
@@ -47,6 +70,16 @@ func NewJSONObject() *Json {
 	return json
 }
 
+// NewJSONArrayFrom makes an array of items, holding the same data as an array
+// parsed from text. `*Json` items are deep copied like TryAdd does
+func NewJSONArrayFrom(items ...interface{}) *Json {
+	array := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		array = append(array, storedValue(item))
+	}
+	return wrapRaw(array)
+}
+
 func NewJSONArray() *Json {
 	json := new(Json)
 	json.value = simplejson.New()
@@ -54,6 +87,42 @@ func NewJSONArray() *Json {
 	return json
 }
 
+// NewJSONNull makes a standalone JSON null. unlike NewEmpty it is a value:
+// it encodes as null, and Set or TryAdd store it as null
+func NewJSONNull() *Json {
+	json := new(Json)
+	json.value = simplejson.New()
+	json.value.SetPath([]string{}, nil)
+	return json
+}
+
+// NewString makes a document holding the string s
+func NewString(s string) *Json {
+	return wrapRaw(s)
+}
+
+// NewInt makes a document holding the number i, stored like a number parsed
+// from text
+func NewInt(i int64) *Json {
+	return wrapRaw(json.Number(strconv.FormatInt(i, 10)))
+}
+
+// NewFloat makes a document holding the number f. NaN and infinities can't be
+// encoded
+func NewFloat(f float64) *Json {
+	return wrapRaw(f)
+}
+
+// NewNumber makes a document holding the number literal n as is
+func NewNumber(n json.Number) *Json {
+	return wrapRaw(n)
+}
+
+// NewBool makes a document holding b
+func NewBool(b bool) *Json {
+	return wrapRaw(b)
+}
+
 func (val *Json) ToSimpleJson() *simplejson.Json {
 	return val.value
 }
@@ -62,16 +131,35 @@ func (val *Json) IsEmpty() bool {
 	return val.value == nil
 }
 
-func (val *Json) IsNullJson() bool {
-	encoded, err := val.value.Encode()
-	if err != nil {
+// IsNull reports whether j holds JSON null, like a key stored with Set(key, nil)
+// or SetNull or a value made by NewJSONNull. an empty Json is not null
+func (j *Json) IsNull() bool {
+	if j.IsEmpty() {
+		return false
+	}
+	switch value := unwrapRaw(j.value.Interface()).(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return value == nil
+	case []interface{}:
+		return value == nil
+	case bool, string, json.Number, float64:
 		return false
+	default:
+		// other go values, like a nil pointer stored with Set, may encode as null
+		encoded, err := json.Marshal(value)
+		return err == nil && string(encoded) == "null"
 	}
-	return len(encoded) == 4 && string(encoded) == "null"
+}
+
+// IsNullJson is the old name of IsNull
+func (j *Json) IsNullJson() bool {
+	return j.IsNull()
 }
 
 func (j *Json) IsEmptyOrNull() bool {
-	return j.IsEmpty() || j.IsNullJson()
+	return j.IsEmpty() || j.IsNull()
 }
 
 func (val *Json) Select(key string) *Json {
@@ -82,7 +170,7 @@ func (val *Json) Select(key string) *Json {
  	if !ok {
  		return val
 	}
-	return FromNotEmptySimpleJson(item)
+	return FromNotEmptySimpleJson(item).linkTo(val, key)
 }
 
 type JsonValueProcessor func (json *simplejson.Json) *simplejson.Json
@@ -116,6 +204,7 @@ func (val *Json) GetKeyValuesIfAllContains(keys []string) *Json {
 }
 
 // WithKey({a: b, ...remaining}, key) => ({a: b, ...remaining}, a, b)
+// b is empty when the key is missing and IsNull when its value is null
 func (j *Json) WithKey(key string) *jsonWithItemKeyValue {
 	result := new(jsonWithItemKeyValue)
 	result.json = j
@@ -124,12 +213,7 @@ func (j *Json) WithKey(key string) *jsonWithItemKeyValue {
 		result.value = j
 		return result
 	}
-	itemVal := j.Get(key)
-	if itemVal.IsEmptyOrNull() {
-		result.value = NewEmpty()
-		return result
-	}
-	result.value = itemVal
+	result.value = j.CheckGet(key)
 	return result
 }
 
@@ -169,11 +253,15 @@ func (j *Json)CheckGet(key string) *Json {
 	if j.IsEmpty() {
 		return j
 	}
+	key = j.documentKey(key)
+	j.materializeKey(key)
 	item, ok := j.value.CheckGet(key)
 	if !ok {
-		return NewEmpty()
+		missing := NewEmpty()
+		missing.settings = j.settings
+		return missing
 	}
-	return FromNotEmptySimpleJson(item)
+	return FromNotEmptySimpleJson(item).linkTo(j, key)
 }
 
 // Interface returns the underlying data
@@ -181,70 +269,116 @@ func (j *Json) Interface() interface{} {
 	if j.IsEmpty() {
 		return nil
 	}
+	j.materializeAll()
 	return j.value.Interface()
 }
 
+// Set stores val under key of an object. nil and an empty `*Json` store JSON null.
+// like SetPath a `*Json` value is deep copied
 func (j *Json)Set(key string, val interface{}) *Json {
 	if j.IsEmpty() {
 		return j
 	}
-	if val == nil {
-		j.value.Set(key, val)
+	object, isObject := j.value.Interface().(map[string]interface{})
+	if !isObject {
 		return j
 	}
-	valJson, valIsJson := val.(*Json)
-	if valIsJson {
-		if valJson.IsEmpty() {
-			j.value.Set(key, nil)
-		} else {
-			j.value.Set(key, valJson.value.Interface())
-		}
-	} else {
-		valSimpleJson, valIsSimpleJson := val.(*simplejson.Json)
-		if valIsSimpleJson {
-			j.value.Set(key, valSimpleJson.Interface())
-		} else {
-			j.value.Set(key, val)
-		}
-	}
+	prior := j.prior([]string{key})
+	object[key] = storedValue(val)
+	j.changed(setOperation([]string{key}, object[key], prior), prior)
 	return j
 }
 
+// SetNull stores JSON null under key, the same as Set(key, nil). the key stays
+// present: ContainsKey is true and Get(key).IsNull(), unlike after Del(key)
+func (j *Json) SetNull(key string) *Json {
+	return j.Set(key, nil)
+}
+
 // SetPath modifies `Json`, recursively checking/creating map keys for the supplied path,
-// and then finally writing in the value
+// and then finally writing in the value. a `*Json` value is deep copied, later changes
+// to it don't affect j. with an empty branch j's own value is replaced in place, so
+// wrappers sharing j's node see the new value
 func (j *Json) SetPath(branch []string, val interface{}) *Json {
-	valJson, valIsJSON := val.(*Json)
-	if j.IsEmpty() {
-		if valIsJSON {
-			*j = *valJson
-		} else {
-			j.value = simplejson.New()
-			j.value.SetPath(branch, val)
-		}
-		return j
+	if len(branch) > 1 {
+		j.materializeKey(branch[0])
 	}
-	if len(branch) == 0 {
-		if valIsJSON {
-			*j = *valJson
-		} else {
-			j.value.SetPath(branch, val)
-		}
+	if !j.isObserved() {
+		j.setPath(branch, val)
 		return j
 	}
-	if valIsJSON {
-		j.value.SetPath(branch, valJson.value)
-	} else {
-		j.value.SetPath(branch, val)
+	target := j.setPathTarget(branch)
+	prior := j.prior(target)
+	j.setPath(branch, val)
+	if !j.IsEmpty() {
+		value, _ := valueAtBranch(j.value.Interface(), target)
+		j.changed(setOperation(target, value, prior), prior)
 	}
 	return j
 }
 
+// setPath stores a deep copy of Json values, so j never shares data with
+// another document. replacing j itself keeps its underlying node: objects are
+// refilled in place and other values are written back to j's parent
+func (j *Json) setPath(branch []string, val interface{}) {
+	if wrapped, ok := val.(*Json); ok && wrapped.IsEmpty() && len(branch) == 0 {
+		j.value = nil
+		j.writeBack()
+		return
+	}
+	val = storedValue(val)
+	if j.IsEmpty() {
+		j.value = simplejson.New()
+		j.value.SetPath(branch, val)
+		return
+	}
+	if len(branch) == 0 {
+		j.replaceData(val)
+		return
+	}
+	j.value.SetPath(branch, val)
+}
+
+// storedValue is the raw data stored for val, a deep copy of `*Json` and
+// `*simplejson.Json` values so documents never share containers
+func storedValue(val interface{}) interface{} {
+	switch val.(type) {
+	case *Json, *simplejson.Json:
+		return deepCopyRaw(val)
+	}
+	return val
+}
+
 // Del modifies `Json` map by deleting `key` if it is present.
 func (j *Json) Del(key string) *Json {
 	if j.IsEmpty() {
 		return j
 	}
+	if _, exists := j.value.CheckGet(key); !exists {
+		return j
+	}
+	prior := j.prior([]string{key})
 	j.value.Del(key)
+	j.changed(patchOperation{op: "remove", path: []string{key}}, prior)
+	return j
+}
+
+// DelPath removes the value at branch if it is present. like GetDottedPath the
+// segments may be array indexes, removing an array item shifts the ones after it
+func (j *Json) DelPath(branch ...string) *Json {
+	if j.IsEmpty() || len(branch) == 0 {
+		return j
+	}
+	prior := j.prior(branch)
+	root, err := patchRemove(j.value.Interface(), branch)
+	if err != nil {
+		return j
+	}
+	if len(branch) == 1 {
+		j.value.SetPath([]string{}, root)
+		j.writeBack()
+	}
+	j.changed(patchOperation{op: "remove", path: append([]string{}, branch...)}, prior)
 	return j
 }
 
@@ -254,7 +388,12 @@ func (j *Json) Del(key string) *Json {
 // useful for chaining operations (to traverse a nested JSON):
 //    js.Get("top_level").Get("dict").Get("value").Int()
 func (j *Json) Get(key string) *Json {
-	return FromNotEmptySimpleJson(j.value.Get(key))
+	if j.strictTraversal() {
+		j.recordTraversal(j.keyProblem(key, false))
+	}
+	key = j.documentKey(key)
+	j.materializeKey(key)
+	return FromNotEmptySimpleJson(j.value.Get(key)).linkTo(j, key)
 }
 
 // GetPath searches for the item as specified by the branch
@@ -276,7 +415,10 @@ func (j *Json) GetPath(branch ...string) *Json {
 // a json array instead of a json object:
 //    js.Get("top_level").Get("array").GetIndex(1).Get("key").Int()
 func (j *Json) GetIndex(index int) *Json {
-	return FromNotEmptySimpleJson(j.value.GetIndex(index))
+	if j.strictTraversal() {
+		j.recordTraversal(j.indexProblem(index))
+	}
+	return FromNotEmptySimpleJson(j.value.GetIndex(index)).linkTo(j, strconv.Itoa(index))
 }
 
 
@@ -285,6 +427,7 @@ func (j *Json) Map() (map[string]interface{}, error) {
 	if j.IsEmpty() {
 		return nil, errors.New("empty json parse to map[string]interface{} failed")
 	}
+	j.materializeAll()
 	return j.value.Map()
 }
 
@@ -320,12 +463,13 @@ func (j *Json) Bytes() ([]byte, error) {
 	return j.value.Bytes()
 }
 
-// StringArray type asserts to an `array` of `string`
+// StringArray type asserts to an `array` of `string`, null items become "".
+// the error is an IndexErrors naming the index and kind of every other item
 func (j *Json) StringArray() ([]string, error) {
 	if j.IsEmpty() {
 		return nil, errors.New("empty json parse to []string failed")
 	}
-	return j.value.StringArray()
+	return j.stringArray(false)
 }
 
 // MustArray guarantees the return of a `[]interface{}` (with optional default)
@@ -336,9 +480,17 @@ func (j *Json) StringArray() ([]string, error) {
 //		}
 func (j *Json) MustArray(args ...[]interface{}) []interface{} {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustArray failed")
+		j.mustOnEmpty("MustArray")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return nil
 	}
+	if j.settings != nil {
+		if _, err := j.value.Array(); err != nil {
+			j.recordError(errors.Wrap(err, "MustArray failed"))
+		}
+	}
 	return j.value.MustArray(args...)
 }
 
@@ -350,9 +502,18 @@ func (j *Json) MustArray(args ...[]interface{}) []interface{} {
 //		}
 func (j *Json) MustMap(args ...map[string]interface{}) map[string]interface{} {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustMap failed")
+		j.mustOnEmpty("MustMap")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return nil
 	}
+	j.materializeAll()
+	if j.settings != nil {
+		if _, err := j.value.Map(); err != nil {
+			j.recordError(errors.Wrap(err, "MustMap failed"))
+		}
+	}
 	return j.value.MustMap(args...)
 }
 
@@ -362,9 +523,17 @@ func (j *Json) MustMap(args ...map[string]interface{}) map[string]interface{} {
 //     myFunc(js.Get("param1").MustString(), js.Get("optional_param").MustString("my_default"))
 func (j *Json) MustString(args ...string) string {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustString failed")
+		j.mustOnEmpty("MustString")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return ""
 	}
+	if j.settings != nil {
+		if _, err := j.value.String(); err != nil {
+			j.recordError(errors.Wrap(err, "MustString failed"))
+		}
+	}
 	return j.value.MustString(args...)
 }
 
@@ -376,9 +545,17 @@ func (j *Json) MustString(args ...string) string {
 //		}
 func (j *Json) MustStringArray(args ...[]string) []string {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustStringArray failed")
+		j.mustOnEmpty("MustStringArray")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return nil
 	}
+	if j.settings != nil {
+		if _, err := j.StringArray(); err != nil {
+			j.recordError(errors.Wrap(err, "MustStringArray failed"))
+		}
+	}
 	return j.value.MustStringArray(args...)
 }
 
@@ -388,9 +565,17 @@ func (j *Json) MustStringArray(args ...[]string) []string {
 //     myFunc(js.Get("param1").MustInt(), js.Get("optional_param").MustInt(5150))
 func (j *Json) MustInt(args ...int) int {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustInt failed")
+		j.mustOnEmpty("MustInt")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return 0
 	}
+	if j.settings != nil {
+		if _, err := j.value.Int(); err != nil {
+			j.recordError(errors.Wrap(err, "MustInt failed"))
+		}
+	}
 	return j.value.MustInt(args...)
 }
 
@@ -400,9 +585,17 @@ func (j *Json) MustInt(args ...int) int {
 //     myFunc(js.Get("param1").MustFloat64(), js.Get("optional_param").MustFloat64(5.150))
 func (j *Json) MustFloat64(args ...float64) float64 {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustFloat64 failed")
+		j.mustOnEmpty("MustFloat64")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return 0
 	}
+	if j.settings != nil {
+		if _, err := j.value.Float64(); err != nil {
+			j.recordError(errors.Wrap(err, "MustFloat64 failed"))
+		}
+	}
 	return j.value.MustFloat64(args...)
 }
 
@@ -412,9 +605,17 @@ func (j *Json) MustFloat64(args ...float64) float64 {
 //     myFunc(js.Get("param1").MustBool(), js.Get("optional_param").MustBool(true))
 func (j *Json) MustBool(args ...bool) bool {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustBool failed")
+		j.mustOnEmpty("MustBool")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return false
 	}
+	if j.settings != nil {
+		if _, err := j.value.Bool(); err != nil {
+			j.recordError(errors.Wrap(err, "MustBool failed"))
+		}
+	}
 	return j.value.MustBool(args...)
 }
 
@@ -424,9 +625,17 @@ func (j *Json) MustBool(args ...bool) bool {
 //     myFunc(js.Get("param1").MustInt64(), js.Get("optional_param").MustInt64(5150))
 func (j *Json) MustInt64(args ...int64) int64 {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustInt64 failed")
+		j.mustOnEmpty("MustInt64")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return 0
 	}
+	if j.settings != nil {
+		if _, err := j.value.Int64(); err != nil {
+			j.recordError(errors.Wrap(err, "MustInt64 failed"))
+		}
+	}
 	return j.value.MustInt64(args...)
 }
 
@@ -436,9 +645,17 @@ func (j *Json) MustInt64(args ...int64) int64 {
 //     myFunc(js.Get("param1").MustUint64(), js.Get("optional_param").MustUint64(5150))
 func (j *Json) MustUint64(args ...uint64) uint64 {
 	if j.IsEmpty() {
-		log.Panicf("empty json MustUint64 failed")
+		j.mustOnEmpty("MustUint64")
+		if len(args) > 0 {
+			return args[0]
+		}
 		return 0
 	}
+	if j.settings != nil {
+		if _, err := j.value.Uint64(); err != nil {
+			j.recordError(errors.Wrap(err, "MustUint64 failed"))
+		}
+	}
 	return j.value.MustUint64(args...)
 }
 
@@ -446,6 +663,12 @@ func (j *Json)Encode() ([]byte, error) {
 	if j.IsEmpty() {
 		return []byte{}, errors.New("empty json can't be encoded")
 	}
+	if sink := metrics; sink != nil {
+		start := time.Now()
+		encoded, err := j.value.Encode()
+		observe(sink, "encode", start, len(encoded), err)
+		return encoded, err
+	}
 	return j.value.Encode()
 }
 
@@ -465,85 +688,59 @@ func (j *Json)EncodeToStringOrDefault(defaultVal string) string {
 	return string(bs)
 }
 
+// DigestJSONForEqual returns a canonical form of j with sorted object keys,
+// equal for documents IsSameJSONWith considers the same. it is valid json,
+// except "nil" for an empty Json and "" when j holds a value that can't be
+// encoded, see WriteDigest for the error
 func (j *Json) DigestJSONForEqual() string {
-	if j.IsEmpty() {
-		return "nil"
-	}
-	jsonVal := j
-	jsonArray, err := jsonVal.Array()
-	if err == nil {
-		var digestBuffer bytes.Buffer
-		digestBuffer.WriteString("[")
-		for idx, _ := range jsonArray {
-			if idx > 0 {
-				digestBuffer.WriteString(",")
-			}
-			itemJson := jsonVal.GetIndex(idx)
-			digestBuffer.WriteString(itemJson.DigestJSONForEqual())
-		}
-		digestBuffer.WriteString("]")
-		return digestBuffer.String()
-	}
-	jsonMap, err := jsonVal.Map()
-	if err == nil {
-		var digestBuffer bytes.Buffer
-		digestBuffer.WriteString("{")
-		keys := make([]string, 0)
-		for k, _ := range jsonMap {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for idx, key := range keys {
-			if idx > 0 {
-				digestBuffer.WriteString(",")
-			}
-			keyEncode, err := json.Marshal(key)
-			if err != nil {
-				digestBuffer.WriteString("\"error\":\"error\"")
-				continue
-			}
-			item := jsonVal.Get(key)
-			digestBuffer.WriteString(string(keyEncode))
-			digestBuffer.WriteString(":")
-			digestBuffer.WriteString(item.DigestJSONForEqual())
-		}
-		digestBuffer.WriteString("}")
-		return digestBuffer.String()
-	}
-	encoded, err := jsonVal.Encode()
-	if err != nil {
-		return "error"
+	var digest bytes.Buffer
+	if err := j.WriteDigest(&digest); err != nil {
+		return ""
 	}
-	encodedStr := string(encoded)
-	return encodedStr
+	return digest.String()
 }
 
-// whether json a and json b have the same value
+// whether json a and json b have the same value. documents holding values
+// that can't be encoded are never the same
 func (j *Json) IsSameJSONWith(other *Json) bool {
 	if other==nil || other.IsEmpty() {
 		return j.IsEmpty()
 	}
-
-	return j.DigestJSONForEqual() == other.DigestJSONForEqual()
+	if j.IsEmpty() {
+		return false
+	}
+	return digestEqual(j.value.Interface(), other.value.Interface())
 }
 
-// try add item when is array
+// try add item when is array. like SetPath a `*Json` item is deep copied
 func (j *Json) TryAdd(val interface{}) *Json {
 	jsonArray, err := j.Array()
 	if err != nil {
 		return j
 	}
-	valJson, valIsJson := val.(*Json)
-	if valIsJson {
-		if valJson.IsEmpty() {
-			jsonArray = append(jsonArray, nil)
-		} else {
-			jsonArray = append(jsonArray, valJson.value)
-		}
-	} else {
-		jsonArray = append(jsonArray, val)
+	jsonArray = append(jsonArray, storedValue(val))
+	appended := []string{"-"}
+	prior := j.prior(appended)
+	j.value.SetPath([]string{}, jsonArray)
+	j.writeBack()
+	j.changed(patchOperation{op: "add", path: appended, value: jsonArray[len(jsonArray)-1]}, prior)
+	return j
+}
+
+// SetIndex replaces the item at index of an array, doing nothing when j is
+// not an array or index is out of range
+func (j *Json) SetIndex(index int, val interface{}) *Json {
+	if j.IsEmpty() {
+		return j
+	}
+	array, isArray := j.value.Interface().([]interface{})
+	if !isArray || index < 0 || index >= len(array) {
+		return j
 	}
-	j.SetPath([]string{}, jsonArray)
+	path := []string{strconv.Itoa(index)}
+	prior := j.prior(path)
+	array[index] = storedValue(val)
+	j.changed(patchOperation{op: "replace", path: path, value: array[index]}, prior)
 	return j
 }
 
@@ -563,6 +760,7 @@ func (j *Json) ContainsKey(key string) bool {
 	return !val.IsEmpty()
 }
 
+// SetValue replaces the value of j, like SetPath with an empty branch
 func (j *Json) SetValue(val interface{}) *Json {
 	j.SetPath([]string{}, val)
 	return j
@@ -5,6 +5,7 @@ import (
 	"github.com/bitly/go-simplejson"
 	"github.com/stretchr/testify/assert"
 	"fmt"
+	"encoding/json"
 )
 
 func TestFromNotEmptySimpleJson(t *testing.T) {
@@ -62,8 +63,7 @@ func TestJson_CheckGet(t *testing.T) {
 }
 
 func TestJson_DigestJSONForEqual(t *testing.T) {
-	a := NewJSONObject()
-	a.Set("hello", "world").Set("hi", NewJSONObject().Set("age", 18).Set("items", NewJSONArray().TryAdd(1).TryAdd(nil).TryAdd("China"))).Set("times", 123).Set("a", "head")
+	a := Obj("hello", "world", "hi", Obj("age", 18, "items", Arr(1, nil, "China")), "times", 123, "a", "head")
 	aStr, err := a.EncodeToString()
 	assert.True(t, err == nil)
 	println(aStr)
@@ -74,7 +74,7 @@ func TestJson_DigestJSONForEqual(t *testing.T) {
 
 func TestJson_WithKey(t *testing.T) {
 	a := NewJSONObject()
-	hiRawJSON := NewJSONObject().Set("age", 18).Set("items", NewJSONArray().TryAdd(1).TryAdd(nil).TryAdd("China"))
+	hiRawJSON := Obj("age", 18, "items", Arr(1, nil, "China"))
 	a.Set("hello", "world").Set("hi", hiRawJSON).Set("times", 123).Set("a", "head")
 	aStr, err := a.EncodeToString()
 	assert.True(t, err == nil)
@@ -86,7 +86,7 @@ func TestJson_WithKey(t *testing.T) {
 	}
 	fmt.Println(hiMap)
 	b := hiJson.WithKey("age").Apply(func (j *Json, key string, value *Json) *Json {
-		return NewEmpty().SetValue(value.MustInt() * 100)
+		return NewInt(value.MustInt64() * 100)
 	})
 	bStr, err := b.EncodeToString()
 	assert.True(t, err == nil)
@@ -95,8 +95,7 @@ func TestJson_WithKey(t *testing.T) {
 }
 
 func TestJson_GetKeyValuesIfAllContains(t *testing.T) {
-	a := NewJSONObject()
-	a.Set("hello", "world").Set("hi", NewJSONObject().Set("age", 18).Set("items", NewJSONArray().TryAdd(1).TryAdd(nil).TryAdd("China"))).Set("times", 123)
+	a := Obj("hello", "world", "hi", Obj("age", 18, "items", Arr(1, nil, "China")), "times", 123)
 	aStr, err := a.EncodeToString()
 	assert.True(t, err == nil)
 	println(aStr)
@@ -108,8 +107,7 @@ func TestJson_GetKeyValuesIfAllContains(t *testing.T) {
 }
 
 func TestJson_IsSameJSONWith(t *testing.T) {
-	a := NewJSONObject()
-	a.Set("hello", "world").Set("hi", NewJSONObject().Set("age", 18).Set("items", NewJSONArray().TryAdd(1).TryAdd(nil).TryAdd("China"))).Set("times", 123)
+	a := Obj("hello", "world", "hi", Obj("age", 18, "items", Arr(1, nil, "China")), "times", 123)
 	aStr, err := a.EncodeToString()
 	assert.True(t, err == nil)
 	println(aStr)
@@ -128,9 +126,61 @@ func TestJson_IsNullJson(t *testing.T) {
 	assert.True(t, b.IsNullJson())
 }
 
+func TestNewJSONNull(t *testing.T) {
+	constructed := NewJSONNull()
+	parsed, err := Parse([]byte("null"))
+	assert.True(t, err == nil)
+	assert.True(t, constructed.IsNull() && constructed.IsNullJson())
+	assert.True(t, !constructed.IsEmpty())
+	assert.True(t, !NewEmpty().IsNull())
+	encoded, err := constructed.Encode()
+	assert.True(t, err == nil)
+	assert.Equal(t, "null", string(encoded))
+	assert.Equal(t, parsed.DigestJSONForEqual(), constructed.DigestJSONForEqual())
+	assert.True(t, constructed.IsSameJSONWith(parsed))
+	assert.True(t, !constructed.IsSameJSONWith(NewEmpty()))
+
+	a := NewJSONObject().Set("k", constructed)
+	b := NewJSONObject().Set("k", parsed)
+	assert.Equal(t, `{"k":null}`, a.EncodeToStringOrDefault(""))
+	assert.True(t, a.IsSameJSONWith(b))
+	assert.True(t, a.ContainsKey("k") && a.Get("k").IsNull())
+	arr := NewJSONArray().TryAdd(constructed).TryAdd(parsed)
+	assert.Equal(t, "[null,null]", arr.EncodeToStringOrDefault(""))
+	var missing *string
+	assert.True(t, NewJSONObject().Set("p", missing).Get("p").IsNull())
+}
+
+func TestScalarConstructors(t *testing.T) {
+	cases := []struct {
+		doc     *Json
+		encoded string
+	}{
+		{NewString("a\"b"), `"a\"b"`},
+		{NewInt(-42), "-42"},
+		{NewFloat(1.5), "1.5"},
+		{NewNumber(json.Number("12345678901234567890")), "12345678901234567890"},
+		{NewBool(true), "true"},
+	}
+	for _, c := range cases {
+		assert.True(t, !c.doc.IsEmpty() && !c.doc.IsNull())
+		assert.Equal(t, c.encoded, c.doc.EncodeToStringOrDefault(""))
+		parsed, err := Parse([]byte(c.encoded))
+		assert.True(t, err == nil)
+		assert.True(t, c.doc.IsSameJSONWith(parsed))
+	}
+	assert.Equal(t, int64(7), NewInt(7).MustInt64())
+	assert.True(t, NewInt(2).IsSameJSONWith(NewFloat(2)))
+
+	a := NewJSONObject().Set("s", NewString("x")).Set("n", NewInt(1)).Set("b", NewBool(false))
+	a.Set("list", NewJSONArray().TryAdd(NewFloat(0.25)).TryAdd(NewNumber("3")))
+	expected, _ := Parse([]byte(`{"s":"x","n":1,"b":false,"list":[0.25,3]}`))
+	assert.True(t, a.IsSameJSONWith(expected))
+	println(a.EncodeToStringOrDefault(""))
+}
+
 func TestJson_ToSimpleJson(t *testing.T) {
-	a := NewJSONObject()
-	a.Set("hello", "world").Set("hi", NewJSONObject().Set("age", 18).Set("items", NewJSONArray().TryAdd(1).TryAdd(nil).TryAdd("China"))).Set("times", 123)
+	a := Obj("hello", "world", "hi", Obj("age", 18, "items", Arr(1, nil, "China")), "times", 123)
 	aStr, err := a.EncodeToString()
 	assert.True(t, err == nil)
 	println(aStr)
@@ -143,8 +193,7 @@ func TestJson_ToSimpleJson(t *testing.T) {
 }
 
 func TestJson_TrampolineKeys(t *testing.T) {
-	a := NewJSONObject()
-	a.Set("hello", "world").Set("hi", NewJSONObject().Set("age", 18).Set("items", NewJSONArray().TryAdd(1).TryAdd(nil).TryAdd("China"))).Set("times", 123)
+	a := Obj("hello", "world", "hi", Obj("age", 18, "items", Arr(1, nil, "China")), "times", 123)
 	aStr, err := a.EncodeToString()
 	assert.True(t, err == nil)
 	println(aStr)
@@ -156,4 +205,89 @@ func TestJson_TrampolineKeys(t *testing.T) {
 	assert.True(t, err == nil)
 	println("result count: ", resultJSON.MustInt())
 	assert.True(t, resultJSON.MustInt() == 2)
-}
\ No newline at end of file
+}
+
+func TestJson_SetValueDoesNotAlias(t *testing.T) {
+	a, _ := Parse([]byte(`{"config":{"port":80},"name":"a"}`))
+	other, _ := Parse([]byte(`{"port":8080,"hosts":["x"]}`))
+	config := a.Get("config")
+	config.SetValue(other)
+	assert.True(t, a.GetDottedPath("config.port").MustInt() == 8080)
+
+	other.Set("port", 1)
+	other.Get("hosts").TryAdd("y")
+	assert.True(t, a.GetDottedPath("config.port").MustInt() == 8080)
+	assert.True(t, a.GetDottedPath("config.hosts").ArrayLength() == 1)
+
+	config.Set("port", 9090)
+	a.Get("config").Get("hosts").TryAdd("z")
+	assert.True(t, a.GetDottedPath("config.port").MustInt() == 9090)
+	assert.True(t, a.GetDottedPath("config.hosts").ArrayLength() == 2)
+	assert.True(t, other.Get("port").MustInt() == 1)
+	assert.True(t, other.Get("hosts").ArrayLength() == 2)
+
+	// wrappers sharing the replaced node keep seeing it
+	alias, _ := a.ObjectAtPath()
+	a.SetValue(other)
+	assert.True(t, alias.Get("port").MustInt() == 1)
+	a.Set("port", 2)
+	assert.True(t, alias.Get("port").MustInt() == 2)
+	assert.True(t, other.Get("port").MustInt() == 1)
+
+	b := NewEmpty().SetPath([]string{"nested", "value"}, other)
+	other.Set("port", 3)
+	assert.True(t, b.GetDottedPath("nested.value.port").MustInt() == 1)
+}
+
+func TestJson_NullTruthTable(t *testing.T) {
+	parsed, _ := Parse([]byte(`{"k":null}`))
+	cases := []struct {
+		name    string
+		doc     *Json
+		present bool
+	}{
+		{"Set nil", NewJSONObject().Set("k", nil), true},
+		{"Set empty", NewJSONObject().Set("k", NewEmpty()), true},
+		{"SetNull", NewJSONObject().SetNull("k"), true},
+		{"parsed null", parsed, true},
+		{"Set then Del", NewJSONObject().SetNull("k").Del("k"), false},
+		{"Del then Set nil", NewJSONObject().Del("k").Set("k", nil), true},
+	}
+	for _, c := range cases {
+		encoded, err := c.doc.EncodeToString()
+		assert.True(t, err == nil)
+		assert.True(t, c.doc.ContainsKey("k") == c.present)
+		assert.True(t, c.doc.CheckGet("k").IsEmpty() == !c.present)
+		assert.True(t, !c.doc.Get("k").IsEmpty())
+		assert.True(t, c.doc.Get("k").IsNull())
+		assert.True(t, c.doc.Get("k").IsNullJson())
+		value := c.doc.WithKey("k").value
+		if c.present {
+			assert.Equal(t, `{"k":null}`, encoded)
+			assert.True(t, value.IsNull() && !value.IsEmpty())
+			assert.True(t, c.doc.IsSameJSONWith(parsed))
+		} else {
+			assert.Equal(t, `{}`, encoded)
+			assert.True(t, value.IsEmpty() && !value.IsNull())
+		}
+	}
+	array := NewJSONArray().TryAdd(nil).TryAdd(NewEmpty())
+	assert.True(t, array.EncodeToStringOrDefault("") == `[null,null]`)
+	assert.True(t, array.GetIndex(0).IsNull() && array.GetIndex(1).IsNull())
+	assert.False(t, NewEmpty().IsNull())
+	assert.False(t, NewJSONObject().IsNull())
+}
+
+func TestJson_SetDoesNotAlias(t *testing.T) {
+	other, _ := Parse([]byte(`{"port":8080,"hosts":["x"]}`))
+	a := NewJSONObject().Set("server", other)
+	b := NewJSONObject().BulkSet(map[string]interface{}{"server": other})
+	other.Set("port", 1)
+	other.Get("hosts").SetIndex(0, "y")
+	for _, js := range []*Json{a, b} {
+		assert.True(t, js.GetDottedPath("server.port").MustInt() == 8080)
+		assert.True(t, js.GetDottedPath("server.hosts.0").MustString() == "x")
+	}
+	a.Get("server").Set("port", 2)
+	assert.True(t, other.Get("port").MustInt() == 1)
+}
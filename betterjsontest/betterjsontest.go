@@ -0,0 +1,92 @@
+// Package betterjsontest has test assertions for betterjson documents that
+// compare them canonically and print the differing paths on failure
+package betterjsontest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoowii/betterjson"
+)
+
+// AssertEqualJSON reports a failure when actual isn't the same document as
+// the json text expected, listing the DiffReport of the two. numbers are
+// compared by value, so 1 matches 1.0 and a go 0.1 matches 0.1:
+//    betterjsontest.AssertEqualJSON(t, `{"id":1,"tags":["a"]}`, result)
+func AssertEqualJSON(t testing.TB, expected string, actual *betterjson.Json) bool {
+	t.Helper()
+	want, ok := parseExpected(t, expected)
+	if !ok {
+		return false
+	}
+	return reportDifferences(t, "json is not equal to the expected document", betterjson.DiffReport(want, actual))
+}
+
+// AssertContainsJSON reports a failure when the json text subset isn't part
+// of actual. objects of actual may have keys subset doesn't have, arrays must
+// have the items of subset at the same indexes and nothing more
+func AssertContainsJSON(t testing.TB, subset string, actual *betterjson.Json) bool {
+	t.Helper()
+	want, ok := parseExpected(t, subset)
+	if !ok {
+		return false
+	}
+	differences := make([]betterjson.Difference, 0)
+	for _, difference := range betterjson.DiffReport(want, actual) {
+		if difference.Kind == betterjson.DiffAdded && len(difference.Path) > 0 {
+			parent := want.GetDottedPath(betterjson.JoinDottedPath(difference.Path[:len(difference.Path)-1]))
+			if _, err := parent.Map(); err == nil {
+				continue
+			}
+		}
+		differences = append(differences, difference)
+	}
+	return reportDifferences(t, "json doesn't contain the expected document", differences)
+}
+
+// AssertPathEquals reports a failure when the value at dottedPath of actual,
+// see GetDottedPath, isn't the same as expected. expected is a go value like
+// the ones Set takes, compared as the json it encodes to
+func AssertPathEquals(t testing.TB, actual *betterjson.Json, dottedPath string, expected interface{}) bool {
+	t.Helper()
+	if !actual.ContainsKeyPath(betterjson.ParseDottedPath(dottedPath)...) {
+		t.Errorf("path %s is missing", dottedPath)
+		return false
+	}
+	// a round trip through json makes []string and the like comparable
+	encoded, err := betterjson.Arr(expected).Encode()
+	if err != nil {
+		t.Errorf("expected value can't be encoded: %s", err.Error())
+		return false
+	}
+	want, _ := betterjson.Parse(encoded)
+	branch := betterjson.ParseDottedPath(dottedPath)
+	differences := betterjson.DiffReport(want.GetIndex(0), actual.GetDottedPath(dottedPath))
+	for idx := range differences {
+		differences[idx].Path = append(branch[:len(branch):len(branch)], differences[idx].Path...)
+	}
+	return reportDifferences(t, "value at "+dottedPath+" is not the expected value", differences)
+}
+
+func parseExpected(t testing.TB, expected string) (*betterjson.Json, bool) {
+	t.Helper()
+	want, err := betterjson.Parse([]byte(expected))
+	if err != nil {
+		t.Errorf("expected json is invalid: %s", err.Error())
+		return nil, false
+	}
+	return want, true
+}
+
+func reportDifferences(t testing.TB, message string, differences []betterjson.Difference) bool {
+	t.Helper()
+	if len(differences) == 0 {
+		return true
+	}
+	lines := make([]string, 0, len(differences))
+	for _, difference := range differences {
+		lines = append(lines, "    "+difference.String())
+	}
+	t.Errorf("%s:\n%s", message, strings.Join(lines, "\n"))
+	return false
+}
@@ -0,0 +1,72 @@
+package betterjsontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/zoowii/betterjson"
+)
+
+// recordingT collects the failures of an assertion instead of failing the test
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEqualJSON(t *testing.T) {
+	actual, _ := betterjson.Parse([]byte(`{"b":[1,2],"a":{"x":1.0}}`))
+	assert.True(t, AssertEqualJSON(t, `{"a":{"x":1},"b":[1,2]}`, actual))
+
+	recorder := &recordingT{TB: t}
+	assert.False(t, AssertEqualJSON(recorder, `{"a":{"x":2},"b":[1]}`, actual))
+	assert.Equal(t, 1, len(recorder.failures))
+	println(recorder.failures[0])
+	assert.Equal(t, "json is not equal to the expected document:\n    changed a.x: 2 => 1.0\n    added b.1: 2", recorder.failures[0])
+
+	assert.False(t, AssertEqualJSON(recorder, `{"a":`, actual))
+	assert.True(t, strings.HasPrefix(recorder.failures[1], "expected json is invalid: "))
+}
+
+func TestAssertContainsJSON(t *testing.T) {
+	actual, _ := betterjson.Parse([]byte(`{"id":7,"user":{"name":"a","roles":[{"name":"admin","since":1}]},"extra":true}`))
+	assert.True(t, AssertContainsJSON(t, `{"user":{"roles":[{"name":"admin"}]}}`, actual))
+	assert.True(t, AssertContainsJSON(t, `{}`, actual))
+
+	recorder := &recordingT{TB: t}
+	assert.False(t, AssertContainsJSON(recorder, `{"id":8,"user":{"roles":[]},"missing":null}`, actual))
+	println(recorder.failures[0])
+	assert.Equal(t, "json doesn't contain the expected document:\n    changed id: 8 => 7\n    removed missing: null\n    added user.roles.0: {\"name\":\"admin\",\"since\":1}", recorder.failures[0])
+}
+
+func TestAssertPathEquals(t *testing.T) {
+	actual := betterjson.Obj("user", betterjson.Obj("id", 1, "tags", betterjson.Arr("a", "b")))
+	assert.True(t, AssertPathEquals(t, actual, "user.id", 1.0))
+	assert.True(t, AssertPathEquals(t, actual, "user.tags", []interface{}{"a", "b"}))
+	assert.True(t, AssertPathEquals(t, actual, "user.tags.1", "b"))
+
+	recorder := &recordingT{TB: t}
+	assert.False(t, AssertPathEquals(recorder, actual, "user.tags", []string{"a"}))
+	assert.False(t, AssertPathEquals(recorder, actual, "user.name", "a"))
+	assert.Equal(t, []string{"value at user.tags is not the expected value:\n    added user.tags.1: \"b\"", "path user.name is missing"}, recorder.failures)
+}
+
+func TestAssertEqualJSONNumbers(t *testing.T) {
+	price, ratio := 0.1, 0.1
+	actual := betterjson.Obj("price", price, "sum", price+0.2, "ratio", float32(ratio), "count", int64(3), "big", json.Number("12345678901234567890"))
+	assert.True(t, AssertEqualJSON(t, `{"price":0.1,"sum":0.30000000000000004,"ratio":0.1,"count":3.0,"big":12345678901234567890}`, actual))
+	assert.True(t, AssertPathEquals(t, actual, "price", 0.1))
+	assert.True(t, AssertContainsJSON(t, `{"count":3}`, actual))
+
+	recorder := &recordingT{TB: t}
+	assert.False(t, AssertEqualJSON(recorder, `{"price":0.1,"sum":0.3,"ratio":0.1,"count":3,"big":12345678901234567891}`, actual))
+	println(recorder.failures[0])
+	assert.Equal(t, "json is not equal to the expected document:\n    changed big: 12345678901234567891 => 12345678901234567890\n    changed sum: 0.3 => 0.30000000000000004", recorder.failures[0])
+}
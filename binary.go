@@ -0,0 +1,74 @@
+package betterjson
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrTypeMismatch is the cause of errors from accessors finding a value
+	// of another kind than they need
+	ErrTypeMismatch = errors.New("json value has another type")
+	// ErrMalformedEncoding is the cause of errors from decoding binary data
+	// out of a string that isn't valid in the expected encoding
+	ErrMalformedEncoding = errors.New("malformed binary encoding")
+)
+
+// Base64Bytes decodes a base64 string value. standard and url-safe alphabets
+// are detected automatically, with or without padding. use errors.Cause to
+// tell ErrTypeMismatch from ErrMalformedEncoding
+func (j *Json) Base64Bytes() ([]byte, error) {
+	s, err := j.binaryString("base64")
+	if err != nil {
+		return nil, err
+	}
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		encoding = base64.URLEncoding
+	}
+	if !strings.HasSuffix(s, "=") && len(s)%4 != 0 {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	data, err := encoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(ErrMalformedEncoding, "invalid base64: "+err.Error())
+	}
+	return data, nil
+}
+
+// HexBytes decodes a hex string value, upper or lower case
+func (j *Json) HexBytes() ([]byte, error) {
+	s, err := j.binaryString("hex")
+	if err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(ErrMalformedEncoding, "invalid hex: "+err.Error())
+	}
+	return data, nil
+}
+
+func (j *Json) binaryString(encoding string) (string, error) {
+	if j.IsEmpty() {
+		return "", errors.Wrap(ErrTypeMismatch, "empty json parse to "+encoding+" bytes failed")
+	}
+	s, ok := j.value.Interface().(string)
+	if !ok {
+		return "", errors.Wrap(ErrTypeMismatch, kindName(j.value.Interface())+" parse to "+encoding+" bytes failed")
+	}
+	return s, nil
+}
+
+// SetBase64 sets key to the standard padded base64 encoding of data
+func (j *Json) SetBase64(key string, data []byte) *Json {
+	return j.Set(key, base64.StdEncoding.EncodeToString(data))
+}
+
+// SetHex sets key to the lower case hex encoding of data
+func (j *Json) SetHex(key string, data []byte) *Json {
+	return j.Set(key, hex.EncodeToString(data))
+}
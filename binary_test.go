@@ -0,0 +1,55 @@
+package betterjson
+
+import (
+	"bytes"
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_SetBase64(t *testing.T) {
+	a := NewJSONObject()
+	for _, data := range [][]byte{{}, {0}, {0, 0}, {0, 0, 0}, {0xfb, 0xff, 0x00, 0xfe}, []byte("hello\x00world")} {
+		a.SetBase64("data", data)
+		decoded, err := a.Get("data").Base64Bytes()
+		assert.True(t, err == nil)
+		assert.True(t, bytes.Equal(decoded, data))
+	}
+	a.SetBase64("data", []byte{0xfb, 0xff})
+	assert.True(t, a.Get("data").MustString() == "+/8=")
+}
+
+func TestJson_Base64BytesVariants(t *testing.T) {
+	expected := []byte{0xfb, 0xff}
+	for _, encoded := range []string{"+/8=", "+/8", "-_8=", "-_8"} {
+		decoded, err := NewEmpty().SetValue(encoded).Base64Bytes()
+		assert.True(t, err == nil, encoded)
+		assert.True(t, bytes.Equal(decoded, expected), encoded)
+	}
+	_, err := NewEmpty().SetValue("ab$=").Base64Bytes()
+	assert.True(t, errors.Cause(err) == ErrMalformedEncoding)
+	_, err = NewEmpty().SetValue("+_8=").Base64Bytes()
+	assert.True(t, errors.Cause(err) == ErrMalformedEncoding)
+	_, err = NewEmpty().SetValue(123).Base64Bytes()
+	assert.True(t, errors.Cause(err) == ErrTypeMismatch)
+	_, err = NewEmpty().Base64Bytes()
+	assert.True(t, errors.Cause(err) == ErrTypeMismatch)
+}
+
+func TestJson_SetHex(t *testing.T) {
+	a := NewJSONObject()
+	a.SetHex("data", []byte{0, 0x1f, 0xab, 0})
+	assert.True(t, a.Get("data").MustString() == "001fab00")
+	decoded, err := a.Get("data").HexBytes()
+	assert.True(t, err == nil)
+	assert.True(t, bytes.Equal(decoded, []byte{0, 0x1f, 0xab, 0}))
+	decoded, err = NewEmpty().SetValue("ABCD").HexBytes()
+	assert.True(t, err == nil)
+	assert.True(t, bytes.Equal(decoded, []byte{0xab, 0xcd}))
+	_, err = NewEmpty().SetValue("abc").HexBytes()
+	assert.True(t, errors.Cause(err) == ErrMalformedEncoding)
+	_, err = NewEmpty().SetValue("zz").HexBytes()
+	assert.True(t, errors.Cause(err) == ErrMalformedEncoding)
+	_, err = a.HexBytes()
+	assert.True(t, errors.Cause(err) == ErrTypeMismatch)
+}
@@ -0,0 +1,112 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Obj builds an object from alternating keys and values, panicking when
+// ObjE fails. values are stored like Set stores them, so they may be plain go
+// values or other *Json documents, from Obj and Arr too:
+//    user := betterjson.Obj("user", betterjson.Obj("id", 1, "tags", betterjson.Arr("a", "b")))
+//    // {"user":{"id":1,"tags":["a","b"]}}
+func Obj(pairs ...interface{}) *Json {
+	result, err := ObjE(pairs...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// ObjE is Obj returning an error for an odd number of arguments, a key that
+// isn't a string, a repeated key or an empty Json value
+func ObjE(pairs ...interface{}) (*Json, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.Errorf("object needs key value pairs, got %d arguments", len(pairs))
+	}
+	object := make(map[string]interface{}, len(pairs)/2)
+	for idx := 0; idx < len(pairs); idx += 2 {
+		key, ok := pairs[idx].(string)
+		if !ok {
+			return nil, errors.Errorf("object key at argument %d is a %T, not a string", idx, pairs[idx])
+		}
+		if _, repeated := object[key]; repeated {
+			return nil, errors.Errorf("object key %q is repeated", key)
+		}
+		value, err := builtValue(pairs[idx+1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "object value of %q", key)
+		}
+		object[key] = value
+	}
+	return wrapRaw(object), nil
+}
+
+// Arr builds an array of items, panicking when ArrE fails. items are stored
+// like TryAdd stores them
+func Arr(items ...interface{}) *Json {
+	result, err := ArrE(items...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// ArrE is Arr returning an error for an empty Json item
+func ArrE(items ...interface{}) (*Json, error) {
+	array := make([]interface{}, 0, len(items))
+	for idx, item := range items {
+		value, err := builtValue(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "array item %d", idx)
+		}
+		array = append(array, value)
+	}
+	return wrapRaw(array), nil
+}
+
+func builtValue(val interface{}) (interface{}, error) {
+	if js, ok := val.(*Json); ok && (js == nil || js.IsEmpty()) {
+		return nil, errors.New("empty json can't be a value")
+	}
+	return storedValue(val), nil
+}
+
+// BulkSet is Set for every key of entries, writing them all in one pass over
+// the object. observers and recordings see one change per key, in sorted key
+// order
+func (j *Json) BulkSet(entries map[string]interface{}) *Json {
+	if j.IsEmpty() {
+		return j
+	}
+	object, isObject := j.value.Interface().(map[string]interface{})
+	if !isObject {
+		return j
+	}
+	if j.isObserved() {
+		for _, key := range sortedKeys(entries) {
+			j.Set(key, entries[key])
+		}
+		return j
+	}
+	// filled in place, so wrappers sharing the object see the keys
+	for key, val := range entries {
+		object[key] = storedValue(val)
+	}
+	return j
+}
+
+// BuildArray makes an array of n items, the item at i being fn(i). items
+// are stored like TryAdd stores them, into a slice allocated once:
+//    rows := betterjson.BuildArray(len(users), func(i int) interface{} {
+//        return betterjson.Obj("id", users[i].ID, "name", users[i].Name)
+//    })
+func BuildArray(n int, fn func(i int) interface{}) *Json {
+	if n < 0 {
+		n = 0
+	}
+	array := make([]interface{}, n)
+	for i := range array {
+		array[i] = storedValue(fn(i))
+	}
+	return wrapRaw(array)
+}
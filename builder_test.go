@@ -0,0 +1,142 @@
+package betterjson
+
+import (
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjArr(t *testing.T) {
+	user := Obj("user", Obj("id", 1, "tags", Arr("a", "b")))
+	encoded, err := user.Encode()
+	assert.True(t, err == nil)
+	println(string(encoded))
+	assert.Equal(t, `{"user":{"id":1,"tags":["a","b"]}}`, string(encoded))
+
+	parsed, _ := Parse([]byte(`{"ok":true}`))
+	built := Arr(parsed, nil, 2.5, []interface{}{"x"}, Obj())
+	encoded, _ = built.Encode()
+	assert.Equal(t, `[{"ok":true},null,2.5,["x"],{}]`, string(encoded))
+	built.GetIndex(0).Set("ok", false)
+	assert.Equal(t, true, parsed.Get("ok").MustBool(false))
+	assert.Equal(t, 0, len(Arr().MustArray()))
+}
+
+func TestObjEArrE(t *testing.T) {
+	_, err := ObjE("a", 1, "b")
+	println(err.Error())
+	assert.Equal(t, "object needs key value pairs, got 3 arguments", err.Error())
+	_, err = ObjE("a", 1, 2, 3)
+	assert.Equal(t, "object key at argument 2 is a int, not a string", err.Error())
+	_, err = ObjE("a", 1, "a", 2)
+	assert.Equal(t, `object key "a" is repeated`, err.Error())
+	_, err = ObjE("a", NewEmpty())
+	assert.Equal(t, `object value of "a": empty json can't be a value`, err.Error())
+	_, err = ArrE(1, NewEmpty())
+	assert.Equal(t, "array item 1: empty json can't be a value", err.Error())
+
+	defer func() {
+		assert.True(t, recover() != nil)
+	}()
+	Obj("key without value")
+}
+
+func TestJson_BulkSet(t *testing.T) {
+	entries := map[string]interface{}{"a": 1, "b": Obj("x", true), "c": nil, "d": []interface{}{"y"}}
+	naive := NewJSONObject().Set("keep", 0)
+	for key, val := range entries {
+		naive.Set(key, val)
+	}
+	bulk := NewJSONObject().Set("keep", 0).BulkSet(entries)
+	assert.Equal(t, naive.DigestJSONForEqual(), bulk.DigestJSONForEqual())
+	assert.Equal(t, 5, len(bulk.MustMap()))
+
+	recorded := NewJSONObject()
+	recorded.StartRecording()
+	recorded.BulkSet(map[string]interface{}{"z": 1, "y": 2})
+	patch, _ := recorded.StopRecording()
+	assert.Equal(t, `[{"op":"add","path":"/y","value":2},{"op":"add","path":"/z","value":1}]`, patch.EncodeToStringOrDefault(""))
+
+	parent := Obj("child", Obj())
+	parent.Get("child").BulkSet(entries)
+	assert.Equal(t, 1, parent.GetDottedPath("child.a").MustInt())
+
+	assert.True(t, NewJSONArray().BulkSet(entries).MustArray() != nil)
+	assert.True(t, NewEmpty().BulkSet(entries).IsEmpty())
+}
+
+func TestBuildArray(t *testing.T) {
+	row := Obj("shared", true)
+	built := BuildArray(100, func(i int) interface{} {
+		if i%2 == 0 {
+			return row
+		}
+		return i
+	})
+	naive := NewJSONArray()
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			naive.TryAdd(row)
+		} else {
+			naive.TryAdd(i)
+		}
+	}
+	assert.Equal(t, naive.DigestJSONForEqual(), built.DigestJSONForEqual())
+	// *Json items are copied
+	built.GetIndex(0).Set("shared", false)
+	assert.Equal(t, true, built.GetIndex(2).Get("shared").MustBool(false))
+	assert.Equal(t, "[]", BuildArray(-1, nil).EncodeToStringOrDefault(""))
+}
+
+func BenchmarkSetLoop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		js := NewJSONObject()
+		for k := 0; k < 1000; k++ {
+			js.Set(benchmarkKeys[k], k)
+		}
+	}
+}
+
+func TestJson_BulkSetKeepsAliases(t *testing.T) {
+	root := Obj("cfg", Obj())
+	alias := root.Get("cfg")
+	root.Get("cfg").BulkSet(map[string]interface{}{"x": 1})
+	assert.Equal(t, `{"cfg":{"x":1}}`, root.EncodeToStringOrDefault(""))
+	assert.Equal(t, `{"x":1}`, alias.EncodeToStringOrDefault(""))
+	alias.Set("y", 2)
+	assert.Equal(t, `{"cfg":{"x":1,"y":2}}`, root.EncodeToStringOrDefault(""))
+}
+
+func BenchmarkBulkSet(b *testing.B) {
+	entries := make(map[string]interface{}, 1000)
+	for k := 0; k < 1000; k++ {
+		entries[benchmarkKeys[k]] = k
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewJSONObject().BulkSet(entries)
+	}
+}
+
+func BenchmarkTryAddLoop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		js := NewJSONArray()
+		for k := 0; k < 10000; k++ {
+			js.TryAdd(k)
+		}
+	}
+}
+
+func BenchmarkBuildArray(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		BuildArray(10000, func(k int) interface{} { return k })
+	}
+}
+
+var benchmarkKeys = func() []string {
+	keys := make([]string, 1000)
+	for k := range keys {
+		keys[k] = "key" + strconv.Itoa(k)
+	}
+	return keys
+}()
@@ -0,0 +1,117 @@
+package betterjson
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// ParseCache caches parsed documents by the hash of their bytes, evicting the
+// least recently used ones beyond its bounds. it is safe for concurrent use
+type ParseCache struct {
+	maxEntries int
+	maxBytes   int64
+
+	mutex   sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	// order holds the *parseCacheEntry values, most recently used first
+	order *list.List
+	bytes int64
+	stats ParseCacheStats
+}
+
+// ParseCacheStats are the counters of a ParseCache
+type ParseCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Entries and Bytes are the documents cached and the size of their data
+	Entries int
+	Bytes   int64
+}
+
+type parseCacheEntry struct {
+	key  [sha256.Size]byte
+	root interface{}
+	size int64
+}
+
+// NewParseCache makes a ParseCache holding at most maxEntries documents of at
+// most maxBytes of data in total. a bound <= 0 is no bound, and a document
+// larger than maxBytes is never cached:
+//    var configs = betterjson.NewParseCache(256, 16<<20)
+//    ...
+//    config, err := configs.Get(payload)
+func NewParseCache(maxEntries int, maxBytes int64) *ParseCache {
+	return &ParseCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[[sha256.Size]byte]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get is Parse(data) returning a copy of the document cached for the same
+// bytes, if any. the copy is the caller's to modify. data that doesn't parse
+// is not cached
+func (c *ParseCache) Get(data []byte) (*Json, error) {
+	key := sha256.Sum256(data)
+	c.mutex.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.stats.Hits++
+		root := element.Value.(*parseCacheEntry).root
+		c.mutex.Unlock()
+		return wrapRaw(deepCopyRaw(root)), nil
+	}
+	c.stats.Misses++
+	c.mutex.Unlock()
+
+	parsed, err := Parse(data)
+	if err != nil {
+		return parsed, err
+	}
+	size := int64(len(data))
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return parsed, nil
+	}
+	entry := &parseCacheEntry{key: key, root: deepCopyRaw(parsed.Interface()), size: size}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[key]; ok {
+		// stored by a concurrent miss meanwhile
+		return parsed, nil
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.bytes += size
+	for (c.maxEntries > 0 && c.order.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.evict(c.order.Back())
+	}
+	return parsed, nil
+}
+
+func (c *ParseCache) evict(element *list.Element) {
+	entry := c.order.Remove(element).(*parseCacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+	c.stats.Evictions++
+}
+
+// Stats returns the current counters of c
+func (c *ParseCache) Stats() ParseCacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// Purge removes every cached document, the counters are kept
+func (c *ParseCache) Purge() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[[sha256.Size]byte]*list.Element)
+	c.order.Init()
+	c.bytes = 0
+}
@@ -0,0 +1,104 @@
+package betterjson
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCacheHitsReturnCopies(t *testing.T) {
+	cache := NewParseCache(10, 0)
+	data := []byte(`{"server":{"port":8080}}`)
+	first, err := cache.Get(data)
+	assert.True(t, err == nil)
+	first.Get("server").Set("port", 1)
+	second, err := cache.Get(data)
+	assert.True(t, err == nil)
+	assert.Equal(t, int64(8080), second.GetPath("server", "port").MustInt64())
+	second.Get("server").Set("port", 2)
+	third, _ := cache.Get(append([]byte{}, data...))
+	assert.Equal(t, int64(8080), third.GetPath("server", "port").MustInt64())
+	stats := cache.Stats()
+	assert.Equal(t, ParseCacheStats{Hits: 2, Misses: 1, Entries: 1, Bytes: int64(len(data))}, stats)
+
+	_, err = cache.Get([]byte(`{"broken"`))
+	assert.True(t, err != nil)
+	assert.Equal(t, 1, cache.Stats().Entries)
+}
+
+func TestParseCacheEviction(t *testing.T) {
+	cache := NewParseCache(2, 0)
+	a, b, c := []byte(`"a"`), []byte(`"b"`), []byte(`"c"`)
+	cache.Get(a)
+	cache.Get(b)
+	cache.Get(a)
+	cache.Get(c) // evicts b, the least recently used
+	cache.Get(a)
+	cache.Get(b)
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(4), stats.Misses)
+	assert.Equal(t, uint64(2), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+
+	sized := NewParseCache(0, 10)
+	sized.Get([]byte(`[1,2,3]`))
+	sized.Get([]byte(`[4,5,6]`))
+	assert.Equal(t, 1, sized.Stats().Entries)
+	assert.Equal(t, int64(7), sized.Stats().Bytes)
+	large, err := sized.Get([]byte(`"far too large"`))
+	assert.True(t, err == nil)
+	assert.Equal(t, "far too large", large.MustString())
+	assert.Equal(t, 1, sized.Stats().Entries)
+	sized.Purge()
+	assert.Equal(t, 0, sized.Stats().Entries)
+	assert.Equal(t, int64(0), sized.Stats().Bytes)
+}
+
+func TestParseCacheConcurrent(t *testing.T) {
+	cache := NewParseCache(4, 0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				js, err := cache.Get([]byte(`{"n":` + strconv.Itoa(i%6) + `}`))
+				assert.True(t, err == nil)
+				assert.Equal(t, int64(i%6), js.Get("n").MustInt64())
+				js.Set("n", g)
+			}
+		}(g)
+	}
+	wg.Wait()
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1600), stats.Hits+stats.Misses)
+	assert.True(t, stats.Entries <= 4)
+}
+
+func benchmarkConfig() []byte {
+	items := make([]interface{}, 16000)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": i, "name": "item " + strconv.Itoa(i), "tags": []interface{}{"a", "b"}, "price": 12.5}
+	}
+	data, _ := wrapRaw(map[string]interface{}{"items": items}).Encode()
+	return data
+}
+
+func BenchmarkParseRepeated(b *testing.B) {
+	data := benchmarkConfig()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		Parse(data)
+	}
+}
+
+func BenchmarkParseCacheRepeated(b *testing.B) {
+	data := benchmarkConfig()
+	cache := NewParseCache(16, 0)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		cache.Get(data)
+	}
+}
@@ -0,0 +1,58 @@
+package betterjson
+
+import (
+	"context"
+	"fmt"
+)
+
+// cancelCheckInterval is how many nodes the ctx variants of long operations
+// visit between checks of their context
+const cancelCheckInterval = 128
+
+// CanceledError is a long operation abandoned because its context was done
+type CanceledError struct {
+	// Operation is "walk", "diff" or "merge"
+	Operation string
+	// Nodes is the number of nodes visited, Path the last of them
+	Nodes int
+	Path  []string
+	// Err is the error of the context
+	Err error
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("%s canceled after %d nodes at %s: %s", e.Operation, e.Nodes, displayPath(e.Path), e.Err.Error())
+}
+
+// Cause returns the error of the context, for errors.Cause
+func (e *CanceledError) Cause() error {
+	return e.Err
+}
+
+// Unwrap is Cause for errors.Is, so errors.Is(err, context.Canceled) works
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// progress counts the nodes visited by an operation and checks its context
+// every cancelCheckInterval of them, the first one included
+type progress struct {
+	ctx       context.Context
+	operation string
+	nodes     int
+}
+
+func newProgress(ctx context.Context, operation string) *progress {
+	return &progress{ctx: ctx, operation: operation}
+}
+
+func (p *progress) visit(branch []string) error {
+	p.nodes++
+	if (p.nodes-1)%cancelCheckInterval != 0 {
+		return nil
+	}
+	if err := p.ctx.Err(); err != nil {
+		return &CanceledError{Operation: p.operation, Nodes: p.nodes - 1, Path: append([]string{}, branch...), Err: err}
+	}
+	return nil
+}
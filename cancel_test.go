@@ -0,0 +1,100 @@
+package betterjson
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func largeDocument(n int) *Json {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": i, "name": "item " + strconv.Itoa(i)}
+	}
+	return wrapRaw(map[string]interface{}{"items": items})
+}
+
+func TestWalkCtxCancelsMidWalk(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	js := largeDocument(1000)
+	visited := 0
+	err := js.WalkCtx(ctx, func(path []string, value *Json) error {
+		visited++
+		if visited == 300 {
+			cancel()
+		}
+		return nil
+	})
+	println(err.Error())
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Cause(err) == context.Canceled)
+	var canceled *CanceledError
+	assert.True(t, errors.As(err, &canceled))
+	assert.Equal(t, "walk", canceled.Operation)
+	assert.Equal(t, visited, canceled.Nodes)
+	assert.True(t, visited >= 300 && visited < 300+cancelCheckInterval)
+	assert.True(t, len(canceled.Path) > 0)
+}
+
+func TestWalkVisitsEveryValue(t *testing.T) {
+	js, _ := Parse([]byte(`{"b":[1,{"c":2}],"a":{"skip":{"x":1}},"z":null}`))
+	paths := make([]string, 0)
+	err := js.Walk(func(path []string, value *Json) error {
+		paths = append(paths, displayPath(path))
+		if JoinDottedPath(path) == "a.skip" {
+			return SkipSubtree
+		}
+		if JoinDottedPath(path) == "b.0" {
+			value.SetValue(10)
+		}
+		return nil
+	})
+	assert.True(t, err == nil)
+	assert.Equal(t, []string{"<root>", "a", "a.skip", "b", "b.0", "b.1", "b.1.c", "z"}, paths)
+	assert.Equal(t, `{"a":{"skip":{"x":1}},"b":[10,{"c":2}],"z":null}`, js.EncodeToStringOrDefault(""))
+	assert.True(t, NewEmpty().Walk(func(path []string, value *Json) error { return errors.New("called") }) == nil)
+}
+
+func TestWalkBothCtxCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	err := WalkBothCtx(ctx, largeDocument(10), largeDocument(10), func(path []string, av, bv *Json) error {
+		called = true
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, !called)
+	assert.Equal(t, "walk canceled after 0 nodes at <root>: context canceled", err.Error())
+}
+
+func TestDiffReportCtxDeadline(t *testing.T) {
+	a, b := largeDocument(500), largeDocument(500)
+	b.GetPath("items").GetIndex(0).Set("name", "changed")
+	b.GetPath("items").GetIndex(499).Set("name", "changed")
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	differences, err := DiffReportCtx(ctx, a, b)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 0, len(differences))
+	differences, err = DiffReportCtx(context.Background(), a, b)
+	assert.True(t, err == nil)
+	assert.Equal(t, 2, len(differences))
+	assert.Equal(t, 2, len(DiffReport(a, b)))
+}
+
+func TestThreeWayMergeCtxCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	base := largeDocument(3)
+	ours := largeDocument(3)
+	ours.Set("extra", true)
+	merged, conflicts, err := ThreeWayMergeCtx(ctx, base, ours, base, MergeOptions{})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, merged.IsEmpty())
+	assert.Equal(t, 0, len(conflicts))
+}
@@ -0,0 +1,441 @@
+package betterjson
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// the CBOR (RFC 8949) codec is implemented here without third party packages.
+//
+// decoding maps unsigned and negative integers, and bignums (tags 2 and 3)
+// to json.Number holding the integer literal, floats of every width to
+// json.Number holding the shortest float literal (so 1.0 decodes as "1"),
+// simple values false/true/null to their json counterparts and undefined to
+// null. indefinite length items are supported. values json can't express are
+// coerced unless CBOROptions.Strict is set:
+//   - byte strings become base64 (standard encoding) strings
+//   - non-string map keys are stringified, 1 becomes "1"
+//   - other tags are dropped and their content is decoded in place
+//
+// encoding writes json.Number as an integer when it parses as one (bignum tags
+// beyond 64 bits), as a float64 otherwise. []byte leaves are written as byte
+// strings and map keys are written sorted, so the output is deterministic
+
+// CBOROptions controls how FromCBORWithOptions maps CBOR values
+type CBOROptions struct {
+	// Strict fails on byte strings, non-string map keys, undefined and tags
+	// other than bignums instead of coercing them
+	Strict bool
+}
+
+// EncodeCBOR encodes j as CBOR
+func (j *Json) EncodeCBOR() ([]byte, error) {
+	if j.IsEmpty() {
+		return []byte{}, errors.New("empty json can't be encoded")
+	}
+	return appendCBOR(make([]byte, 0, 64), j.value.Interface())
+}
+
+// FromCBOR decodes a CBOR data item, coercing values json can't express
+func FromCBOR(data []byte) (*Json, error) {
+	return FromCBORWithOptions(data, CBOROptions{})
+}
+
+// FromCBORWithOptions decodes a CBOR data item, see CBOROptions
+func FromCBORWithOptions(data []byte, opts CBOROptions) (*Json, error) {
+	d := &cborDecoder{data: data, opts: opts}
+	node, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if node == cborBreak {
+		return nil, errors.New("invalid cbor: unexpected break")
+	}
+	if d.pos != len(data) {
+		return nil, errors.Errorf("invalid cbor: %d trailing bytes", len(data)-d.pos)
+	}
+	value := simplejson.New()
+	value.SetPath([]string{}, node)
+	return FromNotEmptySimpleJson(value), nil
+}
+
+func appendCBORHead(dst []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(dst, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, major|27), n)
+}
+
+func appendCBORInt(dst []byte, i int64) []byte {
+	if i >= 0 {
+		return appendCBORHead(dst, 0, uint64(i))
+	}
+	return appendCBORHead(dst, 1, uint64(-1-i))
+}
+
+func appendCBORFloat64(dst []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(dst, 0xfb), math.Float64bits(f))
+}
+
+func appendCBORBigInt(dst []byte, i *big.Int) []byte {
+	if i.Sign() >= 0 {
+		dst = appendCBORHead(dst, 6, 2)
+		magnitude := i.Bytes()
+		return append(appendCBORHead(dst, 2, uint64(len(magnitude))), magnitude...)
+	}
+	dst = appendCBORHead(dst, 6, 3)
+	magnitude := new(big.Int).Sub(new(big.Int).Neg(i), big.NewInt(1)).Bytes()
+	return append(appendCBORHead(dst, 2, uint64(len(magnitude))), magnitude...)
+}
+
+func appendCBOR(dst []byte, node interface{}) ([]byte, error) {
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		return append(dst, 0xf6), nil
+	case bool:
+		if value {
+			return append(dst, 0xf5), nil
+		}
+		return append(dst, 0xf4), nil
+	case string:
+		return append(appendCBORHead(dst, 3, uint64(len(value))), value...), nil
+	case []byte:
+		return append(appendCBORHead(dst, 2, uint64(len(value))), value...), nil
+	case json.Number:
+		if isNegativeZero(value) {
+			return appendCBORFloat64(dst, math.Copysign(0, -1)), nil
+		}
+		if i, err := value.Int64(); err == nil {
+			return appendCBORInt(dst, i), nil
+		}
+		if u, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return appendCBORHead(dst, 0, u), nil
+		}
+		if i, ok := new(big.Int).SetString(string(value), 10); ok {
+			if i.Sign() < 0 && i.Cmp(minCBORNegative) >= 0 {
+				return appendCBORHead(dst, 1, new(big.Int).Sub(new(big.Int).Neg(i), big.NewInt(1)).Uint64()), nil
+			}
+			return appendCBORBigInt(dst, i), nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return nil, errors.Errorf("invalid number %q", string(value))
+		}
+		return appendCBORFloat64(dst, f), nil
+	case float64:
+		return appendCBORFloat64(dst, value), nil
+	case float32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xfa), math.Float32bits(value)), nil
+	case int, int8, int16, int32, int64:
+		return appendCBORInt(dst, reflect.ValueOf(value).Int()), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return appendCBORHead(dst, 0, reflect.ValueOf(value).Uint()), nil
+	case []interface{}:
+		dst = appendCBORHead(dst, 4, uint64(len(value)))
+		var err error
+		for _, item := range value {
+			if dst, err = appendCBOR(dst, item); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case map[string]interface{}:
+		dst = appendCBORHead(dst, 5, uint64(len(value)))
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var err error
+		for _, key := range keys {
+			dst = append(appendCBORHead(dst, 3, uint64(len(key))), key...)
+			if dst, err = appendCBOR(dst, value[key]); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	}
+	return nil, errors.Errorf("can't encode %T as cbor", node)
+}
+
+// minCBORNegative is the smallest integer major type 1 can hold, -2^64
+var minCBORNegative = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 64))
+
+type cborBreakMarker struct{}
+
+// cborBreak is returned by decode for the break stop code of indefinite items
+var cborBreak = cborBreakMarker{}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+	opts CBOROptions
+}
+
+func (d *cborDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.Errorf("invalid cbor: unexpected end of data at offset %d", d.pos)
+	}
+	chunk := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return chunk, nil
+}
+
+// readHead returns the major type, additional info and argument of the next item
+func (d *cborDecoder) readHead() (byte, byte, uint64, error) {
+	head, err := d.read(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info := head[0]>>5, head[0]&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		arg, err := d.read(1 << (info - 24))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		var n uint64
+		for _, b := range arg {
+			n = n<<8 | uint64(b)
+		}
+		return major, info, n, nil
+	case info == 31:
+		return major, info, 0, nil
+	}
+	return 0, 0, 0, errors.Errorf("invalid cbor: reserved additional info %d at offset %d", info, d.pos-1)
+}
+
+func (d *cborDecoder) decode() (interface{}, error) {
+	start := d.pos
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	indefinite := info == 31
+	switch major {
+	case 0:
+		if indefinite {
+			break
+		}
+		return json.Number(strconv.FormatUint(arg, 10)), nil
+	case 1:
+		if indefinite {
+			break
+		}
+		if arg <= math.MaxInt64 {
+			return json.Number(strconv.FormatInt(-1-int64(arg), 10)), nil
+		}
+		n := new(big.Int).SetUint64(arg)
+		return json.Number(n.Neg(n).Sub(n, big.NewInt(1)).String()), nil
+	case 2, 3:
+		chunk, err := d.decodeStringBytes(major, indefinite, arg)
+		if err != nil {
+			return nil, err
+		}
+		if major == 3 {
+			return string(chunk), nil
+		}
+		if d.opts.Strict {
+			return nil, errors.Errorf("cbor byte string at offset %d has no json counterpart", start)
+		}
+		return base64.StdEncoding.EncodeToString(chunk), nil
+	case 4:
+		return d.decodeArray(indefinite, arg)
+	case 5:
+		return d.decodeMap(indefinite, arg)
+	case 6:
+		if indefinite {
+			break
+		}
+		return d.decodeTag(arg, start)
+	case 7:
+		return d.decodeSimple(info, arg, start)
+	}
+	return nil, errors.Errorf("invalid cbor: indefinite length for major type %d at offset %d", major, start)
+}
+
+func (d *cborDecoder) decodeStringBytes(major byte, indefinite bool, length uint64) ([]byte, error) {
+	if !indefinite {
+		chunk, err := d.read(length)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, chunk...), nil
+	}
+	result := make([]byte, 0)
+	for {
+		if d.pos < len(d.data) && d.data[d.pos] == 0xff {
+			d.pos++
+			return result, nil
+		}
+		chunkMajor, info, chunkLength, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || info == 31 {
+			return nil, errors.Errorf("invalid cbor: bad chunk in indefinite string at offset %d", d.pos-1)
+		}
+		chunk, err := d.read(chunkLength)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, chunk...)
+	}
+}
+
+func (d *cborDecoder) decodeArray(indefinite bool, length uint64) (interface{}, error) {
+	if !indefinite && length > uint64(len(d.data)-d.pos) {
+		return nil, errors.Errorf("invalid cbor: array length %d exceeds data at offset %d", length, d.pos)
+	}
+	array := make([]interface{}, 0, int(length))
+	for i := uint64(0); indefinite || i < length; i++ {
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if item == cborBreak {
+			if !indefinite {
+				return nil, errors.New("invalid cbor: unexpected break")
+			}
+			break
+		}
+		array = append(array, item)
+	}
+	return array, nil
+}
+
+func (d *cborDecoder) decodeMap(indefinite bool, length uint64) (interface{}, error) {
+	if !indefinite && length > uint64(len(d.data)-d.pos) {
+		return nil, errors.Errorf("invalid cbor: map length %d exceeds data at offset %d", length, d.pos)
+	}
+	object := make(map[string]interface{}, int(length))
+	for i := uint64(0); indefinite || i < length; i++ {
+		keyStart := d.pos
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if key == cborBreak {
+			if !indefinite {
+				return nil, errors.New("invalid cbor: unexpected break")
+			}
+			break
+		}
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if item == cborBreak {
+			return nil, errors.New("invalid cbor: unexpected break")
+		}
+		keyString, ok := key.(string)
+		if !ok || d.data[keyStart]>>5 != 3 {
+			if d.opts.Strict {
+				return nil, errors.Errorf("cbor map key at offset %d is not a text string", keyStart)
+			}
+			keyString = fmt.Sprint(key)
+		}
+		object[keyString] = item
+	}
+	return object, nil
+}
+
+func (d *cborDecoder) decodeTag(tag uint64, start int) (interface{}, error) {
+	if tag == 2 || tag == 3 {
+		contentStart := d.pos
+		major, info, length, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if major != 2 {
+			return nil, errors.Errorf("invalid cbor: bignum content at offset %d is not a byte string", contentStart)
+		}
+		magnitude, err := d.decodeStringBytes(2, info == 31, length)
+		if err != nil {
+			return nil, err
+		}
+		n := new(big.Int).SetBytes(magnitude)
+		if tag == 3 {
+			n.Neg(n).Sub(n, big.NewInt(1))
+		}
+		return json.Number(n.String()), nil
+	}
+	if d.opts.Strict {
+		return nil, errors.Errorf("cbor tag %d at offset %d has no json counterpart", tag, start)
+	}
+	content, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if content == cborBreak {
+		return nil, errors.New("invalid cbor: unexpected break")
+	}
+	return content, nil
+}
+
+func (d *cborDecoder) decodeSimple(info byte, arg uint64, start int) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22:
+		return nil, nil
+	case 23:
+		if d.opts.Strict {
+			return nil, errors.Errorf("cbor undefined at offset %d has no json counterpart", start)
+		}
+		return nil, nil
+	case 25:
+		return floatNumber(float16ToFloat64(uint16(arg)), 64), nil
+	case 26:
+		return floatNumber(float64(math.Float32frombits(uint32(arg))), 32), nil
+	case 27:
+		return floatNumber(math.Float64frombits(arg), 64), nil
+	case 31:
+		return cborBreak, nil
+	}
+	return nil, errors.Errorf("unsupported cbor simple value %d at offset %d", arg, start)
+}
+
+func float16ToFloat64(bits uint16) float64 {
+	exponent := int(bits>>10) & 0x1f
+	mantissa := float64(bits & 0x3ff)
+	var value float64
+	switch exponent {
+	case 0:
+		value = math.Ldexp(mantissa, -24)
+	case 31:
+		if mantissa == 0 {
+			value = math.Inf(1)
+		} else {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mantissa+1024, exponent-25)
+	}
+	if bits&0x8000 != 0 {
+		return -value
+	}
+	return value
+}
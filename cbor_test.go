@@ -0,0 +1,122 @@
+package betterjson
+
+import (
+	"encoding/hex"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// examples from RFC 8949 appendix A that have a json counterpart
+var cborExamples = []struct {
+	hex  string
+	json string
+}{
+	{"00", "0"},
+	{"17", "23"},
+	{"1818", "24"},
+	{"1903e8", "1000"},
+	{"1a000f4240", "1000000"},
+	{"1b000000e8d4a51000", "1000000000000"},
+	{"1bffffffffffffffff", "18446744073709551615"},
+	{"c249010000000000000000", "18446744073709551616"},
+	{"3bffffffffffffffff", "-18446744073709551616"},
+	{"c349010000000000000000", "-18446744073709551617"},
+	{"20", "-1"},
+	{"3903e7", "-1000"},
+	{"f90000", "0"},
+	{"f98000", "-0"},
+	{"f93c00", "1"},
+	{"fb3ff199999999999a", "1.1"},
+	{"f93e00", "1.5"},
+	{"f97bff", "65504"},
+	{"fa47c35000", "100000"},
+	{"fa7f7fffff", "3.4028235e+38"},
+	{"fb7e37e43c8800759c", "1e+300"},
+	{"f90001", "5.960464477539063e-08"},
+	{"f9c400", "-4"},
+	{"fbc010666666666666", "-4.1"},
+	{"f4", "false"},
+	{"f5", "true"},
+	{"f6", "null"},
+	{"f7", "null"},
+	{"60", `""`},
+	{"6449455446", `"IETF"`},
+	{"62225c", `"\"\\"`},
+	{"62c3bc", `"ü"`},
+	{"63e6b0b4", `"水"`},
+	{"4401020304", `"AQIDBA=="`},
+	{"80", "[]"},
+	{"8301820203820405", "[1,[2,3],[4,5]]"},
+	{"98190102030405060708090a0b0c0d0e0f101112131415161718181819", "[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25]"},
+	{"a0", "{}"},
+	{"a201020304", `{"1":2,"3":4}`},
+	{"a26161016162820203", `{"a":1,"b":[2,3]}`},
+	{"826161a161626163", `["a",{"b":"c"}]`},
+	{"c074323031332d30332d32315432303a30343a30305a", `"2013-03-21T20:04:00Z"`},
+	{"c11a514b67b0", "1363896240"},
+	{"5f42010243030405ff", `"AQIDBAU="`},
+	{"7f657374726561646d696e67ff", `"streaming"`},
+	{"9fff", "[]"},
+	{"9f018202039f0405ffff", "[1,[2,3],[4,5]]"},
+	{"83018202039f0405ff", "[1,[2,3],[4,5]]"},
+	{"bf61610161629f0203ffff", `{"a":1,"b":[2,3]}`},
+	{"bf6346756ef563416d7421ff", `{"Amt":-2,"Fun":true}`},
+}
+
+func TestFromCBORExamples(t *testing.T) {
+	for _, example := range cborExamples {
+		data, err := hex.DecodeString(example.hex)
+		assert.True(t, err == nil)
+		a, err := FromCBOR(data)
+		assert.True(t, err == nil, example.hex, err)
+		if err != nil {
+			continue
+		}
+		aStr, err := a.EncodeToString()
+		assert.True(t, err == nil)
+		assert.True(t, aStr == example.json, example.hex, aStr)
+	}
+}
+
+func TestCBORRoundTripExamples(t *testing.T) {
+	for _, example := range cborExamples {
+		a, err := Parse([]byte(example.json))
+		assert.True(t, err == nil)
+		encoded, err := a.EncodeCBOR()
+		assert.True(t, err == nil)
+		b, err := FromCBOR(encoded)
+		assert.True(t, err == nil, example.json, err)
+		assert.True(t, b.IsSameJSONWith(a), example.json)
+	}
+}
+
+func TestCBORRoundTripBuiltDocument(t *testing.T) {
+	a := Obj("device", "sensor-1", "online", true, "battery", 0.5, "readings", Arr(Obj("t", -40, "ok", nil), 1<<40))
+	encoded, err := a.EncodeCBOR()
+	assert.True(t, err == nil)
+	b, err := FromCBOR(encoded)
+	assert.True(t, err == nil)
+	assert.True(t, b.IsSameJSONWith(a))
+	bStr, err := b.EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, bStr == `{"battery":0.5,"device":"sensor-1","online":true,"readings":[{"ok":null,"t":-40},1099511627776]}`)
+	_, err = NewEmpty().EncodeCBOR()
+	assert.True(t, err != nil)
+}
+
+func TestFromCBORStrict(t *testing.T) {
+	for _, rejected := range []string{"4401020304", "a201020304", "c074323031332d30332d32315432303a30343a30305a", "f7"} {
+		data, _ := hex.DecodeString(rejected)
+		_, err := FromCBORWithOptions(data, CBOROptions{Strict: true})
+		assert.True(t, err != nil, rejected)
+	}
+	data, _ := hex.DecodeString("c249010000000000000000")
+	a, err := FromCBORWithOptions(data, CBOROptions{Strict: true})
+	assert.True(t, err == nil)
+	assert.True(t, a.EncodeToStringOrDefault("") == "18446744073709551616")
+	for _, invalid := range []string{"ff", "8301", "0001", "1c", "5f01ff"} {
+		data, _ := hex.DecodeString(invalid)
+		_, err := FromCBOR(data)
+		assert.True(t, err != nil, invalid)
+	}
+}
@@ -0,0 +1,79 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrEmptyJson is returned by the checked mutators SetE, DelE, SetPathE and
+// TryAddE when the receiver is empty, where Set, Del, SetPath and TryAdd do
+// nothing or start a new document
+var ErrEmptyJson = errors.New("json is empty")
+
+// SetE is Set reporting when nothing was stored: ErrEmptyJson for an empty j,
+// and an error when j isn't an object
+func (j *Json) SetE(key string, val interface{}) error {
+	if j.IsEmpty() {
+		return ErrEmptyJson
+	}
+	if _, isObject := j.value.Interface().(map[string]interface{}); !isObject {
+		return errors.Errorf("can't set %s of %s", key, kindName(j.value.Interface()))
+	}
+	j.Set(key, val)
+	return nil
+}
+
+// DelE is Del reporting when nothing was removed: ErrEmptyJson for an empty j,
+// and an error when j isn't an object or has no key
+func (j *Json) DelE(key string) error {
+	if j.IsEmpty() {
+		return ErrEmptyJson
+	}
+	if _, isObject := j.value.Interface().(map[string]interface{}); !isObject {
+		return errors.Errorf("can't delete %s of %s", key, kindName(j.value.Interface()))
+	}
+	if _, exists := j.value.CheckGet(key); !exists {
+		return errors.Errorf("key %s doesn't exist", key)
+	}
+	j.Del(key)
+	return nil
+}
+
+// SetPathE is SetPath reporting ErrEmptyJson for an empty j instead of
+// starting a new document, and an error instead of replacing a value on the
+// way to branch that isn't an object
+func (j *Json) SetPathE(branch []string, val interface{}) error {
+	if j.IsEmpty() {
+		return ErrEmptyJson
+	}
+	if len(branch) > 1 {
+		j.materializeKey(branch[0])
+	}
+	node := j.value.Interface()
+	for idx, segment := range branch {
+		object, isObject := unwrapRaw(node).(map[string]interface{})
+		if !isObject {
+			return errors.Errorf("can't set %s, %s is %s", JoinDottedPath(branch),
+				displayPath(branch[:idx]), kindName(node))
+		}
+		next, exists := object[segment]
+		if !exists {
+			break
+		}
+		node = next
+	}
+	j.SetPath(branch, val)
+	return nil
+}
+
+// TryAddE is TryAdd reporting ErrEmptyJson for an empty j and an error when
+// j isn't an array
+func (j *Json) TryAddE(val interface{}) error {
+	if j.IsEmpty() {
+		return ErrEmptyJson
+	}
+	if _, isArray := j.value.Interface().([]interface{}); !isArray {
+		return errors.Errorf("can't add to %s", kindName(j.value.Interface()))
+	}
+	j.TryAdd(val)
+	return nil
+}
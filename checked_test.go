@@ -0,0 +1,55 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_SetE(t *testing.T) {
+	js := NewJSONObject()
+	assert.True(t, js.SetE("a", 1) == nil)
+	assert.Equal(t, int64(1), js.Get("a").MustInt64())
+	assert.True(t, NewEmpty().SetE("a", 1) == ErrEmptyJson)
+	err := NewJSONArray().SetE("a", 1)
+	assert.True(t, err != nil && err != ErrEmptyJson)
+	println(err.Error())
+}
+
+func TestJson_DelE(t *testing.T) {
+	js, err := Parse([]byte(`{"a":1,"b":null}`))
+	assert.True(t, err == nil)
+	assert.True(t, js.DelE("b") == nil)
+	assert.True(t, !js.ContainsKey("b"))
+	err = js.DelE("b")
+	assert.True(t, err != nil && err != ErrEmptyJson)
+	println(err.Error())
+	assert.True(t, NewEmpty().DelE("a") == ErrEmptyJson)
+	assert.True(t, NewString("x").DelE("a") != nil)
+	assert.Equal(t, `{"a":1}`, js.EncodeToStringOrDefault(""))
+}
+
+func TestJson_SetPathE(t *testing.T) {
+	js, err := Parse([]byte(`{"a":{"b":1},"s":"x"}`))
+	assert.True(t, err == nil)
+	assert.True(t, js.SetPathE([]string{"a", "c", "d"}, 2) == nil)
+	assert.True(t, js.SetPathE([]string{"a", "b"}, 3) == nil)
+	assert.Equal(t, `{"a":{"b":3,"c":{"d":2}},"s":"x"}`, js.EncodeToStringOrDefault(""))
+	err = js.SetPathE([]string{"s", "t"}, 1)
+	assert.True(t, err != nil && err != ErrEmptyJson)
+	println(err.Error())
+	assert.Equal(t, "x", js.Get("s").MustString())
+	assert.True(t, js.SetPathE([]string{"a", "b", "c"}, 1) != nil)
+	empty := NewEmpty()
+	assert.True(t, empty.SetPathE([]string{"a"}, 1) == ErrEmptyJson)
+	assert.True(t, empty.IsEmpty())
+}
+
+func TestJson_TryAddE(t *testing.T) {
+	js := NewJSONArray()
+	assert.True(t, js.TryAddE("a") == nil)
+	assert.Equal(t, `["a"]`, js.EncodeToStringOrDefault(""))
+	assert.True(t, NewEmpty().TryAddE("a") == ErrEmptyJson)
+	err := NewJSONObject().TryAddE("a")
+	assert.True(t, err != nil && err != ErrEmptyJson)
+	println(err.Error())
+}
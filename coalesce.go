@@ -0,0 +1,50 @@
+package betterjson
+
+// Coalesce returns the first value found at one of the paths that is neither
+// empty nor null, or an empty Json when none of them resolves
+//
+// useful when the same datum lives in different places across payload versions:
+//    js.Coalesce([]string{"user", "name"}, []string{"userName"})
+func (j *Json) Coalesce(paths ...[]string) *Json {
+	result, _ := j.CoalesceWithPath(paths...)
+	return result
+}
+
+// CoalesceWithPath is Coalesce also returning the dotted form of the path that
+// won, or "" when none of them resolved. callers can use it to log deprecation
+// warnings when an old field location is still in use
+func (j *Json) CoalesceWithPath(paths ...[]string) (*Json, string) {
+	for _, branch := range paths {
+		item, ok := j.lookupPath(branch)
+		if ok && !item.IsEmptyOrNull() {
+			return item, JoinDottedPath(branch)
+		}
+	}
+	return NewEmpty(), ""
+}
+
+// CoalesceDotted is Coalesce with paths in dotted form:
+//
+//   js.CoalesceDotted("user.name", "userName")
+func (j *Json) CoalesceDotted(paths ...string) *Json {
+	return j.Coalesce(parseDottedPaths(paths)...)
+}
+
+// CoalesceOr is Coalesce falling back to the literal defaultVal when none of
+// the paths resolves. defaultVal can be a *Json or plain value; the default
+// is returned as a copy, so changing it leaves defaultVal alone
+func (j *Json) CoalesceOr(defaultVal interface{}, paths ...[]string) *Json {
+	result := j.Coalesce(paths...)
+	if !result.IsEmpty() {
+		return result
+	}
+	return wrapRaw(deepCopyRaw(defaultVal))
+}
+
+func parseDottedPaths(paths []string) [][]string {
+	branches := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		branches = append(branches, ParseDottedPath(path))
+	}
+	return branches
+}
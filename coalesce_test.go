@@ -0,0 +1,47 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_Coalesce(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("userName", "old").Set("user", NewJSONObject().Set("name", nil)).Set("profile", NewJSONObject().Set("name", "new"))
+	b := a.Coalesce([]string{"user", "name"}, []string{"profile", "name"}, []string{"userName"})
+	assert.True(t, b.MustString() == "new")
+	c, path := a.CoalesceWithPath([]string{"missing"}, []string{"userName"})
+	assert.True(t, c.MustString() == "old")
+	assert.True(t, path == "userName")
+	d, path := a.CoalesceWithPath([]string{"missing"}, []string{"user", "name"})
+	assert.True(t, d.IsEmpty())
+	assert.True(t, path == "")
+	assert.True(t, NewEmpty().Coalesce([]string{"a"}).IsEmpty())
+}
+
+func TestJson_CoalesceDotted(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("items", NewJSONArray().TryAdd(NewJSONObject().Set("id", 7)))
+	b := a.CoalesceDotted("item.id", "items.0.id")
+	assert.True(t, b.MustInt() == 7)
+}
+
+func TestJson_CoalesceOr(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("name", "zoowii")
+	assert.True(t, a.CoalesceOr("unknown", []string{"nick"}).MustString() == "unknown")
+	assert.True(t, a.CoalesceOr("unknown", []string{"nick"}, []string{"name"}).MustString() == "zoowii")
+	bStr, err := a.CoalesceOr(NewJSONObject(), []string{"nick"}).EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, bStr == "{}")
+
+	// the default is copied, not shared with the caller
+	defaults := map[string]interface{}{"tags": []interface{}{"a"}}
+	c := a.CoalesceOr(defaults, []string{"nick"})
+	c.Set("extra", true)
+	c.Get("tags").TryAdd("b")
+	assert.Equal(t, map[string]interface{}{"tags": []interface{}{"a"}}, defaults)
+	defaultJson := Obj("id", 1)
+	a.CoalesceOr(defaultJson, []string{"nick"}).Set("id", 2)
+	assert.True(t, defaultJson.Get("id").MustInt() == 1)
+}
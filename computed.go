@@ -0,0 +1,43 @@
+package betterjson
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// WithComputed returns a copy of j with the result of every Search
+// expression of fields stored at its key, a dotted path created like SetPath
+// creates it. the expressions are evaluated on j itself, so they don't see
+// each other's results, and the ones on missing data store null:
+//    indexed, err := order.WithComputed(map[string]string{
+//        "itemCount": "length(items)",
+//        "meta.firstTag": "items[0].tags[0]",
+//    })
+// an expression that doesn't parse is an error naming its key, and j is
+// returned unchanged
+func (j *Json) WithComputed(fields map[string]string) (*Json, error) {
+	if j.IsEmpty() {
+		return j, errors.New("empty json can't have computed fields")
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	expressions := make([]searchNode, len(keys))
+	for idx, key := range keys {
+		node, err := parseSearch(fields[key])
+		if err != nil {
+			return j, errors.Wrapf(err, "computed field %q", key)
+		}
+		expressions[idx] = node
+	}
+	root := j.value.Interface()
+	result := wrapRaw(deepCopyRaw(root))
+	result.settings = j.settings
+	for idx, key := range keys {
+		result.SetPath(ParseDottedPath(key), deepCopyRaw(expressions[idx].eval(root)))
+	}
+	return result, nil
+}
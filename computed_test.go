@@ -0,0 +1,56 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_WithComputed(t *testing.T) {
+	order, _ := Parse([]byte(`{"id":7,"items":[{"sku":"a","tags":["new","sale"]},{"sku":"b","tags":[]}],"customer":{"name":"Ann","address":{"city":"Oslo"}}}`))
+	indexed, err := order.WithComputed(map[string]string{
+		"itemCount":     "length(items)",
+		"firstTag":      "items[0].tags[0]",
+		"skus":          "join(',', items[*].sku)",
+		"search.city":   "customer.address.city",
+		"search.fields": "keys(customer)",
+		"onSale":        "contains(items[].tags[], 'sale')",
+		"missing":       "customer.phone",
+		"nameLength":    "length(customer.name)",
+	})
+	assert.True(t, err == nil)
+	println(indexed.EncodeToStringOrDefault(""))
+	assert.Equal(t, 2, indexed.Get("itemCount").MustInt())
+	assert.Equal(t, "new", indexed.Get("firstTag").MustString())
+	assert.Equal(t, "a,b", indexed.Get("skus").MustString())
+	assert.Equal(t, "Oslo", indexed.GetDottedPath("search.city").MustString())
+	assert.Equal(t, `["address","name"]`, indexed.GetDottedPath("search.fields").EncodeToStringOrDefault(""))
+	assert.Equal(t, true, indexed.Get("onSale").MustBool(false))
+	assert.True(t, indexed.ContainsKey("missing") && indexed.Get("missing").Interface() == nil)
+	assert.Equal(t, 3, indexed.Get("nameLength").MustInt())
+	// the receiver is untouched and the copy shares nothing with it
+	assert.False(t, order.ContainsKey("itemCount"))
+	indexed.GetDottedPath("items.0").Set("sku", "z")
+	assert.Equal(t, "a", order.GetDottedPath("items.0.sku").MustString())
+}
+
+func TestJson_WithComputedSeesOnlyTheOriginal(t *testing.T) {
+	js, _ := Parse([]byte(`{"a":1}`))
+	result, err := js.WithComputed(map[string]string{"b": "a", "c": "b", "a": "`2`"})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"a":2,"b":1,"c":null}`, result.EncodeToStringOrDefault(""))
+}
+
+func TestJson_WithComputedErrors(t *testing.T) {
+	js, _ := Parse([]byte(`{"items":[1]}`))
+	_, err := js.WithComputed(map[string]string{"ok": "items", "count": "length(items"})
+	println(err.Error())
+	assert.Equal(t, `computed field "count": unexpected end of expression at offset 12 of "length(items"`, err.Error())
+	_, err = js.WithComputed(map[string]string{"n": "size(items)"})
+	assert.Equal(t, `computed field "n": unknown function size() at offset 0 of "size(items)"`, err.Error())
+	_, err = js.WithComputed(map[string]string{"n": "length(items, @)"})
+	assert.Equal(t, `computed field "n": length() takes 1 arguments, got 2 at offset 0 of "length(items, @)"`, err.Error())
+	result, _ := js.WithComputed(map[string]string{"n": "length(`1`)", "v": "values(items)"})
+	assert.Equal(t, `{"items":[1],"n":null,"v":null}`, result.EncodeToStringOrDefault(""))
+	_, err = NewEmpty().WithComputed(map[string]string{})
+	assert.True(t, err != nil)
+}
@@ -0,0 +1,73 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// ParseConcatenated parses the documents of a stream written back to back,
+// like {"a":1}{"b":2} or one per line, separated by any white space. on a
+// malformed document it returns the documents before it with the error
+func ParseConcatenated(r io.Reader) ([]*Json, error) {
+	documents := make([]*Json, 0)
+	err := IterateConcatenated(r, func(i int, j *Json) error {
+		documents = append(documents, j)
+		return nil
+	})
+	return documents, err
+}
+
+// IterateConcatenated is ParseConcatenated calling fn with each document and
+// its index as soon as it is read, instead of collecting them. an error of
+// fn stops the iteration and is returned. a malformed document is a
+// SyntaxError, with the offset in the stream, wrapped with the index of the
+// document:
+//    err := betterjson.IterateConcatenated(conn, func(i int, event *betterjson.Json) error {
+//        return handle(event)
+//    })
+func IterateConcatenated(r io.Reader, fn func(i int, j *Json) error) error {
+	counter := &countingReader{r: r}
+	dec := json.NewDecoder(counter)
+	dec.UseNumber()
+	for i := 0; ; i++ {
+		var data interface{}
+		if err := dec.Decode(&data); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrapf(concatenatedError(err, counter), "document %d", i)
+		}
+		value := simplejson.New()
+		value.SetPath([]string{}, data)
+		if err := fn(i, FromNotEmptySimpleJson(value)); err != nil {
+			return err
+		}
+	}
+}
+
+// concatenatedError makes the errors of the decoder SyntaxErrors with the
+// offset in the stream, counter's count of bytes being where the input ended
+func concatenatedError(err error, counter *countingReader) error {
+	switch cause := err.(type) {
+	case *json.SyntaxError:
+		return &SyntaxError{Offset: cause.Offset - 1, Err: errors.New(cause.Error())}
+	}
+	if err == io.ErrUnexpectedEOF {
+		return &SyntaxError{Offset: counter.n, Err: errors.New("unexpected end of JSON input")}
+	}
+	return err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
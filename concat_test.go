@@ -0,0 +1,55 @@
+package betterjson
+
+import (
+	"strings"
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConcatenated(t *testing.T) {
+	stream := "{\"a\":1}{\"b\":[2]}\n[1,2]  \"text\"\t\r\n12 true null 3.50\n{}"
+	documents, err := ParseConcatenated(strings.NewReader(stream))
+	assert.True(t, err == nil)
+	encoded := make([]string, len(documents))
+	for idx, document := range documents {
+		encoded[idx] = document.EncodeToStringOrDefault("")
+	}
+	assert.Equal(t, []string{`{"a":1}`, `{"b":[2]}`, `[1,2]`, `"text"`, `12`, `true`, `null`, `3.50`, `{}`}, encoded)
+
+	documents, err = ParseConcatenated(strings.NewReader("  \n"))
+	assert.True(t, err == nil)
+	assert.Equal(t, 0, len(documents))
+}
+
+func TestParseConcatenatedMalformed(t *testing.T) {
+	documents, err := ParseConcatenated(strings.NewReader(`{"a":1} [2] {"b":}` + ` {"c":3}`))
+	println(err.Error())
+	assert.Equal(t, 2, len(documents))
+	assert.Equal(t, int64(1), documents[0].Get("a").MustInt64())
+	documents[1].TryAdd(3)
+	assert.Equal(t, `[2,3]`, documents[1].EncodeToStringOrDefault(""))
+	var syntaxError *SyntaxError
+	assert.True(t, errors.As(err, &syntaxError))
+	assert.Equal(t, int64(17), syntaxError.Offset)
+	assert.Equal(t, "document 2: invalid character '}' looking for beginning of value at offset 17", err.Error())
+
+	documents, err = ParseConcatenated(strings.NewReader(`1 {"open":`))
+	assert.Equal(t, 1, len(documents))
+	assert.Equal(t, "document 1: unexpected end of JSON input at offset 10", err.Error())
+}
+
+func TestIterateConcatenated(t *testing.T) {
+	stop := errors.New("stop")
+	seen := make([]int, 0)
+	err := IterateConcatenated(strings.NewReader(`{"i":0}{"i":1}{"i":2}{"i"`), func(i int, j *Json) error {
+		assert.Equal(t, int64(i), j.Get("i").MustInt64())
+		seen = append(seen, i)
+		if i == 1 {
+			return stop
+		}
+		return nil
+	})
+	assert.True(t, err == stop)
+	assert.Equal(t, []int{0, 1}, seen)
+}
@@ -0,0 +1,71 @@
+package betterjson
+
+import (
+	"sort"
+	"strconv"
+)
+
+// SetIf is Set done only when cond returns true for the current value of key,
+// an empty Json when key is missing:
+//    js.SetIf("retries", 3, func(current *Json) bool { return current.IsEmptyOrNull() })
+func (j *Json) SetIf(key string, val interface{}, cond func(current *Json) bool) *Json {
+	if j.IsEmpty() || !cond(j.CheckGet(key)) {
+		return j
+	}
+	return j.Set(key, val)
+}
+
+// DeleteWhere removes every key of the object j for which cond returns true
+// and returns how many were removed. keys are visited in sorted order
+func (j *Json) DeleteWhere(cond func(key string, value *Json) bool) int {
+	object, ok := j.objectValue()
+	if !ok {
+		return 0
+	}
+	removed := 0
+	for _, key := range sortedKeys(object) {
+		if cond(key, newChild(j, key, object[key])) {
+			j.Del(key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// DeleteWhereDeep is DeleteWhere applied to j and to every object nested in
+// it, inside arrays too. the values of removed keys aren't descended into
+func (j *Json) DeleteWhereDeep(cond func(key string, value *Json) bool) int {
+	if j.IsEmpty() {
+		return 0
+	}
+	removed := 0
+	switch container := j.value.Interface().(type) {
+	case map[string]interface{}:
+		removed = j.DeleteWhere(cond)
+		for _, key := range sortedKeys(container) {
+			removed += newChild(j, key, container[key]).DeleteWhereDeep(cond)
+		}
+	case []interface{}:
+		for idx, item := range container {
+			removed += newChild(j, strconv.Itoa(idx), item).DeleteWhereDeep(cond)
+		}
+	}
+	return removed
+}
+
+func (j *Json) objectValue() (map[string]interface{}, bool) {
+	if j.IsEmpty() {
+		return nil, false
+	}
+	object, ok := j.value.Interface().(map[string]interface{})
+	return object, ok
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,46 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_SetIf(t *testing.T) {
+	js, _ := Parse([]byte(`{"retries":null,"timeout":30,"owner":{"name":"ops"}}`))
+	missing := func(current *Json) bool { return current.IsEmptyOrNull() }
+	js.SetIf("retries", 3, missing).SetIf("timeout", 60, missing).SetIf("region", "eu", missing)
+	js.SetIf("owner", "nobody", func(current *Json) bool {
+		return current.Get("name").MustString() == "dev"
+	})
+	assert.True(t, js.DigestJSONForEqual() == `{"owner":{"name":"ops"},"region":"eu","retries":3,"timeout":30}`)
+}
+
+func TestJson_DeleteWhere(t *testing.T) {
+	js, _ := Parse([]byte(`{"a":{"flag":"beta","x":1},"b":{"flag":"ga"},"c":1,"d":{"flag":"beta"}}`))
+	removed := js.DeleteWhere(func(key string, value *Json) bool {
+		return value.Get("flag").MustString("") == "beta"
+	})
+	assert.True(t, removed == 2)
+	assert.True(t, js.DigestJSONForEqual() == `{"b":{"flag":"ga"},"c":1}`)
+	assert.True(t, NewJSONArray().DeleteWhere(func(key string, value *Json) bool { return true }) == 0)
+}
+
+func TestJson_DeleteWhereDeep(t *testing.T) {
+	js, _ := Parse([]byte(`{"stale":true,"items":[{"id":1,"meta":{"stale":true,"keep":1}},{"id":2,"stale":true}],"nested":{"deeper":{"stale":false}}}`))
+	js.StartRecording()
+	removed := js.DeleteWhereDeep(func(key string, value *Json) bool {
+		return key == "stale" && value.MustBool(false)
+	})
+	patch, _ := js.StopRecording()
+	assert.True(t, removed == 3)
+	assert.True(t, js.DigestJSONForEqual() == `{"items":[{"id":1,"meta":{"keep":1}},{"id":2}],"nested":{"deeper":{"stale":false}}}`)
+	println(patch.DigestJSONForEqual())
+	assert.True(t, len(patch.MustArray()) == 3)
+
+	prune, _ := Parse([]byte(`{"a":{"drop":{"drop":1}},"b":[{"drop":{"x":1}}]}`))
+	removed = prune.DeleteWhereDeep(func(key string, value *Json) bool {
+		return key == "drop" && value.Get("x").MustInt(0) == 0
+	})
+	assert.True(t, removed == 1)
+	assert.True(t, prune.DigestJSONForEqual() == `{"a":{},"b":[{"drop":{"x":1}}]}`)
+}
@@ -0,0 +1,141 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// ObjectAtPath returns the object at branch, creating empty objects for it and
+// for every missing intermediate. missing or null nodes are created, an
+// existing node of another kind is an error.
+//
+// the returned Json aliases the node inside j, so later Set calls on it are
+// visible when encoding j:
+//    obj, _ := js.ObjectAtPath("a", "b", "c")
+//    obj.Set("key", "value")
+func (j *Json) ObjectAtPath(branch ...string) (*Json, error) {
+	return j.containerAtPath(branch, "object")
+}
+
+// ArrayAtPath is ObjectAtPath creating an empty array at the end of branch,
+// so the result can be filled with TryAdd
+func (j *Json) ArrayAtPath(branch ...string) (*Json, error) {
+	return j.containerAtPath(branch, "array")
+}
+
+func (j *Json) containerAtPath(branch []string, kind string) (*Json, error) {
+	if j.IsEmpty() {
+		return NewEmpty(), errors.New("empty json can't create " + kind + " at path")
+	}
+	current := j
+	for idx, segment := range branch {
+		last := idx == len(branch)-1
+		var created interface{}
+		if last {
+			created = newContainer(kind)
+		} else {
+			created = newContainer("object")
+		}
+		var item interface{}
+		switch container := current.value.Interface().(type) {
+		case map[string]interface{}:
+			item = unwrapRaw(container[segment])
+			if item == nil {
+				item = created
+				prior := current.prior([]string{segment})
+				container[segment] = item
+				current.changed(setOperation([]string{segment}, item, prior), prior)
+			}
+			container[segment] = item
+		case []interface{}:
+			itemIdx, err := strconv.Atoi(segment)
+			if err != nil || itemIdx < 0 || itemIdx >= len(container) {
+				return NewEmpty(), errors.Errorf("index %s out of range of array at %s", segment, displayPath(branch[:idx]))
+			}
+			item = unwrapRaw(container[itemIdx])
+			if item == nil {
+				item = created
+				prior := current.prior([]string{segment})
+				container[itemIdx] = item
+				current.changed(setOperation([]string{segment}, item, prior), prior)
+			}
+			container[itemIdx] = item
+		default:
+			return NewEmpty(), errors.Errorf("%s at %s is not an object", kindName(container), displayPath(branch[:idx]))
+		}
+		current = newChild(current, segment, item)
+	}
+	if actual := kindName(current.value.Interface()); actual != kind {
+		return NewEmpty(), errors.Errorf("%s at %s is not an %s", actual, displayPath(branch), kind)
+	}
+	return current, nil
+}
+
+func newContainer(kind string) interface{} {
+	if kind == "array" {
+		return make([]interface{}, 0)
+	}
+	return make(map[string]interface{})
+}
+
+// newChild wraps item found under key of parent, linking it so replacing the
+// child's value also replaces it in parent
+func newChild(parent *Json, key string, item interface{}) *Json {
+	value := simplejson.New()
+	value.SetPath([]string{}, unwrapRaw(item))
+	return FromNotEmptySimpleJson(value).linkTo(parent, key)
+}
+
+// linkTo marks j as the child under key of parent and returns it
+func (j *Json) linkTo(parent *Json, key string) *Json {
+	j.parent = parent
+	j.parentKey = key
+	j.settings = parent.settings
+	if parent.trace != nil {
+		j.trace = parent.trace
+		j.trace.record(j)
+	}
+	return j
+}
+
+// writeBack stores j's current value into the container it was derived from
+func (j *Json) writeBack() {
+	if j.parent == nil || j.parent.IsEmpty() {
+		return
+	}
+	var data interface{}
+	if !j.IsEmpty() {
+		data = j.value.Interface()
+	}
+	switch container := j.parent.value.Interface().(type) {
+	case map[string]interface{}:
+		container[j.parentKey] = data
+	case []interface{}:
+		idx, err := strconv.Atoi(j.parentKey)
+		if err == nil && idx >= 0 && idx < len(container) {
+			container[idx] = data
+		}
+	}
+}
+
+// kindName returns the JSON type name of a raw node
+func kindName(node interface{}) string {
+	switch unwrapRaw(node).(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case json.Number, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "number"
+	}
+	return "unknown"
+}
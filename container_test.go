@@ -0,0 +1,86 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_ObjectAtPath(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("hello", "world")
+	b, err := a.ObjectAtPath("a", "b", "c")
+	assert.True(t, err == nil)
+	b.Set("name", "zoowii").Set("age", 18)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	println(aStr)
+	assert.True(t, aStr == "{\"a\":{\"b\":{\"c\":{\"age\":18,\"name\":\"zoowii\"}}},\"hello\":\"world\"}")
+	c, err := a.ObjectAtPath("a", "b")
+	assert.True(t, err == nil)
+	assert.True(t, c.ContainsKey("c"))
+	root, err := a.ObjectAtPath()
+	assert.True(t, err == nil)
+	assert.True(t, root.ContainsKey("hello"))
+}
+
+func TestJson_ObjectAtPathConflict(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("hello", "world").Set("items", NewJSONArray().TryAdd(1))
+	_, err := a.ObjectAtPath("hello", "x")
+	assert.True(t, err != nil)
+	println(err.Error())
+	assert.True(t, err.Error() == "string at hello is not an object")
+	_, err = a.ObjectAtPath("items")
+	assert.True(t, err != nil)
+	assert.True(t, err.Error() == "array at items is not an object")
+	_, err = a.ObjectAtPath("items", "3")
+	assert.True(t, err != nil)
+	_, err = NewEmpty().ObjectAtPath("a")
+	assert.True(t, err != nil)
+	aStr, _ := a.EncodeToString()
+	assert.True(t, aStr == "{\"hello\":\"world\",\"items\":[1]}")
+}
+
+func TestJson_ArrayAtPath(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("meta", nil)
+	items, err := a.ArrayAtPath("meta", "items")
+	assert.True(t, err == nil)
+	items.TryAdd(1).TryAdd("two")
+	again, err := a.ArrayAtPath("meta", "items")
+	assert.True(t, err == nil)
+	again.TryAdd(NewJSONObject().Set("three", 3))
+	element, err := a.ObjectAtPath("meta", "items", "2")
+	assert.True(t, err == nil)
+	element.Set("four", 4)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	println(aStr)
+	assert.True(t, aStr == "{\"meta\":{\"items\":[1,\"two\",{\"four\":4,\"three\":3}]}}")
+	_, err = a.ArrayAtPath("meta")
+	assert.True(t, err != nil)
+}
+
+func TestJson_ContainerAtPathRecorded(t *testing.T) {
+	original := `{"cfg":null,"items":[null]}`
+	doc, _ := Parse([]byte(original))
+	history, err := NewHistory(doc)
+	assert.True(t, err == nil)
+	events := 0
+	doc.OnChange(func(event ChangeEvent) { events++ })
+	list, err := doc.ArrayAtPath("cfg", "list")
+	assert.True(t, err == nil)
+	list.TryAdd(1)
+	object, err := doc.ObjectAtPath("items", "0", "meta")
+	assert.True(t, err == nil)
+	object.Set("k", "v")
+	revision := history.Commit("containers")
+	assert.Equal(t, 6, events)
+
+	replayed, err := history.ReplayTo(revision)
+	assert.True(t, err == nil)
+	assert.True(t, replayed.IsSameJSONWith(doc))
+	copied, _ := Parse([]byte(original))
+	assert.True(t, copied.ApplyPatch(revision.Patch) == nil)
+	assert.Equal(t, `{"cfg":{"list":[1]},"items":[{"meta":{"k":"v"}}]}`, copied.EncodeToStringOrDefault(""))
+}
@@ -0,0 +1,86 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// scalarString stringifies a scalar leaf: strings as they are, numbers and
+// bools as their json text and null as "". containers are an error
+func scalarString(node interface{}) (string, error) {
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case json.Number:
+		return string(value), nil
+	case map[string]interface{}, []interface{}:
+		return "", errors.Errorf("%s can't be converted to string", kindName(value))
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	}
+}
+
+// ToStringMap converts a flat object of scalars to map[string]string, for
+// consumers such as http headers or environment variables. numbers and bools
+// are stringified as their json text and null becomes "". object and array
+// values are an error naming their key
+func (j *Json) ToStringMap() (map[string]string, error) {
+	object, err := j.Map()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(object))
+	for key, item := range object {
+		s, err := scalarString(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "value of key %q", key)
+		}
+		result[key] = s
+	}
+	return result, nil
+}
+
+// Invert returns a new object swapping the keys and values of a flat object
+// of scalars, values stringified like ToStringMap does:
+//    {"a":1,"b":"x"} => {"1":"a","x":"b"}
+//
+// values that stringify to the same key are an error listing every colliding pair
+func (j *Json) Invert() (*Json, error) {
+	stringMap, err := j.ToStringMap()
+	if err != nil {
+		return NewEmpty(), err
+	}
+	keys := make([]string, 0, len(stringMap))
+	for key := range stringMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make(map[string]interface{}, len(stringMap))
+	collisions := make([]string, 0)
+	for _, key := range keys {
+		inverted := stringMap[key]
+		if previous, exists := result[inverted]; exists {
+			collisions = append(collisions, "keys "+previous.(string)+" and "+key+" both invert to "+jsonQuote(inverted))
+			continue
+		}
+		result[inverted] = key
+	}
+	if len(collisions) > 0 {
+		return NewEmpty(), errors.New("can't invert object: " + strings.Join(collisions, ", "))
+	}
+	return wrapRaw(result), nil
+}
+
+func jsonQuote(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
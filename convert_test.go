@@ -0,0 +1,35 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_ToStringMap(t *testing.T) {
+	a, err := Parse([]byte(`{"region":"eu","replicas":3,"ratio":0.25,"debug":false,"owner":null}`))
+	assert.True(t, err == nil)
+	a.Set("port", 8080).Set("scale", 1.5)
+	m, err := a.ToStringMap()
+	assert.True(t, err == nil)
+	assert.Equal(t, map[string]string{"region": "eu", "replicas": "3", "ratio": "0.25", "debug": "false", "owner": "", "port": "8080", "scale": "1.5"}, m)
+	_, err = a.Set("nested", NewJSONObject()).ToStringMap()
+	assert.True(t, err != nil)
+	println(err.Error())
+	_, err = NewJSONObject().Set("list", NewJSONArray()).ToStringMap()
+	assert.True(t, err != nil)
+	_, err = NewJSONArray().ToStringMap()
+	assert.True(t, err != nil)
+}
+
+func TestJson_Invert(t *testing.T) {
+	a := NewJSONObject().Set("a", 1).Set("b", "x").Set("c", true)
+	b, err := a.Invert()
+	assert.True(t, err == nil)
+	assert.True(t, b.EncodeToStringOrDefault("") == `{"1":"a","true":"c","x":"b"}`)
+	_, err = NewJSONObject().Set("a", 1).Set("b", "1").Set("c", "x").Set("d", "x").Invert()
+	assert.True(t, err != nil)
+	println(err.Error())
+	assert.True(t, err.Error() == `can't invert object: keys a and b both invert to "1", keys c and d both invert to "x"`)
+	_, err = NewJSONObject().Set("a", NewJSONArray()).Invert()
+	assert.True(t, err != nil)
+}
@@ -0,0 +1,83 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// CopyPath sets the value at srcBranch of src at dstBranch of dst, creating the
+// missing objects on the way. both branches may go through arrays by index. a
+// missing source path, or a destination path through a value that isn't an
+// object or an array or through an index out of range, is a *TraversalError
+// like GetPathStrict's, leaving dst unchanged.
+//
+// with deep the value is copied. without it dst shares the node with src:
+// changes inside a shared object show in both documents, a shared array shares
+// its items but not items added or removed later, and scalars are always
+// copies. copied to the root of dst an object refills dst's own object, which
+// then shares only the members:
+//    err := betterjson.CopyPath(order, []string{"shipping"}, customer, []string{"addresses", "0"}, true)
+func CopyPath(dst *Json, dstBranch []string, src *Json, srcBranch []string, deep bool) error {
+	if dst == nil || src == nil {
+		return errors.New("can't copy between nil documents")
+	}
+	if src.IsEmpty() {
+		return errors.New("empty json has nothing to copy")
+	}
+	value, err := src.GetPathStrict(srcBranch...)
+	if err != nil {
+		return err
+	}
+	node := value.value.Interface()
+	if deep {
+		node = deepCopyRaw(node)
+	}
+	if dst.IsEmpty() || len(dstBranch) == 0 {
+		dst.SetPath(dstBranch, unwrapRaw(node))
+		return nil
+	}
+	parent, rest, err := copyTarget(dst, dstBranch)
+	if err != nil {
+		return err
+	}
+	if _, isArray := parent.arrayValue(); isArray {
+		index, _ := arrayIndexSegment(rest[0])
+		parent.SetIndex(index, unwrapRaw(node))
+		return nil
+	}
+	parent.SetPath(rest, unwrapRaw(node))
+	return nil
+}
+
+// copyTarget follows the existing part of branch, returning the deepest
+// container on it and the rest of branch, to be created or set in it. the
+// last segment in an array must be an index in range
+func copyTarget(dst *Json, branch []string) (*Json, []string, error) {
+	current := dst
+	for idx, segment := range branch {
+		last := idx == len(branch)-1
+		if _, isArray := current.arrayValue(); isArray {
+			index, ok := arrayIndexSegment(segment)
+			if !ok {
+				return nil, nil, current.traversalError(segment, TraversalNotObject)
+			}
+			item, err := current.GetIndexStrict(index)
+			if err != nil {
+				return nil, nil, err
+			}
+			if last {
+				return current, branch[idx:], nil
+			}
+			current = item
+			continue
+		}
+		object, isObject := current.objectValue()
+		if !isObject {
+			return nil, nil, current.traversalError(segment, TraversalNotObject)
+		}
+		if _, exists := object[current.documentKey(segment)]; !exists || last {
+			return current, branch[idx:], nil
+		}
+		current = current.Get(segment)
+	}
+	return current, []string{}, nil
+}
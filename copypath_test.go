@@ -0,0 +1,99 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func copyDocuments() (*Json, *Json) {
+	src, _ := Parse([]byte(`{"customer":{"addresses":[{"city":"Oslo","lines":["a"]}],"name":"Ann"}}`))
+	return NewJSONObject().Set("id", 1), src
+}
+
+func TestCopyPathDeep(t *testing.T) {
+	dst, src := copyDocuments()
+	err := CopyPath(dst, []string{"shipping", "address"}, src, []string{"customer", "addresses", "0"}, true)
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"id":1,"shipping":{"address":{"city":"Oslo","lines":["a"]}}}`, dst.EncodeToStringOrDefault(""))
+
+	dst.GetPath("shipping", "address").Set("city", "Bergen")
+	src.GetPath("customer", "addresses").GetIndex(0).Set("zip", "0150")
+	assert.Equal(t, "Oslo", src.GetPath("customer", "addresses").GetIndex(0).Get("city").MustString())
+	assert.True(t, !dst.GetPath("shipping", "address").ContainsKey("zip"))
+
+	assert.True(t, CopyPath(dst, []string{"name"}, src, []string{"customer", "name"}, true) == nil)
+	assert.Equal(t, "Ann", dst.Get("name").MustString())
+}
+
+func TestCopyPathShared(t *testing.T) {
+	dst, src := copyDocuments()
+	err := CopyPath(dst, []string{"address"}, src, []string{"customer", "addresses", "0"}, false)
+	assert.True(t, err == nil)
+	// the object is shared both ways
+	dst.Get("address").Set("city", "Bergen")
+	assert.Equal(t, "Bergen", src.GetPath("customer", "addresses").GetIndex(0).Get("city").MustString())
+	src.GetPath("customer", "addresses").GetIndex(0).Set("zip", "5003")
+	assert.Equal(t, "5003", dst.GetPath("address", "zip").MustString())
+
+	// a shared array shares its items, not its length
+	assert.True(t, CopyPath(dst, []string{"addresses"}, src, []string{"customer", "addresses"}, false) == nil)
+	dst.Get("addresses").TryAdd("new")
+	assert.Equal(t, 2, len(dst.Get("addresses").MustArray()))
+	assert.Equal(t, 1, len(src.GetPath("customer", "addresses").MustArray()))
+	dst.Get("addresses").GetIndex(0).Set("city", "Stavanger")
+	assert.Equal(t, "Stavanger", src.GetPath("customer", "addresses").GetIndex(0).Get("city").MustString())
+}
+
+func TestCopyPathMissingSource(t *testing.T) {
+	dst, src := copyDocuments()
+	before := dst.EncodeToStringOrDefault("")
+	err := CopyPath(dst, []string{"x"}, src, []string{"customer", "addresses", "3"}, true)
+	var traversal *TraversalError
+	assert.True(t, errors.As(err, &traversal))
+	assert.Equal(t, TraversalOutOfRange, traversal.Problem)
+	err = CopyPath(dst, []string{"x"}, src, []string{"customer", "phone"}, false)
+	assert.Equal(t, `key "phone" is missing in object at customer`, err.Error())
+	assert.Equal(t, before, dst.EncodeToStringOrDefault(""))
+	assert.True(t, CopyPath(dst, []string{"x"}, NewEmpty(), []string{}, true) != nil)
+
+	// copying into an empty document creates it
+	empty := NewEmpty()
+	assert.True(t, CopyPath(empty, []string{"a", "b"}, src, []string{"customer", "name"}, true) == nil)
+	assert.Equal(t, `{"a":{"b":"Ann"}}`, empty.EncodeToStringOrDefault(""))
+}
+
+func TestCopyPathThroughArrays(t *testing.T) {
+	_, src := copyDocuments()
+	dst, _ := Parse([]byte(`{"items":[{"n":1}],"count":2}`))
+	assert.True(t, CopyPath(dst, []string{"items", "0", "name"}, src, []string{"customer", "name"}, true) == nil)
+	assert.True(t, CopyPath(dst, []string{"items", "0", "n"}, src, []string{"customer", "addresses", "0", "city"}, true) == nil)
+	assert.Equal(t, `{"count":2,"items":[{"n":"Oslo","name":"Ann"}]}`, dst.EncodeToStringOrDefault(""))
+
+	list, _ := Parse([]byte(`[{"k":1},2]`))
+	assert.True(t, CopyPath(list, []string{"1"}, src, []string{"customer", "name"}, true) == nil)
+	assert.Equal(t, `[{"k":1},"Ann"]`, list.EncodeToStringOrDefault(""))
+	var traversal *TraversalError
+	err := CopyPath(list, []string{"k"}, src, []string{"customer", "name"}, true)
+	assert.True(t, errors.As(err, &traversal))
+	assert.Equal(t, TraversalNotObject, traversal.Problem)
+	err = CopyPath(list, []string{"5", "k"}, src, []string{"customer", "name"}, true)
+	assert.True(t, errors.As(err, &traversal))
+	assert.Equal(t, TraversalOutOfRange, traversal.Problem)
+	assert.Equal(t, `[{"k":1},"Ann"]`, list.EncodeToStringOrDefault(""))
+}
+
+func TestCopyPathThroughScalar(t *testing.T) {
+	_, src := copyDocuments()
+	dst, _ := Parse([]byte(`{"items":[{"n":1}],"count":2}`))
+	before := dst.EncodeToStringOrDefault("")
+	err := CopyPath(dst, []string{"count", "name"}, src, []string{"customer", "name"}, true)
+	var traversal *TraversalError
+	assert.True(t, errors.As(err, &traversal))
+	println(err.Error())
+	assert.Equal(t, []string{"count"}, traversal.Path)
+	assert.Equal(t, TraversalNotObject, traversal.Problem)
+	err = CopyPath(dst, []string{"items", "0", "n", "x"}, src, []string{"customer", "name"}, true)
+	assert.True(t, errors.As(err, &traversal))
+	assert.Equal(t, before, dst.EncodeToStringOrDefault(""))
+}
@@ -0,0 +1,194 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxDecimalExponent bounds the exponent ParseDecimal accepts, so a literal
+// like 1e999999999 can't make it allocate gigabytes of digits
+const maxDecimalExponent = 10000
+
+// Decimal is an exact decimal number for monetary values, read from and
+// written as number literals without going through float64. the zero value is 0
+type Decimal struct {
+	unscaled *big.Int
+	// scale is the number of digits after the decimal point
+	scale int
+}
+
+// ParseDecimal parses a number literal in json syntax like "12.30" or "1e-3"
+func ParseDecimal(s string) (Decimal, error) {
+	if !isValidNumber(s) {
+		return Decimal{}, errors.Errorf("%q is not a number", s)
+	}
+	mantissa, exponent := s, 0
+	if idx := strings.IndexAny(s, "eE"); idx >= 0 {
+		mantissa = s[:idx]
+		var err error
+		if exponent, err = strconv.Atoi(strings.TrimPrefix(s[idx+1:], "+")); err != nil || exponent > maxDecimalExponent || exponent < -maxDecimalExponent {
+			return Decimal{}, errors.Errorf("exponent of %q out of range", s)
+		}
+	}
+	scale := 0
+	if idx := strings.IndexByte(mantissa, '.'); idx >= 0 {
+		scale = len(mantissa) - idx - 1
+		mantissa = mantissa[:idx] + mantissa[idx+1:]
+	}
+	unscaled, _ := new(big.Int).SetString(mantissa, 10)
+	d := Decimal{unscaled: unscaled, scale: scale - exponent}
+	if d.scale < 0 {
+		d.unscaled.Mul(d.unscaled, pow10(-d.scale))
+		d.scale = 0
+	}
+	return d, nil
+}
+
+// DecimalFromInt returns the Decimal of i
+func DecimalFromInt(i int64) Decimal {
+	return Decimal{unscaled: big.NewInt(i)}
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescaled returns the unscaled value of d at a scale at least d.scale
+func (d Decimal) rescaled(scale int) *big.Int {
+	if scale == d.scale {
+		return d.value()
+	}
+	return new(big.Int).Mul(d.value(), pow10(scale-d.scale))
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := d.scale
+	if other.scale > scale {
+		scale = other.scale
+	}
+	return Decimal{unscaled: new(big.Int).Add(d.rescaled(scale), other.rescaled(scale)), scale: scale}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	return d.Add(Decimal{unscaled: new(big.Int).Neg(other.value()), scale: other.scale})
+}
+
+// Cmp compares d and other like big.Int.Cmp, regardless of their scales:
+// 30 and 30.00 are equal
+func (d Decimal) Cmp(other Decimal) int {
+	scale := d.scale
+	if other.scale > scale {
+		scale = other.scale
+	}
+	return d.rescaled(scale).Cmp(other.rescaled(scale))
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.value(), pow10(d.scale)).Float64()
+	return f
+}
+
+// String formats d without exponent, keeping its digits after the decimal
+// point: the sum of 0.10 and 0.20 is "0.30"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.value()).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Decimal returns the number at path below j exactly, from its literal:
+//    amount, err := order.Decimal("total", "amount")
+//
+// parsed documents keep number literals, so no float64 is involved.
+// values that aren't numbers are an error
+func (j *Json) Decimal(path ...string) (Decimal, error) {
+	node, ok := j.leafAt(path)
+	if !ok {
+		return Decimal{}, errors.Errorf("no number at %s", displayPath(path))
+	}
+	return decimalOf(node)
+}
+
+func decimalOf(node interface{}) (Decimal, error) {
+	switch value := node.(type) {
+	case json.Number:
+		return ParseDecimal(string(value))
+	case float64:
+		return ParseDecimal(strconv.FormatFloat(value, 'g', -1, 64))
+	case float32:
+		return ParseDecimal(strconv.FormatFloat(float64(value), 'g', -1, 32))
+	case string, bool, map[string]interface{}, []interface{}, nil:
+		return Decimal{}, errors.Errorf("%s is not a number", kindName(node))
+	}
+	if number, ok := rawNumber(node); ok {
+		return ParseDecimal(number.Num().String())
+	}
+	return Decimal{}, errors.Errorf("%s is not a number", kindName(node))
+}
+
+// MustDecimal guarantees the return of a `Decimal` (with optional default)
+func (j *Json) MustDecimal(args ...Decimal) Decimal {
+	var def Decimal
+	if len(args) > 0 {
+		def = args[0]
+	}
+	if j.IsEmpty() {
+		j.mustOnEmpty("MustDecimal")
+		return def
+	}
+	d, err := j.Decimal()
+	if err != nil {
+		j.recordError(errors.Wrap(err, "MustDecimal failed"))
+		return def
+	}
+	return d
+}
+
+// SetDecimal is Set storing d as a number literal, so Encode writes its exact digits
+func (j *Json) SetDecimal(key string, d Decimal) *Json {
+	return j.Set(key, json.Number(d.String()))
+}
+
+// SumDecimal is SumFloat64 adding exactly, with Decimal
+func (j *Json) SumDecimal(path ...string) (Decimal, error) {
+	sum := DecimalFromInt(0)
+	err := j.pluckEach(path, func(idx int, value *Json) error {
+		d, err := decimalOf(unwrapRaw(value.value.Interface()))
+		if err != nil {
+			return err
+		}
+		sum = sum.Add(d)
+		return nil
+	}, func(length int) {})
+	if err != nil {
+		return Decimal{}, err
+	}
+	return sum, nil
+}
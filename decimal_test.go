@@ -0,0 +1,70 @@
+package betterjson
+
+import (
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for literal, expected := range map[string]string{
+		"0": "0", "12.30": "12.30", "-0.5": "-0.5", "-0": "0", "1e3": "1000", "1.5E-2": "0.015",
+		"2.50e1": "25.0", "123456789012345678901234567890.000000000000000000001": "123456789012345678901234567890.000000000000000000001",
+	} {
+		d, err := ParseDecimal(literal)
+		assert.True(t, err == nil, literal)
+		assert.True(t, d.String() == expected, literal, d.String())
+	}
+	for _, literal := range []string{"", "abc", "1.", ".5", "0x10", "1e99999999"} {
+		_, err := ParseDecimal(literal)
+		assert.True(t, err != nil, literal)
+	}
+	var zero Decimal
+	assert.True(t, zero.String() == "0" && zero.Sign() == 0)
+	a, _ := ParseDecimal("30")
+	b, _ := ParseDecimal("30.00")
+	assert.True(t, a.Cmp(b) == 0 && a.Sub(b).Sign() == 0)
+	assert.True(t, b.Float64() == 30)
+}
+
+func TestJson_SumDecimal(t *testing.T) {
+	items := make([]string, 300)
+	for idx := range items {
+		items[idx] = `{"amount":0.1}`
+	}
+	orders, _ := Parse([]byte("[" + strings.Join(items, ",") + "]"))
+	sum, err := orders.SumDecimal("amount")
+	assert.True(t, err == nil)
+	thirty, _ := ParseDecimal("30")
+	assert.True(t, sum.Cmp(thirty) == 0)
+	assert.True(t, sum.String() == "30.0")
+	floatSum, _ := orders.SumFloat64("amount")
+	assert.True(t, floatSum != 30)
+
+	mixed, _ := Parse([]byte(`[1, 2.25, "3"]`))
+	_, err = mixed.SumDecimal()
+	assert.True(t, err != nil && err.Error() == "item 2: string is not a number")
+	sum, err = NewJSONArray().TryAdd(1).TryAdd(0.1).SumDecimal()
+	assert.True(t, err == nil && sum.String() == "1.1")
+}
+
+func TestJson_Decimal(t *testing.T) {
+	order, _ := Parse([]byte(`{"total":{"amount":19.990},"count":3,"note":"x"}`))
+	amount, err := order.Decimal("total", "amount")
+	assert.True(t, err == nil && amount.String() == "19.990")
+	_, err = order.Decimal("note")
+	assert.True(t, err != nil)
+	_, err = order.Decimal("missing")
+	assert.True(t, err != nil)
+
+	order.SetDecimal("doubled", amount.Add(amount))
+	encoded, _ := order.Get("doubled").Encode()
+	assert.True(t, string(encoded) == "39.980")
+	assert.True(t, order.Get("count").MustDecimal().String() == "3")
+
+	fallback := DecimalFromInt(7)
+	lenient := NewJSONObjectWithOptions(Options{PanicOnMust: false}).Set("note", "x")
+	assert.True(t, lenient.Get("note").MustDecimal(fallback).Cmp(fallback) == 0)
+	assert.True(t, lenient.LastError() != nil)
+	assert.True(t, lenient.CheckGet("missing").MustDecimal(fallback).Cmp(fallback) == 0)
+}
@@ -0,0 +1,168 @@
+package betterjson
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DiffKind is how a Difference changed a value
+type DiffKind string
+
+const (
+	// DiffAdded is a value only the second document has
+	DiffAdded DiffKind = "added"
+	// DiffRemoved is a value only the first document has
+	DiffRemoved DiffKind = "removed"
+	// DiffChanged is a value both documents have, different in a leaf or
+	// in its kind
+	DiffChanged DiffKind = "changed"
+)
+
+// Difference is one entry of a DiffReport
+type Difference struct {
+	Kind DiffKind
+	Path []string
+	// Old and New are copies of the value at Path in the first and the
+	// second document, empty on the side that has none
+	Old *Json
+	New *Json
+}
+
+// String formats d as one line like "changed a.b: 1 => 2"
+func (d Difference) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("added %s: %s", displayPath(d.Path), d.New.EncodeToStringOrDefault("?"))
+	case DiffRemoved:
+		return fmt.Sprintf("removed %s: %s", displayPath(d.Path), d.Old.EncodeToStringOrDefault("?"))
+	}
+	return fmt.Sprintf("changed %s: %s => %s", displayPath(d.Path), d.Old.EncodeToStringOrDefault("?"), d.New.EncodeToStringOrDefault("?"))
+}
+
+// DiffReport lists how b differs from a. objects are compared key by key in
+// sorted order and arrays index by index, leaves are compared like
+// IsSameJSONWith except that numbers are compared by value, so 1 and 1.0 are
+// the same. an empty Json has no value at all:
+//    for _, difference := range betterjson.DiffReport(before, after) {
+//        log.Println(difference)
+//    }
+func DiffReport(a, b *Json) []Difference {
+	differences, _ := DiffReportCtx(context.Background(), a, b)
+	return differences
+}
+
+// DiffReportCtx is DiffReport giving up with a CanceledError once ctx is
+// done, returning the differences found until then
+func DiffReportCtx(ctx context.Context, a, b *Json) ([]Difference, error) {
+	if sink := metrics; sink != nil {
+		start := time.Now()
+		differences, err := diffReportCtx(ctx, a, b)
+		observe(sink, "diff", start, len(differences), err)
+		return differences, err
+	}
+	return diffReportCtx(ctx, a, b)
+}
+
+func diffReportCtx(ctx context.Context, a, b *Json) ([]Difference, error) {
+	differences := make([]Difference, 0)
+	aRoot, aOk := documentRoot(a)
+	bRoot, bOk := documentRoot(b)
+	switch {
+	case aOk && bOk:
+		err := diffRaw([]string{}, aRoot, bRoot, &differences, newProgress(ctx, "diff"), nil)
+		return differences, err
+	case aOk:
+		differences = append(differences, newDifference(DiffRemoved, []string{}, aRoot, nil))
+	case bOk:
+		differences = append(differences, newDifference(DiffAdded, []string{}, nil, bRoot))
+	}
+	return differences, nil
+}
+
+func documentRoot(j *Json) (interface{}, bool) {
+	if j == nil || j.IsEmpty() {
+		return nil, false
+	}
+	return j.value.Interface(), true
+}
+
+func newDifference(kind DiffKind, branch []string, old interface{}, new interface{}) Difference {
+	difference := Difference{Kind: kind, Path: append([]string{}, branch...), Old: NewEmpty(), New: NewEmpty()}
+	if kind != DiffAdded {
+		difference.Old = wrapRaw(deepCopyRaw(old))
+	}
+	if kind != DiffRemoved {
+		difference.New = wrapRaw(deepCopyRaw(new))
+	}
+	return difference
+}
+
+// diffRaw adds the differences of a and b to differences, skipping the values
+// at the paths ignored matches
+func diffRaw(branch []string, a interface{}, b interface{}, differences *[]Difference, p *progress, ignored ignoredPaths) error {
+	if err := p.visit(branch); err != nil {
+		return err
+	}
+	a, b = unwrapRaw(a), unwrapRaw(b)
+	switch left := a.(type) {
+	case map[string]interface{}:
+		if right, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(left)+len(right))
+			for key := range left {
+				keys = append(keys, key)
+			}
+			for key := range right {
+				if _, ok := left[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				leftItem, inLeft := left[key]
+				rightItem, inRight := right[key]
+				child := append(branch[:len(branch):len(branch)], key)
+				if ignored.match(child) {
+					continue
+				}
+				switch {
+				case !inRight:
+					*differences = append(*differences, newDifference(DiffRemoved, child, leftItem, nil))
+				case !inLeft:
+					*differences = append(*differences, newDifference(DiffAdded, child, nil, rightItem))
+				default:
+					if err := diffRaw(child, leftItem, rightItem, differences, p, ignored); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+	case []interface{}:
+		if right, ok := b.([]interface{}); ok {
+			for idx := 0; idx < len(left) || idx < len(right); idx++ {
+				child := append(branch[:len(branch):len(branch)], strconv.Itoa(idx))
+				if ignored.match(child) {
+					continue
+				}
+				switch {
+				case idx >= len(right):
+					*differences = append(*differences, newDifference(DiffRemoved, child, left[idx], nil))
+				case idx >= len(left):
+					*differences = append(*differences, newDifference(DiffAdded, child, nil, right[idx]))
+				default:
+					if err := diffRaw(child, left[idx], right[idx], differences, p, ignored); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+	}
+	if !rawEqual(a, b) {
+		*differences = append(*differences, newDifference(DiffChanged, branch, a, b))
+	}
+	return nil
+}
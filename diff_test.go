@@ -0,0 +1,40 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func diffLines(differences []Difference) []string {
+	lines := make([]string, 0, len(differences))
+	for _, difference := range differences {
+		lines = append(lines, difference.String())
+	}
+	return lines
+}
+
+func TestDiffReport(t *testing.T) {
+	a, _ := Parse([]byte(`{"name":"a","port":1,"tags":["x","y"],"db":{"host":"h","user":"u"},"kind":[1]}`))
+	b, _ := Parse([]byte(`{"name":"b","port":1.0,"tags":["x"],"db":{"host":"h","pool":{"size":2}},"kind":{"0":1}}`))
+	differences := DiffReport(a, b)
+	for _, line := range diffLines(differences) {
+		println(line)
+	}
+	assert.Equal(t, []string{
+		`added db.pool: {"size":2}`,
+		`removed db.user: "u"`,
+		`changed kind: [1] => {"0":1}`,
+		`changed name: "a" => "b"`,
+		`removed tags.1: "y"`,
+	}, diffLines(differences))
+	assert.Equal(t, []string{"db", "pool"}, differences[0].Path)
+	assert.True(t, differences[0].Old.IsEmpty())
+	// the values are copies
+	differences[0].New.Set("size", 3)
+	assert.Equal(t, 2, b.GetDottedPath("db.pool.size").MustInt())
+
+	assert.Equal(t, 0, len(DiffReport(a, a)))
+	assert.Equal(t, 0, len(DiffReport(NewEmpty(), nil)))
+	assert.Equal(t, []string{`added <root>: {"name":"b"}`}, diffLines(DiffReport(NewEmpty(), Obj("name", "b"))))
+	assert.Equal(t, []string{`removed <root>: 1`}, diffLines(DiffReport(Arr(1).GetIndex(0), NewEmpty())))
+}
@@ -0,0 +1,137 @@
+package betterjson
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+)
+
+// DiffPrettyOptions configures DiffPretty
+type DiffPrettyOptions struct {
+	// Context adds the unchanged siblings of the changed values as lines
+	// starting with two spaces
+	Context bool
+	// Color colors removed lines red, added lines green and context lines gray
+	// with ANSI escapes
+	Color bool
+}
+
+const (
+	colorRemoved = "\x1b[31m"
+	colorAdded   = "\x1b[32m"
+	colorContext = "\x1b[90m"
+)
+
+// diffLine is one line of DiffPretty
+type diffLine struct {
+	prefix string
+	path   []string
+	value  *Json
+}
+
+// DiffPretty renders DiffReport(a, b) like a unified diff, one line per value
+// ordered by path: a changed value is a "- path: old" line followed by a
+// "+ path: new" line, a removed value only has the first and an added value
+// only the second. values are encoded compactly and the same documents always
+// render the same text, "" when they don't differ:
+//    - server.port: 80
+//    + server.port: 8080
+//    + server.tls: true
+func DiffPretty(a, b *Json, opts DiffPrettyOptions) string {
+	differences := DiffReport(a, b)
+	lines := make([]diffLine, 0, len(differences)*2)
+	for _, difference := range differences {
+		if difference.Kind != DiffAdded {
+			lines = append(lines, diffLine{prefix: "- ", path: difference.Path, value: difference.Old})
+		}
+		if difference.Kind != DiffRemoved {
+			lines = append(lines, diffLine{prefix: "+ ", path: difference.Path, value: difference.New})
+		}
+	}
+	if opts.Context {
+		lines = append(lines, contextLines(a, b, differences)...)
+	}
+	// the lines of a Difference stay in order, context lines have other paths
+	sort.SliceStable(lines, func(x, y int) bool {
+		return comparePaths(lines[x].path, lines[y].path) < 0
+	})
+	var buffer bytes.Buffer
+	for _, line := range lines {
+		color := ""
+		if opts.Color {
+			color = map[string]string{"- ": colorRemoved, "+ ": colorAdded, "  ": colorContext}[line.prefix]
+			buffer.WriteString(color)
+		}
+		buffer.WriteString(line.prefix)
+		buffer.WriteString(displayPath(line.path))
+		buffer.WriteString(": ")
+		buffer.WriteString(line.value.EncodeToStringOrDefault("?"))
+		if opts.Color {
+			buffer.WriteString(colorReset)
+		}
+		buffer.WriteByte('\n')
+	}
+	return buffer.String()
+}
+
+// contextLines returns a line for every unchanged item of the containers
+// holding a changed value
+func contextLines(a, b *Json, differences []Difference) []diffLine {
+	lines := make([]diffLine, 0)
+	aRoot, aOk := documentRoot(a)
+	bRoot, bOk := documentRoot(b)
+	if !aOk || !bOk {
+		return lines
+	}
+	changed := make(map[string]bool, len(differences))
+	for _, difference := range differences {
+		for depth := 1; depth <= len(difference.Path); depth++ {
+			changed[JoinDottedPath(difference.Path[:depth])] = true
+		}
+	}
+	parents := make(map[string]bool)
+	for _, difference := range differences {
+		if len(difference.Path) == 0 {
+			continue
+		}
+		parent := difference.Path[:len(difference.Path)-1]
+		if parents[JoinDottedPath(parent)] {
+			continue
+		}
+		parents[JoinDottedPath(parent)] = true
+		node, _ := valueAtBranch(aRoot, parent)
+		for _, segment := range childSegments(unwrapRaw(node), nil) {
+			child := append(parent[:len(parent):len(parent)], segment)
+			if changed[JoinDottedPath(child)] {
+				continue
+			}
+			if item, ok := valueAtBranch(bRoot, child); ok {
+				lines = append(lines, diffLine{prefix: "  ", path: child, value: wrapRaw(deepCopyRaw(item))})
+			}
+		}
+	}
+	return lines
+}
+
+// comparePaths orders paths segment by segment like DiffReport, comparing
+// array indexes as numbers
+func comparePaths(x, y []string) int {
+	for idx := 0; idx < len(x) && idx < len(y); idx++ {
+		if x[idx] == y[idx] {
+			continue
+		}
+		left, leftErr := strconv.Atoi(x[idx])
+		right, rightErr := strconv.Atoi(y[idx])
+		if leftErr == nil && rightErr == nil {
+			if left < right {
+				return -1
+			}
+			return 1
+		}
+		if x[idx] < y[idx] {
+			return -1
+		}
+		return 1
+	}
+	return len(x) - len(y)
+}
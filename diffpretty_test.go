@@ -0,0 +1,50 @@
+package betterjson
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	diffPrettyBefore = `{"name":"svc","server":{"host":"localhost","port":80,"tls":false},"hosts":["a","b","c","d","e","f","g","h","i","j","k"],"owner":{"team":"core"},"legacy":true}`
+	diffPrettyAfter  = `{"name":"svc","server":{"host":"localhost","port":8080,"tls":true},"hosts":["a","b","c","d","e","f","g","h","i","j","z"],"owner":{"team":"core"},"region":"eu"}`
+)
+
+func TestDiffPretty(t *testing.T) {
+	before, _ := Parse([]byte(diffPrettyBefore))
+	after, _ := Parse([]byte(diffPrettyAfter))
+	sections := []string{
+		"# plain", DiffPretty(before, after, DiffPrettyOptions{}),
+		"# context", DiffPretty(before, after, DiffPrettyOptions{Context: true}),
+	}
+	rendered := strings.Join(sections, "\n")
+	println(rendered)
+	golden, err := ioutil.ReadFile("testdata/diff_pretty.golden")
+	assert.True(t, err == nil)
+	assert.Equal(t, string(golden), rendered)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, sections[3], DiffPretty(before, after, DiffPrettyOptions{Context: true}))
+	}
+	assert.Equal(t, "", DiffPretty(before, before, DiffPrettyOptions{Context: true}))
+	// the lines agree with DiffReport
+	lines := 0
+	for _, difference := range DiffReport(before, after) {
+		lines++
+		if difference.Kind == DiffChanged {
+			lines++
+		}
+	}
+	assert.Equal(t, lines, strings.Count(sections[1], "\n"))
+}
+
+func TestDiffPrettyColor(t *testing.T) {
+	before, _ := Parse([]byte(`{"a":1,"b":2}`))
+	after, _ := Parse([]byte(`{"a":1,"b":3}`))
+	colored := DiffPretty(before, after, DiffPrettyOptions{Color: true, Context: true})
+	assert.Equal(t, "\x1b[90m  a: 1\x1b[0m\n\x1b[31m- b: 2\x1b[0m\n\x1b[32m+ b: 3\x1b[0m\n", colored)
+	assert.Equal(t, "- <root>: 1\n+ <root>: \"x\"\n", DiffPretty(NewInt(1), NewString("x"), DiffPrettyOptions{Context: true}))
+	assert.Equal(t, "+ <root>: {}\n", DiffPretty(NewEmpty(), NewJSONObject(), DiffPrettyOptions{}))
+}
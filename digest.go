@@ -0,0 +1,88 @@
+package betterjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// WriteDigest streams the canonical form returned by DigestJSONForEqual to w,
+// for example into a hash.Hash to fingerprint large documents without
+// holding the digest in memory:
+//    hash := sha256.New()
+//    if err := js.WriteDigest(hash); err != nil {
+//        ...
+//    }
+//    fingerprint := hash.Sum(nil)
+// a value that can't be encoded, like a NaN float, is an error after the
+// digest before it was written
+func (j *Json) WriteDigest(w io.Writer) error {
+	if j.IsEmpty() {
+		_, err := io.WriteString(w, "nil")
+		return err
+	}
+	buffered := bufio.NewWriter(w)
+	encoder := &streamEncoder{w: buffered}
+	if err := encoder.encode(j.value.Interface(), 0); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// digestEqual reports whether raw nodes a and b have the same digest, walking
+// both in lockstep and stopping at the first difference
+func digestEqual(a interface{}, b interface{}) bool {
+	a, b = unwrapRaw(a), unwrapRaw(b)
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok && a != nil && b != nil {
+			if len(a) != len(b) {
+				return false
+			}
+			for key, item := range a {
+				other, ok := b[key]
+				if !ok || !digestEqual(item, other) {
+					return false
+				}
+			}
+			return true
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok && a != nil && b != nil {
+			if len(a) != len(b) {
+				return false
+			}
+			for idx, item := range a {
+				if !digestEqual(item, b[idx]) {
+					return false
+				}
+			}
+			return true
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return a == b
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			return a == b
+		}
+	case json.Number:
+		if b, ok := b.(json.Number); ok && isValidNumber(string(a)) && isValidNumber(string(b)) {
+			return a == b
+		}
+	}
+	digestA, errA := leafDigest(a)
+	digestB, errB := leafDigest(b)
+	return errA == nil && errB == nil && bytes.Equal(digestA, digestB)
+}
+
+func leafDigest(node interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := &streamEncoder{w: &buffer}
+	if err := encoder.encode(node, 0); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
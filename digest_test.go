@@ -0,0 +1,148 @@
+package betterjson
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// legacyDigest is the string concatenating DigestJSONForEqual implementation
+// the streaming one must stay compatible with
+func legacyDigest(j *Json) string {
+	if j.IsEmpty() {
+		return "nil"
+	}
+	if jsonArray, err := j.Array(); err == nil {
+		var digestBuffer bytes.Buffer
+		digestBuffer.WriteString("[")
+		for idx := range jsonArray {
+			if idx > 0 {
+				digestBuffer.WriteString(",")
+			}
+			digestBuffer.WriteString(legacyDigest(j.GetIndex(idx)))
+		}
+		digestBuffer.WriteString("]")
+		return digestBuffer.String()
+	}
+	if jsonMap, err := j.Map(); err == nil {
+		var digestBuffer bytes.Buffer
+		digestBuffer.WriteString("{")
+		keys := make([]string, 0)
+		for k := range jsonMap {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for idx, key := range keys {
+			if idx > 0 {
+				digestBuffer.WriteString(",")
+			}
+			keyEncode, _ := json.Marshal(key)
+			digestBuffer.WriteString(string(keyEncode))
+			digestBuffer.WriteString(":")
+			digestBuffer.WriteString(legacyDigest(j.Get(key)))
+		}
+		digestBuffer.WriteString("}")
+		return digestBuffer.String()
+	}
+	encoded, err := j.Encode()
+	if err != nil {
+		return "error"
+	}
+	return string(encoded)
+}
+
+func TestJson_DigestCompatible(t *testing.T) {
+	fixtures := append(encodeFixtures(), NewEmpty())
+	for _, fixture := range fixtures {
+		assert.Equal(t, legacyDigest(fixture), fixture.DigestJSONForEqual())
+	}
+}
+
+func TestJson_WriteDigest(t *testing.T) {
+	a, _ := Parse([]byte(`{"b":[1,2],"a":"x"}`))
+	b, _ := Parse([]byte(`{"a":"x","b":[1,2]}`))
+	hashA, hashB := sha256.New(), sha256.New()
+	assert.True(t, a.WriteDigest(hashA) == nil)
+	assert.True(t, b.WriteDigest(hashB) == nil)
+	assert.Equal(t, hashA.Sum(nil), hashB.Sum(nil))
+	var buffer bytes.Buffer
+	assert.True(t, a.WriteDigest(&buffer) == nil)
+	assert.True(t, buffer.String() == `{"a":"x","b":[1,2]}`)
+}
+
+func TestJson_IsSameJSONWithMatchesDigest(t *testing.T) {
+	texts := []string{`{"a":1,"b":[1,2]}`, `{"b":[1,2],"a":1}`, `{"a":1,"b":[2,1]}`, `{"a":1}`, `{"a":1.0,"b":[1,2]}`, `[1,2]`, `"x"`, `null`, `{"a":null,"b":[1,2]}`}
+	docs := make([]*Json, 0)
+	for _, text := range texts {
+		doc, _ := Parse([]byte(text))
+		docs = append(docs, doc)
+	}
+	native := Obj("a", 1, "b", Arr(1, 2))
+	docs = append(docs, native, NewEmpty())
+	for _, a := range docs {
+		for _, b := range docs {
+			assert.Equal(t, a.DigestJSONForEqual() == b.DigestJSONForEqual(), a.IsSameJSONWith(b))
+		}
+	}
+	assert.True(t, docs[0].IsSameJSONWith(native))
+}
+
+func largeDigestFixture() *Json {
+	items := make([]interface{}, 0)
+	for i := 0; i < 30000; i++ {
+		items = append(items, map[string]interface{}{"id": json.Number(strconv.Itoa(i)), "name": "item " + strconv.Itoa(i), "tags": []interface{}{"a", "b"}, "description": "some padding text for the fixture document size"})
+	}
+	return wrapRaw(map[string]interface{}{"items": items})
+}
+
+func BenchmarkJson_DigestJSONForEqual(b *testing.B) {
+	fixture := largeDigestFixture()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fixture.DigestJSONForEqual()
+	}
+}
+
+func BenchmarkJson_LegacyDigest(b *testing.B) {
+	fixture := largeDigestFixture()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacyDigest(fixture)
+	}
+}
+
+func BenchmarkJson_IsSameJSONWith(b *testing.B) {
+	fixture, other := largeDigestFixture(), largeDigestFixture()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fixture.IsSameJSONWith(other)
+	}
+}
+
+func TestJson_DigestIsValidJSON(t *testing.T) {
+	for _, fixture := range encodeFixtures() {
+		digest := fixture.DigestJSONForEqual()
+		parsed, err := Parse([]byte(digest))
+		assert.True(t, err == nil)
+		assert.True(t, parsed.IsSameJSONWith(fixture))
+	}
+}
+
+func TestJson_DigestUnencodable(t *testing.T) {
+	broken := NewJSONObject().Set("value", math.NaN())
+	sentinel := NewJSONObject().Set("value", "error")
+	var buffer bytes.Buffer
+	assert.True(t, broken.WriteDigest(&buffer) != nil)
+	assert.True(t, broken.DigestJSONForEqual() == "")
+	assert.True(t, broken.DigestJSONForEqual() != sentinel.DigestJSONForEqual())
+	assert.False(t, broken.IsSameJSONWith(sentinel))
+	assert.False(t, broken.IsSameJSONWith(NewJSONObject().Set("value", math.NaN())))
+}
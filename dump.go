@@ -0,0 +1,194 @@
+package betterjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// DumpOptions configures Dump
+type DumpOptions struct {
+	// Indent is the indent of one level, two spaces when empty
+	Indent string
+	// Color colors keys, strings, numbers, booleans and null with ANSI
+	// escapes when w is a terminal
+	Color bool
+	// ForceColor colors even when w is not a terminal
+	ForceColor bool
+	// MaxDepth collapses the non-empty containers at that depth, the root
+	// being depth 0, to summaries like {…3 keys} and […12 items]. 0 is no limit
+	MaxDepth int
+}
+
+const (
+	colorReset  = "\x1b[0m"
+	colorKey    = "\x1b[34m"
+	colorString = "\x1b[32m"
+	colorNumber = "\x1b[36m"
+	colorBool   = "\x1b[33m"
+	colorNull   = "\x1b[90m"
+)
+
+// Dump pretty prints j to w for reading in a terminal. object keys are
+// sorted like Encode sorts them, and without colors or MaxDepth the output is
+// the same json as EncodePrettyTo with the indent:
+//    js.Dump(os.Stdout, betterjson.DumpOptions{Color: true, MaxDepth: 3})
+func (j *Json) Dump(w io.Writer, opts DumpOptions) error {
+	if j.IsEmpty() {
+		return errors.New("empty json can't be dumped")
+	}
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	d := &dumper{w: bufio.NewWriter(w), opts: opts, color: opts.ForceColor || (opts.Color && isTerminal(w))}
+	if err := d.dump(j.value.Interface(), 0); err != nil {
+		return err
+	}
+	d.w.WriteByte('\n')
+	return d.w.Flush()
+}
+
+// isTerminal reports whether w is a character device like a terminal
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+type dumper struct {
+	w     *bufio.Writer
+	opts  DumpOptions
+	color bool
+	// scalar encodes the leaves exactly like Encode
+	scalar streamEncoder
+}
+
+func (d *dumper) dump(node interface{}, depth int) error {
+	switch value := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		if value != nil {
+			return d.dumpObject(value, depth)
+		}
+	case []interface{}:
+		if value != nil {
+			return d.dumpArray(value, depth)
+		}
+	}
+	return d.dumpLeaf(node)
+}
+
+func (d *dumper) dumpObject(object map[string]interface{}, depth int) error {
+	if len(object) == 0 {
+		d.w.WriteString("{}")
+		return nil
+	}
+	if d.collapsed(depth) {
+		d.w.WriteString("{…" + strconv.Itoa(len(object)) + plural(len(object), " key", " keys") + "}")
+		return nil
+	}
+	d.w.WriteByte('{')
+	for idx, key := range sortedKeys(object) {
+		if idx > 0 {
+			d.w.WriteByte(',')
+		}
+		d.newline(depth + 1)
+		if err := d.write(key, colorKey); err != nil {
+			return err
+		}
+		d.w.WriteString(": ")
+		if err := d.dump(object[key], depth+1); err != nil {
+			return err
+		}
+	}
+	d.newline(depth)
+	d.w.WriteByte('}')
+	return nil
+}
+
+func (d *dumper) dumpArray(array []interface{}, depth int) error {
+	if len(array) == 0 {
+		d.w.WriteString("[]")
+		return nil
+	}
+	if d.collapsed(depth) {
+		d.w.WriteString("[…" + strconv.Itoa(len(array)) + plural(len(array), " item", " items") + "]")
+		return nil
+	}
+	d.w.WriteByte('[')
+	for idx, item := range array {
+		if idx > 0 {
+			d.w.WriteByte(',')
+		}
+		d.newline(depth + 1)
+		if err := d.dump(item, depth+1); err != nil {
+			return err
+		}
+	}
+	d.newline(depth)
+	d.w.WriteByte(']')
+	return nil
+}
+
+func (d *dumper) dumpLeaf(node interface{}) error {
+	color := ""
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		color = colorNull
+	case bool:
+		color = colorBool
+	case string:
+		color = colorString
+	case map[string]interface{}, []interface{}:
+		// nil containers encode as null
+		color = colorNull
+	default:
+		if _, ok := rawNumber(value); ok {
+			color = colorNumber
+		} else if _, ok := value.(json.Number); ok {
+			color = colorNumber
+		}
+	}
+	return d.write(node, color)
+}
+
+// write encodes node like Encode, in color when colors are on
+func (d *dumper) write(node interface{}, color string) error {
+	d.scalar.buffer.b = d.scalar.buffer.b[:0]
+	d.scalar.w = &d.scalar.buffer
+	if err := d.scalar.encode(node, 0); err != nil {
+		return err
+	}
+	if d.color && color != "" {
+		d.w.WriteString(color)
+		d.w.Write(d.scalar.buffer.b)
+		d.w.WriteString(colorReset)
+		return nil
+	}
+	d.w.Write(d.scalar.buffer.b)
+	return nil
+}
+
+func (d *dumper) collapsed(depth int) bool {
+	return d.opts.MaxDepth > 0 && depth >= d.opts.MaxDepth
+}
+
+func (d *dumper) newline(depth int) {
+	d.w.WriteByte('\n')
+	for i := 0; i < depth; i++ {
+		d.w.WriteString(d.opts.Indent)
+	}
+}
+
+func plural(n int, one string, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
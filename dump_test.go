@@ -0,0 +1,74 @@
+package betterjson
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const dumpFixture = `{"name":"betterjson","version":1.5,"stable":false,"license":null,"tags":["json","go"],"owner":{"login":"zoowii","repos":[{"id":1,"forks":[]},{"id":2}],"meta":{}},"html":"<b> & \"q\""}`
+
+func TestJson_DumpGolden(t *testing.T) {
+	js, _ := Parse([]byte(dumpFixture))
+	var out bytes.Buffer
+	err := js.Dump(&out, DumpOptions{})
+	assert.True(t, err == nil)
+	println(out.String())
+	golden, err := ioutil.ReadFile("testdata/dump.golden")
+	assert.True(t, err == nil)
+	assert.Equal(t, string(golden), out.String())
+
+	// without colors and depth limit the output is the pretty encoding
+	var pretty bytes.Buffer
+	js.EncodePrettyTo(&pretty, "    ")
+	out.Reset()
+	js.Dump(&out, DumpOptions{Indent: "    "})
+	assert.Equal(t, pretty.String()+"\n", out.String())
+	_, err = Parse(out.Bytes())
+	assert.True(t, err == nil)
+}
+
+func TestJson_DumpMaxDepth(t *testing.T) {
+	js, _ := Parse([]byte(dumpFixture))
+	var out bytes.Buffer
+	js.Dump(&out, DumpOptions{MaxDepth: 1})
+	println(out.String())
+	assert.True(t, strings.Contains(out.String(), `"owner": {…3 keys}`))
+	assert.True(t, strings.Contains(out.String(), `"tags": […2 items]`))
+	assert.True(t, strings.Contains(out.String(), `"name": "betterjson"`))
+
+	out.Reset()
+	js.Get("owner").Dump(&out, DumpOptions{MaxDepth: 2})
+	assert.False(t, strings.Contains(out.String(), `"forks"`))
+	assert.True(t, strings.Contains(out.String(), "{…2 keys}"))
+	assert.True(t, strings.Contains(out.String(), "{…1 key}"))
+	assert.True(t, strings.Contains(out.String(), `"meta": {}`))
+
+	out.Reset()
+	Arr(1).Dump(&out, DumpOptions{MaxDepth: 1})
+	assert.Equal(t, "[\n  1\n]\n", out.String())
+	out.Reset()
+	Obj("list", Arr(1)).Dump(&out, DumpOptions{MaxDepth: 1})
+	assert.Equal(t, "{\n  \"list\": […1 item]\n}\n", out.String())
+}
+
+func TestJson_DumpColor(t *testing.T) {
+	js := Obj("k", "v", "n", 2, "b", true, "z", nil)
+	var out bytes.Buffer
+	// a buffer is not a terminal
+	js.Dump(&out, DumpOptions{Color: true})
+	assert.False(t, strings.Contains(out.String(), "\x1b["))
+
+	out.Reset()
+	js.Dump(&out, DumpOptions{ForceColor: true})
+	assert.True(t, strings.Contains(out.String(), colorKey+`"k"`+colorReset+": "+colorString+`"v"`+colorReset))
+	assert.True(t, strings.Contains(out.String(), colorNumber+"2"+colorReset))
+	assert.True(t, strings.Contains(out.String(), colorBool+"true"+colorReset))
+	assert.True(t, strings.Contains(out.String(), colorNull+"null"+colorReset))
+
+	assert.True(t, NewEmpty().Dump(&out, DumpOptions{}) != nil)
+	assert.True(t, Obj("bad", math.NaN()).Dump(&out, DumpOptions{}) != nil)
+}
@@ -0,0 +1,80 @@
+package betterjson
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// EditOptions configures EditAtWithOptions
+type EditOptions struct {
+	// CreateMissing creates empty objects for missing keys along the path,
+	// the node itself included, instead of failing
+	CreateMissing bool
+}
+
+// EditAt calls fn with a wrapper aliasing the node at branch, which may go
+// through arrays by index. every edit fn makes through the wrapper, including
+// SetValue and TryAdd on array elements and scalars, is in j afterwards. when
+// fn returns an error j is restored to its state before EditAt and the error
+// is returned:
+//    err := js.EditAt([]string{"servers", "0"}, func(server *Json) error {
+//        server.Set("port", 8080)
+//        return validate(server)
+//    })
+func (j *Json) EditAt(branch []string, fn func(sub *Json) error) error {
+	return j.EditAtWithOptions(branch, EditOptions{}, fn)
+}
+
+// EditAtWithOptions is EditAt configured by opts
+func (j *Json) EditAtWithOptions(branch []string, opts EditOptions, fn func(sub *Json) error) error {
+	if j.IsEmpty() {
+		return errors.New("empty json can't be edited")
+	}
+	id := j.Snapshot()
+	sub, err := j.editTarget(branch, opts.CreateMissing)
+	if err == nil {
+		err = fn(sub)
+	}
+	if err != nil {
+		j.Rollback(id)
+		return err
+	}
+	j.DiscardSnapshot(id)
+	sub.writeBack()
+	return nil
+}
+
+// editTarget resolves branch to a chain of linked wrappers, so replacing the
+// value of the last one writes it back into j
+func (j *Json) editTarget(branch []string, create bool) (*Json, error) {
+	current := j
+	for idx, segment := range branch {
+		var item interface{}
+		switch container := current.value.Interface().(type) {
+		case map[string]interface{}:
+			var exists bool
+			item, exists = container[segment]
+			last := idx == len(branch)-1
+			if !exists || (!last && unwrapRaw(item) == nil) {
+				if !create {
+					return nil, errors.Errorf("path %s doesn't exist", displayPath(branch[:idx+1]))
+				}
+				item = newContainer("object")
+				prior := current.prior([]string{segment})
+				container[segment] = item
+				current.changed(setOperation([]string{segment}, item, prior), prior)
+			}
+		case []interface{}:
+			itemIdx, err := strconv.Atoi(segment)
+			if err != nil || itemIdx < 0 || itemIdx >= len(container) {
+				return nil, errors.Errorf("index %s out of range of array at %s", segment, displayPath(branch[:idx]))
+			}
+			item = container[itemIdx]
+		default:
+			return nil, errors.Errorf("%s at %s is not an object", kindName(container), displayPath(branch[:idx]))
+		}
+		current = newChild(current, segment, item)
+	}
+	return current, nil
+}
@@ -0,0 +1,102 @@
+package betterjson
+
+import (
+	"errors"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_EditAt(t *testing.T) {
+	doc, _ := Parse([]byte(`{"servers":[{"port":80,"tags":["a"]},"plain"],"count":1}`))
+	err := doc.EditAt([]string{"servers", "0"}, func(server *Json) error {
+		server.Set("port", 8080)
+		server.Get("tags").TryAdd("b")
+		return nil
+	})
+	assert.True(t, err == nil)
+	err = doc.EditAt([]string{"servers", "1"}, func(plain *Json) error {
+		plain.SetValue("replaced")
+		return nil
+	})
+	assert.True(t, err == nil)
+	err = doc.EditAt([]string{"servers", "0", "tags"}, func(tags *Json) error {
+		tags.TryAdd("c")
+		return nil
+	})
+	assert.True(t, err == nil)
+	assert.True(t, doc.DigestJSONForEqual() == `{"count":1,"servers":[{"port":8080,"tags":["a","b","c"]},"replaced"]}`)
+}
+
+func TestJson_EditAtRollsBack(t *testing.T) {
+	doc, _ := Parse([]byte(`{"a":{"b":1},"list":[1]}`))
+	before := doc.DigestJSONForEqual()
+	failure := errors.New("invalid")
+	err := doc.EditAt([]string{"a"}, func(sub *Json) error {
+		sub.Set("b", 2).Set("c", 3)
+		doc.Get("list").TryAdd(2)
+		return failure
+	})
+	assert.True(t, err == failure)
+	assert.True(t, doc.DigestJSONForEqual() == before)
+
+	err = doc.EditAtWithOptions([]string{"x", "y"}, EditOptions{CreateMissing: true}, func(sub *Json) error {
+		sub.Set("z", 1)
+		return failure
+	})
+	assert.True(t, err == failure)
+	assert.True(t, doc.DigestJSONForEqual() == before)
+}
+
+func TestJson_EditAtCreate(t *testing.T) {
+	doc := NewJSONObject()
+	err := doc.EditAt([]string{"x", "y"}, func(sub *Json) error { return nil })
+	assert.True(t, err != nil && err.Error() == "path x doesn't exist")
+	err = doc.EditAtWithOptions([]string{"x", "y"}, EditOptions{CreateMissing: true}, func(sub *Json) error {
+		sub.Set("z", 1)
+		return nil
+	})
+	assert.True(t, err == nil)
+	err = doc.EditAtWithOptions([]string{"list"}, EditOptions{CreateMissing: true}, func(sub *Json) error {
+		sub.SetValue(NewJSONArray())
+		return nil
+	})
+	assert.True(t, err == nil)
+	err = doc.EditAt([]string{"list"}, func(sub *Json) error {
+		sub.TryAdd(1)
+		return nil
+	})
+	assert.True(t, err == nil)
+	assert.True(t, doc.DigestJSONForEqual() == `{"list":[1],"x":{"y":{"z":1}}}`)
+
+	err = doc.EditAt([]string{"list", "5"}, func(sub *Json) error { return nil })
+	assert.True(t, err != nil)
+	err = doc.EditAt([]string{"list", "0", "deeper"}, func(sub *Json) error { return nil })
+	assert.True(t, err != nil && err.Error() == "number at list.0 is not an object")
+}
+
+func TestJson_EditAtCreateRecorded(t *testing.T) {
+	doc, _ := Parse([]byte(`{"x":null,"list":[{}]}`))
+	replay, _ := Parse([]byte(`{"x":null,"list":[{}]}`))
+	paths := make([]string, 0)
+	doc.OnChange(func(event ChangeEvent) {
+		paths = append(paths, JoinDottedPath(event.Path))
+	})
+	doc.StartRecording()
+	err := doc.EditAtWithOptions([]string{"x", "y", "z"}, EditOptions{CreateMissing: true}, func(sub *Json) error {
+		sub.Set("k", 1)
+		return nil
+	})
+	assert.True(t, err == nil)
+	err = doc.EditAtWithOptions([]string{"list", "0", "a"}, EditOptions{CreateMissing: true}, func(sub *Json) error {
+		sub.Set("b", true)
+		return nil
+	})
+	assert.True(t, err == nil)
+	patch, err := doc.StopRecording()
+	assert.True(t, err == nil)
+	println(patch.EncodeToStringOrDefault(""))
+	assert.True(t, replay.ApplyPatch(patch) == nil)
+	assert.True(t, replay.IsSameJSONWith(doc))
+	assert.Equal(t, `{"list":[{"a":{"b":true}}],"x":{"y":{"z":{"k":1}}}}`, replay.EncodeToStringOrDefault(""))
+	assert.Equal(t, []string{"x", "x.y", "x.y.z", "x.y.z.k", "list.0.a", "list.0.a.b"}, paths)
+}
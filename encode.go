@@ -0,0 +1,437 @@
+package betterjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// EncodeTo writes the same bytes as Encode to w, walking the document instead
+// of building the whole encoding in memory first. it returns the number of bytes
+// written. an empty Json is an error before anything is written, an
+// unencodable value deeper in the document may leave partial output in w
+func (j *Json) EncodeTo(w io.Writer) (int64, error) {
+	return j.encodeTo(w, &streamEncoder{})
+}
+
+// EncodePrettyTo is EncodeTo writing the layout of json.MarshalIndent
+// with an empty prefix and the given indent
+func (j *Json) EncodePrettyTo(w io.Writer, indent string) (int64, error) {
+	return j.encodeTo(w, &streamEncoder{pretty: true, indent: indent})
+}
+
+func (j *Json) encodeTo(w io.Writer, encoder *streamEncoder) (int64, error) {
+	if j.IsEmpty() {
+		return 0, errors.New("empty json can't be encoded")
+	}
+	counter := &countingWriter{w: w}
+	buffered := bufio.NewWriter(counter)
+	encoder.w = buffered
+	sink := metrics
+	var start time.Time
+	if sink != nil {
+		start = time.Now()
+	}
+	err := encoder.encode(j.value.Interface(), 0)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if sink != nil {
+		observe(sink, "encode", start, int(counter.n), err)
+	}
+	return counter.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// encodeBuffer is what streamEncoder writes to, bufio.Writer and
+// bytes.Buffer both are one
+type encodeBuffer interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+}
+
+// streamEncoder writes raw documents byte for byte like encoding/json does.
+// values of types it doesn't know are handed to encoding/json
+type streamEncoder struct {
+	w       encodeBuffer
+	pretty  bool
+	indent  string
+	scratch [64]byte
+	// keys holds the sorted keys of the object being written at each depth,
+	// kept for reuse when the encoder is pooled
+	keys [][]string
+	// buffer is the writer of AppendEncode
+	buffer appendBuffer
+	// options are the ones of EncodeWithOptions, nil for Encode
+	options *EncodeOptions
+	// aliases are the parsed FieldAliases of options, path the branch of the
+	// value being written while there are any
+	aliases []fieldAlias
+	path    []string
+}
+
+type appendBuffer struct {
+	b []byte
+}
+
+func (buffer *appendBuffer) Write(p []byte) (int, error) {
+	buffer.b = append(buffer.b, p...)
+	return len(p), nil
+}
+
+func (buffer *appendBuffer) WriteByte(c byte) error {
+	buffer.b = append(buffer.b, c)
+	return nil
+}
+
+func (buffer *appendBuffer) WriteString(s string) (int, error) {
+	buffer.b = append(buffer.b, s...)
+	return len(s), nil
+}
+
+var encoderPool = sync.Pool{New: func() interface{} { return &streamEncoder{} }}
+
+// AppendEncode appends the encoding of j, the same bytes as Encode, to dst and
+// returns the extended buffer. reusing the buffer makes repeated encodes
+// allocation free once it is large enough:
+//    buffer := make([]byte, 0, 4096)
+//    for _, message := range messages {
+//        buffer, err = message.AppendEncode(buffer[:0])
+//        ...
+//    }
+// on error dst is returned unchanged
+func (j *Json) AppendEncode(dst []byte) ([]byte, error) {
+	if j.IsEmpty() {
+		return dst, errors.New("empty json can't be encoded")
+	}
+	encoder := encoderPool.Get().(*streamEncoder)
+	encoder.buffer.b = dst
+	encoder.w = &encoder.buffer
+	err := encoder.encode(j.value.Interface(), 0)
+	result := encoder.buffer.b
+	encoder.buffer.b = nil
+	encoder.w = nil
+	encoderPool.Put(encoder)
+	if err != nil {
+		return dst, err
+	}
+	return result, nil
+}
+
+// EncodeSorted is Encode with object keys in sorted order. Encode sorts the
+// keys as well, EncodeSorted spells that out for callers comparing encodings
+// byte for byte
+func (j *Json) EncodeSorted() ([]byte, error) {
+	if j.IsEmpty() {
+		return []byte{}, errors.New("empty json can't be encoded")
+	}
+	return j.AppendEncode(nil)
+}
+
+// maxPooledBuffer keeps ReleaseBuffer from holding on to huge buffers
+const maxPooledBuffer = 1 << 20
+
+var (
+	// bufferPool holds released buffers, bufferHolderPool the emptied
+	// holders so ReleaseBuffer doesn't allocate one each time
+	bufferPool       sync.Pool
+	bufferHolderPool sync.Pool
+)
+
+// EncodePooled is Encode writing into a buffer taken from a pool. pass the
+// result to ReleaseBuffer when done with it, and don't use it afterwards
+func (j *Json) EncodePooled() ([]byte, error) {
+	var buffer []byte
+	if holder, ok := bufferPool.Get().(*[]byte); ok {
+		buffer = *holder
+		*holder = nil
+		bufferHolderPool.Put(holder)
+	}
+	return j.AppendEncode(buffer[:0])
+}
+
+// ReleaseBuffer returns a buffer from EncodePooled to the pool
+func ReleaseBuffer(buffer []byte) {
+	if cap(buffer) == 0 || cap(buffer) > maxPooledBuffer {
+		return
+	}
+	holder, ok := bufferHolderPool.Get().(*[]byte)
+	if !ok {
+		holder = new([]byte)
+	}
+	*holder = buffer[:0]
+	bufferPool.Put(holder)
+}
+
+func (e *streamEncoder) encode(node interface{}, depth int) error {
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		e.w.WriteString("null")
+	case bool:
+		if value {
+			e.w.WriteString("true")
+		} else {
+			e.w.WriteString("false")
+		}
+	case string:
+		return e.encodeString(value)
+	case json.Number:
+		if value == "" {
+			value = "0"
+		}
+		if !isValidNumber(string(value)) {
+			return e.marshal(value, depth)
+		}
+		e.w.WriteString(string(value))
+	case float64:
+		return e.encodeFloat(value, 64, depth)
+	case float32:
+		return e.encodeFloat(float64(value), 32, depth)
+	case int:
+		e.w.Write(strconv.AppendInt(e.scratch[:0], int64(value), 10))
+	case int8:
+		e.w.Write(strconv.AppendInt(e.scratch[:0], int64(value), 10))
+	case int16:
+		e.w.Write(strconv.AppendInt(e.scratch[:0], int64(value), 10))
+	case int32:
+		e.w.Write(strconv.AppendInt(e.scratch[:0], int64(value), 10))
+	case int64:
+		e.w.Write(strconv.AppendInt(e.scratch[:0], value, 10))
+	case uint:
+		e.w.Write(strconv.AppendUint(e.scratch[:0], uint64(value), 10))
+	case uint8:
+		e.w.Write(strconv.AppendUint(e.scratch[:0], uint64(value), 10))
+	case uint16:
+		e.w.Write(strconv.AppendUint(e.scratch[:0], uint64(value), 10))
+	case uint32:
+		e.w.Write(strconv.AppendUint(e.scratch[:0], uint64(value), 10))
+	case uint64:
+		e.w.Write(strconv.AppendUint(e.scratch[:0], value, 10))
+	case map[string]interface{}:
+		if value == nil {
+			e.w.WriteString("null")
+			return nil
+		}
+		return e.encodeObject(value, depth)
+	case []interface{}:
+		if value == nil {
+			e.w.WriteString("null")
+			return nil
+		}
+		return e.encodeArray(value, depth)
+	default:
+		return e.marshal(value, depth)
+	}
+	return nil
+}
+
+func (e *streamEncoder) encodeObject(object map[string]interface{}, depth int) error {
+	if len(object) == 0 {
+		e.w.WriteString("{}")
+		return nil
+	}
+	for len(e.keys) <= depth {
+		e.keys = append(e.keys, nil)
+	}
+	keys := e.keys[depth][:0]
+	for key, item := range object {
+		if !e.omitted(item) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	e.keys[depth] = keys
+	if len(keys) == 0 {
+		e.w.WriteString("{}")
+		return nil
+	}
+	if e.aliases != nil {
+		return e.encodeAliasedObject(object, keys, depth)
+	}
+	e.w.WriteByte('{')
+	for idx, key := range keys {
+		if idx > 0 {
+			e.w.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		if err := e.encodeString(key); err != nil {
+			return err
+		}
+		e.w.WriteByte(':')
+		if e.pretty {
+			e.w.WriteByte(' ')
+		}
+		if err := e.encode(object[key], depth+1); err != nil {
+			return err
+		}
+	}
+	e.newline(depth)
+	e.w.WriteByte('}')
+	return nil
+}
+
+func (e *streamEncoder) encodeArray(array []interface{}, depth int) error {
+	if len(array) == 0 {
+		e.w.WriteString("[]")
+		return nil
+	}
+	e.w.WriteByte('[')
+	for idx, item := range array {
+		if idx > 0 {
+			e.w.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		if e.aliases != nil {
+			e.path = append(e.path, strconv.Itoa(idx))
+		}
+		if err := e.encode(item, depth+1); err != nil {
+			return err
+		}
+		if e.aliases != nil {
+			e.path = e.path[:len(e.path)-1]
+		}
+	}
+	e.newline(depth)
+	e.w.WriteByte(']')
+	return nil
+}
+
+func (e *streamEncoder) newline(depth int) {
+	if !e.pretty {
+		return
+	}
+	e.w.WriteByte('\n')
+	for i := 0; i < depth; i++ {
+		e.w.WriteString(e.indent)
+	}
+}
+
+// encodeString writes strings that need no escaping directly and leaves the
+// others to encoding/json, so the escapes always match Encode
+func (e *streamEncoder) encodeString(s string) error {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c < 0x20 || c == '"' || c == '\\' || (c == '<' || c == '>' || c == '&') && e.escapeHTML() {
+				return e.marshalString(s)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || r == '\u2028' || r == '\u2029' {
+			return e.marshalString(s)
+		}
+		i += size
+	}
+	e.w.WriteByte('"')
+	e.w.WriteString(s)
+	e.w.WriteByte('"')
+	return nil
+}
+
+// encodeFloat formats f like encoding/json's float encoder
+func (e *streamEncoder) encodeFloat(f float64, bitSize int, depth int) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		if bitSize == 32 {
+			return e.marshal(float32(f), depth)
+		}
+		return e.marshal(f, depth)
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) || bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b := strconv.AppendFloat(e.scratch[:0], f, format, -1, bitSize)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	e.w.Write(b)
+	return nil
+}
+
+func (e *streamEncoder) marshal(value interface{}, depth int) error {
+	var encoded []byte
+	var err error
+	if e.pretty {
+		encoded, err = json.MarshalIndent(value, strings.Repeat(e.indent, depth), e.indent)
+	} else {
+		encoded, err = json.Marshal(value)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "can't encode %s", reflect.TypeOf(value))
+	}
+	e.w.Write(encoded)
+	return nil
+}
+
+// isValidNumber reports whether s is a number in json syntax
+func isValidNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	if s[0] == '-' {
+		s = s[1:]
+		if s == "" {
+			return false
+		}
+	}
+	switch {
+	case s[0] == '0':
+		s = s[1:]
+	case '1' <= s[0] && s[0] <= '9':
+		s = s[1:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	default:
+		return false
+	}
+	if len(s) >= 2 && s[0] == '.' && '0' <= s[1] && s[1] <= '9' {
+		s = s[2:]
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+	if len(s) >= 2 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s[0] == '+' || s[0] == '-' {
+			s = s[1:]
+			if s == "" {
+				return false
+			}
+		}
+		for len(s) > 0 && '0' <= s[0] && s[0] <= '9' {
+			s = s[1:]
+		}
+	}
+	return s == ""
+}
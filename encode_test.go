@@ -0,0 +1,157 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeFixtures() []*Json {
+	fixtures := make([]*Json, 0)
+	for _, text := range []string{
+		`{"a":1,"b":[true,false,null],"c":{"d":"e"},"empty":{},"none":[]}`,
+		`["<html> &    ", "tab\there", "quote\" back\\slash", "ünïcödé 漢字 😀", "\u0001\u001f"]`,
+		`[0, -0, 1.5e300, -1e-7, 123456789012345678901234567890, 0.000001]`,
+		`"plain"`,
+		`null`,
+		`[[[[{"deep":[{}]}]]]]`,
+	} {
+		fixture, err := Parse([]byte(text))
+		if err != nil {
+			panic(err)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	native := NewJSONObject()
+	native.Set("int", -42).Set("uint8", uint8(200)).Set("float", 1e21).Set("small", 1e-7).Set("float32", float32(3.14))
+	native.Set("bytes", []byte("raw")).Set("strings", []string{"x", "y"}).Set("struct", struct {
+		Name string `json:"name"`
+	}{"n"})
+	native.Set("numberless", json.Number(""))
+	native.Set("items", Arr(Obj("k", "v"), 1))
+	return append(fixtures, native)
+}
+
+// lossyEncodeFixtures don't parse back to the same document, invalid utf8 is
+// encoded as replacement characters
+func lossyEncodeFixtures() []*Json {
+	return []*Json{NewJSONObject().Set("invalid utf8", "a\xffb")}
+}
+
+func TestJson_EncodeToMatchesEncode(t *testing.T) {
+	for _, fixture := range append(encodeFixtures(), lossyEncodeFixtures()...) {
+		expected, err := fixture.Encode()
+		assert.True(t, err == nil)
+		var buffer bytes.Buffer
+		n, err := fixture.EncodeTo(&buffer)
+		assert.True(t, err == nil)
+		assert.Equal(t, string(expected), buffer.String())
+		assert.True(t, n == int64(len(expected)))
+
+		expected, _ = json.MarshalIndent(fixture.Interface(), "", "\t")
+		buffer.Reset()
+		_, err = fixture.EncodePrettyTo(&buffer, "\t")
+		assert.True(t, err == nil)
+		assert.Equal(t, string(expected), buffer.String())
+	}
+}
+
+func TestJson_EncodeToErrors(t *testing.T) {
+	var buffer bytes.Buffer
+	n, err := NewEmpty().EncodeTo(&buffer)
+	assert.True(t, err != nil)
+	assert.True(t, n == 0 && buffer.Len() == 0)
+	_, err = NewJSONArray().TryAdd(math.NaN()).EncodeTo(&buffer)
+	assert.True(t, err != nil)
+	println(err.Error())
+}
+
+func largeEncodeFixture() *Json {
+	items := NewJSONArray()
+	for i := 0; i < 60000; i++ {
+		items.TryAdd(Obj("id", i, "name", "item number "+strconv.Itoa(i), "tags", []interface{}{"a", "b", "c"}, "price", 12.5, "description", "a fairly long description of the item to pad the document size"))
+	}
+	return items
+}
+
+func BenchmarkJson_EncodeThenWrite(b *testing.B) {
+	fixture := largeEncodeFixture()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encoded, _ := fixture.Encode()
+		ioutil.Discard.Write(encoded)
+	}
+}
+
+func BenchmarkJson_EncodeTo(b *testing.B) {
+	fixture := largeEncodeFixture()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fixture.EncodeTo(ioutil.Discard)
+	}
+}
+
+func TestJson_AppendEncode(t *testing.T) {
+	for _, fixture := range append(encodeFixtures(), lossyEncodeFixtures()...) {
+		expected, _ := fixture.Encode()
+		appended, err := fixture.AppendEncode([]byte("prefix:"))
+		assert.True(t, err == nil)
+		assert.Equal(t, "prefix:"+string(expected), string(appended))
+		pooled, err := fixture.EncodePooled()
+		assert.True(t, err == nil)
+		assert.Equal(t, string(expected), string(pooled))
+		ReleaseBuffer(pooled)
+	}
+	dst := []byte("kept")
+	result, err := NewEmpty().AppendEncode(dst)
+	assert.True(t, err != nil)
+	assert.True(t, string(result) == "kept")
+	result, err = NewJSONArray().TryAdd(math.Inf(1)).AppendEncode(dst)
+	assert.True(t, err != nil)
+	assert.True(t, string(result) == "kept")
+}
+
+func TestJson_AppendEncodeAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	fixture, _ := Parse([]byte(`{"id":12,"name":"widget","tags":["a","b"],"price":9.99,"meta":{"active":true,"owner":null}}`))
+	buffer := make([]byte, 0, 1024)
+	fixture.AppendEncode(buffer)
+	allocs := testing.AllocsPerRun(100, func() {
+		buffer, _ = fixture.AppendEncode(buffer[:0])
+	})
+	assert.True(t, allocs == 0)
+}
+
+func BenchmarkJson_Encode(b *testing.B) {
+	fixture, _ := Parse([]byte(`{"id":12,"name":"widget","tags":["a","b"],"price":9.99,"meta":{"active":true,"owner":null}}`))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fixture.Encode()
+	}
+}
+
+func BenchmarkJson_AppendEncode(b *testing.B) {
+	fixture, _ := Parse([]byte(`{"id":12,"name":"widget","tags":["a","b"],"price":9.99,"meta":{"active":true,"owner":null}}`))
+	buffer := make([]byte, 0, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buffer, _ = fixture.AppendEncode(buffer[:0])
+	}
+}
+
+func BenchmarkJson_EncodePooled(b *testing.B) {
+	fixture, _ := Parse([]byte(`{"id":12,"name":"widget","tags":["a","b"],"price":9.99,"meta":{"active":true,"owner":null}}`))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encoded, _ := fixture.EncodePooled()
+		ReleaseBuffer(encoded)
+	}
+}
@@ -0,0 +1,164 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// EncodeOptions controls the output of EncodeWithOptions, the options combine
+// freely
+type EncodeOptions struct {
+	// OmitNulls leaves out object members that are null. null array items
+	// are kept, leaving them out would shift the indexes after them
+	OmitNulls bool
+	// OmitEmptyObjects and OmitEmptyArrays leave out object members that
+	// are {} or [], also when they only become empty by the other options
+	OmitEmptyObjects bool
+	OmitEmptyArrays  bool
+	// EscapeHTML escapes <, > and & in strings as \u003c, \u003e and \u0026
+	// like Encode does
+	EscapeHTML bool
+	// FieldAliases renames object keys in the output. the keys of the map
+	// are dotted paths in the glob syntax of PathsMatching, like
+	// "items.*.createdAt", the values the names to write instead of the last
+	// segment. when several paths match, the first in sorted order wins. a
+	// renamed key colliding with another key of its object is an error
+	FieldAliases map[string]string
+}
+
+// EncodeWithOptions is Encode with the output adjusted by opts while it is
+// written, j itself is left as it is:
+//    body, err := js.EncodeWithOptions(betterjson.EncodeOptions{OmitNulls: true, EscapeHTML: true})
+func (j *Json) EncodeWithOptions(opts EncodeOptions) ([]byte, error) {
+	if j.IsEmpty() {
+		return []byte{}, errors.New("empty json can't be encoded")
+	}
+	encoder := &streamEncoder{options: &opts}
+	encoder.w = &encoder.buffer
+	if len(opts.FieldAliases) > 0 {
+		patterns := make([]string, 0, len(opts.FieldAliases))
+		for pattern := range opts.FieldAliases {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			encoder.aliases = append(encoder.aliases, fieldAlias{glob: parseGlobPattern(pattern), name: opts.FieldAliases[pattern]})
+		}
+		encoder.path = make([]string, 0, 8)
+	}
+	if err := encoder.encode(j.Interface(), 0); err != nil {
+		return []byte{}, err
+	}
+	return encoder.buffer.b, nil
+}
+
+type fieldAlias struct {
+	glob globPattern
+	name string
+}
+
+// aliasedKey is an object key and the name it is written as
+type aliasedKey struct {
+	key  string
+	name string
+}
+
+// encodeAliasedObject is encodeObject renaming the keys per FieldAliases,
+// the members written in the sorted order of their new names
+func (e *streamEncoder) encodeAliasedObject(object map[string]interface{}, keys []string, depth int) error {
+	members := make([]aliasedKey, len(keys))
+	owners := make(map[string]string, len(keys))
+	for idx, key := range keys {
+		member := aliasedKey{key: key, name: key}
+		e.path = append(e.path, key)
+		for _, alias := range e.aliases {
+			if alias.glob.match(e.path) {
+				member.name = alias.name
+				break
+			}
+		}
+		e.path = e.path[:len(e.path)-1]
+		if owner, ok := owners[member.name]; ok {
+			return errors.Errorf("keys %q and %q of %s are both encoded as %q", owner, key, displayPath(e.path), member.name)
+		}
+		owners[member.name] = key
+		members[idx] = member
+	}
+	sort.Slice(members, func(a, b int) bool { return members[a].name < members[b].name })
+	e.w.WriteByte('{')
+	for idx, member := range members {
+		if idx > 0 {
+			e.w.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		if err := e.encodeString(member.name); err != nil {
+			return err
+		}
+		e.w.WriteByte(':')
+		if e.pretty {
+			e.w.WriteByte(' ')
+		}
+		e.path = append(e.path, member.key)
+		err := e.encode(object[member.key], depth+1)
+		e.path = e.path[:len(e.path)-1]
+		if err != nil {
+			return err
+		}
+	}
+	e.newline(depth)
+	e.w.WriteByte('}')
+	return nil
+}
+
+func (e *streamEncoder) escapeHTML() bool {
+	return e.options == nil || e.options.EscapeHTML
+}
+
+// omitted reports whether the object member node is left out by the options
+func (e *streamEncoder) omitted(node interface{}) bool {
+	if e.options == nil {
+		return false
+	}
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		return e.options.OmitNulls
+	case map[string]interface{}:
+		if value == nil {
+			return e.options.OmitNulls
+		}
+		if !e.options.OmitEmptyObjects {
+			return false
+		}
+		for _, item := range value {
+			if !e.omitted(item) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		if value == nil {
+			return e.options.OmitNulls
+		}
+		return e.options.OmitEmptyArrays && len(value) == 0
+	}
+	return false
+}
+
+// marshalString writes s escaped by encoding/json, with or without the
+// escaping of HTML characters
+func (e *streamEncoder) marshalString(s string) error {
+	if e.escapeHTML() {
+		return e.marshal(s, 0)
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return errors.Wrap(err, "can't encode string")
+	}
+	e.w.Write(bytes.TrimSuffix(buffer.Bytes(), []byte{'\n'}))
+	return nil
+}
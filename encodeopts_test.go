@@ -0,0 +1,85 @@
+package betterjson
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const encodeOptionsFixture = `{"name":"<b>Tom & Jerry</b>","nickname":null,"tags":[],"meta":{},
+"nested":{"only":null,"list":[null,{},[]],"deeper":{"gone":null,"empty":[]}},"keep":[1,null]}`
+
+func TestEncodeWithOptions(t *testing.T) {
+	js, _ := Parse([]byte(encodeOptionsFixture))
+	before := js.EncodeToStringOrDefault("")
+	cases := []struct {
+		name string
+		opts EncodeOptions
+	}{
+		{"default", EncodeOptions{EscapeHTML: true}},
+		{"no-html-escaping", EncodeOptions{}},
+		{"omit-nulls", EncodeOptions{OmitNulls: true, EscapeHTML: true}},
+		{"omit-empty-objects", EncodeOptions{OmitEmptyObjects: true, EscapeHTML: true}},
+		{"omit-empty-arrays", EncodeOptions{OmitEmptyArrays: true, EscapeHTML: true}},
+		{"omit-all", EncodeOptions{OmitNulls: true, OmitEmptyObjects: true, OmitEmptyArrays: true}},
+	}
+	lines := make([]string, 0, len(cases))
+	for _, c := range cases {
+		encoded, err := js.EncodeWithOptions(c.opts)
+		assert.True(t, err == nil)
+		lines = append(lines, c.name+": "+string(encoded))
+	}
+	golden, err := ioutil.ReadFile("testdata/encode_options.golden")
+	assert.True(t, err == nil)
+	assert.Equal(t, string(golden), strings.Join(lines, "\n")+"\n")
+
+	assert.Equal(t, before, js.EncodeToStringOrDefault(""))
+	encoded, _ := js.EncodeWithOptions(EncodeOptions{EscapeHTML: true})
+	assert.Equal(t, before, string(encoded))
+}
+
+func TestEncodeWithOptionsRoot(t *testing.T) {
+	empty, _ := Parse([]byte(`{"a":null}`))
+	encoded, err := empty.EncodeWithOptions(EncodeOptions{OmitNulls: true, OmitEmptyObjects: true})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{}`, string(encoded))
+	null, _ := Parse([]byte(`null`))
+	encoded, _ = null.EncodeWithOptions(EncodeOptions{OmitNulls: true})
+	assert.Equal(t, `null`, string(encoded))
+	_, err = NewEmpty().EncodeWithOptions(EncodeOptions{})
+	assert.True(t, err != nil)
+	// control characters are still escaped without html escaping
+	encoded, _ = wrapRaw("a<\n\u2028").EncodeWithOptions(EncodeOptions{})
+	assert.Equal(t, `"a<\n\u2028"`, string(encoded))
+}
+
+func TestEncodeWithOptionsFieldAliases(t *testing.T) {
+	js, _ := Parse([]byte(`{"userId":1,"items":[{"createdAt":"2020","sku":"a"},{"createdAt":"2021"}],"meta":{"createdAt":"x"}}`))
+	before := js.EncodeToStringOrDefault("")
+	encoded, err := js.EncodeWithOptions(EncodeOptions{FieldAliases: map[string]string{
+		"userId":            "user_id",
+		"items.*.createdAt": "created_at",
+		"missing.path":      "never",
+	}})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"items":[{"created_at":"2020","sku":"a"},{"created_at":"2021"}],"meta":{"createdAt":"x"},"user_id":1}`, string(encoded))
+	assert.Equal(t, before, js.EncodeToStringOrDefault(""))
+
+	// aliases combine with the other options, the first matching pattern wins
+	encoded, err = js.EncodeWithOptions(EncodeOptions{OmitNulls: true, FieldAliases: map[string]string{
+		"**.createdAt": "created",
+		"meta.*":       "other",
+	}})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"items":[{"created":"2020","sku":"a"},{"created":"2021"}],"meta":{"created":"x"},"userId":1}`, string(encoded))
+}
+
+func TestEncodeWithOptionsFieldAliasCollision(t *testing.T) {
+	js, _ := Parse([]byte(`{"items":[{"createdAt":"2020","created_at":"old"}]}`))
+	_, err := js.EncodeWithOptions(EncodeOptions{FieldAliases: map[string]string{"items.*.createdAt": "created_at"}})
+	println(err.Error())
+	assert.Equal(t, `keys "createdAt" and "created_at" of items.0 are both encoded as "created_at"`, err.Error())
+	_, err = js.EncodeWithOptions(EncodeOptions{FieldAliases: map[string]string{"items": "created_at"}})
+	assert.True(t, err == nil)
+}
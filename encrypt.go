@@ -0,0 +1,182 @@
+package betterjson
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// encryptedVersion is the "$enc" marker of the envelopes of EncryptFields
+const encryptedVersion = "v1"
+
+// FieldsReport lists the paths EncryptFields, DecryptFields or DecryptAll
+// processed, and the ones they skipped because they are missing
+type FieldsReport struct {
+	Processed []string
+	Skipped   []string
+}
+
+// EncryptFields returns a copy of j with the value at each of the dotted
+// paths replaced by an envelope holding its canonical form, see
+// DigestJSONForEqual, encrypted with AES-GCM:
+//    {"$enc":"v1","nonce":"<base64>","data":"<base64>"}
+// key must be 16, 24 or 32 bytes. paths may have array index segments like
+// GetDottedPath's, missing ones are skipped and listed in the report. the
+// path of the field is authenticated too, so an envelope moved to another
+// path doesn't decrypt
+func (j *Json) EncryptFields(paths []string, key []byte) (*Json, FieldsReport, error) {
+	return j.editFields(paths, key, func(aead cipher.AEAD, path string, branch []string, node interface{}) (interface{}, error) {
+		var plaintext bytes.Buffer
+		if err := wrapRaw(node).WriteDigest(&plaintext); err != nil {
+			return nil, errors.Wrapf(err, "can't encrypt %s", path)
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, errors.Wrapf(err, "can't encrypt %s", path)
+		}
+		return map[string]interface{}{
+			"$enc":  encryptedVersion,
+			"nonce": base64.StdEncoding.EncodeToString(nonce),
+			"data":  base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext.Bytes(), []byte(JSONPointer(branch)))),
+		}, nil
+	})
+}
+
+// DecryptFields reverses EncryptFields for the envelopes at paths. a value
+// there that isn't an envelope, or one that doesn't decrypt with key at that
+// path, is an error naming its path
+func (j *Json) DecryptFields(paths []string, key []byte) (*Json, FieldsReport, error) {
+	return j.editFields(paths, key, func(aead cipher.AEAD, path string, branch []string, node interface{}) (interface{}, error) {
+		envelope, ok := encryptedEnvelope(node)
+		if !ok {
+			return nil, errors.Errorf("value at %s is not an encrypted envelope", path)
+		}
+		return decryptEnvelope(aead, path, branch, envelope)
+	})
+}
+
+// DecryptAll is DecryptFields for every envelope in j, its report lists the
+// dotted paths of the envelopes in document order
+func (j *Json) DecryptAll(key []byte) (*Json, FieldsReport, error) {
+	report := FieldsReport{Processed: make([]string, 0), Skipped: make([]string, 0)}
+	if j.IsEmpty() {
+		return j, report, errors.New("empty json can't be decrypted")
+	}
+	aead, err := newFieldsCipher(key)
+	if err != nil {
+		return j, report, err
+	}
+	root, err := decryptAllRaw(aead, deepCopyRaw(j.value.Interface()), []string{}, &report)
+	if err != nil {
+		return j, report, err
+	}
+	result := wrapRaw(root)
+	result.settings = j.settings
+	return result, report, nil
+}
+
+func newFieldsCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encryption key")
+	}
+	return cipher.NewGCM(block)
+}
+
+// editFields replaces the values at paths of a copy of j by the results of edit
+func (j *Json) editFields(paths []string, key []byte, edit func(aead cipher.AEAD, path string, branch []string, node interface{}) (interface{}, error)) (*Json, FieldsReport, error) {
+	report := FieldsReport{Processed: make([]string, 0), Skipped: make([]string, 0)}
+	if j.IsEmpty() {
+		return j, report, errors.New("empty json has no fields")
+	}
+	aead, err := newFieldsCipher(key)
+	if err != nil {
+		return j, report, err
+	}
+	root := deepCopyRaw(j.value.Interface())
+	for _, path := range paths {
+		branch := ParseDottedPath(path)
+		node, ok := valueAtBranch(root, branch)
+		if !ok {
+			report.Skipped = append(report.Skipped, path)
+			continue
+		}
+		replacement, err := edit(aead, path, branch, node)
+		if err != nil {
+			return j, report, err
+		}
+		if root, err = applyPatchOperation(root, patchOperation{op: "replace", path: branch, value: replacement}); err != nil {
+			return j, report, errors.Wrapf(err, "can't replace %s", path)
+		}
+		report.Processed = append(report.Processed, path)
+	}
+	result := wrapRaw(root)
+	result.settings = j.settings
+	return result, report, nil
+}
+
+// encryptedEnvelope returns node if it is an envelope of EncryptFields
+func encryptedEnvelope(node interface{}) (map[string]interface{}, bool) {
+	envelope, ok := unwrapRaw(node).(map[string]interface{})
+	if !ok || len(envelope) != 3 {
+		return nil, false
+	}
+	version, _ := unwrapRaw(envelope["$enc"]).(string)
+	_, hasNonce := unwrapRaw(envelope["nonce"]).(string)
+	_, hasData := unwrapRaw(envelope["data"]).(string)
+	return envelope, version == encryptedVersion && hasNonce && hasData
+}
+
+// decryptEnvelope opens the envelope found at branch, displayed as path
+func decryptEnvelope(aead cipher.AEAD, path string, branch []string, envelope map[string]interface{}) (interface{}, error) {
+	nonce, err := base64.StdEncoding.DecodeString(unwrapRaw(envelope["nonce"]).(string))
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, errors.Errorf("can't decrypt %s: invalid nonce", path)
+	}
+	data, err := base64.StdEncoding.DecodeString(unwrapRaw(envelope["data"]).(string))
+	if err != nil {
+		return nil, errors.Errorf("can't decrypt %s: invalid data", path)
+	}
+	plaintext, err := aead.Open(nil, nonce, data, []byte(JSONPointer(branch)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't decrypt %s", path)
+	}
+	value, err := Parse(plaintext)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't decrypt %s", path)
+	}
+	return value.value.Interface(), nil
+}
+
+func decryptAllRaw(aead cipher.AEAD, node interface{}, branch []string, report *FieldsReport) (interface{}, error) {
+	if envelope, ok := encryptedEnvelope(node); ok {
+		value, err := decryptEnvelope(aead, displayPath(branch), branch, envelope)
+		if err != nil {
+			return nil, err
+		}
+		report.Processed = append(report.Processed, JoinDottedPath(branch))
+		return value, nil
+	}
+	var err error
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(container) {
+			if container[key], err = decryptAllRaw(aead, container[key], append(branch, key), report); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for idx, item := range container {
+			if container[idx], err = decryptAllRaw(aead, item, append(branch, strconv.Itoa(idx)), report); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return unwrapRaw(node), nil
+}
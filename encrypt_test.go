@@ -0,0 +1,86 @@
+package betterjson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+var fieldsKey = bytes.Repeat([]byte("k"), 32)
+
+func TestJson_EncryptDecryptFields(t *testing.T) {
+	user, _ := Parse([]byte(`{"id":7,"email":"ann@example.com","profile":{"ssn":"123","tags":["a"]},"phones":["555-1",{"n":"555-2"}]}`))
+	paths := []string{"email", "profile", "phones.1.n", "missing.path", "phones.5"}
+	encrypted, report, err := user.EncryptFields(paths, fieldsKey)
+	assert.True(t, err == nil)
+	println(encrypted.EncodeToStringOrDefault(""))
+	assert.Equal(t, []string{"email", "profile", "phones.1.n"}, report.Processed)
+	assert.Equal(t, []string{"missing.path", "phones.5"}, report.Skipped)
+	assert.Equal(t, "v1", encrypted.GetDottedPath("email.$enc").MustString())
+	assert.Equal(t, "v1", encrypted.GetDottedPath("phones.1.n.$enc").MustString())
+	assert.False(t, strings.Contains(encrypted.EncodeToStringOrDefault(""), "ann@example.com"))
+	assert.Equal(t, "ann@example.com", user.Get("email").MustString())
+
+	decrypted, report, err := encrypted.DecryptFields([]string{"email", "profile", "phones.1.n"}, fieldsKey)
+	assert.True(t, err == nil)
+	assert.True(t, decrypted.IsSameJSONWith(user))
+	assert.Equal(t, 3, len(report.Processed))
+
+	all, report, err := encrypted.DecryptAll(fieldsKey)
+	assert.True(t, err == nil)
+	assert.True(t, all.IsSameJSONWith(user))
+	assert.Equal(t, []string{"email", "phones.1.n", "profile"}, report.Processed)
+}
+
+func TestJson_DecryptFieldsFailures(t *testing.T) {
+	doc, _ := Parse([]byte(`{"a":{"b":"secret"},"plain":1}`))
+	encrypted, _, _ := doc.EncryptFields([]string{"a.b"}, fieldsKey)
+	_, _, err := encrypted.DecryptFields([]string{"a.b"}, bytes.Repeat([]byte("x"), 32))
+	println(err.Error())
+	assert.True(t, strings.HasPrefix(err.Error(), "can't decrypt a.b: "))
+	_, _, err = encrypted.DecryptAll(bytes.Repeat([]byte("x"), 32))
+	assert.True(t, strings.HasPrefix(err.Error(), "can't decrypt a.b: "))
+	_, _, err = encrypted.DecryptFields([]string{"plain"}, fieldsKey)
+	assert.Equal(t, "value at plain is not an encrypted envelope", err.Error())
+	_, _, err = doc.EncryptFields([]string{"a"}, []byte("short"))
+	assert.True(t, strings.HasPrefix(err.Error(), "invalid encryption key"))
+
+	encrypted.GetDottedPath("a").Get("b").Set("data", "AAAA")
+	_, _, err = encrypted.DecryptFields([]string{"a.b"}, fieldsKey)
+	assert.True(t, strings.HasPrefix(err.Error(), "can't decrypt a.b: "))
+}
+
+func TestJson_EncryptWholeDocument(t *testing.T) {
+	doc, _ := Parse([]byte(`[1,"two",{"three":3.0}]`))
+	encrypted, _, err := doc.EncryptFields([]string{""}, fieldsKey)
+	assert.True(t, err == nil)
+	assert.Equal(t, "v1", encrypted.Get("$enc").MustString())
+	decrypted, report, _ := encrypted.DecryptAll(fieldsKey)
+	assert.Equal(t, []string{""}, report.Processed)
+	assert.Equal(t, `[1,"two",{"three":3.0}]`, decrypted.EncodeToStringOrDefault(""))
+}
+
+func TestJson_EncryptedEnvelopesAreBoundToTheirPath(t *testing.T) {
+	doc, _ := Parse([]byte(`{"a":"secret a","b":"secret b","list":["x"]}`))
+	encrypted, _, err := doc.EncryptFields([]string{"a", "b", "list.0"}, fieldsKey)
+	assert.True(t, err == nil)
+
+	swapped, _ := Parse([]byte(encrypted.EncodeToStringOrDefault("")))
+	swapped.Set("a", encrypted.Get("b")).Set("b", encrypted.Get("a"))
+	_, _, err = swapped.DecryptFields([]string{"a"}, fieldsKey)
+	assert.True(t, err != nil)
+	println(err.Error())
+	_, _, err = swapped.DecryptAll(fieldsKey)
+	assert.True(t, err != nil)
+
+	other := NewJSONObject().Set("list", NewJSONArray().TryAdd("y").TryAdd(encrypted.GetDottedPath("list.0")))
+	_, _, err = other.DecryptFields([]string{"list.1"}, fieldsKey)
+	assert.True(t, err != nil)
+
+	// the same path in another document still decrypts
+	moved := NewJSONObject().Set("a", encrypted.Get("a"))
+	decrypted, _, err := moved.DecryptFields([]string{"a"}, fieldsKey)
+	assert.True(t, err == nil)
+	assert.Equal(t, "secret a", decrypted.Get("a").MustString())
+}
@@ -0,0 +1,75 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+)
+
+// rawEqual reports whether two raw nodes are deep equal, comparing numbers by
+// value so 1, 1.0 and 1e0 are all equal whatever their Go type. Go floats have
+// the value of their shortest literal, so float64(0.1) equals a parsed 0.1.
+// this is looser than IsSameJSONWith, whose digests tell 1 from 1.0 apart
+// like their encodings do
+func rawEqual(a, b interface{}) bool {
+	a, b = unwrapRaw(a), unwrapRaw(b)
+	switch left := a.(type) {
+	case map[string]interface{}:
+		right, ok := b.(map[string]interface{})
+		if !ok || len(left) != len(right) {
+			return false
+		}
+		for key, item := range left {
+			other, exists := right[key]
+			if !exists || !rawEqual(item, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		right, ok := b.([]interface{})
+		if !ok || len(left) != len(right) {
+			return false
+		}
+		for idx, item := range left {
+			if !rawEqual(item, right[idx]) {
+				return false
+			}
+		}
+		return true
+	}
+	leftNumber, leftIsNumber := rawNumber(a)
+	rightNumber, rightIsNumber := rawNumber(b)
+	if leftIsNumber || rightIsNumber {
+		return leftIsNumber && rightIsNumber && leftNumber.Cmp(rightNumber) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// rawNumber returns the exact value of a numeric leaf
+func rawNumber(node interface{}) (*big.Rat, bool) {
+	switch value := node.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(string(value))
+	case float64:
+		return shortestFloat(value, 64)
+	case float32:
+		return shortestFloat(float64(value), 32)
+	case int, int8, int16, int32, int64:
+		return new(big.Rat).SetInt64(reflect.ValueOf(value).Int()), true
+	case uint, uint8, uint16, uint32, uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(reflect.ValueOf(value).Uint())), true
+	}
+	return nil, false
+}
+
+
+// shortestFloat is the value of the shortest literal of a float of bits bits,
+// the number it was most likely written as, see floatNumber
+func shortestFloat(value float64, bits int) (*big.Rat, bool) {
+	literal, ok := floatNumber(value, bits).(json.Number)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).SetString(string(literal))
+}
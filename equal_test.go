@@ -0,0 +1,29 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawEqual(t *testing.T) {
+	assert.True(t, rawEqual(json.Number("1"), 1))
+	assert.True(t, rawEqual(json.Number("1.0"), 1.0))
+	assert.True(t, rawEqual(json.Number("1e3"), uint64(1000)))
+	assert.True(t, rawEqual(json.Number("0.1"), json.Number("0.10")))
+	// Go floats compare as their shortest literal
+	assert.True(t, rawEqual(json.Number("0.1"), 0.1))
+	assert.True(t, rawEqual(json.Number("0.1"), float32(0.1)))
+	assert.True(t, rawEqual(json.Number("19.99"), 19.99))
+	assert.True(t, !rawEqual(json.Number("0.1"), 0.1000000001))
+	assert.True(t, !rawEqual(json.Number("0.30000000000000004"), 0.3))
+	assert.True(t, !rawEqual(json.Number("1"), "1"))
+	assert.True(t, rawEqual(nil, nil))
+	assert.True(t, !rawEqual(nil, false))
+	a := NewJSONObject().Set("a", NewJSONArray().TryAdd(1).TryAdd(NewJSONObject().Set("b", 2.0)))
+	b, err := Parse([]byte(`{"a":[1.0,{"b":2}]}`))
+	assert.True(t, err == nil)
+	assert.True(t, rawEqual(a.Interface(), b.Interface()))
+	assert.True(t, !rawEqual(a.Interface(), NewJSONObject().Set("a", NewJSONArray().TryAdd(1)).Interface()))
+	assert.True(t, rawEqual([]byte("x"), []byte("x")))
+}
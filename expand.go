@@ -0,0 +1,160 @@
+package betterjson
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Expand is the reverse of Extract: spec maps keys of the flat object j to
+// dotted destination paths in a new document. intermediate objects are
+// created, or arrays when all segments below a path are indexes; gaps in those
+// arrays are null. keys of spec that j doesn't have are left out, and the
+// values are copies:
+//    spec := map[string]string{"id": "data.id", "sku": "data.lines.0.sku"}
+//    payload, err := order.Expand(spec) // {"data":{"id":..,"lines":[{"sku":..}]}}
+//
+// destinations where one is inside the other, like "a" and "a.b", are an
+// error naming both keys, and so are array indexes beyond the number of
+// entries of spec, which would only make room for nulls
+func (j *Json) Expand(spec map[string]string) (*Json, error) {
+	object, ok := j.objectValue()
+	if !ok {
+		return NewEmpty(), errors.Errorf("can't expand %s", inputKind(j))
+	}
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	all, present := newExpandNode(), newExpandNode()
+	for _, key := range keys {
+		branch := ParseDottedPath(spec[key])
+		if err := all.insert(branch, key); err != nil {
+			return NewEmpty(), err
+		}
+		if _, ok := object[key]; ok {
+			present.insert(branch, key)
+		}
+	}
+	if err := all.checkIndexes(len(spec), []string{}); err != nil {
+		return NewEmpty(), err
+	}
+	return wrapRaw(present.build(object)), nil
+}
+
+// expandNode is a destination path of Expand, either a leaf holding the value
+// of key or a container of children
+type expandNode struct {
+	key      string
+	leaf     bool
+	children map[string]*expandNode
+}
+
+func newExpandNode() *expandNode {
+	return &expandNode{children: make(map[string]*expandNode)}
+}
+
+func (node *expandNode) insert(branch []string, key string) error {
+	for _, segment := range branch {
+		if node.leaf {
+			return errors.Errorf("expand destinations of %q and %q conflict", node.key, key)
+		}
+		child, ok := node.children[segment]
+		if !ok {
+			child = newExpandNode()
+			node.children[segment] = child
+		}
+		node = child
+	}
+	if node.leaf {
+		return errors.Errorf("expand destinations of %q and %q conflict", node.key, key)
+	}
+	if len(node.children) > 0 {
+		return errors.Errorf("expand destinations of %q and %q conflict", node.anyKey(), key)
+	}
+	node.leaf = true
+	node.key = key
+	return nil
+}
+
+// anyKey returns the first key of a leaf below node
+func (node *expandNode) anyKey() string {
+	if node.leaf {
+		return node.key
+	}
+	return node.children[sortedChildren(node.children)[0]].anyKey()
+}
+
+// arrayLength is the length of the array node builds, false when it builds
+// an object
+func (node *expandNode) arrayLength() (int, bool) {
+	if len(node.children) == 0 {
+		return 0, false
+	}
+	length := 0
+	for segment := range node.children {
+		idx, ok := arrayIndexSegment(segment)
+		if !ok {
+			return 0, false
+		}
+		if idx >= length {
+			length = idx + 1
+		}
+	}
+	return length, true
+}
+
+// checkIndexes fails for an array below node, at branch, with an index
+// beyond limit
+func (node *expandNode) checkIndexes(limit int, branch []string) error {
+	if length, isArray := node.arrayLength(); isArray && length-1 > limit {
+		return errors.Errorf("expand index %d at %s is beyond the %d entries of the spec",
+			length-1, displayPath(branch), limit)
+	}
+	for _, segment := range sortedChildren(node.children) {
+		if err := node.children[segment].checkIndexes(limit, append(branch[:len(branch):len(branch)], segment)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedChildren(children map[string]*expandNode) []string {
+	segments := make([]string, 0, len(children))
+	for segment := range children {
+		segments = append(segments, segment)
+	}
+	sort.Strings(segments)
+	return segments
+}
+
+func (node *expandNode) build(object map[string]interface{}) interface{} {
+	if node.leaf {
+		return deepCopyRaw(object[node.key])
+	}
+	length, isArray := node.arrayLength()
+	if !isArray {
+		result := make(map[string]interface{}, len(node.children))
+		for segment, child := range node.children {
+			result[segment] = child.build(object)
+		}
+		return result
+	}
+	result := make([]interface{}, length)
+	for segment, child := range node.children {
+		idx, _ := arrayIndexSegment(segment)
+		result[idx] = child.build(object)
+	}
+	return result
+}
+
+// arrayIndexSegment parses segment as an array index written without leading zeros
+func arrayIndexSegment(segment string) (int, bool) {
+	idx, err := strconv.Atoi(segment)
+	if err != nil || idx < 0 || strconv.Itoa(idx) != segment {
+		return 0, false
+	}
+	return idx, true
+}
@@ -0,0 +1,65 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_Expand(t *testing.T) {
+	order, _ := Parse([]byte(`{"id":"o1","sku":"x","sku2":"y","email":"a@b.c","meta":{"k":"v"},"unused":1}`))
+	spec := map[string]string{
+		"id":    "data.id",
+		"sku":   "data.lines.0.sku",
+		"sku2":  "data.lines.2.sku",
+		"email": "data.customer.email",
+		"meta":  "data.meta",
+		"gone":  "data.gone",
+	}
+	payload, err := order.Expand(spec)
+	assert.True(t, err == nil)
+	assert.True(t, payload.DigestJSONForEqual() == `{"data":{"customer":{"email":"a@b.c"},"id":"o1","lines":[{"sku":"x"},null,{"sku":"y"}],"meta":{"k":"v"}}}`)
+	payload.GetDottedPath("data.meta").Set("k", "changed")
+	assert.True(t, order.GetDottedPath("meta.k").MustString() == "v")
+
+	matrix, err := order.Expand(map[string]string{"id": "0.1", "sku": "1.0", "email": "01"})
+	assert.True(t, err == nil)
+	assert.True(t, matrix.DigestJSONForEqual() == `{"0":[null,"o1"],"01":"a@b.c","1":["x"]}`)
+	grid, err := order.Expand(map[string]string{"id": "rows.0.0", "sku": "rows.1.1"})
+	assert.True(t, err == nil)
+	assert.True(t, grid.DigestJSONForEqual() == `{"rows":[["o1"],[null,"x"]]}`)
+
+	// Extract undoes Expand
+	back, err := payload.Extract(map[string]string{"id": "data.id", "sku": "data.lines.0.sku"})
+	assert.True(t, err == nil && back.DigestJSONForEqual() == `{"id":"o1","sku":"x"}`)
+}
+
+func TestJson_ExpandConflicts(t *testing.T) {
+	order, _ := Parse([]byte(`{"a":1,"b":2}`))
+	_, err := order.Expand(map[string]string{"a": "x", "b": "x.y"})
+	assert.True(t, err != nil && err.Error() == `expand destinations of "a" and "b" conflict`)
+	_, err = order.Expand(map[string]string{"a": "x.y.z", "b": "x"})
+	assert.True(t, err != nil && err.Error() == `expand destinations of "a" and "b" conflict`)
+	_, err = order.Expand(map[string]string{"a": "same", "b": "same"})
+	assert.True(t, err != nil && err.Error() == `expand destinations of "a" and "b" conflict`)
+	// keys missing from the object conflict too, the spec itself is wrong
+	_, err = order.Expand(map[string]string{"a": "x", "missing": "x.y"})
+	assert.True(t, err != nil)
+	_, err = NewJSONArray().Expand(map[string]string{})
+	assert.True(t, err != nil && err.Error() == "can't expand array")
+}
+
+func TestJson_ExpandIndexLimit(t *testing.T) {
+	order, _ := Parse([]byte(`{"k":1,"j":2}`))
+	_, err := order.Expand(map[string]string{"k": "a.9999999999"})
+	assert.True(t, err != nil)
+	println(err.Error())
+	_, err = order.Expand(map[string]string{"k": "a.0.b.3", "j": "c"})
+	assert.Equal(t, "expand index 3 at a.0.b is beyond the 2 entries of the spec", err.Error())
+	expanded, err := order.Expand(map[string]string{"k": "a.2", "j": "a.0"})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"a":[2,null,1]}`, expanded.EncodeToStringOrDefault(""))
+	// a key next to the index makes an object, which any index fits into
+	expanded, err = order.Expand(map[string]string{"k": "a.9999999999", "j": "a.x"})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"a":{"9999999999":1,"x":2}}`, expanded.EncodeToStringOrDefault(""))
+}
@@ -0,0 +1,65 @@
+package betterjson
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExtractOptions configures ExtractWithOptions
+type ExtractOptions struct {
+	// OmitMissing leaves out output keys whose source path is missing instead
+	// of setting them to null
+	OmitMissing bool
+}
+
+// MissingPathsError lists every source path of an Extract that didn't resolve
+type MissingPathsError struct {
+	Paths []string
+}
+
+func (e *MissingPathsError) Error() string {
+	return fmt.Sprintf("paths %s are missing", strings.Join(e.Paths, ", "))
+}
+
+// Extract builds a new object from spec, which maps output keys to dotted
+// source paths in j. dotted output keys create nested objects, and source
+// paths may go through arrays by index. the values are copies:
+//    spec := map[string]string{"id": "data.object.id", "customer.email": "data.object.receipt_email"}
+//    order, err := webhook.Extract(spec)
+//
+// missing source paths give null and are all listed in a MissingPathsError,
+// which comes with the result still built
+func (j *Json) Extract(spec map[string]string) (*Json, error) {
+	return j.ExtractWithOptions(spec, ExtractOptions{})
+}
+
+// ExtractWithOptions is Extract configured by opts
+func (j *Json) ExtractWithOptions(spec map[string]string, opts ExtractOptions) (*Json, error) {
+	outputs := make([]string, 0, len(spec))
+	for output := range spec {
+		outputs = append(outputs, output)
+	}
+	// sorted, so a key that is also the parent of another one resolves the same way each time
+	sort.Strings(outputs)
+	result := NewJSONObject()
+	missing := make([]string, 0)
+	for _, output := range outputs {
+		value, ok := j.lookupPath(ParseDottedPath(spec[output]))
+		if !ok {
+			missing = append(missing, spec[output])
+			if opts.OmitMissing {
+				continue
+			}
+		}
+		var data interface{}
+		if ok {
+			data = deepCopyRaw(value.value.Interface())
+		}
+		result.setPath(ParseDottedPath(output), data)
+	}
+	if len(missing) > 0 {
+		return result, &MissingPathsError{Paths: missing}
+	}
+	return result, nil
+}
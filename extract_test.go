@@ -0,0 +1,50 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const extractFixture = `{"data":{"object":{"id":"ch_1","amount":500,"receipt_email":"a@b.c","lines":[{"sku":"x"}],"meta":{"k":"v"}}},"type":"charge.succeeded"}`
+
+func TestJson_Extract(t *testing.T) {
+	webhook, _ := Parse([]byte(extractFixture))
+	spec := map[string]string{
+		"id":             "data.object.id",
+		"event":          "type",
+		"customer.email": "data.object.receipt_email",
+		"customer.meta":  "data.object.meta",
+		"firstSku":       "data.object.lines.0.sku",
+	}
+	order, err := webhook.Extract(spec)
+	assert.True(t, err == nil)
+	assert.True(t, order.DigestJSONForEqual() == `{"customer":{"email":"a@b.c","meta":{"k":"v"}},"event":"charge.succeeded","firstSku":"x","id":"ch_1"}`)
+	order.GetDottedPath("customer.meta").Set("k", "changed")
+	assert.True(t, webhook.GetDottedPath("data.object.meta.k").MustString() == "v")
+
+	whole, err := webhook.Extract(map[string]string{"raw": ""})
+	assert.True(t, err == nil && whole.Get("raw").IsSameJSONWith(webhook))
+}
+
+func TestJson_ExtractMissing(t *testing.T) {
+	webhook, _ := Parse([]byte(extractFixture))
+	spec := map[string]string{
+		"id":       "data.object.id",
+		"refund":   "data.object.refund.id",
+		"lastSku":  "data.object.lines.3.sku",
+		"nested.x": "nope",
+	}
+	order, err := webhook.Extract(spec)
+	missingErr, ok := err.(*MissingPathsError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"data.object.lines.3.sku", "nope", "data.object.refund.id"}, missingErr.Paths)
+	println(err.Error())
+	assert.True(t, order.DigestJSONForEqual() == `{"id":"ch_1","lastSku":null,"nested":{"x":null},"refund":null}`)
+
+	order, err = webhook.ExtractWithOptions(spec, ExtractOptions{OmitMissing: true})
+	assert.True(t, err != nil && len(err.(*MissingPathsError).Paths) == 3)
+	assert.True(t, order.DigestJSONForEqual() == `{"id":"ch_1"}`)
+
+	order, err = NewEmpty().Extract(map[string]string{"id": "id"})
+	assert.True(t, err != nil && order.DigestJSONForEqual() == `{"id":null}`)
+}
@@ -0,0 +1,210 @@
+package betterjson
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FieldMaskError is a syntax error of a field mask, at the 0-based byte
+// Offset of the mask
+type FieldMaskError struct {
+	Offset  int
+	Message string
+}
+
+func (e *FieldMaskError) Error() string {
+	return fmt.Sprintf("field mask: %s at position %d", e.Message, e.Offset)
+}
+
+// fieldMask selects members of an object by name, a nil sub mask selects the
+// whole value of the member
+type fieldMask map[string]fieldMask
+
+// ApplyFieldMask returns a copy of j with only the fields mask selects, in the
+// syntax of partial responses: a comma separated list of field names, where
+// "name(a,b)" selects a and b inside name and "name/a" is short for "name(a)".
+// masks apply to every item of arrays, fields that aren't there are left out,
+// and so are values that a sub selection is applied to but that aren't objects
+// or arrays:
+//    summary, err := order.ApplyFieldMask("id,customer(name,address/city),items(sku,qty)")
+// a malformed mask is a *FieldMaskError with the position of the problem
+func (j *Json) ApplyFieldMask(mask string) (*Json, error) {
+	if j.IsEmpty() {
+		return NewEmpty(), errors.New("can't apply a field mask to empty json")
+	}
+	parsed, err := parseFieldMask(mask)
+	if err != nil {
+		return NewEmpty(), err
+	}
+	root := j.Interface()
+	switch unwrapRaw(root).(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return NewEmpty(), errors.Errorf("can't apply a field mask to %s", kindName(unwrapRaw(root)))
+	}
+	result, _ := parsed.apply(root)
+	return wrapRaw(result), nil
+}
+
+// apply returns the part of node mask selects and whether there is one
+func (mask fieldMask) apply(node interface{}) (interface{}, bool) {
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		if container == nil {
+			return nil, false
+		}
+		result := make(map[string]interface{}, len(mask))
+		for name, sub := range mask {
+			item, ok := container[name]
+			if !ok {
+				continue
+			}
+			if sub == nil {
+				result[name] = deepCopyRaw(item)
+			} else if selected, ok := sub.apply(item); ok {
+				result[name] = selected
+			}
+		}
+		return result, true
+	case []interface{}:
+		if container == nil {
+			return nil, false
+		}
+		result := make([]interface{}, 0, len(container))
+		for _, item := range container {
+			if selected, ok := mask.apply(item); ok {
+				result = append(result, selected)
+			}
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// fieldMaskParser is a recursive descent parser of
+//    list  = field { "," field }
+//    field = name { "/" name } [ "(" list ")" ]
+type fieldMaskParser struct {
+	mask string
+	pos  int
+}
+
+func parseFieldMask(mask string) (fieldMask, error) {
+	p := &fieldMaskParser{mask: mask}
+	result, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.mask) {
+		if p.mask[p.pos] == ')' {
+			return nil, p.fail("unexpected )")
+		}
+		return nil, p.fail(fmt.Sprintf("unexpected %q", p.mask[p.pos]))
+	}
+	return result, nil
+}
+
+func (p *fieldMaskParser) fail(message string) error {
+	return &FieldMaskError{Offset: p.pos, Message: message}
+}
+
+func (p *fieldMaskParser) skipSpace() {
+	for p.pos < len(p.mask) && strings.IndexByte(" \t\r\n", p.mask[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *fieldMaskParser) parseList() (fieldMask, error) {
+	result := make(fieldMask)
+	for {
+		if err := p.parseField(result); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos == len(p.mask) || p.mask[p.pos] != ',' {
+			return result, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *fieldMaskParser) parseField(into fieldMask) error {
+	path := make([]string, 0, 1)
+	for {
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		path = append(path, name)
+		if p.pos == len(p.mask) || p.mask[p.pos] != '/' {
+			break
+		}
+		p.pos++
+	}
+	var sub fieldMask
+	p.skipSpace()
+	if p.pos < len(p.mask) && p.mask[p.pos] == '(' {
+		open := p.pos
+		p.pos++
+		var err error
+		if sub, err = p.parseList(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.pos == len(p.mask) || p.mask[p.pos] != ')' {
+			if p.pos == len(p.mask) {
+				return &FieldMaskError{Offset: open, Message: "unclosed ("}
+			}
+			return p.fail(fmt.Sprintf("expected , or ) instead of %q", p.mask[p.pos]))
+		}
+		p.pos++
+	}
+	for _, name := range path[:len(path)-1] {
+		next, exists := into[name]
+		if exists && next == nil {
+			// the whole value is selected already
+			return nil
+		}
+		if !exists {
+			next = make(fieldMask)
+			into[name] = next
+		}
+		into = next
+	}
+	into.merge(path[len(path)-1], sub)
+	return nil
+}
+
+// merge adds the selection of sub under name, the whole value winning
+func (mask fieldMask) merge(name string, sub fieldMask) {
+	existing, exists := mask[name]
+	switch {
+	case !exists:
+		mask[name] = sub
+	case existing == nil:
+	case sub == nil:
+		mask[name] = nil
+	default:
+		for key, inner := range sub {
+			existing.merge(key, inner)
+		}
+	}
+}
+
+func (p *fieldMaskParser) parseName() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.mask) && strings.IndexByte(",()/ \t\r\n", p.mask[p.pos]) < 0 {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos == len(p.mask) {
+			return "", p.fail("expected a field name at the end")
+		}
+		return "", p.fail(fmt.Sprintf("expected a field name instead of %q", p.mask[p.pos]))
+	}
+	return p.mask[start:p.pos], nil
+}
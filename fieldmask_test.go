@@ -0,0 +1,98 @@
+package betterjson
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_ApplyFieldMask(t *testing.T) {
+	js, err := Parse([]byte(`{"id":1,"name":"order","secret":"x","address":{"city":"Paris","zip":"75001","street":"Rue"},` +
+		`"items":[{"sku":"a","qty":1},{"sku":"b","qty":2},3,{"qty":4}]}`))
+	assert.True(t, err == nil)
+	masked, err := js.ApplyFieldMask("id, name,address(city,zip),items(sku),missing")
+	assert.True(t, err == nil)
+	println(masked.EncodeToStringOrDefault(""))
+	assert.Equal(t, `{"address":{"city":"Paris","zip":"75001"},"id":1,"items":[{"sku":"a"},{"sku":"b"},{}],"name":"order"}`,
+		masked.EncodeToStringOrDefault(""))
+	masked.Get("address").Set("city", "Lyon")
+	assert.Equal(t, "Paris", js.GetPath("address", "city").MustString())
+
+	shorthand, err := js.ApplyFieldMask("address/city,items/qty,address(zip)")
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"address":{"city":"Paris","zip":"75001"},"items":[{"qty":1},{"qty":2},{"qty":4}]}`,
+		shorthand.EncodeToStringOrDefault(""))
+	whole, err := js.ApplyFieldMask("address(city),address")
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"address":{"city":"Paris","street":"Rue","zip":"75001"}}`, whole.EncodeToStringOrDefault(""))
+	scalar, err := js.ApplyFieldMask("name(first)")
+	assert.True(t, err == nil)
+	assert.Equal(t, `{}`, scalar.EncodeToStringOrDefault(""))
+
+	items, err := js.Get("items").ApplyFieldMask("sku")
+	assert.True(t, err == nil)
+	assert.Equal(t, `[{"sku":"a"},{"sku":"b"},{}]`, items.EncodeToStringOrDefault(""))
+
+	_, err = NewString("x").ApplyFieldMask("a")
+	assert.True(t, err != nil)
+	_, err = NewEmpty().ApplyFieldMask("a")
+	assert.True(t, err != nil)
+}
+
+func TestJson_ApplyFieldMaskErrors(t *testing.T) {
+	js := NewJSONObject()
+	for mask, offset := range map[string]int{
+		"":           0,
+		"id,":        3,
+		"id,,name":   3,
+		"a(b":        1,
+		"a(b,c(d)":   1,
+		"a)":         1,
+		"a(b))":      4,
+		"a()":        2,
+		"a/":         2,
+		"a b":        2,
+		"a(b c)":     4,
+		" id , (x)":  6,
+	} {
+		_, err := js.ApplyFieldMask(mask)
+		maskErr, ok := err.(*FieldMaskError)
+		assert.True(t, ok, mask)
+		if ok {
+			println(maskErr.Error())
+			assert.Equal(t, offset, maskErr.Offset, mask)
+		}
+	}
+}
+
+func TestJson_ApplyFieldMaskNeverPanics(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "0", ",", "(", ")", "/", " "}
+	for i := 0; i < 5000; i++ {
+		doc := wrapRaw(randomAtDocument(r, 0))
+		var mask strings.Builder
+		for n := r.Intn(12); n > 0; n-- {
+			mask.WriteString(alphabet[r.Intn(len(alphabet))])
+		}
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					t.Fatalf("ApplyFieldMask(%q) panicked: %v", mask.String(), recovered)
+				}
+			}()
+			masked, err := doc.ApplyFieldMask(mask.String())
+			if maskErr, ok := err.(*FieldMaskError); ok {
+				assert.True(t, maskErr.Offset >= 0 && maskErr.Offset <= len(mask.String()), mask.String())
+				assert.True(t, masked.IsEmpty())
+				return
+			}
+			if err == nil {
+				// masking again with the same mask changes nothing
+				again, err := masked.ApplyFieldMask(mask.String())
+				assert.True(t, err == nil)
+				assert.True(t, masked.IsSameJSONWith(again), mask.String())
+			}
+		}()
+	}
+}
@@ -0,0 +1,77 @@
+package betterjson
+
+import (
+	"regexp"
+)
+
+// FilterKeysMatching returns a copy of the object j holding only its keys that
+// re matches, an empty object when none does or j isn't an object:
+//    public := record.FilterKeysMatching(regexp.MustCompile(`^(id|name|created_at)$`))
+func (j *Json) FilterKeysMatching(re *regexp.Regexp) *Json {
+	result := make(map[string]interface{})
+	if object, ok := j.objectValue(); ok {
+		for key, item := range object {
+			if re.MatchString(key) {
+				result[key] = deepCopyRaw(item)
+			}
+		}
+	}
+	return wrapRaw(result)
+}
+
+// FilterKeysMatchingDeep is FilterKeysMatching for nested objects too: values
+// of matching keys are kept whole, other objects and arrays only keep what
+// matches below them and are dropped when nothing does. j can be an array
+func (j *Json) FilterKeysMatchingDeep(re *regexp.Regexp) *Json {
+	if j.IsEmpty() {
+		return NewJSONObject()
+	}
+	filtered, ok := filterKeysDeep(j.value.Interface(), re)
+	if !ok {
+		if _, isArray := unwrapRaw(j.value.Interface()).([]interface{}); isArray {
+			return NewJSONArray()
+		}
+		return NewJSONObject()
+	}
+	return wrapRaw(filtered)
+}
+
+// filterKeysDeep returns the filtered copy of node, false when nothing in it matched
+func filterKeysDeep(node interface{}, re *regexp.Regexp) (interface{}, bool) {
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for key, item := range container {
+			if re.MatchString(key) {
+				result[key] = deepCopyRaw(item)
+			} else if filtered, ok := filterKeysDeep(item, re); ok {
+				result[key] = filtered
+			}
+		}
+		return result, len(result) > 0
+	case []interface{}:
+		result := make([]interface{}, 0)
+		for _, item := range container {
+			if filtered, ok := filterKeysDeep(item, re); ok {
+				result = append(result, filtered)
+			}
+		}
+		return result, len(result) > 0
+	}
+	return nil, false
+}
+
+// StringsMatching returns every string value re matches with its path, in
+// document order: object keys sorted, array items by index
+func (j *Json) StringsMatching(re *regexp.Regexp) []PathValue {
+	matches := make([]PathValue, 0)
+	if j.IsEmpty() {
+		return matches
+	}
+	walkLeavesInOrder(j.value.Interface(), make([]string, 0, 8), func(branch []string, leaf interface{}) {
+		if value, ok := leaf.(string); ok && re.MatchString(value) {
+			matches = append(matches, PathValue{Path: JoinDottedPath(branch), Value: wrapRaw(value)})
+		}
+	})
+	return matches
+}
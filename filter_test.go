@@ -0,0 +1,44 @@
+package betterjson_test
+
+import (
+	"regexp"
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/zoowii/betterjson"
+	"github.com/zoowii/betterjson/betterjsontest"
+)
+
+func TestJson_FilterKeysMatching(t *testing.T) {
+	record, _ := betterjson.Parse([]byte(`{"id":1,"name":"a","password":"x","meta":{"id":2,"secret":"y"}}`))
+	public := record.FilterKeysMatching(regexp.MustCompile(`^(id|name|meta)$`))
+	betterjsontest.AssertEqualJSON(t, `{"id":1,"meta":{"id":2,"secret":"y"},"name":"a"}`, public)
+	public.Get("meta").Set("id", 3)
+	betterjsontest.AssertPathEquals(t, record, "meta.id", 2)
+	betterjsontest.AssertEqualJSON(t, `{}`, record.FilterKeysMatching(regexp.MustCompile(`^nothing$`)))
+	betterjsontest.AssertEqualJSON(t, `{}`, betterjson.NewJSONArray().FilterKeysMatching(regexp.MustCompile(`.`)))
+}
+
+func TestJson_FilterKeysMatchingDeep(t *testing.T) {
+	record, _ := betterjson.Parse([]byte(`{"public_id":1,"user":{"public_name":"a","password":"x","devices":[{"public_os":"linux","token":"t"},{"token":"u"},"plain"]},"internal":{"cost":3},"public_tags":{"token":"kept whole"}}`))
+	re := regexp.MustCompile(`^public_`)
+	filtered := record.FilterKeysMatchingDeep(re)
+	betterjsontest.AssertEqualJSON(t, `{"public_id":1,"public_tags":{"token":"kept whole"},"user":{"devices":[{"public_os":"linux"}],"public_name":"a"}}`, filtered)
+	betterjsontest.AssertEqualJSON(t, `{}`, record.FilterKeysMatchingDeep(regexp.MustCompile(`^nothing$`)))
+
+	list, _ := betterjson.Parse([]byte(`[{"public_a":1},{"b":2},[{"public_c":3}]]`))
+	betterjsontest.AssertEqualJSON(t, `[{"public_a":1},[{"public_c":3}]]`, list.FilterKeysMatchingDeep(re))
+	betterjsontest.AssertEqualJSON(t, `[]`, list.FilterKeysMatchingDeep(regexp.MustCompile(`^nothing$`)))
+}
+
+func TestJson_StringsMatching(t *testing.T) {
+	payload, _ := betterjson.Parse([]byte(`{"b":"mail bob@example.com","a":[1,"x@y.io",{"c":"none"}],"d":{"e":"z@example.com"},"f":"@"}`))
+	matches := payload.StringsMatching(regexp.MustCompile(`\w+@\w+\.\w+`))
+	paths := make([]string, 0)
+	for _, match := range matches {
+		paths = append(paths, match.Path)
+	}
+	assert.Equal(t, []string{"a.1", "b", "d.e"}, paths)
+	assert.True(t, matches[0].Value.MustString() == "x@y.io")
+	assert.True(t, len(payload.StringsMatching(regexp.MustCompile(`^nothing$`))) == 0)
+	assert.True(t, len(betterjson.NewEmpty().StringsMatching(regexp.MustCompile(`.`))) == 0)
+}
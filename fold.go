@@ -0,0 +1,81 @@
+package betterjson
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GetFold is Get matching key case-insensitively, so "userid" finds
+// "UserId". a key matching exactly is preferred, otherwise the first
+// matching key in sorted order wins:
+//    id := payload.GetFold("userId").MustInt64()
+func (j *Json) GetFold(key string) *Json {
+	folded, _ := j.foldKey(key)
+	return j.Get(folded)
+}
+
+// CheckGetFold is CheckGet matching key like GetFold
+func (j *Json) CheckGetFold(key string) *Json {
+	folded, _ := j.foldKey(key)
+	return j.CheckGet(folded)
+}
+
+// CheckGetFoldE is CheckGetFold returning an error instead of picking one
+// when several keys differ from key only by case and none matches exactly
+func (j *Json) CheckGetFoldE(key string) (*Json, error) {
+	folded, matches := j.foldKey(key)
+	if len(matches) > 1 {
+		return NewEmpty(), errors.Errorf("key %q is ambiguous, it matches %s", key, strings.Join(matches, ", "))
+	}
+	return j.CheckGet(folded), nil
+}
+
+// ContainsKeyFold is ContainsKey matching key like GetFold
+func (j *Json) ContainsKeyFold(key string) bool {
+	return !j.CheckGetFold(key).IsEmpty()
+}
+
+// GetPathFold is GetPath matching every key of branch like GetFold
+func (j *Json) GetPathFold(branch ...string) *Json {
+	jin := j
+	for _, p := range branch {
+		jin = jin.GetFold(p)
+	}
+	return jin
+}
+
+// documentKey is the key of j Get and CheckGet look up for key, folded
+// when the document has the CaseInsensitiveKeys option
+func (j *Json) documentKey(key string) string {
+	if j.settings == nil || !j.settings.options.CaseInsensitiveKeys {
+		return key
+	}
+	folded, _ := j.foldKey(key)
+	return folded
+}
+
+// foldKey returns the key of object j matching key case-insensitively, and
+// all the matching keys in sorted order when there is no exact match. key
+// itself is returned when nothing matches
+func (j *Json) foldKey(key string) (string, []string) {
+	object, ok := j.objectValue()
+	if !ok {
+		return key, nil
+	}
+	if _, ok := object[key]; ok {
+		return key, nil
+	}
+	matches := make([]string, 0)
+	for candidate := range object {
+		if strings.EqualFold(candidate, key) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return key, nil
+	}
+	sort.Strings(matches)
+	return matches[0], matches
+}
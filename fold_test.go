@@ -0,0 +1,52 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFold(t *testing.T) {
+	js, _ := Parse([]byte(`{"UserId":7,"Profile":{"DisplayName":"ann","Tags":["a"]},"name":"exact","NAME":"upper"}`))
+	assert.Equal(t, int64(7), js.GetFold("userid").MustInt64())
+	assert.Equal(t, int64(7), js.GetFold("USERID").MustInt64())
+	assert.True(t, js.Get("userid").IsNull())
+	assert.Equal(t, "exact", js.GetFold("name").MustString())
+	assert.Equal(t, "upper", js.GetFold("NAME").MustString())
+	assert.True(t, js.ContainsKeyFold("profile"))
+	assert.True(t, !js.ContainsKeyFold("missing"))
+	assert.True(t, js.CheckGetFold("missing").IsEmpty())
+	assert.Equal(t, "ann", js.GetPathFold("profile", "displayname").MustString())
+	assert.Equal(t, 1, len(js.GetPathFold("PROFILE", "tags").MustArray()))
+	assert.True(t, NewEmpty().CheckGetFold("a").IsEmpty())
+
+	js.GetFold("profile").Set("DisplayName", "bob")
+	assert.Equal(t, "bob", js.GetPath("Profile", "DisplayName").MustString())
+}
+
+func TestGetFoldAmbiguity(t *testing.T) {
+	js, _ := Parse([]byte(`{"userId":1,"UserID":2,"userid":3}`))
+	assert.Equal(t, int64(3), js.GetFold("userid").MustInt64())
+	assert.Equal(t, int64(2), js.GetFold("USERID").MustInt64())
+	item, err := js.CheckGetFoldE("userid")
+	assert.True(t, err == nil)
+	assert.Equal(t, int64(3), item.MustInt64())
+	item, err = js.CheckGetFoldE("USERID")
+	println(err.Error())
+	assert.True(t, item.IsEmpty())
+	assert.Equal(t, `key "USERID" is ambiguous, it matches UserID, userId, userid`, err.Error())
+	item, err = js.CheckGetFoldE("other")
+	assert.True(t, err == nil && item.IsEmpty())
+}
+
+func TestCaseInsensitiveKeysOption(t *testing.T) {
+	js, _ := Parse([]byte(`{"User":{"EMail":"a@b.c"},"id":1,"ID":2}`))
+	assert.True(t, js.GetPath("user", "email").IsNull())
+	js.WithOptions(Options{PanicOnMust: true, CaseInsensitiveKeys: true})
+	assert.Equal(t, "a@b.c", js.GetPath("user", "email").MustString())
+	assert.Equal(t, "a@b.c", js.CheckGet("USER").CheckGet("email").MustString())
+	assert.True(t, js.ContainsKey("uSeR"))
+	assert.Equal(t, int64(1), js.Get("id").MustInt64())
+	assert.Equal(t, int64(2), js.Get("ID").MustInt64())
+	js.Get("user").Set("EMail", "x@y.z")
+	assert.Equal(t, `{"ID":2,"User":{"EMail":"x@y.z"},"id":1}`, js.EncodeToStringOrDefault(""))
+}
@@ -0,0 +1,144 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// GetPathOr returns the value at branch, or fallback when it is missing or
+// null. segments may be array indexes like GetDottedPath's
+func (j *Json) GetPathOr(fallback *Json, branch ...string) *Json {
+	item, ok := j.lookupPath(branch)
+	if !ok || item.IsNull() {
+		if fallback == nil {
+			return NewEmpty()
+		}
+		return fallback
+	}
+	return item
+}
+
+// StringAt returns the value at branch as a string, or default_ when it is
+// missing, null or a container. numbers and bools are converted to their json
+// text. like the other typed getters it never panics and needs no error check:
+//    region := req.StringAt("us-east-1", "settings", "region")
+//    limit := req.IntAt(100, "paging", "limit") // also 5 for "limit": "5"
+func (j *Json) StringAt(default_ string, branch ...string) string {
+	node, ok := j.leafAt(branch)
+	if !ok {
+		return default_
+	}
+	if _, isContainer := node.(map[string]interface{}); isContainer {
+		return default_
+	}
+	if _, isContainer := node.([]interface{}); isContainer {
+		return default_
+	}
+	s, err := scalarString(node)
+	if err != nil {
+		return default_
+	}
+	return s
+}
+
+// IntAt is StringAt for int values. strings holding a number are converted,
+// numbers with a fraction and values out of range give default_
+func (j *Json) IntAt(default_ int, branch ...string) int {
+	i, ok := j.int64At(branch)
+	if !ok || i < math.MinInt || i > math.MaxInt {
+		return default_
+	}
+	return int(i)
+}
+
+// Int64At is IntAt for int64 values
+func (j *Json) Int64At(default_ int64, branch ...string) int64 {
+	i, ok := j.int64At(branch)
+	if !ok {
+		return default_
+	}
+	return i
+}
+
+// Float64At is StringAt for float64 values. strings holding a number are converted
+func (j *Json) Float64At(default_ float64, branch ...string) float64 {
+	node, ok := j.leafAt(branch)
+	if !ok {
+		return default_
+	}
+	number, ok := coercedNumber(node)
+	if !ok {
+		return default_
+	}
+	f, _ := number.Float64()
+	return f
+}
+
+// BoolAt is StringAt for bool values. the strings accepted by strconv.ParseBool,
+// like "true" and "0", and the numbers 0 and 1 are converted
+func (j *Json) BoolAt(default_ bool, branch ...string) bool {
+	node, ok := j.leafAt(branch)
+	if !ok {
+		return default_
+	}
+	switch value := node.(type) {
+	case bool:
+		return value
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return default_
+		}
+		return b
+	}
+	number, ok := rawNumber(node)
+	switch {
+	case !ok:
+		return default_
+	case number.Sign() == 0:
+		return false
+	case number.IsInt() && number.Num().IsInt64() && number.Num().Int64() == 1:
+		return true
+	}
+	return default_
+}
+
+// leafAt returns the raw value at branch, false when it is missing or null
+func (j *Json) leafAt(branch []string) (interface{}, bool) {
+	if j.IsEmpty() {
+		return nil, false
+	}
+	if j.trace != nil {
+		j.trace.recordBranch(j, j.value.Interface(), branch)
+	}
+	node, ok := valueAtBranch(j.value.Interface(), branch)
+	node = unwrapRaw(node)
+	return node, ok && node != nil
+}
+
+func (j *Json) int64At(branch []string) (int64, bool) {
+	node, ok := j.leafAt(branch)
+	if !ok {
+		return 0, false
+	}
+	number, ok := coercedNumber(node)
+	if !ok || !number.IsInt() || !number.Num().IsInt64() {
+		return 0, false
+	}
+	return number.Num().Int64(), true
+}
+
+// coercedNumber is rawNumber also accepting strings in json number syntax
+func coercedNumber(node interface{}) (*big.Rat, bool) {
+	if s, isString := node.(string); isString {
+		s = strings.TrimSpace(s)
+		if !isValidNumber(s) {
+			return nil, false
+		}
+		node = json.Number(s)
+	}
+	return rawNumber(node)
+}
@@ -0,0 +1,69 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const gettersFixture = `{"paging":{"limit":"5","offset":10,"size":2.5,"page":"x","huge":1e30},"flags":{"on":true,"yes":"true","one":1,"zero":0,"two":2,"word":"maybe"},"name":"svc","port":8080,"none":null,"items":[{"id":"7"}],"tags":["a"],"hex":"0x10"}`
+
+func TestJson_StringAt(t *testing.T) {
+	req, _ := Parse([]byte(gettersFixture))
+	assert.True(t, req.StringAt("d", "name") == "svc")
+	assert.True(t, req.StringAt("d", "port") == "8080")
+	assert.True(t, req.StringAt("d", "flags", "on") == "true")
+	assert.True(t, req.StringAt("d", "items", "0", "id") == "7")
+	assert.True(t, req.StringAt("d", "none") == "d")
+	assert.True(t, req.StringAt("d", "missing", "deeper") == "d")
+	assert.True(t, req.StringAt("d", "tags") == "d")
+	assert.True(t, req.StringAt("d", "paging") == "d")
+	assert.True(t, NewEmpty().StringAt("d", "name") == "d")
+	assert.True(t, req.Get("missing").StringAt("d") == "d")
+}
+
+func TestJson_NumberAt(t *testing.T) {
+	req, _ := Parse([]byte(gettersFixture))
+	assert.True(t, req.IntAt(100, "paging", "limit") == 5)
+	assert.True(t, req.IntAt(100, "paging", "offset") == 10)
+	assert.True(t, req.IntAt(100, "paging", "size") == 100)
+	assert.True(t, req.IntAt(100, "paging", "page") == 100)
+	assert.True(t, req.IntAt(100, "paging", "huge") == 100)
+	assert.True(t, req.IntAt(100, "items", "0", "id") == 7)
+	assert.True(t, req.IntAt(100, "none") == 100)
+	assert.True(t, req.IntAt(100, "tags") == 100)
+	assert.True(t, req.IntAt(100, "hex") == 100)
+	assert.True(t, req.IntAt(100, "flags", "on") == 100)
+	assert.True(t, req.Int64At(-1, "port") == 8080)
+	assert.True(t, req.Int64At(-1, "missing") == -1)
+	assert.True(t, req.Float64At(-1, "paging", "size") == 2.5)
+	assert.True(t, req.Float64At(-1, "paging", "limit") == 5)
+	assert.True(t, req.Float64At(-1, "paging", "huge") == 1e30)
+	assert.True(t, req.Float64At(-1, "name") == -1)
+	assert.True(t, req.Float64At(-1, "none") == -1)
+	assert.True(t, NewEmpty().Float64At(-1) == -1)
+}
+
+func TestJson_BoolAt(t *testing.T) {
+	req, _ := Parse([]byte(gettersFixture))
+	assert.True(t, req.BoolAt(false, "flags", "on"))
+	assert.True(t, req.BoolAt(false, "flags", "yes"))
+	assert.True(t, req.BoolAt(false, "flags", "one"))
+	assert.True(t, !req.BoolAt(true, "flags", "zero"))
+	assert.True(t, req.BoolAt(true, "flags", "two"))
+	assert.True(t, !req.BoolAt(false, "flags", "two"))
+	assert.True(t, req.BoolAt(true, "flags", "word"))
+	assert.True(t, req.BoolAt(true, "none"))
+	assert.True(t, req.BoolAt(true, "flags"))
+	assert.True(t, !req.BoolAt(false, "missing"))
+}
+
+func TestJson_GetPathOr(t *testing.T) {
+	req, _ := Parse([]byte(gettersFixture))
+	fallback := NewJSONObject().Set("limit", 1)
+	assert.True(t, req.GetPathOr(fallback, "paging", "offset").MustInt() == 10)
+	assert.True(t, req.GetPathOr(fallback, "items", "0", "id").MustString() == "7")
+	assert.True(t, req.GetPathOr(fallback, "missing") == fallback)
+	assert.True(t, req.GetPathOr(fallback, "none") == fallback)
+	assert.True(t, req.GetPathOr(nil, "missing").IsEmpty())
+	assert.True(t, NewEmpty().GetPathOr(fallback) == fallback)
+}
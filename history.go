@@ -0,0 +1,95 @@
+package betterjson
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// History keeps the committed versions of a document as a chain of JSON
+// Patches from its initial state, for audit and undo without a database
+type History struct {
+	doc       *Json
+	initial   interface{}
+	revisions []Revision
+}
+
+// Revision is one commit of a History. Number counts the commits from 1,
+// the Revision with Number 0 is the initial state
+type Revision struct {
+	Number  int
+	Message string
+	Time    time.Time
+	// Patch is the RFC 6902 patch from the previous revision to this one
+	Patch *Json
+}
+
+// NewHistory starts the history of doc at its current state. it records
+// the mutations of doc, and those through wrappers derived from it, with
+// StartRecording, so doc must not be recorded otherwise meanwhile:
+//    history, _ := betterjson.NewHistory(config)
+//    config.Get("server").Set("port", 8080)
+//    history.Commit("move to 8080")
+func NewHistory(doc *Json) (*History, error) {
+	if doc.IsEmpty() {
+		return nil, errors.New("empty json can't have a history")
+	}
+	history := &History{doc: doc, initial: deepCopyRaw(doc.value.Interface()), revisions: make([]Revision, 0)}
+	doc.StartRecording()
+	return history, nil
+}
+
+// Commit makes the mutations of the document since the previous commit a
+// new revision, even when there were none
+func (h *History) Commit(message string) Revision {
+	patch, err := h.doc.StopRecording()
+	if err != nil {
+		// recording was stopped from outside, commit nothing
+		patch = wrapRaw([]interface{}{})
+	}
+	h.doc.StartRecording()
+	revision := Revision{Number: len(h.revisions) + 1, Message: message, Time: time.Now(), Patch: patch}
+	h.revisions = append(h.revisions, revision)
+	return revision
+}
+
+// Revisions returns the commits, oldest first
+func (h *History) Revisions() []Revision {
+	return append([]Revision{}, h.revisions...)
+}
+
+// ReplayTo returns a copy of the document as it was committed in rev, built
+// by applying the patches of the revisions up to rev to the initial state
+func (h *History) ReplayTo(rev Revision) (*Json, error) {
+	if rev.Number < 0 || rev.Number > len(h.revisions) {
+		return NewEmpty(), errors.Errorf("revision %d doesn't exist", rev.Number)
+	}
+	state := wrapRaw(deepCopyRaw(h.initial))
+	for _, revision := range h.revisions[:rev.Number] {
+		if err := state.ApplyPatch(revision.Patch); err != nil {
+			return NewEmpty(), errors.Wrapf(err, "revision %d", revision.Number)
+		}
+	}
+	state.settings = h.doc.settings
+	return state, nil
+}
+
+// RevertLast drops the newest commit and restores the document to the
+// revision before it, discarding the mutations not committed yet as well
+func (h *History) RevertLast() error {
+	if len(h.revisions) == 0 {
+		return errors.New("history has no commit to revert")
+	}
+	previous, err := h.ReplayTo(Revision{Number: len(h.revisions) - 1})
+	if err != nil {
+		return err
+	}
+	h.doc.StopRecording()
+	data := previous.value.Interface()
+	prior := h.doc.prior([]string{})
+	h.doc.replaceData(data)
+	h.doc.changed(setOperation([]string{}, data, prior), prior)
+	h.revisions = h.revisions[:len(h.revisions)-1]
+	h.doc.StartRecording()
+	return nil
+}
@@ -0,0 +1,84 @@
+package betterjson
+
+import (
+	"crypto/sha256"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func historyDigest(js *Json) [sha256.Size]byte {
+	hash := sha256.New()
+	js.WriteDigest(hash)
+	var digest [sha256.Size]byte
+	copy(digest[:], hash.Sum(nil))
+	return digest
+}
+
+func TestHistoryReplayTo(t *testing.T) {
+	config := Obj("server", Obj("port", 80, "host", "a"), "tags", Arr("x"))
+	history, err := NewHistory(config)
+	assert.True(t, err == nil)
+	digests := [][sha256.Size]byte{historyDigest(config)}
+
+	config.Get("server").Set("port", 8080)
+	config.Get("tags").TryAdd("y")
+	first := history.Commit("move to 8080")
+	digests = append(digests, historyDigest(config))
+
+	config.Del("tags")
+	config.SetPath([]string{"server", "tls"}, true)
+	history.Commit("enable tls")
+	digests = append(digests, historyDigest(config))
+
+	config.Get("server").Set("host", "b")
+	history.Commit("rename host")
+	digests = append(digests, historyDigest(config))
+
+	revisions := history.Revisions()
+	assert.Equal(t, 3, len(revisions))
+	assert.Equal(t, first.Number, revisions[0].Number)
+	assert.Equal(t, "enable tls", revisions[1].Message)
+	assert.True(t, !revisions[2].Time.Before(revisions[0].Time))
+	println(revisions[0].Patch.EncodeToStringOrDefault(""))
+
+	for number, digest := range digests {
+		state, err := history.ReplayTo(Revision{Number: number})
+		assert.True(t, err == nil)
+		assert.Equal(t, digest, historyDigest(state))
+	}
+	middle, _ := history.ReplayTo(first)
+	assert.Equal(t, `{"server":{"host":"a","port":8080},"tags":["x","y"]}`, middle.EncodeToStringOrDefault(""))
+	middle.Set("changed", true)
+	again, _ := history.ReplayTo(first)
+	assert.True(t, !again.ContainsKey("changed"))
+
+	_, err = history.ReplayTo(Revision{Number: 4})
+	assert.Equal(t, "revision 4 doesn't exist", err.Error())
+}
+
+func TestHistoryRevertLast(t *testing.T) {
+	config := Obj("a", 1)
+	history, _ := NewHistory(config)
+	assert.Equal(t, "history has no commit to revert", history.RevertLast().Error())
+	config.Set("a", 2)
+	history.Commit("two")
+	config.Set("a", 3).Set("b", true)
+	history.Commit("three")
+	config.Set("uncommitted", 1)
+
+	assert.True(t, history.RevertLast() == nil)
+	assert.Equal(t, `{"a":2}`, config.EncodeToStringOrDefault(""))
+	assert.Equal(t, 1, len(history.Revisions()))
+
+	config.Set("c", 4)
+	history.Commit("four")
+	state, _ := history.ReplayTo(history.Revisions()[1])
+	assert.Equal(t, `{"a":2,"c":4}`, state.EncodeToStringOrDefault(""))
+
+	assert.True(t, history.RevertLast() == nil)
+	assert.True(t, history.RevertLast() == nil)
+	assert.Equal(t, `{"a":1}`, config.EncodeToStringOrDefault(""))
+
+	_, err := NewHistory(NewEmpty())
+	assert.True(t, err != nil)
+}
@@ -0,0 +1,105 @@
+package betterjson
+
+import (
+	"context"
+	"strconv"
+)
+
+// ignoredPaths are the glob patterns of the paths a comparison skips
+type ignoredPaths []globPattern
+
+func parseIgnoredPaths(paths []string) ignoredPaths {
+	ignored := make(ignoredPaths, 0, len(paths))
+	for _, path := range paths {
+		ignored = append(ignored, parseGlobPattern(path))
+	}
+	return ignored
+}
+
+func (ignored ignoredPaths) match(branch []string) bool {
+	for _, glob := range ignored {
+		if glob.match(branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSameJSONWithIgnoring is IsSameJSONWith with the values at ignorePaths left
+// out on both sides. the paths are dotted glob patterns like PathsMatching's,
+// a "*" segment also matches every item of an array:
+//    same := got.IsSameJSONWithIgnoring(want, []string{"requestId", "items.*.updatedAt"})
+func (j *Json) IsSameJSONWithIgnoring(other *Json, ignorePaths []string) bool {
+	if len(ignorePaths) == 0 {
+		return j.IsSameJSONWith(other)
+	}
+	ignored := parseIgnoredPaths(ignorePaths)
+	left, leftOk := documentRoot(j)
+	right, rightOk := documentRoot(other)
+	if leftOk != rightOk {
+		return false
+	}
+	return !leftOk || ignored.match([]string{}) || digestEqualIgnoring(make([]string, 0, 8), left, right, ignored)
+}
+
+// digestEqualIgnoring is digestEqual skipping the values at the paths ignored
+// matches, which may be present on one side only
+func digestEqualIgnoring(branch []string, a interface{}, b interface{}, ignored ignoredPaths) bool {
+	a, b = unwrapRaw(a), unwrapRaw(b)
+	switch left := a.(type) {
+	case map[string]interface{}:
+		if right, ok := b.(map[string]interface{}); ok && left != nil && right != nil {
+			for key, item := range left {
+				child := append(branch, key)
+				if ignored.match(child) {
+					continue
+				}
+				other, exists := right[key]
+				if !exists || !digestEqualIgnoring(child, item, other, ignored) {
+					return false
+				}
+			}
+			for key := range right {
+				if _, exists := left[key]; !exists && !ignored.match(append(branch, key)) {
+					return false
+				}
+			}
+			return true
+		}
+	case []interface{}:
+		if right, ok := b.([]interface{}); ok && left != nil && right != nil {
+			for idx := 0; idx < len(left) || idx < len(right); idx++ {
+				child := append(branch, strconv.Itoa(idx))
+				if ignored.match(child) {
+					continue
+				}
+				if idx >= len(left) || idx >= len(right) || !digestEqualIgnoring(child, left[idx], right[idx], ignored) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return digestEqual(a, b)
+}
+
+// DiffReportIgnoring is DiffReport with the values at ignorePaths left out on
+// both sides, the paths matched like IsSameJSONWithIgnoring's
+func DiffReportIgnoring(a, b *Json, ignorePaths []string) []Difference {
+	differences := make([]Difference, 0)
+	ignored := parseIgnoredPaths(ignorePaths)
+	if ignored.match([]string{}) {
+		return differences
+	}
+	aRoot, aOk := documentRoot(a)
+	bRoot, bOk := documentRoot(b)
+	switch {
+	case aOk && bOk:
+		diffRaw([]string{}, aRoot, bRoot, &differences, newProgress(context.Background(), "diff"), ignored)
+	case aOk:
+		differences = append(differences, newDifference(DiffRemoved, []string{}, aRoot, nil))
+	case bOk:
+		differences = append(differences, newDifference(DiffAdded, []string{}, nil, bRoot))
+	}
+	return differences
+}
@@ -0,0 +1,47 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func ignoreDocuments() (*Json, *Json) {
+	a, _ := Parse([]byte(`{"requestId":"r1","status":"ok","items":[{"id":1,"updatedAt":"t1"},{"id":2,"updatedAt":"t2"}]}`))
+	b, _ := Parse([]byte(`{"requestId":"r2","status":"ok","items":[{"id":1,"updatedAt":"t3"},{"id":2}]}`))
+	return a, b
+}
+
+func TestIsSameJSONWithIgnoring(t *testing.T) {
+	a, b := ignoreDocuments()
+	assert.True(t, !a.IsSameJSONWith(b))
+	assert.True(t, !a.IsSameJSONWithIgnoring(b, []string{"requestId"}))
+	assert.True(t, a.IsSameJSONWithIgnoring(b, []string{"requestId", "items.*.updatedAt"}))
+	assert.True(t, a.IsSameJSONWithIgnoring(b, []string{"**.updatedAt", "requestId"}))
+	assert.True(t, a.IsSameJSONWithIgnoring(b, []string{"requestId", "items"}))
+	assert.True(t, a.IsSameJSONWithIgnoring(b, []string{"**"}))
+	// a path absent from both documents changes nothing
+	assert.True(t, !a.IsSameJSONWithIgnoring(b, []string{"missing.path"}))
+	assert.True(t, a.IsSameJSONWithIgnoring(a, []string{"missing.path"}))
+
+	b.Get("items").GetIndex(1).Set("id", 3)
+	assert.True(t, !a.IsSameJSONWithIgnoring(b, []string{"requestId", "items.*.updatedAt"}))
+	assert.True(t, !NewJSONObject().IsSameJSONWithIgnoring(NewEmpty(), []string{"a"}))
+	assert.True(t, NewEmpty().IsSameJSONWithIgnoring(NewEmpty(), []string{"a"}))
+}
+
+func TestDiffReportIgnoring(t *testing.T) {
+	a, b := ignoreDocuments()
+	differences := DiffReportIgnoring(a, b, []string{"items.*.updatedAt"})
+	assert.Equal(t, 1, len(differences))
+	assert.Equal(t, `changed requestId: "r1" => "r2"`, differences[0].String())
+
+	differences = DiffReportIgnoring(a, b, []string{"requestId", "items.0"})
+	assert.Equal(t, 1, len(differences))
+	assert.Equal(t, `removed items.1.updatedAt: "t2"`, differences[0].String())
+
+	assert.Equal(t, 0, len(DiffReportIgnoring(a, b, []string{"requestId", "**.updatedAt"})))
+	assert.Equal(t, len(DiffReport(a, b)), len(DiffReportIgnoring(a, b, []string{"missing"})))
+	for _, difference := range DiffReport(a, b) {
+		println(difference.String())
+	}
+}
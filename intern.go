@@ -0,0 +1,126 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/bitly/go-simplejson"
+)
+
+// internKey returns the interned copy of key, adding it to the table
+func internKey(keys *map[string]string, key string) string {
+	if *keys == nil {
+		*keys = make(map[string]string)
+	}
+	if interned, ok := (*keys)[key]; ok {
+		return interned
+	}
+	(*keys)[key] = key
+	return key
+}
+
+// parseInterned parses ParseOptions{InternKeys: true} documents from data,
+// which must be valid json. keys already in the table are found without
+// converting them to a string first, so repeated keys cost no allocation
+func parseInterned(data []byte) *Json {
+	decoder := &internDecoder{data: data, keys: make(map[string]string)}
+	value := simplejson.New()
+	value.SetPath([]string{}, decoder.value())
+	return FromNotEmptySimpleJson(value)
+}
+
+type internDecoder struct {
+	data []byte
+	i    int
+	keys map[string]string
+}
+
+func (d *internDecoder) value() interface{} {
+	d.i = skipSpace(d.data, d.i)
+	switch d.data[d.i] {
+	case '{':
+		object := make(map[string]interface{})
+		d.i = skipSpace(d.data, d.i+1)
+		for d.data[d.i] != '}' {
+			key := d.key()
+			d.i = skipSpace(d.data, d.i)
+			d.i++ // the ':'
+			object[key] = d.value()
+			d.i = skipSpace(d.data, d.i)
+			if d.data[d.i] == ',' {
+				d.i = skipSpace(d.data, d.i+1)
+			}
+		}
+		d.i++
+		return object
+	case '[':
+		array := make([]interface{}, 0)
+		d.i = skipSpace(d.data, d.i+1)
+		for d.data[d.i] != ']' {
+			array = append(array, d.value())
+			d.i = skipSpace(d.data, d.i)
+			if d.data[d.i] == ',' {
+				d.i = skipSpace(d.data, d.i+1)
+			}
+		}
+		d.i++
+		return array
+	case '"':
+		quoted := d.next()
+		if raw := quoted[1 : len(quoted)-1]; plainString(raw) {
+			return string(raw)
+		}
+		return unquote(quoted)
+	case 't':
+		d.i += len("true")
+		return true
+	case 'f':
+		d.i += len("false")
+		return false
+	case 'n':
+		d.i += len("null")
+		return nil
+	}
+	return json.Number(d.next())
+}
+
+func (d *internDecoder) key() string {
+	quoted := d.next()
+	raw := quoted[1 : len(quoted)-1]
+	if !plainString(raw) {
+		key := unquote(quoted)
+		if interned, ok := d.keys[key]; ok {
+			return interned
+		}
+		d.keys[key] = key
+		return key
+	}
+	// the compiler doesn't allocate for string(raw) in a map index
+	if interned, ok := d.keys[string(raw)]; ok {
+		return interned
+	}
+	key := string(raw)
+	d.keys[key] = key
+	return key
+}
+
+// next returns the bytes of the value at d.i and moves past it
+func (d *internDecoder) next() []byte {
+	start := d.i
+	d.i = skipValue(d.data, d.i)
+	return d.data[start:d.i]
+}
+
+// plainString reports whether the bytes of a string literal are its value,
+// without escapes or invalid utf8 for encoding/json to replace
+func plainString(raw []byte) bool {
+	return bytes.IndexByte(raw, '\\') < 0 && utf8.Valid(raw)
+}
+
+func unquote(quoted []byte) string {
+	var s string
+	// quoted was validated by the caller
+	json.Unmarshal(quoted, &s)
+	return s
+}
@@ -0,0 +1,156 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/bitly/go-simplejson"
+)
+
+// ParseLazy is Parse for large documents of which only a few top level keys
+// are used. the input is validated up front, but the values of a top level
+// object are kept as their input bytes and only parsed when first reached
+// through Get, GetPath or any other access. Encode writes values that were
+// never reached as they were in the input. other documents than objects are
+// parsed right away, like Parse does
+//
+// reaching a value parses it, so a lazily parsed document must not be used
+// from several goroutines at once, even only for reading
+func ParseLazy(data []byte) (*Json, error) {
+	if !json.Valid(data) {
+		// for Parse's error
+		return Parse(data)
+	}
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return Parse(data)
+	}
+	data = append([]byte{}, data...)
+	object := make(map[string]interface{})
+	i = skipSpace(data, i+1)
+	for i < len(data) && data[i] != '}' {
+		end := skipValue(data, i)
+		key := lazyKey(data[i:end])
+		i = skipSpace(data, end)
+		i = skipSpace(data, i+1) // the ':'
+		end = skipValue(data, i)
+		object[key] = &lazyValue{raw: data[i:end]}
+		i = skipSpace(data, end)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	value := simplejson.New()
+	value.SetPath([]string{}, object)
+	result := FromNotEmptySimpleJson(value)
+	result.lazy = true
+	return result, nil
+}
+
+// lazyValue is a value of a ParseLazy document, parsed on first use
+type lazyValue struct {
+	raw    []byte
+	parsed bool
+	value  interface{}
+}
+
+func (lazy *lazyValue) materialize() interface{} {
+	if !lazy.parsed {
+		dec := json.NewDecoder(bytes.NewReader(lazy.raw))
+		dec.UseNumber()
+		// the input was validated by ParseLazy
+		dec.Decode(&lazy.value)
+		lazy.parsed = true
+		lazy.raw = nil
+	}
+	return lazy.value
+}
+
+// MarshalJSON writes the input bytes of a value that was never parsed
+func (lazy *lazyValue) MarshalJSON() ([]byte, error) {
+	if !lazy.parsed {
+		return lazy.raw, nil
+	}
+	return json.Marshal(lazy.value)
+}
+
+// materializeKey replaces a lazily parsed value under key of the object j by
+// its parsed data, before it is handed out or edited
+func (j *Json) materializeKey(key string) {
+	if j.IsEmpty() {
+		return
+	}
+	if object, ok := j.value.Interface().(map[string]interface{}); ok {
+		if lazy, ok := object[key].(*lazyValue); ok {
+			object[key] = lazy.materialize()
+		}
+	}
+}
+
+// materializeAll parses every value of a ParseLazy document not parsed yet,
+// before its raw data is handed out
+func (j *Json) materializeAll() {
+	if !j.lazy || j.IsEmpty() {
+		return
+	}
+	if object, ok := j.value.Interface().(map[string]interface{}); ok {
+		for key, item := range object {
+			if lazy, ok := item.(*lazyValue); ok {
+				object[key] = lazy.materialize()
+			}
+		}
+	}
+	j.lazy = false
+}
+
+func lazyKey(quoted []byte) string {
+	if raw := quoted[1 : len(quoted)-1]; plainString(raw) {
+		return string(raw)
+	}
+	return unquote(quoted)
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipValue returns the end of the value starting at data[i], which must be
+// valid json
+func skipValue(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		for i++; i < len(data); i++ {
+			switch data[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return i
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = skipValue(data, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	}
+	for i < len(data) && data[i] != ',' && data[i] != '}' && data[i] != ']' && data[i] != ' ' &&
+		data[i] != '\t' && data[i] != '\n' && data[i] != '\r' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,110 @@
+package betterjson
+
+import (
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLazy(t *testing.T) {
+	texts := []string{
+		`{"a" : {"b":[1, 2.50, "xA"]} , "c":"d\"e","eé":null,"n":-1.5e3,"t":true}`,
+		"  {\n\t\"k\":[[],{}] }\n",
+		`{}`, `[1,{"a":2}]`, `"plain"`, `12`, `null`,
+		`{"dup":1,"dup":2}`,
+	}
+	for _, fixture := range encodeFixtures() {
+		encoded, _ := fixture.Encode()
+		texts = append(texts, string(encoded))
+	}
+	for _, text := range texts {
+		eager, err := Parse([]byte(text))
+		assert.True(t, err == nil, text)
+		lazy, err := ParseLazy([]byte(text))
+		assert.True(t, err == nil, text)
+		assert.True(t, lazy.DigestJSONForEqual() == eager.DigestJSONForEqual(), text)
+		assert.True(t, lazy.IsSameJSONWith(eager), text)
+		untouched, _ := ParseLazy([]byte(text))
+		encoded, err := untouched.Encode()
+		assert.True(t, err == nil, text)
+		reparsed, _ := Parse(encoded)
+		assert.True(t, reparsed.IsSameJSONWith(eager), text)
+	}
+	for _, text := range []string{`{"a":`, `{"a":1,}`, `{"a" 1}`, ``, `{"a":1} x`} {
+		_, err := ParseLazy([]byte(text))
+		assert.True(t, err != nil, text)
+	}
+}
+
+func TestParseLazyVerbatim(t *testing.T) {
+	input := []byte(`{"big":{"keep":"xA","n":1.50},"small":{"v":1}}`)
+	js, _ := ParseLazy(input)
+	input[9] = 'X'
+	assert.True(t, js.Get("small").Get("v").MustInt() == 1)
+	encoded, _ := js.Encode()
+	assert.True(t, string(encoded) == `{"big":{"keep":"xA","n":1.50},"small":{"v":1}}`)
+	assert.True(t, js.GetDottedPath("big.keep").MustString() == "xA")
+}
+
+func TestParseLazyMutation(t *testing.T) {
+	text := `{"a":{"b":1,"list":[1]},"c":[1,2],"d":"s","e":{"x":{"y":1}}}`
+	js, _ := ParseLazy([]byte(text))
+	js.Get("a").Set("b", 2)
+	js.Get("c").TryAdd(3)
+	js.SetPath([]string{"e", "x", "z"}, 2)
+	js.Get("a").Get("list").TryAdd(2)
+	assert.True(t, js.DigestJSONForEqual() == `{"a":{"b":2,"list":[1,2]},"c":[1,2,3],"d":"s","e":{"x":{"y":1,"z":2}}}`)
+	encoded, _ := js.Encode()
+	assert.True(t, string(encoded) == `{"a":{"b":2,"list":[1,2]},"c":[1,2,3],"d":"s","e":{"x":{"y":1,"z":2}}}`)
+
+	other, _ := ParseLazy([]byte(text))
+	object := other.MustMap()
+	_, isMap := object["a"].(map[string]interface{})
+	assert.True(t, isMap)
+	id := other.Snapshot()
+	other.Set("d", "changed")
+	other.Rollback(id)
+	assert.True(t, other.Get("d").MustString() == "s")
+
+	recorded, _ := ParseLazy([]byte(text))
+	recorded.StartRecording()
+	recorded.Get("e").Get("x").Set("y", 5)
+	patch, _ := recorded.StopRecording()
+	eager, _ := Parse([]byte(text))
+	assert.True(t, eager.ApplyPatch(patch) == nil)
+	assert.True(t, eager.IsSameJSONWith(recorded))
+}
+
+func lazyBenchmarkInput() []byte {
+	items := largeEncodeFixture()
+	document := NewJSONObject().Set("items", items).Set("id", "request-1").Set("meta", Obj("page", 1))
+	for i := 0; i < 20; i++ {
+		document.Set("section"+strconv.Itoa(i), items.GetIndex(i))
+	}
+	encoded, _ := document.Encode()
+	return encoded
+}
+
+func BenchmarkParseLazy_ReadOneKeyAndEncode(b *testing.B) {
+	input := lazyBenchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		js, _ := ParseLazy(input)
+		js.Get("meta").Set("page", 2)
+		js.Encode()
+	}
+}
+
+func BenchmarkParse_ReadOneKeyAndEncode(b *testing.B) {
+	input := lazyBenchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		js, _ := Parse(input)
+		js.Get("meta").Set("page", 2)
+		js.Encode()
+	}
+}
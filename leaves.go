@@ -0,0 +1,276 @@
+package betterjson
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Paths returns the dotted paths of every scalar and null leaf in j, sorted.
+// array items use their index as segment and keys are escaped like
+// JoinDottedPath does, so every result can be passed to ValueAt.
+// empty objects and arrays have no leaves
+func (j *Json) Paths() []string {
+	paths := make([]string, 0)
+	if j.IsEmpty() {
+		return paths
+	}
+	walkLeaves(j.value.Interface(), make([]string, 0, 8), func(branch []string, leaf interface{}) {
+		paths = append(paths, JoinDottedPath(branch))
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// PathsMatching returns the sorted leaf paths matching the glob pattern:
+// a "*" segment matches exactly one segment and "**" matches any number of
+// segments (including none). escape a literal "*" key as "\*"
+//
+//   js.PathsMatching("users.*.email")
+func (j *Json) PathsMatching(pattern string) []string {
+	paths := make([]string, 0)
+	if j.IsEmpty() {
+		return paths
+	}
+	glob := parseGlobPattern(pattern)
+	walkLeaves(j.value.Interface(), make([]string, 0, 8), func(branch []string, leaf interface{}) {
+		if glob.match(branch) {
+			paths = append(paths, JoinDottedPath(branch))
+		}
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// PathsOfValue returns the sorted paths of the leaves deep equal to val,
+// numbers compared by value like ReplaceValue does. val can be a *Json or a
+// plain value:
+//    leaks := payload.PathsOfValue(apiKey)
+func (j *Json) PathsOfValue(val interface{}) []string {
+	target := unwrapRaw(val)
+	return j.pathsOfLeaves(func(leaf interface{}) bool {
+		return rawEqual(leaf, target)
+	})
+}
+
+// PathsWhere returns the sorted paths of the leaves pred returns true for
+func (j *Json) PathsWhere(pred func(value *Json) bool) []string {
+	return j.pathsOfLeaves(func(leaf interface{}) bool {
+		return pred(wrapRaw(leaf))
+	})
+}
+
+func (j *Json) pathsOfLeaves(match func(leaf interface{}) bool) []string {
+	paths := make([]string, 0)
+	if j.IsEmpty() {
+		return paths
+	}
+	walkLeaves(j.value.Interface(), make([]string, 0, 8), func(branch []string, leaf interface{}) {
+		if match(leaf) {
+			paths = append(paths, JoinDottedPath(branch))
+		}
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// GetGlob returns every value, containers included, whose path matches the
+// glob pattern of PathsMatching, in document order: object keys sorted, array
+// items by index. array items match their index as segment. "\." and "\*"
+// match a literal dot or asterisk inside a key, JoinDottedPath escapes paths
+// that way:
+//    images := manifest.GetGlob("spec.**.image")
+func (j *Json) GetGlob(pattern string) []PathValue {
+	matches := make([]PathValue, 0)
+	for _, branch := range j.globBranches(pattern) {
+		value, _ := valueAtBranch(j.value.Interface(), branch)
+		matches = append(matches, PathValue{Path: JoinDottedPath(branch), Value: wrapRaw(value)})
+	}
+	return matches
+}
+
+// DelGlob removes every value GetGlob(pattern) returns, like DelPath does, and
+// returns how many were removed. values inside another removed value aren't
+// counted, and the root is never removed:
+//    manifest.DelGlob("metadata.managedFields")
+func (j *Json) DelGlob(pattern string) int {
+	branches := make([][]string, 0)
+	for _, branch := range j.globBranches(pattern) {
+		if len(branch) == 0 {
+			continue
+		}
+		if len(branches) > 0 && hasPrefix(branch, branches[len(branches)-1]) {
+			continue
+		}
+		branches = append(branches, branch)
+	}
+	// backwards, so removing an array item doesn't shift the indexes of the
+	// matches still to remove
+	for idx := len(branches) - 1; idx >= 0; idx-- {
+		j.DelPath(branches[idx]...)
+	}
+	return len(branches)
+}
+
+// globBranches returns the branches of the nodes matching pattern in document order
+func (j *Json) globBranches(pattern string) [][]string {
+	branches := make([][]string, 0)
+	if j.IsEmpty() {
+		return branches
+	}
+	glob := parseGlobPattern(pattern)
+	walkNodesInOrder(j.value.Interface(), make([]string, 0, 8), func(branch []string) {
+		if glob.match(branch) {
+			branches = append(branches, append([]string{}, branch...))
+		}
+	})
+	return branches
+}
+
+// ValueAt returns the node at the dotted path, or an empty Json when missing.
+// it resolves every path returned by Paths
+func (j *Json) ValueAt(dottedPath string) *Json {
+	return j.GetDottedPath(dottedPath)
+}
+
+// PathValue is a value found in a document with its dotted path, escaped like
+// JoinDottedPath does
+type PathValue struct {
+	Path  string
+	Value *Json
+}
+
+// walkLeavesInOrder is walkLeaves visiting object keys sorted and array items
+// by index, so results come in a deterministic document order
+func walkLeavesInOrder(node interface{}, branch []string, fn func(branch []string, leaf interface{})) {
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(container) {
+			walkLeavesInOrder(container[key], append(branch, key), fn)
+		}
+	case []interface{}:
+		for idx, item := range container {
+			walkLeavesInOrder(item, append(branch, strconv.Itoa(idx)), fn)
+		}
+	default:
+		fn(branch, container)
+	}
+}
+
+// walkNodesInOrder calls fn with the branch of node and of everything below
+// it, parents before their children and in the order of walkLeavesInOrder
+func walkNodesInOrder(node interface{}, branch []string, fn func(branch []string)) {
+	fn(branch)
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(container) {
+			walkNodesInOrder(container[key], append(branch, key), fn)
+		}
+	case []interface{}:
+		for idx, item := range container {
+			walkNodesInOrder(item, append(branch, strconv.Itoa(idx)), fn)
+		}
+	}
+}
+
+// walkLeaves calls fn with the branch of every scalar and null leaf under node.
+// branch is reused between calls, so fn must copy it to keep it
+func walkLeaves(node interface{}, branch []string, fn func(branch []string, leaf interface{})) {
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		for key, item := range container {
+			walkLeaves(item, append(branch, key), fn)
+		}
+	case []interface{}:
+		for idx, item := range container {
+			walkLeaves(item, append(branch, strconv.Itoa(idx)), fn)
+		}
+	default:
+		fn(branch, container)
+	}
+}
+
+const (
+	globLiteral = iota
+	globOne
+	globAny
+)
+
+type globSegment struct {
+	kind int
+	text string
+}
+
+type globPattern []globSegment
+
+// parseGlobPattern splits a dotted glob pattern. only unescaped segments that
+// are exactly "*" or "**" are wildcards
+func parseGlobPattern(pattern string) globPattern {
+	glob := make(globPattern, 0)
+	if pattern == "" {
+		return glob
+	}
+	var text []byte
+	wildcard := true
+	flush := func() {
+		segment := globSegment{kind: globLiteral, text: string(text)}
+		if wildcard && segment.text == "*" {
+			segment.kind = globOne
+		} else if wildcard && segment.text == "**" {
+			segment.kind = globAny
+		}
+		glob = append(glob, segment)
+		text = text[:0]
+		wildcard = true
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			text = append(text, pattern[i])
+			wildcard = false
+		case c == '\\':
+			// a trailing backslash escapes nothing, `\` is the key ""
+			wildcard = false
+		case c == '.':
+			flush()
+		default:
+			text = append(text, c)
+		}
+	}
+	flush()
+	return glob
+}
+
+func (glob globPattern) match(branch []string) bool {
+	if len(glob) == 0 {
+		return len(branch) == 0
+	}
+	switch glob[0].kind {
+	case globAny:
+		if glob[1:].match(branch) {
+			return true
+		}
+		return len(branch) > 0 && glob.match(branch[1:])
+	case globOne:
+		return len(branch) > 0 && glob[1:].match(branch[1:])
+	}
+	return len(branch) > 0 && branch[0] == glob[0].text && glob[1:].match(branch[1:])
+}
+
+// matchPrefix reports whether glob matches some path below branch
+func (glob globPattern) matchPrefix(branch []string) bool {
+	if len(branch) == 0 {
+		return len(glob) > 0
+	}
+	if len(glob) == 0 {
+		return false
+	}
+	switch glob[0].kind {
+	case globAny:
+		return glob[1:].matchPrefix(branch) || glob.matchPrefix(branch[1:])
+	case globOne:
+		return glob[1:].matchPrefix(branch[1:])
+	}
+	return branch[0] == glob[0].text && glob[1:].matchPrefix(branch[1:])
+}
@@ -0,0 +1,126 @@
+package betterjson
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"github.com/bitly/go-simplejson"
+	"github.com/stretchr/testify/assert"
+)
+
+func leavesFixture(t *testing.T) *Json {
+	a, err := simplejson.NewJson([]byte(`{"users":[{"name":"a","email":"a@x.com","tags":["x",null]},{"name":"b","email":null,"meta":{}}],
+		"a.b":{"c*":1},"flag":true,"count":12.5,"nested":{"deep":{"deeper":{"email":"root@x.com"}}}}`))
+	assert.True(t, err == nil)
+	return FromNotEmptySimpleJson(a)
+}
+
+func TestJson_Paths(t *testing.T) {
+	a := leavesFixture(t)
+	paths := a.Paths()
+	fmt.Println(paths)
+	assert.Equal(t, []string{"a\\.b.c\\*", "count", "flag", "nested.deep.deeper.email", "users.0.email", "users.0.name",
+		"users.0.tags.0", "users.0.tags.1", "users.1.email", "users.1.name"}, paths)
+	for _, path := range paths {
+		value := a.ValueAt(path)
+		assert.True(t, !value.IsEmpty(), path)
+	}
+	assert.True(t, a.ValueAt("a\\.b.c\\*").MustInt() == 1)
+	assert.True(t, a.ValueAt("users.0.tags.1").IsNullJson())
+	assert.True(t, a.ValueAt("users.2").IsEmpty())
+	assert.Equal(t, []string{}, NewEmpty().Paths())
+}
+
+func TestJson_PathsEmptyKey(t *testing.T) {
+	a, _ := Parse([]byte(`{"":1,"x":{"":2}}`))
+	paths := a.Paths()
+	assert.Equal(t, []string{"\\", "x."}, paths)
+	assert.Equal(t, int64(1), a.ValueAt(paths[0]).MustInt64())
+	assert.Equal(t, int64(2), a.ValueAt(paths[1]).MustInt64())
+	assert.Equal(t, []string{"\\"}, a.PathsMatching("\\"))
+	assert.Equal(t, []string{"\\", "x."}, a.PathsMatching("**"))
+}
+
+func TestJson_PathsMatching(t *testing.T) {
+	a := leavesFixture(t)
+	assert.Equal(t, []string{"users.0.email", "users.1.email"}, a.PathsMatching("users.*.email"))
+	assert.Equal(t, []string{"nested.deep.deeper.email", "users.0.email", "users.1.email"}, a.PathsMatching("**.email"))
+	assert.Equal(t, []string{"users.0.tags.0", "users.0.tags.1"}, a.PathsMatching("users.**.tags.*"))
+	assert.Equal(t, []string{"a\\.b.c\\*"}, a.PathsMatching("a\\.b.c\\*"))
+	assert.Equal(t, []string{}, a.PathsMatching("a\\.b.c\\*\\*"))
+	assert.Equal(t, []string{"count", "flag"}, a.PathsMatching("*"))
+	assert.Equal(t, len(a.Paths()), len(a.PathsMatching("**")))
+}
+
+func TestJson_PathsOfValue(t *testing.T) {
+	payload, _ := Parse([]byte(`{"user":{"id":"cust-42","name":"x"},"events":[{"actor":"cust-42"},"cust-42",{"ids":[1,"cust-42"]}],"ref":"cust-420","amount":1.0}`))
+	assert.Equal(t, []string{"events.0.actor", "events.1", "events.2.ids.1", "user.id"}, payload.PathsOfValue("cust-42"))
+	assert.Equal(t, []string{"amount", "events.2.ids.0"}, payload.PathsOfValue(1))
+	assert.Equal(t, []string{}, payload.PathsOfValue("cust-43"))
+	assert.Equal(t, []string{}, NewEmpty().PathsOfValue("cust-42"))
+
+	prices, _ := Parse([]byte(`{"price":0.1,"tax":[0.10,0.2],"total":{"value":19.99}}`))
+	assert.Equal(t, []string{"price", "tax.0"}, prices.PathsOfValue(0.1))
+	assert.Equal(t, []string{"total.value"}, prices.PathsOfValue(19.99))
+	assert.Equal(t, []string{"tax.1"}, prices.PathsOfValue(float32(0.2)))
+}
+
+func TestJson_PathsWhere(t *testing.T) {
+	payload, _ := Parse([]byte(`{"cards":["4111-1111-1111-1111","n/a"],"note":"paid with 5500-0000-0000-0004","total":120,"items":[{"price":80},{"price":40}]}`))
+	card := regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`)
+	assert.Equal(t, []string{"cards.0", "note"}, payload.PathsWhere(func(value *Json) bool {
+		s, err := value.String()
+		return err == nil && card.MatchString(s)
+	}))
+	assert.Equal(t, []string{"items.0.price", "total"}, payload.PathsWhere(func(value *Json) bool {
+		return value.MustFloat64(0) > 50
+	}))
+}
+
+func globPaths(matches []PathValue) []string {
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		paths = append(paths, match.Path)
+	}
+	return paths
+}
+
+func TestJson_GetGlob(t *testing.T) {
+	manifest, _ := Parse([]byte(`{"spec":{"containers":[{"image":"a","ports":[1]},{"image":"b","sidecar":{"image":"c"}}],"image":"top"},"metadata":{"image":"meta","a.b":{"c*":1}}}`))
+	images := manifest.GetGlob("spec.**.image")
+	assert.Equal(t, []string{"spec.containers.0.image", "spec.containers.1.image", "spec.containers.1.sidecar.image", "spec.image"}, globPaths(images))
+	assert.True(t, images[2].Value.MustString() == "c")
+	assert.Equal(t, []string{"metadata.image", "spec.containers.0.image", "spec.containers.1.image", "spec.containers.1.sidecar.image", "spec.image"}, globPaths(manifest.GetGlob("**.image")))
+	assert.Equal(t, []string{"spec.containers.0", "spec.containers.0.image", "spec.containers.0.ports", "spec.containers.0.ports.0"}, globPaths(manifest.GetGlob("spec.containers.0.**")))
+	assert.Equal(t, []string{"spec.containers.0", "spec.containers.1"}, globPaths(manifest.GetGlob("spec.containers.*")))
+	assert.Equal(t, []string{"spec.containers.1.sidecar"}, globPaths(manifest.GetGlob("spec.*.1.sidecar")))
+	assert.Equal(t, []string{"metadata.a\\.b.c\\*"}, globPaths(manifest.GetGlob("metadata.a\\.b.c\\*")))
+	assert.True(t, manifest.GetGlob("metadata.a\\.b.c\\*")[0].Value.MustInt() == 1)
+	assert.Equal(t, []string{}, globPaths(manifest.GetGlob("spec.nothing.**")))
+	assert.True(t, len(manifest.GetGlob("**")) == 16)
+	assert.Equal(t, []string{}, globPaths(NewEmpty().GetGlob("**")))
+}
+
+func TestJson_DelGlob(t *testing.T) {
+	manifest, _ := Parse([]byte(`{"metadata":{"managedFields":[{"x":1}],"name":"n"},"spec":{"items":[{"managedFields":1,"keep":1},{"managedFields":2}],"managedFields":{"managedFields":3}}}`))
+	assert.True(t, manifest.DelGlob("**.managedFields") == 4)
+	assert.True(t, manifest.DigestJSONForEqual() == `{"metadata":{"name":"n"},"spec":{"items":[{"keep":1},{}]}}`)
+
+	list, _ := Parse([]byte(`{"items":[0,1,2,3,4]}`))
+	assert.True(t, list.DelGlob("items.*") == 5)
+	assert.True(t, list.DigestJSONForEqual() == `{"items":[]}`)
+	assert.True(t, list.DelGlob("**") == 1)
+	assert.True(t, list.DigestJSONForEqual() == `{}`)
+	assert.True(t, list.DelGlob("nothing") == 0)
+}
+
+func BenchmarkJson_Paths(b *testing.B) {
+	a := NewJSONArray()
+	for i := 0; i < 10000; i++ {
+		a.TryAdd(Obj("id", i, "name", "item", "tags", Arr("x", "y")))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Paths()
+	}
+}
@@ -0,0 +1,83 @@
+package betterjson
+
+// StripJSONComments returns a copy of data with // line comments, /* block */
+// comments and trailing commas before a closing } or ] blanked out. the
+// removed bytes are replaced by spaces (newlines are kept), so offsets and
+// line numbers in later parse errors still match the original input.
+// sequences inside string literals are never touched
+func StripJSONComments(data []byte) []byte {
+	result := make([]byte, len(data))
+	copy(result, data)
+	inString := false
+	for i := 0; i < len(result); i++ {
+		c := result[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(result) && result[i+1] == '/':
+			for i < len(result) && result[i] != '\n' {
+				result[i] = ' '
+				i++
+			}
+		case c == '/' && i+1 < len(result) && result[i+1] == '*':
+			end := i + 2
+			for end+1 < len(result) && !(result[end] == '*' && result[end+1] == '/') {
+				end++
+			}
+			if end+1 >= len(result) {
+				// unterminated comment, left for the parser to report
+				return result
+			}
+			blank(result[i : end+2])
+			i = end + 1
+		case c == ',' && closesAfter(result, i+1):
+			result[i] = ' '
+		}
+	}
+	return result
+}
+
+// closesAfter reports whether the next significant byte from start, skipping
+// whitespace and comments, is a closing brace or bracket
+func closesAfter(data []byte, start int) bool {
+	for i := start; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		default:
+			return c == '}' || c == ']'
+		}
+	}
+	return false
+}
+
+func blank(data []byte) {
+	for i, c := range data {
+		if c != '\n' {
+			data[i] = ' '
+		}
+	}
+}
+
+// ParseLenient parses hand-edited json that may contain comments and trailing
+// commas, see StripJSONComments. the result is plain json, Encode emits strict json
+func ParseLenient(data []byte) (*Json, error) {
+	return Parse(StripJSONComments(data))
+}
@@ -0,0 +1,36 @@
+package betterjson
+
+import (
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripJSONComments(t *testing.T) {
+	input := "{\"a\": 1, // line comment\n\"b\": /* block, */ 2,}"
+	stripped := string(StripJSONComments([]byte(input)))
+	println(stripped)
+	assert.True(t, len(stripped) == len(input))
+	assert.True(t, stripped == "{\"a\": 1,"+strings.Repeat(" ", 16)+"\n\"b\": "+strings.Repeat(" ", 13)+"2 }")
+	unchanged := "{\"url\":\"http://example.com/*x*/\",\"s\":\"a,]\\\"//\"}"
+	assert.True(t, string(StripJSONComments([]byte(unchanged))) == unchanged)
+}
+
+func TestParseLenient(t *testing.T) {
+	input := `{
+		// service config
+		"url": "https://example.com//path", /* keep the // in the string */
+		"items": [1, 2, [3, 4,], {"x": "a,}",},],
+		"nested": {"a": {"b": "/* not a comment */",}, /* trailing */ },
+	}`
+	a, err := ParseLenient([]byte(input))
+	assert.True(t, err == nil, err)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	println(aStr)
+	assert.True(t, aStr == `{"items":[1,2,[3,4],{"x":"a,}"}],"nested":{"a":{"b":"/* not a comment */"}},"url":"https://example.com//path"}`)
+	_, err = ParseLenient([]byte(`{"a":1 /* unterminated`))
+	assert.True(t, err != nil)
+	_, err = ParseLenient([]byte(`[1,,]`))
+	assert.True(t, err != nil)
+}
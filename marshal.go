@@ -0,0 +1,240 @@
+package betterjson
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// the binary form of MarshalBinary is the raw value tree written out as is,
+// for caches that would otherwise store json text and parse it again. it is
+// a version byte followed by the root value: a tag byte, then for strings,
+// numbers and []byte an uvarint length and the bytes, for arrays an uvarint
+// length and the items, for objects an uvarint length and the key, value
+// pairs in sorted key order. json.Number keeps its literal, and an empty Json
+// has a tag of its own, so both survive the round trip
+
+const binaryFormatVersion = 1
+
+const (
+	binaryTagEmpty byte = iota
+	binaryTagNull
+	binaryTagFalse
+	binaryTagTrue
+	binaryTagString
+	binaryTagNumber
+	binaryTagFloat
+	binaryTagInt
+	binaryTagUint
+	binaryTagBytes
+	binaryTagArray
+	binaryTagObject
+)
+
+// MarshalBinary encodes j in a compact binary form for UnmarshalBinary, it
+// makes Json an encoding.BinaryMarshaler and so encodable with encoding/gob:
+//    data, _ := js.MarshalBinary()
+//    cache.Set(key, data)
+//    ...
+//    cached := betterjson.NewEmpty()
+//    err := cached.UnmarshalBinary(data)
+func (j *Json) MarshalBinary() ([]byte, error) {
+	dst := append(make([]byte, 0, 64), binaryFormatVersion)
+	if j.IsEmpty() {
+		return append(dst, binaryTagEmpty), nil
+	}
+	return appendBinary(dst, j.Interface())
+}
+
+// UnmarshalBinary replaces the value of j by the one encoded by MarshalBinary
+func (j *Json) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryFormatVersion {
+		return errors.New("invalid binary json: unknown format version")
+	}
+	d := &binaryDecoder{data: data, pos: 1}
+	if len(data) == 2 && data[1] == binaryTagEmpty {
+		j.value = nil
+		j.writeBack()
+		return nil
+	}
+	root, err := d.decode()
+	if err != nil {
+		return err
+	}
+	if d.pos != len(data) {
+		return errors.Errorf("invalid binary json: %d trailing bytes", len(data)-d.pos)
+	}
+	prior := j.prior([]string{})
+	j.replaceData(root)
+	j.changed(setOperation([]string{}, root, prior), prior)
+	return nil
+}
+
+func appendBinary(dst []byte, node interface{}) ([]byte, error) {
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		return append(dst, binaryTagNull), nil
+	case bool:
+		if value {
+			return append(dst, binaryTagTrue), nil
+		}
+		return append(dst, binaryTagFalse), nil
+	case string:
+		return appendBinaryString(append(dst, binaryTagString), value), nil
+	case json.Number:
+		return appendBinaryString(append(dst, binaryTagNumber), string(value)), nil
+	case []byte:
+		return appendBinaryString(append(dst, binaryTagBytes), string(value)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(dst, binaryTagFloat), math.Float64bits(value)), nil
+	case float32:
+		return binary.LittleEndian.AppendUint64(append(dst, binaryTagFloat), math.Float64bits(float64(value))), nil
+	case int, int8, int16, int32, int64:
+		return binary.AppendVarint(append(dst, binaryTagInt), reflect.ValueOf(value).Int()), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return binary.AppendUvarint(append(dst, binaryTagUint), reflect.ValueOf(value).Uint()), nil
+	case []interface{}:
+		dst = binary.AppendUvarint(append(dst, binaryTagArray), uint64(len(value)))
+		var err error
+		for _, item := range value {
+			if dst, err = appendBinary(dst, item); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case map[string]interface{}:
+		dst = binary.AppendUvarint(append(dst, binaryTagObject), uint64(len(value)))
+		var err error
+		for _, key := range sortedKeys(value) {
+			dst = appendBinaryString(dst, key)
+			if dst, err = appendBinary(dst, value[key]); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	}
+	return nil, errors.Errorf("can't encode %T in binary form", node)
+}
+
+func appendBinaryString(dst []byte, s string) []byte {
+	return append(binary.AppendUvarint(dst, uint64(len(s))), s...)
+}
+
+type binaryDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *binaryDecoder) truncated() error {
+	return errors.Errorf("invalid binary json: unexpected end of data at offset %d", d.pos)
+}
+
+func (d *binaryDecoder) uvarint() (uint64, error) {
+	u, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, d.truncated()
+	}
+	d.pos += n
+	return u, nil
+}
+
+// length reads a length, which can't be more than the bytes left
+func (d *binaryDecoder) length() (int, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)-d.pos) {
+		return 0, d.truncated()
+	}
+	return int(n), nil
+}
+
+func (d *binaryDecoder) bytes() ([]byte, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	chunk := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return chunk, nil
+}
+
+func (d *binaryDecoder) decode() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, d.truncated()
+	}
+	tag := d.data[d.pos]
+	d.pos++
+	switch tag {
+	case binaryTagNull:
+		return nil, nil
+	case binaryTagFalse:
+		return false, nil
+	case binaryTagTrue:
+		return true, nil
+	case binaryTagString, binaryTagNumber:
+		chunk, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		if tag == binaryTagNumber {
+			return json.Number(chunk), nil
+		}
+		return string(chunk), nil
+	case binaryTagBytes:
+		chunk, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, chunk...), nil
+	case binaryTagFloat:
+		if len(d.data)-d.pos < 8 {
+			return nil, d.truncated()
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(d.data[d.pos:]))
+		d.pos += 8
+		return f, nil
+	case binaryTagInt:
+		i, n := binary.Varint(d.data[d.pos:])
+		if n <= 0 {
+			return nil, d.truncated()
+		}
+		d.pos += n
+		return i, nil
+	case binaryTagUint:
+		return d.uvarint()
+	case binaryTagArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		array := make([]interface{}, n)
+		for idx := range array {
+			if array[idx], err = d.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	case binaryTagObject:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		object := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			key, err := d.bytes()
+			if err != nil {
+				return nil, err
+			}
+			if object[string(key)], err = d.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	}
+	return nil, errors.Errorf("invalid binary json: unknown tag %d at offset %d", tag, d.pos-1)
+}
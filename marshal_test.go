@@ -0,0 +1,94 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	js, _ := Parse([]byte(`{"a":[1,2.50,-3e400,"x",null,true,false,{}],"big":123456789012345678901234567890,"nested":{"k":"v","empty":[]}}`))
+	js.Set("int", -7).Set("uint", uint8(200)).Set("float", 0.25).Set("bytes", []byte{1, 2})
+	data, err := js.MarshalBinary()
+	assert.True(t, err == nil)
+	decoded := NewEmpty()
+	assert.True(t, decoded.UnmarshalBinary(data) == nil)
+	assert.True(t, js.IsSameJSONWith(decoded))
+	// number literals are kept as they were
+	assert.Equal(t, json.Number("2.50"), decoded.Get("a").GetIndex(1).Interface())
+	assert.Equal(t, "123456789012345678901234567890", decoded.Get("big").EncodeToStringOrDefault(""))
+	assert.True(t, decoded.Get("a").GetIndex(4).IsNull())
+	assert.True(t, !decoded.Get("a").GetIndex(4).IsEmpty())
+
+	again, _ := decoded.MarshalBinary()
+	assert.Equal(t, data, again)
+}
+
+func TestMarshalBinaryEmpty(t *testing.T) {
+	data, err := NewEmpty().MarshalBinary()
+	assert.True(t, err == nil)
+	decoded := NewJSONObject()
+	assert.True(t, decoded.UnmarshalBinary(data) == nil)
+	assert.True(t, decoded.IsEmpty())
+
+	null, _ := Parse([]byte(`null`))
+	data, _ = null.MarshalBinary()
+	assert.True(t, decoded.UnmarshalBinary(data) == nil)
+	assert.True(t, !decoded.IsEmpty())
+	assert.True(t, decoded.IsNull())
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	js := Obj("a", Arr("long string value", 1))
+	data, _ := js.MarshalBinary()
+	for cut := 0; cut < len(data); cut++ {
+		assert.True(t, NewEmpty().UnmarshalBinary(data[:cut]) != nil)
+	}
+	err := NewEmpty().UnmarshalBinary(append(append([]byte{}, data...), 0))
+	assert.Equal(t, "invalid binary json: 1 trailing bytes", err.Error())
+	err = NewEmpty().UnmarshalBinary([]byte{binaryFormatVersion, 99})
+	assert.Equal(t, "invalid binary json: unknown tag 99 at offset 1", err.Error())
+	err = NewEmpty().UnmarshalBinary([]byte{2, binaryTagNull})
+	assert.Equal(t, "invalid binary json: unknown format version", err.Error())
+}
+
+func TestMarshalBinaryGob(t *testing.T) {
+	type entry struct {
+		Name string
+		Doc  *Json
+	}
+	js := Obj("a", 1, "b", Arr("x", nil))
+	var buffer bytes.Buffer
+	assert.True(t, gob.NewEncoder(&buffer).Encode(entry{Name: "config", Doc: js}) == nil)
+	var decoded entry
+	assert.True(t, gob.NewDecoder(&buffer).Decode(&decoded) == nil)
+	assert.Equal(t, "config", decoded.Name)
+	assert.True(t, js.IsSameJSONWith(decoded.Doc))
+}
+
+func benchmarkDocument() *Json {
+	items := make([]interface{}, 3500)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": json.Number(strconv.Itoa(i)), "name": "item " + strconv.Itoa(i), "active": i%2 == 0, "score": json.Number("12.5")}
+	}
+	return wrapRaw(map[string]interface{}{"items": items})
+}
+
+func BenchmarkUnmarshalBinary(b *testing.B) {
+	data, _ := benchmarkDocument().MarshalBinary()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		NewEmpty().UnmarshalBinary(data)
+	}
+}
+
+func BenchmarkParseText(b *testing.B) {
+	data, _ := benchmarkDocument().Encode()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		Parse(data)
+	}
+}
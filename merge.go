@@ -0,0 +1,164 @@
+package betterjson
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConflictStrategy picks the value ThreeWayMerge keeps when ours and theirs
+// changed the same path differently
+type ConflictStrategy int
+
+const (
+	// ConflictPreferOurs keeps the value of ours
+	ConflictPreferOurs ConflictStrategy = iota
+	// ConflictPreferTheirs keeps the value of theirs
+	ConflictPreferTheirs
+	// ConflictFail makes the merge fail with an error
+	ConflictFail
+)
+
+// MergeOptions configures ThreeWayMergeWithOptions
+type MergeOptions struct {
+	Strategy ConflictStrategy
+}
+
+// Conflict is a path ours and theirs changed differently. the values are
+// copies, empty on the sides where the path is missing
+type Conflict struct {
+	Path   []string
+	Base   *Json
+	Ours   *Json
+	Theirs *Json
+}
+
+// ThreeWayMerge is ThreeWayMergeWithOptions preferring ours on conflicts
+func ThreeWayMerge(base, ours, theirs *Json) (*Json, []Conflict, error) {
+	return ThreeWayMergeWithOptions(base, ours, theirs, MergeOptions{})
+}
+
+// ThreeWayMergeWithOptions merges the changes ours and theirs made to base
+// into a new document. a path changed, added or removed on one side only takes
+// that change, a path changed the same way on both sides takes it once, and
+// objects changed on both sides are merged key by key. anything else changed
+// differently on both sides, arrays as a whole included, is a Conflict,
+// resolved by opts.Strategy. with ConflictFail the result is empty and the
+// error comes with the conflicts:
+//    merged, conflicts, err := betterjson.ThreeWayMerge(base, userEdits, automatedEdits)
+func ThreeWayMergeWithOptions(base, ours, theirs *Json, opts MergeOptions) (*Json, []Conflict, error) {
+	return ThreeWayMergeCtx(context.Background(), base, ours, theirs, opts)
+}
+
+// ThreeWayMergeCtx is ThreeWayMergeWithOptions giving up with a
+// CanceledError and an empty result once ctx is done
+func ThreeWayMergeCtx(ctx context.Context, base, ours, theirs *Json, opts MergeOptions) (*Json, []Conflict, error) {
+	if sink := metrics; sink != nil {
+		start := time.Now()
+		merged, conflicts, err := threeWayMergeCtx(ctx, base, ours, theirs, opts)
+		observe(sink, "merge", start, len(conflicts), err)
+		return merged, conflicts, err
+	}
+	return threeWayMergeCtx(ctx, base, ours, theirs, opts)
+}
+
+func threeWayMergeCtx(ctx context.Context, base, ours, theirs *Json, opts MergeOptions) (*Json, []Conflict, error) {
+	conflicts := make([]Conflict, 0)
+	baseNode, baseOk := documentRoot(base)
+	oursNode, oursOk := documentRoot(ours)
+	theirsNode, theirsOk := documentRoot(theirs)
+	merged, ok, err := mergeRaw([]string{}, mergeSide{baseNode, baseOk}, mergeSide{oursNode, oursOk}, mergeSide{theirsNode, theirsOk}, opts, &conflicts, newProgress(ctx, "merge"))
+	if err != nil {
+		return NewEmpty(), conflicts, err
+	}
+	if opts.Strategy == ConflictFail && len(conflicts) > 0 {
+		return NewEmpty(), conflicts, errors.Errorf("%d merge conflicts, the first at %s", len(conflicts), displayPath(conflicts[0].Path))
+	}
+	if !ok {
+		return NewEmpty(), conflicts, nil
+	}
+	return wrapRaw(deepCopyRaw(merged)), conflicts, nil
+}
+
+// mergeSide is the value at a path of one merged document, if it has one
+type mergeSide struct {
+	node   interface{}
+	exists bool
+}
+
+func (side mergeSide) same(other mergeSide) bool {
+	if !side.exists || !other.exists {
+		return side.exists == other.exists
+	}
+	return rawEqual(side.node, other.node)
+}
+
+func (side mergeSide) child(key string) mergeSide {
+	if object, ok := unwrapRaw(side.node).(map[string]interface{}); ok && side.exists {
+		item, exists := object[key]
+		return mergeSide{item, exists}
+	}
+	return mergeSide{}
+}
+
+func (side mergeSide) object() (map[string]interface{}, bool) {
+	object, ok := unwrapRaw(side.node).(map[string]interface{})
+	return object, ok && side.exists && object != nil
+}
+
+func mergeRaw(branch []string, base, ours, theirs mergeSide, opts MergeOptions, conflicts *[]Conflict, p *progress) (interface{}, bool, error) {
+	if err := p.visit(branch); err != nil {
+		return nil, false, err
+	}
+	switch {
+	case ours.same(theirs), theirs.same(base):
+		return ours.node, ours.exists, nil
+	case ours.same(base):
+		return theirs.node, theirs.exists, nil
+	}
+	oursObject, oursIsObject := ours.object()
+	theirsObject, theirsIsObject := theirs.object()
+	if oursIsObject && theirsIsObject {
+		keys := make([]string, 0, len(oursObject)+len(theirsObject))
+		for key := range oursObject {
+			keys = append(keys, key)
+		}
+		for key := range theirsObject {
+			if _, ok := oursObject[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		merged := make(map[string]interface{}, len(keys))
+		for _, key := range keys {
+			child := append(branch[:len(branch):len(branch)], key)
+			item, ok, err := mergeRaw(child, base.child(key), ours.child(key), theirs.child(key), opts, conflicts, p)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				merged[key] = item
+			}
+		}
+		return merged, true, nil
+	}
+	*conflicts = append(*conflicts, Conflict{
+		Path:   append([]string{}, branch...),
+		Base:   conflictValue(base),
+		Ours:   conflictValue(ours),
+		Theirs: conflictValue(theirs),
+	})
+	if opts.Strategy == ConflictPreferTheirs {
+		return theirs.node, theirs.exists, nil
+	}
+	return ours.node, ours.exists, nil
+}
+
+func conflictValue(side mergeSide) *Json {
+	if !side.exists {
+		return NewEmpty()
+	}
+	return wrapRaw(deepCopyRaw(side.node))
+}
@@ -0,0 +1,84 @@
+package betterjson_test
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/zoowii/betterjson"
+	"github.com/zoowii/betterjson/betterjsontest"
+)
+
+func mergeFixture(t *testing.T, text string) *betterjson.Json {
+	js, err := betterjson.Parse([]byte(text))
+	assert.True(t, err == nil)
+	return js
+}
+
+func TestThreeWayMergeClean(t *testing.T) {
+	base := mergeFixture(t, `{"name":"svc","port":80,"tags":["a"],"limits":{"cpu":1,"mem":2},"old":true}`)
+	ours := mergeFixture(t, `{"name":"svc","port":8080,"tags":["a"],"limits":{"cpu":1,"mem":4},"old":true,"owner":"me"}`)
+	theirs := mergeFixture(t, `{"name":"svc2","port":80,"tags":["a","b"],"limits":{"cpu":2,"mem":2}}`)
+	merged, conflicts, err := betterjson.ThreeWayMerge(base, ours, theirs)
+	assert.True(t, err == nil)
+	assert.Equal(t, 0, len(conflicts))
+	println(merged.EncodeToStringOrDefault(""))
+	betterjsontest.AssertEqualJSON(t, `{"limits":{"cpu":2,"mem":4},"name":"svc2","owner":"me","port":8080,"tags":["a","b"]}`, merged)
+	// the merge shares nothing with its inputs
+	merged.Get("limits").Set("cpu", 9)
+	betterjsontest.AssertPathEquals(t, theirs, "limits.cpu", 2)
+}
+
+func TestThreeWayMergeIdenticalEdits(t *testing.T) {
+	base := mergeFixture(t, `{"version":1,"list":[1],"gone":1}`)
+	ours := mergeFixture(t, `{"version":2,"list":[1,2],"added":{"x":1}}`)
+	theirs := mergeFixture(t, `{"version":2.0,"list":[1,2],"added":{"x":1}}`)
+	merged, conflicts, err := betterjson.ThreeWayMerge(base, ours, theirs)
+	assert.True(t, err == nil)
+	assert.Equal(t, 0, len(conflicts))
+	// the literal of ours is kept when both sides agree on the value
+	assert.Equal(t, `{"added":{"x":1},"list":[1,2],"version":2}`, merged.EncodeToStringOrDefault(""))
+}
+
+func TestThreeWayMergeConflicts(t *testing.T) {
+	base := mergeFixture(t, `{"port":80,"db":{"host":"a","pool":{"size":1}},"list":[1],"keep":1}`)
+	ours := mergeFixture(t, `{"port":81,"db":{"host":"a","pool":{"size":2}},"list":[1,2],"keep":1}`)
+	theirs := mergeFixture(t, `{"port":82,"db":{"host":"b","pool":"default"},"list":[0,1],"keep":2}`)
+	merged, conflicts, err := betterjson.ThreeWayMerge(base, ours, theirs)
+	assert.True(t, err == nil)
+	paths := make([]string, 0)
+	for _, conflict := range conflicts {
+		paths = append(paths, betterjson.JoinDottedPath(conflict.Path))
+	}
+	assert.Equal(t, []string{"db.pool", "list", "port"}, paths)
+	betterjsontest.AssertEqualJSON(t, `{"size":1}`, conflicts[0].Base)
+	betterjsontest.AssertEqualJSON(t, `{"size":2}`, conflicts[0].Ours)
+	assert.Equal(t, `"default"`, conflicts[0].Theirs.EncodeToStringOrDefault(""))
+	betterjsontest.AssertEqualJSON(t, `{"db":{"host":"b","pool":{"size":2}},"keep":2,"list":[1,2],"port":81}`, merged)
+
+	merged, _, _ = betterjson.ThreeWayMergeWithOptions(base, ours, theirs, betterjson.MergeOptions{Strategy: betterjson.ConflictPreferTheirs})
+	betterjsontest.AssertEqualJSON(t, `{"db":{"host":"b","pool":"default"},"keep":2,"list":[0,1],"port":82}`, merged)
+
+	merged, conflicts, err = betterjson.ThreeWayMergeWithOptions(base, ours, theirs, betterjson.MergeOptions{Strategy: betterjson.ConflictFail})
+	println(err.Error())
+	assert.Equal(t, "3 merge conflicts, the first at db.pool", err.Error())
+	assert.True(t, merged.IsEmpty())
+	assert.Equal(t, 3, len(conflicts))
+}
+
+func TestThreeWayMergeDeleteAgainstEdit(t *testing.T) {
+	base := mergeFixture(t, `{"a":{"x":1},"b":1}`)
+	ours := mergeFixture(t, `{"b":1}`)
+	theirs := mergeFixture(t, `{"a":{"x":2},"b":1,"c":{"n":1}}`)
+	ours2 := mergeFixture(t, `{"a":{"x":1},"b":1,"c":{"m":1}}`)
+	merged, conflicts, _ := betterjson.ThreeWayMerge(base, ours, theirs)
+	assert.Equal(t, 1, len(conflicts))
+	assert.True(t, conflicts[0].Ours.IsEmpty())
+	betterjsontest.AssertEqualJSON(t, `{"b":1,"c":{"n":1}}`, merged)
+
+	// objects added on both sides are merged like edits of an empty base
+	merged, conflicts, _ = betterjson.ThreeWayMerge(base, ours2, theirs)
+	assert.Equal(t, 0, len(conflicts))
+	betterjsontest.AssertEqualJSON(t, `{"a":{"x":2},"b":1,"c":{"m":1,"n":1}}`, merged)
+
+	merged, conflicts, _ = betterjson.ThreeWayMerge(betterjson.NewEmpty(), betterjson.NewEmpty(), nil)
+	assert.True(t, merged.IsEmpty() && len(conflicts) == 0)
+}
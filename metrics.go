@@ -0,0 +1,84 @@
+package betterjson
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// MetricsSink receives observations about Parse, Encode, DiffReport and
+// ThreeWayMerge calls after EnableMetrics. name is the operation, "parse",
+// "encode", "diff" or "merge", and for IncCounter also a suffixed counter like
+// "parse.errors". ObserveSize gets the bytes parsed or encoded, and the number
+// of differences or conflicts found by diff and merge. methods may be called
+// from many goroutines at once
+type MetricsSink interface {
+	IncCounter(name string)
+	ObserveDuration(name string, d time.Duration)
+	ObserveSize(name string, n int)
+}
+
+// metrics is the sink set by EnableMetrics, nil when disabled
+var metrics MetricsSink
+
+// EnableMetrics makes the package report its operations to sink, nil disables
+// the reporting again. it isn't synchronized with the operations, so call it
+// before using the package, like at program start:
+//    betterjson.EnableMetrics(betterjson.NewExpvarSink("betterjson"))
+func EnableMetrics(sink MetricsSink) {
+	metrics = sink
+}
+
+// observe reports one operation that started at start to sink
+func observe(sink MetricsSink, name string, start time.Time, size int, err error) {
+	sink.IncCounter(name)
+	if err != nil {
+		sink.IncCounter(name + ".errors")
+	}
+	sink.ObserveDuration(name, time.Since(start))
+	sink.ObserveSize(name, size)
+}
+
+// ExpvarSink is a MetricsSink publishing an expvar.Map with, per operation
+// name, the count as name, the total nanoseconds as name.nanoseconds, the
+// total size as name.size and the largest size as name.max_size
+type ExpvarSink struct {
+	values *expvar.Map
+	mutex  sync.Mutex
+	max    map[string]int
+}
+
+// NewExpvarSink publishes the map of an ExpvarSink as the expvar variable
+// name, reusing the map when name was published by an earlier call
+func NewExpvarSink(name string) *ExpvarSink {
+	values, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		values = expvar.NewMap(name)
+	}
+	return &ExpvarSink{values: values, max: make(map[string]int)}
+}
+
+func (s *ExpvarSink) IncCounter(name string) {
+	s.values.Add(name, 1)
+}
+
+func (s *ExpvarSink) ObserveDuration(name string, d time.Duration) {
+	s.values.Add(name+".nanoseconds", d.Nanoseconds())
+}
+
+func (s *ExpvarSink) ObserveSize(name string, n int) {
+	s.values.Add(name+".size", int64(n))
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if n > s.max[name] || s.values.Get(name+".max_size") == nil {
+		s.max[name] = n
+		max := new(expvar.Int)
+		max.Set(int64(n))
+		s.values.Set(name+".max_size", max)
+	}
+}
+
+// String returns the published map as json
+func (s *ExpvarSink) String() string {
+	return s.values.String()
+}
@@ -0,0 +1,100 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	mutex     sync.Mutex
+	counters  map[string]int
+	durations map[string]int
+	sizes     map[string][]int
+}
+
+func newRecordingSink() *recordingSink {
+	return &recordingSink{counters: make(map[string]int), durations: make(map[string]int), sizes: make(map[string][]int)}
+}
+
+func (s *recordingSink) IncCounter(name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.counters[name]++
+}
+
+func (s *recordingSink) ObserveDuration(name string, d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if d >= 0 {
+		s.durations[name]++
+	}
+}
+
+func (s *recordingSink) ObserveSize(name string, n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sizes[name] = append(s.sizes[name], n)
+}
+
+func TestEnableMetrics(t *testing.T) {
+	sink := newRecordingSink()
+	EnableMetrics(sink)
+	defer EnableMetrics(nil)
+
+	data := []byte(`{"a":1,"b":[1,2]}`)
+	a, err := Parse(data)
+	assert.True(t, err == nil)
+	_, err = Parse([]byte(`{"a":`))
+	assert.True(t, err != nil)
+	b, _ := ParseReaderWithOptions(bytes.NewReader([]byte(`{"a":2}`)), ParseOptions{})
+	assert.Equal(t, 3, sink.counters["parse"])
+	assert.Equal(t, 1, sink.counters["parse.errors"])
+	assert.Equal(t, 3, sink.durations["parse"])
+	assert.Equal(t, []int{len(data), 5, 7}, sink.sizes["parse"])
+
+	encoded, _ := a.Encode()
+	var out bytes.Buffer
+	a.EncodeTo(&out)
+	assert.Equal(t, 2, sink.counters["encode"])
+	assert.Equal(t, []int{len(encoded), out.Len()}, sink.sizes["encode"])
+
+	DiffReport(a, b)
+	ThreeWayMerge(a, b, a)
+	assert.Equal(t, 1, sink.counters["diff"])
+	assert.Equal(t, []int{2}, sink.sizes["diff"])
+	assert.Equal(t, 1, sink.counters["merge"])
+	assert.Equal(t, []int{0}, sink.sizes["merge"])
+
+	EnableMetrics(nil)
+	Parse(data)
+	assert.Equal(t, 3, sink.counters["parse"])
+}
+
+func TestExpvarSink(t *testing.T) {
+	sink := NewExpvarSink("betterjson_test")
+	EnableMetrics(sink)
+	defer EnableMetrics(nil)
+	Parse([]byte(`[1,2,3]`))
+	Parse([]byte(`[1]`))
+	assert.True(t, NewExpvarSink("betterjson_test").values == sink.values)
+
+	published, _ := Parse([]byte(expvar.Get("betterjson_test").String()))
+	assert.Equal(t, int64(2), published.Get("parse").MustInt64())
+	assert.Equal(t, int64(10), published.Get("parse.size").MustInt64())
+	assert.Equal(t, int64(7), published.Get("parse.max_size").MustInt64())
+	assert.True(t, published.Get("parse.nanoseconds").MustInt64() > 0)
+	assert.True(t, json.Valid([]byte(sink.String())))
+	println(sink.String())
+}
+
+func BenchmarkParseWithoutMetrics(b *testing.B) {
+	data := []byte(`{"a":1,"b":[1,2,3],"c":{"d":"e"}}`)
+	for i := 0; i < b.N; i++ {
+		Parse(data)
+	}
+}
@@ -0,0 +1,376 @@
+package betterjson
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// the MessagePack codec is implemented here without third party packages,
+// so only callers of these functions pay for it.
+//
+// type mapping: nil, bool, string, array and map map to their MessagePack
+// counterparts. integers decode to json.Number holding the integer literal
+// and floats to json.Number holding the shortest float literal, so a float that
+// happens to be integral (1.0) decodes as "1" and no longer digests equal to the
+// json text "1.0". json.Number values encode as int when they parse as an
+// integer and as float64 otherwise. non-string map keys are stringified
+
+// MsgPackOptions controls how FromMsgPackWithOptions maps MessagePack values
+type MsgPackOptions struct {
+	// BinaryAsBytes keeps bin values as []byte instead of base64 strings.
+	// []byte leaves still encode to json as base64 strings and back to bin
+	// values by EncodeMsgPack
+	BinaryAsBytes bool
+}
+
+// EncodeMsgPack encodes j as MessagePack. map keys are written sorted so the
+// output is deterministic
+func (j *Json) EncodeMsgPack() ([]byte, error) {
+	if j.IsEmpty() {
+		return []byte{}, errors.New("empty json can't be encoded")
+	}
+	return appendMsgPack(make([]byte, 0, 64), j.value.Interface())
+}
+
+// FromMsgPack decodes a MessagePack value, mapping bin values to base64 strings
+func FromMsgPack(data []byte) (*Json, error) {
+	return FromMsgPackWithOptions(data, MsgPackOptions{})
+}
+
+// FromMsgPackWithOptions decodes a MessagePack value, see MsgPackOptions
+func FromMsgPackWithOptions(data []byte, opts MsgPackOptions) (*Json, error) {
+	d := &msgPackDecoder{data: data, opts: opts}
+	node, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, errors.Errorf("invalid msgpack: %d trailing bytes", len(data)-d.pos)
+	}
+	value := simplejson.New()
+	value.SetPath([]string{}, node)
+	return FromNotEmptySimpleJson(value), nil
+}
+
+func appendMsgPack(dst []byte, node interface{}) ([]byte, error) {
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		return append(dst, 0xc0), nil
+	case bool:
+		if value {
+			return append(dst, 0xc3), nil
+		}
+		return append(dst, 0xc2), nil
+	case string:
+		return appendMsgPackString(dst, value), nil
+	case []byte:
+		return appendMsgPackBinary(dst, value), nil
+	case json.Number:
+		if isNegativeZero(value) {
+			return appendMsgPackFloat64(dst, math.Copysign(0, -1)), nil
+		}
+		if i, err := value.Int64(); err == nil {
+			return appendMsgPackInt(dst, i), nil
+		}
+		if u, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			return appendMsgPackUint(dst, u), nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return nil, errors.Errorf("invalid number %q", string(value))
+		}
+		return appendMsgPackFloat64(dst, f), nil
+	case float64:
+		return appendMsgPackFloat64(dst, value), nil
+	case float32:
+		dst = append(dst, 0xca)
+		return binary.BigEndian.AppendUint32(dst, math.Float32bits(value)), nil
+	case int, int8, int16, int32, int64:
+		return appendMsgPackInt(dst, reflect.ValueOf(value).Int()), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return appendMsgPackUint(dst, reflect.ValueOf(value).Uint()), nil
+	case []interface{}:
+		dst = appendMsgPackHeader(dst, len(value), 0x90, 16, 0xdc, 0xdd)
+		var err error
+		for _, item := range value {
+			if dst, err = appendMsgPack(dst, item); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case map[string]interface{}:
+		dst = appendMsgPackHeader(dst, len(value), 0x80, 16, 0xde, 0xdf)
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var err error
+		for _, key := range keys {
+			dst = appendMsgPackString(dst, key)
+			if dst, err = appendMsgPack(dst, value[key]); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	}
+	return nil, errors.Errorf("can't encode %T as msgpack", node)
+}
+
+// appendMsgPackHeader writes a fix/16/32 length header
+func appendMsgPackHeader(dst []byte, length int, fix byte, fixMax int, code16 byte, code32 byte) []byte {
+	switch {
+	case length < fixMax:
+		return append(dst, fix|byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, code16), uint16(length))
+	}
+	return binary.BigEndian.AppendUint32(append(dst, code32), uint32(length))
+}
+
+func appendMsgPackString(dst []byte, s string) []byte {
+	if len(s) >= 32 && len(s) <= math.MaxUint8 {
+		dst = append(dst, 0xd9, byte(len(s)))
+	} else {
+		dst = appendMsgPackHeader(dst, len(s), 0xa0, 32, 0xda, 0xdb)
+	}
+	return append(dst, s...)
+}
+
+func appendMsgPackBinary(dst []byte, data []byte) []byte {
+	switch {
+	case len(data) <= math.MaxUint8:
+		dst = append(dst, 0xc4, byte(len(data)))
+	case len(data) <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xc5), uint16(len(data)))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xc6), uint32(len(data)))
+	}
+	return append(dst, data...)
+}
+
+func appendMsgPackInt(dst []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgPackUint(dst, uint64(i))
+	case i >= -32:
+		return append(dst, byte(i))
+	case i >= math.MinInt8:
+		return append(dst, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(i))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(i))
+}
+
+func appendMsgPackUint(dst []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(dst, byte(u))
+	case u <= math.MaxUint8:
+		return append(dst, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(u))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, 0xcf), u)
+}
+
+func appendMsgPackFloat64(dst []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(dst, 0xcb), math.Float64bits(f))
+}
+
+type msgPackDecoder struct {
+	data []byte
+	pos  int
+	opts MsgPackOptions
+}
+
+func (d *msgPackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errors.Errorf("invalid msgpack: unexpected end of data at offset %d", d.pos)
+	}
+	chunk := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return chunk, nil
+}
+
+func (d *msgPackDecoder) readUint(size int) (uint64, error) {
+	chunk, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(chunk[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(chunk)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(chunk)), nil
+	}
+	return binary.BigEndian.Uint64(chunk), nil
+}
+
+func (d *msgPackDecoder) decode() (interface{}, error) {
+	codes, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	code := codes[0]
+	switch {
+	case code <= 0x7f:
+		return json.Number(strconv.Itoa(int(code))), nil
+	case code >= 0xe0:
+		return json.Number(strconv.Itoa(int(int8(code)))), nil
+	case code&0xf0 == 0x80:
+		return d.decodeMap(int(code & 0x0f))
+	case code&0xf0 == 0x90:
+		return d.decodeArray(int(code & 0x0f))
+	case code&0xe0 == 0xa0:
+		return d.decodeString(int(code & 0x1f))
+	}
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		length, err := d.readUint(1 << (code - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeBinary(int(length))
+	case 0xca:
+		bits, err := d.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		return floatNumber(float64(math.Float32frombits(uint32(bits))), 32), nil
+	case 0xcb:
+		bits, err := d.readUint(8)
+		if err != nil {
+			return nil, err
+		}
+		return floatNumber(math.Float64frombits(bits), 64), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.readUint(1 << (code - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatUint(u, 10)), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (code - 0xd0)
+		u, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		// sign extend from the encoded width
+		shift := uint(64 - 8*size)
+		return json.Number(strconv.FormatInt(int64(u<<shift)>>shift, 10)), nil
+	case 0xd9, 0xda, 0xdb:
+		length, err := d.readUint(1 << (code - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(int(length))
+	case 0xdc, 0xdd:
+		length, err := d.readUint(2 << (code - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(length))
+	case 0xde, 0xdf:
+		length, err := d.readUint(2 << (code - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(length))
+	}
+	return nil, errors.Errorf("unsupported msgpack type 0x%02x at offset %d", code, d.pos-1)
+}
+
+// isNegativeZero reports whether an integer literal is -0, which has to be
+// kept as a float to survive a round trip
+func isNegativeZero(n json.Number) bool {
+	return len(n) > 0 && n[0] == '-' && strings.Trim(string(n[1:]), "0") == ""
+}
+
+func floatNumber(f float64, bitSize int) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		// json has no literal for these, keep the float itself
+		return f
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+func (d *msgPackDecoder) decodeString(length int) (interface{}, error) {
+	chunk, err := d.read(length)
+	if err != nil {
+		return nil, err
+	}
+	return string(chunk), nil
+}
+
+func (d *msgPackDecoder) decodeBinary(length int) (interface{}, error) {
+	chunk, err := d.read(length)
+	if err != nil {
+		return nil, err
+	}
+	if d.opts.BinaryAsBytes {
+		return append([]byte{}, chunk...), nil
+	}
+	return base64.StdEncoding.EncodeToString(chunk), nil
+}
+
+func (d *msgPackDecoder) decodeArray(length int) (interface{}, error) {
+	if length > len(d.data)-d.pos {
+		return nil, errors.Errorf("invalid msgpack: array length %d exceeds data at offset %d", length, d.pos)
+	}
+	array := make([]interface{}, 0, length)
+	for i := 0; i < length; i++ {
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+	return array, nil
+}
+
+func (d *msgPackDecoder) decodeMap(length int) (interface{}, error) {
+	if length > len(d.data)-d.pos {
+		return nil, errors.Errorf("invalid msgpack: map length %d exceeds data at offset %d", length, d.pos)
+	}
+	object := make(map[string]interface{}, length)
+	for i := 0; i < length; i++ {
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		keyString, ok := key.(string)
+		if !ok {
+			keyString = fmt.Sprint(key)
+		}
+		object[keyString] = item
+	}
+	return object, nil
+}
@@ -0,0 +1,64 @@
+package betterjson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_EncodeMsgPack(t *testing.T) {
+	a := Obj("a", 1, "b", Arr(true, nil, -1))
+	encoded, err := a.EncodeMsgPack()
+	assert.True(t, err == nil)
+	assert.True(t, bytes.Equal(encoded, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x93, 0xc3, 0xc0, 0xff}))
+	_, err = NewEmpty().EncodeMsgPack()
+	assert.True(t, err != nil)
+}
+
+func TestMsgPackRoundTrip(t *testing.T) {
+	a, err := Parse([]byte(`{"small":5,"neg":-33,"big":18446744073709551615,"min":-9223372036854775808,
+		"float":2.5,"ok":true,"none":null,"nested":{"items":[1,"two",{"three":[3.25]}]}}`))
+	assert.True(t, err == nil)
+	a.Set("s31", strings.Repeat("x", 31)).Set("s32", strings.Repeat("x", 32)).Set("s300", strings.Repeat("y", 300)).Set("s70000", strings.Repeat("z", 70000))
+	items := NewJSONArray()
+	for i := 0; i < 20; i++ {
+		items.TryAdd(i * 1000)
+	}
+	a.Set("items", items)
+	encoded, err := a.EncodeMsgPack()
+	assert.True(t, err == nil)
+	b, err := FromMsgPack(encoded)
+	assert.True(t, err == nil, err)
+	assert.True(t, b.IsSameJSONWith(a))
+	assert.True(t, b.Get("big").MustUint64() == 18446744073709551615)
+	assert.True(t, b.Get("min").MustInt64() == -9223372036854775808)
+	assert.True(t, b.GetPath("nested", "items").GetIndex(2).Get("three").GetIndex(0).MustFloat64() == 3.25)
+}
+
+func TestFromMsgPackBinary(t *testing.T) {
+	data := []byte{0x81, 0xa3, 'b', 'i', 'n', 0xc4, 0x03, 0x00, 0x01, 0xff}
+	a, err := FromMsgPack(data)
+	assert.True(t, err == nil)
+	assert.True(t, a.Get("bin").MustString() == "AAH/")
+	b, err := FromMsgPackWithOptions(data, MsgPackOptions{BinaryAsBytes: true})
+	assert.True(t, err == nil)
+	bStr, err := b.EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, bStr == `{"bin":"AAH/"}`)
+	encoded, err := b.EncodeMsgPack()
+	assert.True(t, err == nil)
+	assert.True(t, bytes.Equal(encoded, data))
+}
+
+func TestFromMsgPackInvalid(t *testing.T) {
+	_, err := FromMsgPack([]byte{0x92, 0x01})
+	assert.True(t, err != nil)
+	_, err = FromMsgPack([]byte{0x01, 0x02})
+	assert.True(t, err != nil)
+	_, err = FromMsgPack([]byte{0xd4, 0x01, 0x02})
+	assert.True(t, err != nil)
+	a, err := FromMsgPack([]byte{0x81, 0x01, 0xa1, 'x'})
+	assert.True(t, err == nil)
+	assert.True(t, a.Get("1").MustString() == "x")
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package betterjson
+
+const raceEnabled = false
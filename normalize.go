@@ -0,0 +1,120 @@
+package betterjson
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeOptions selects the transformations of NormalizeStrings, each
+// independent of the others
+type NormalizeOptions struct {
+	// NFC composes the strings to Unicode normalization form C, so "e" with
+	// a combining acute accent becomes "é"
+	NFC bool
+	// TrimSpace removes leading and trailing white space, CollapseSpace
+	// replaces every inner run of white space by a single space. white space
+	// is what unicode.IsSpace says, non-breaking spaces included
+	TrimSpace     bool
+	CollapseSpace bool
+	// Lowercase maps the strings to lower case with the Unicode mapping,
+	// the same for every locale
+	Lowercase bool
+	// Keys normalizes object keys as well as string values
+	Keys bool
+}
+
+// NormalizeStrings returns a copy of j with every string normalized per
+// opts, so documents that only look the same encode and digest the same:
+//    normalized, err := post.NormalizeStrings(betterjson.NormalizeOptions{NFC: true, TrimSpace: true, CollapseSpace: true})
+// with Keys, two keys of an object normalizing to the same key are an error
+// naming both, and j is returned unchanged
+func (j *Json) NormalizeStrings(opts NormalizeOptions) (*Json, error) {
+	if j.IsEmpty() {
+		return j, errors.New("empty json can't be normalized")
+	}
+	root, err := normalizeRaw([]string{}, j.Interface(), opts)
+	if err != nil {
+		return j, err
+	}
+	result := wrapRaw(root)
+	result.settings = j.settings
+	return result, nil
+}
+
+func normalizeRaw(branch []string, node interface{}, opts NormalizeOptions) (interface{}, error) {
+	switch value := unwrapRaw(node).(type) {
+	case string:
+		return normalizeString(value, opts), nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		sources := make(map[string]string, len(value))
+		for _, key := range sortedKeys(value) {
+			normalizedKey := key
+			if opts.Keys {
+				normalizedKey = normalizeString(key, opts)
+				if other, ok := sources[normalizedKey]; ok {
+					return nil, errors.Errorf("keys %q and %q at %s both normalize to %q", other, key, displayPath(branch), normalizedKey)
+				}
+				sources[normalizedKey] = key
+			}
+			item, err := normalizeRaw(append(branch[:len(branch):len(branch)], key), value[key], opts)
+			if err != nil {
+				return nil, err
+			}
+			result[normalizedKey] = item
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for idx, item := range value {
+			normalized, err := normalizeRaw(append(branch[:len(branch):len(branch)], strconv.Itoa(idx)), item, opts)
+			if err != nil {
+				return nil, err
+			}
+			result[idx] = normalized
+		}
+		return result, nil
+	default:
+		return deepCopyRaw(value), nil
+	}
+}
+
+func normalizeString(s string, opts NormalizeOptions) string {
+	if opts.Lowercase {
+		s = strings.ToLower(s)
+	}
+	if opts.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if opts.CollapseSpace {
+		s = collapseSpace(s)
+	}
+	// last, lowercasing may decompose
+	if opts.NFC {
+		s = norm.NFC.String(s)
+	}
+	return s
+}
+
+// collapseSpace replaces the runs of white space of s by single spaces
+func collapseSpace(s string) string {
+	var builder strings.Builder
+	builder.Grow(len(s))
+	inSpace := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				builder.WriteByte(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,61 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeStrings(t *testing.T) {
+	// "e" followed by a combining acute accent, and a non-breaking space
+	js := Obj("title", "  Café  au   lait\t", "tags", Arr("Née", " A "), "count", 3)
+	normalized, err := js.NormalizeStrings(NormalizeOptions{NFC: true, TrimSpace: true, CollapseSpace: true})
+	assert.True(t, err == nil)
+	assert.Equal(t, "Café au lait", normalized.Get("title").MustString())
+	assert.Equal(t, []string{"Née", "A"}, normalized.Get("tags").MustStringArray())
+	assert.Equal(t, int64(3), normalized.Get("count").MustInt64())
+	// the source is unchanged
+	assert.Equal(t, "  Café  au   lait\t", js.Get("title").MustString())
+
+	composed := Obj("a", "Café")
+	decomposed := Obj("a", "Café")
+	assert.True(t, !composed.IsSameJSONWith(decomposed))
+	normalized, _ = decomposed.NormalizeStrings(NormalizeOptions{NFC: true})
+	assert.True(t, composed.IsSameJSONWith(normalized))
+}
+
+func TestNormalizeStringsIndependentOptions(t *testing.T) {
+	js := Obj("s", " A  B ")
+	cases := []struct {
+		opts     NormalizeOptions
+		expected string
+	}{
+		{NormalizeOptions{}, " A  B "},
+		{NormalizeOptions{TrimSpace: true}, "A  B"},
+		{NormalizeOptions{CollapseSpace: true}, " A B "},
+		{NormalizeOptions{Lowercase: true}, " a  b "},
+		{NormalizeOptions{TrimSpace: true, CollapseSpace: true, Lowercase: true}, "a b"},
+	}
+	for _, c := range cases {
+		normalized, err := js.NormalizeStrings(c.opts)
+		assert.True(t, err == nil)
+		assert.Equal(t, c.expected, normalized.Get("s").MustString())
+	}
+}
+
+func TestNormalizeStringsKeys(t *testing.T) {
+	js := Obj(" Name ", "x", "nested", Obj("CAFÉ", 1))
+	normalized, err := js.NormalizeStrings(NormalizeOptions{NFC: true, TrimSpace: true, Lowercase: true, Keys: true})
+	assert.True(t, err == nil)
+	assert.Equal(t, "{\"name\":\"x\",\"nested\":{\"café\":1}}", normalized.EncodeToStringOrDefault(""))
+
+	values, _ := js.NormalizeStrings(NormalizeOptions{TrimSpace: true})
+	assert.True(t, values.ContainsKey(" Name "))
+
+	colliding := Obj("meta", Obj("UserId", 1, "userid", 2))
+	result, err := colliding.NormalizeStrings(NormalizeOptions{Lowercase: true, Keys: true})
+	println(err.Error())
+	assert.True(t, result == colliding)
+	assert.Equal(t, `keys "UserId" and "userid" at meta both normalize to "userid"`, err.Error())
+	_, err = NewEmpty().NormalizeStrings(NormalizeOptions{})
+	assert.True(t, err != nil)
+}
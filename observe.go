@@ -0,0 +1,169 @@
+package betterjson
+
+// ChangeOp is the kind of mutation a ChangeEvent reports
+type ChangeOp string
+
+const (
+	// ChangeSet is a value added or replaced by Set, SetPath, SetValue,
+	// SetIndex, Rollback or ApplyPatch
+	ChangeSet ChangeOp = "set"
+	// ChangeDelete is a value removed by Del or DelPath
+	ChangeDelete ChangeOp = "delete"
+	// ChangeAppend is an item added to an array by TryAdd
+	ChangeAppend ChangeOp = "append"
+	// ChangeInsert is an item inserted into an array by InsertAt or
+	// PrependArray, shifting the items from its index on
+	ChangeInsert ChangeOp = "insert"
+)
+
+// ChangeEvent describes one mutation of a document observed with OnChange
+type ChangeEvent struct {
+	Op ChangeOp
+	// Path is the branch of the changed value relative to the observed
+	// wrapper. for ChangeAppend it is the branch of the array, for
+	// ChangeInsert the branch of the inserted item at its new index
+	Path []string
+	// OldValue and NewValue are copies of the value at Path before and after
+	// the mutation, empty when there was or is no value
+	OldValue *Json
+	NewValue *Json
+}
+
+type observer struct {
+	fn      func(event ChangeEvent)
+	removed bool
+}
+
+// OnChange calls fn synchronously after every mutation of j, including
+// mutations through wrappers derived from j by Get, GetIndex, CheckGet, Select
+// or ObjectAtPath. unsubscribe stops the calls, it may be called more than once
+// and from inside a callback:
+//    unsubscribe := config.OnChange(func(event betterjson.ChangeEvent) {
+//        cache.Invalidate(betterjson.JoinDottedPath(event.Path))
+//    })
+//    defer unsubscribe()
+func (j *Json) OnChange(fn func(event ChangeEvent)) (unsubscribe func()) {
+	entry := &observer{fn: fn}
+	// never append in place, a dispatch in progress keeps ranging over the old slice
+	j.observers = append(j.observers[:len(j.observers):len(j.observers)], entry)
+	return func() {
+		if entry.removed {
+			return
+		}
+		entry.removed = true
+		remaining := make([]*observer, 0, len(j.observers))
+		for _, other := range j.observers {
+			if other != entry {
+				remaining = append(remaining, other)
+			}
+		}
+		j.observers = remaining
+	}
+}
+
+// isObserved reports whether j or a wrapper j was derived from is recording
+// or has observers. mutations skip all change reporting work otherwise
+func (j *Json) isObserved() bool {
+	for node := j; node != nil; node = node.parent {
+		if node.recorder != nil || len(node.observers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *Json) hasObservers() bool {
+	for node := j; node != nil; node = node.parent {
+		if len(node.observers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// priorValue is the value at the path of a mutation before it is performed
+type priorValue struct {
+	observed bool
+	exists   bool
+	value    interface{}
+}
+
+// prior captures the value at branch below j for passing to changed after
+// the mutation, which is already a no-op when j isn't observed
+func (j *Json) prior(branch []string) priorValue {
+	prior := priorValue{observed: j.isObserved()}
+	if !prior.observed || j.IsEmpty() {
+		return prior
+	}
+	var value interface{}
+	value, prior.exists = valueAtBranch(j.value.Interface(), branch)
+	if len(branch) == 0 && j.parent != nil && !j.parent.IsEmpty() {
+		// a wrapper from Get of a missing key has no value in its parent yet
+		_, prior.exists = childValue(j.parent.value.Interface(), j.parentKey)
+	}
+	if prior.exists && j.hasObservers() {
+		prior.value = deepCopyRaw(value)
+	}
+	return prior
+}
+
+// changed reports operation, performed on j with paths relative to j, to the
+// recordings and observers of j and of every wrapper j was derived from
+func (j *Json) changed(operation patchOperation, prior priorValue) {
+	if !prior.observed {
+		return
+	}
+	j.recordChange(operation)
+	if j.hasObservers() {
+		j.notifyChange(operation.changeEvent(prior))
+	}
+}
+
+// inserted is changed for operation adding an item at an index of the j
+// array, which observers see as ChangeInsert instead of ChangeSet
+func (j *Json) inserted(operation patchOperation, prior priorValue) {
+	if !prior.observed {
+		return
+	}
+	j.recordChange(operation)
+	if j.hasObservers() {
+		event := operation.changeEvent(prior)
+		event.Op = ChangeInsert
+		j.notifyChange(event)
+	}
+}
+
+func (operation patchOperation) changeEvent(prior priorValue) ChangeEvent {
+	event := ChangeEvent{Op: ChangeSet, Path: operation.path, OldValue: NewEmpty(), NewValue: NewEmpty()}
+	if prior.exists {
+		event.OldValue = wrapRaw(prior.value)
+	}
+	switch {
+	case operation.op == "remove":
+		event.Op = ChangeDelete
+	case len(operation.path) > 0 && operation.path[len(operation.path)-1] == "-":
+		event.Op = ChangeAppend
+		event.Path = operation.path[:len(operation.path)-1]
+		event.NewValue = wrapRaw(deepCopyRaw(operation.value))
+	default:
+		event.NewValue = wrapRaw(deepCopyRaw(operation.value))
+	}
+	return event
+}
+
+// notifyChange calls the observers of j and of every wrapper j was derived
+// from with event, its path made relative to each of them
+func (j *Json) notifyChange(event ChangeEvent) {
+	path := event.Path
+	for node := j; node != nil; node = node.parent {
+		for _, entry := range node.observers {
+			if !entry.removed {
+				event.Path = append([]string{}, path...)
+				entry.fn(event)
+			}
+		}
+		if node.parent != nil {
+			path = append([]string{node.parentKey}, path...)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_OnChange(t *testing.T) {
+	a, _ := Parse([]byte(`{"name":"app","server":{"port":80},"hosts":["a"]}`))
+	server := a.Get("server")
+	hosts := a.Get("hosts")
+	events := make([]ChangeEvent, 0)
+	unsubscribe := a.OnChange(func(event ChangeEvent) {
+		events = append(events, event)
+	})
+	a.Set("name", "renamed")
+	server.Set("port", 8080)
+	a.Del("name")
+	a.SetPath([]string{"server", "tls", "enabled"}, true)
+	hosts.TryAdd("b")
+	a.Del("missing")
+
+	assert.True(t, len(events) == 5)
+	assert.True(t, events[0].Op == ChangeSet)
+	assert.Equal(t, []string{"name"}, events[0].Path)
+	assert.True(t, events[0].OldValue.MustString() == "app")
+	assert.True(t, events[0].NewValue.MustString() == "renamed")
+
+	assert.Equal(t, []string{"server", "port"}, events[1].Path)
+	assert.True(t, events[1].OldValue.MustInt() == 80)
+	assert.True(t, events[1].NewValue.MustInt() == 8080)
+
+	assert.True(t, events[2].Op == ChangeDelete)
+	assert.True(t, events[2].OldValue.MustString() == "renamed")
+	assert.True(t, events[2].NewValue.IsEmpty())
+
+	assert.Equal(t, []string{"server", "tls"}, events[3].Path)
+	assert.True(t, events[3].OldValue.IsEmpty())
+	assert.True(t, events[3].NewValue.Get("enabled").MustBool())
+
+	assert.True(t, events[4].Op == ChangeAppend)
+	assert.Equal(t, []string{"hosts"}, events[4].Path)
+	assert.True(t, events[4].NewValue.MustString() == "b")
+
+	unsubscribe()
+	unsubscribe()
+	a.Set("after", 1)
+	assert.True(t, len(events) == 5)
+}
+
+func TestJson_OnChangeUnsubscribeDuringCallback(t *testing.T) {
+	a := NewJSONObject()
+	calls := make([]string, 0)
+	var unsubscribeSecond func()
+	unsubscribeFirst := a.OnChange(func(event ChangeEvent) {
+		calls = append(calls, "first")
+		unsubscribeSecond()
+	})
+	unsubscribeSecond = a.OnChange(func(event ChangeEvent) {
+		calls = append(calls, "second")
+	})
+	a.OnChange(func(event ChangeEvent) {
+		calls = append(calls, "third")
+	})
+	a.Set("a", 1)
+	a.Set("b", 2)
+	assert.Equal(t, []string{"first", "third", "first", "third"}, calls)
+	unsubscribeFirst()
+	a.Set("c", 3)
+	assert.True(t, len(calls) == 5)
+	assert.True(t, a.Get("c").MustInt() == 3)
+}
+
+func BenchmarkJson_SetUnobserved(b *testing.B) {
+	a := NewJSONObject()
+	child := a.Get("child")
+	child.SetValue(NewJSONObject())
+	for i := 0; i < b.N; i++ {
+		child.Set("key", i)
+	}
+}
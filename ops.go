@@ -0,0 +1,139 @@
+package betterjson
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// OpKind is the kind of change of an Op
+type OpKind int
+
+const (
+	// SetOp stores Value at Path, creating the missing objects on the way. an
+	// array item at Path is replaced, "-" appends one. unlike SetPath, a value
+	// on the way that isn't an object or an array, or an array index out of
+	// range, fails the batch instead of being replaced
+	SetOp OpKind = iota
+	// DelOp removes the value at Path the way DelPath does, shifting the array
+	// items after a removed one. unlike DelPath, a missing Path fails the batch
+	DelOp
+	// AddOp adds Value at Path like a JSON Patch add: array items are inserted
+	// before the index or appended for "-", object members are set
+	AddOp
+	// MoveOp moves the value at From to Path, added there like AddOp
+	MoveOp
+)
+
+func (kind OpKind) String() string {
+	switch kind {
+	case SetOp:
+		return "set"
+	case DelOp:
+		return "del"
+	case AddOp:
+		return "add"
+	case MoveOp:
+		return "move"
+	}
+	return "op(" + strconv.Itoa(int(kind)) + ")"
+}
+
+// Op is one change of ApplyOps. paths are branches like those of SetPath,
+// with array indexes as segments
+type Op struct {
+	Kind  OpKind
+	Path  []string
+	From  []string
+	Value interface{}
+}
+
+// ApplyOps applies ops to j in order, as a whole: when any of them fails j is
+// left unchanged and the error names the index of the failing op. it is
+// ApplyPatch for Go values, recorded and observed the same way
+//    err := j.ApplyOps([]betterjson.Op{
+//        {Kind: betterjson.SetOp, Path: []string{"user", "name"}, Value: "ann"},
+//        {Kind: betterjson.MoveOp, From: []string{"tags", "0"}, Path: []string{"primaryTag"}},
+//    })
+func (j *Json) ApplyOps(ops []Op) error {
+	if j.IsEmpty() {
+		return ErrEmptyJson
+	}
+	root := deepCopyRaw(j.Interface())
+	operations := make([]patchOperation, 0, len(ops))
+	for idx, op := range ops {
+		steps, err := op.patchOperations(root)
+		for stepIdx := 0; err == nil && stepIdx < len(steps); stepIdx++ {
+			root, err = applyPatchOperation(root, steps[stepIdx])
+		}
+		if err != nil {
+			if op.Kind == MoveOp {
+				return errors.Wrapf(err, "op %d (%s %s to %s)", idx, op.Kind, displayPath(op.From), displayPath(op.Path))
+			}
+			return errors.Wrapf(err, "op %d (%s %s)", idx, op.Kind, displayPath(op.Path))
+		}
+		operations = append(operations, steps...)
+	}
+	j.commitPatch(root, operations)
+	return nil
+}
+
+// patchOperations are the JSON Patch operations doing op to root
+func (op Op) patchOperations(root interface{}) ([]patchOperation, error) {
+	path := append([]string{}, op.Path...)
+	switch op.Kind {
+	case DelOp:
+		if _, ok := valueAtBranch(root, path); !ok && len(path) > 0 {
+			return nil, errors.Errorf("path %s doesn't exist", displayPath(path))
+		}
+		return []patchOperation{{op: "remove", path: path}}, nil
+	case AddOp:
+		return []patchOperation{{op: "add", path: path, value: deepCopyRaw(op.Value)}}, nil
+	case MoveOp:
+		if _, ok := valueAtBranch(root, op.From); !ok {
+			return nil, errors.Errorf("path %s doesn't exist", displayPath(op.From))
+		}
+		return []patchOperation{{op: "move", path: path, from: append([]string{}, op.From...)}}, nil
+	case SetOp:
+		return setOperations(root, path, deepCopyRaw(op.Value))
+	}
+	return nil, errors.Errorf("unknown op kind %d", int(op.Kind))
+}
+
+// setOperations adds the objects missing on the way to branch, then adds or
+// replaces the value at branch
+func setOperations(root interface{}, branch []string, value interface{}) ([]patchOperation, error) {
+	if len(branch) == 0 {
+		return []patchOperation{{op: "replace", path: branch, value: value}}, nil
+	}
+	operations := make([]patchOperation, 0, 1)
+	node := root
+	for idx, segment := range branch[:len(branch)-1] {
+		next, ok := childValue(node, segment)
+		if !ok {
+			if _, isObject := unwrapRaw(node).(map[string]interface{}); !isObject {
+				return nil, errors.Errorf("path %s doesn't exist", displayPath(branch[:idx+1]))
+			}
+			next = map[string]interface{}{}
+			operations = append(operations, patchOperation{op: "add", path: branch[:idx+1], value: next})
+		}
+		node = next
+	}
+	last := branch[len(branch)-1]
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		if _, exists := container[last]; !exists {
+			return append(operations, patchOperation{op: "add", path: branch, value: value}), nil
+		}
+	case []interface{}:
+		if last == "-" {
+			return append(operations, patchOperation{op: "add", path: branch, value: value}), nil
+		}
+		if _, ok := childValue(container, last); !ok {
+			return nil, errors.Errorf("path %s doesn't exist", displayPath(branch))
+		}
+	default:
+		return nil, errors.Errorf("can't set %s of %s", last, kindName(container))
+	}
+	return append(operations, patchOperation{op: "replace", path: branch, value: value}), nil
+}
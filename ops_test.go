@@ -0,0 +1,102 @@
+package betterjson
+
+import (
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_ApplyOps(t *testing.T) {
+	js, err := Parse([]byte(`{"name":"a","tags":["x","y"],"old":1}`))
+	assert.True(t, err == nil)
+	err = js.ApplyOps([]Op{
+		{Kind: SetOp, Path: []string{"name"}, Value: "b"},
+		{Kind: SetOp, Path: []string{"user", "profile", "age"}, Value: 3},
+		{Kind: SetOp, Path: []string{"tags", "1"}, Value: "z"},
+		{Kind: AddOp, Path: []string{"tags", "0"}, Value: "w"},
+		{Kind: SetOp, Path: []string{"tags", "-"}, Value: NewString("last")},
+		{Kind: DelOp, Path: []string{"old"}},
+	})
+	assert.True(t, err == nil)
+	println(js.EncodeToStringOrDefault(""))
+	assert.Equal(t, `{"name":"b","tags":["w","x","z","last"],"user":{"profile":{"age":3}}}`, js.EncodeToStringOrDefault(""))
+
+	assert.True(t, js.ApplyOps([]Op{{Kind: SetOp, Path: []string{}, Value: []interface{}{1}}}) == nil)
+	assert.Equal(t, `[1]`, js.EncodeToStringOrDefault(""))
+	assert.True(t, NewEmpty().ApplyOps(nil) == ErrEmptyJson)
+}
+
+func TestJson_ApplyOpsAllOrNothing(t *testing.T) {
+	js, err := Parse([]byte(`{"a":1,"list":[1,2]}`))
+	assert.True(t, err == nil)
+	before := js.EncodeToStringOrDefault("")
+	for _, failing := range []Op{
+		{Kind: DelOp, Path: []string{"missing"}},
+		{Kind: SetOp, Path: []string{"a", "b"}, Value: 1},
+		{Kind: SetOp, Path: []string{"list", "5"}, Value: 1},
+		{Kind: SetOp, Path: []string{"list", "5", "x"}, Value: 1},
+		{Kind: AddOp, Path: []string{"list", "9"}, Value: 1},
+		{Kind: MoveOp, From: []string{"nothing"}, Path: []string{"a"}},
+		{Kind: MoveOp, From: []string{"list"}, Path: []string{"list", "0"}},
+		{Kind: OpKind(9), Path: []string{"a"}},
+	} {
+		err := js.ApplyOps([]Op{
+			{Kind: SetOp, Path: []string{"a"}, Value: 2},
+			{Kind: DelOp, Path: []string{"list", "0"}},
+			failing,
+			{Kind: SetOp, Path: []string{"c"}, Value: 3},
+		})
+		assert.True(t, err != nil)
+		println(err.Error())
+		assert.True(t, strings.HasPrefix(err.Error(), "op 2 ("), err.Error())
+		assert.Equal(t, before, js.EncodeToStringOrDefault(""))
+	}
+}
+
+func TestJson_ApplyOpsMove(t *testing.T) {
+	js, err := Parse([]byte(`{"queue":[{"id":1},{"id":2}],"current":null,"done":{}}`))
+	assert.True(t, err == nil)
+	err = js.ApplyOps([]Op{
+		{Kind: MoveOp, From: []string{"queue", "0"}, Path: []string{"current"}},
+		{Kind: MoveOp, From: []string{"current"}, Path: []string{"done", "first"}},
+		{Kind: MoveOp, From: []string{"done", "first"}, Path: []string{"queue", "-"}},
+	})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"done":{},"queue":[{"id":2},{"id":1}]}`, js.EncodeToStringOrDefault(""))
+}
+
+func TestJson_ApplyOpsRecorded(t *testing.T) {
+	js, err := Parse([]byte(`{"a":1}`))
+	assert.True(t, err == nil)
+	replay, err := Parse([]byte(`{"a":1}`))
+	assert.True(t, err == nil)
+	js.StartRecording()
+	err = js.ApplyOps([]Op{
+		{Kind: SetOp, Path: []string{"b", "c"}, Value: 2},
+		{Kind: SetOp, Path: []string{"a"}, Value: 3},
+	})
+	assert.True(t, err == nil)
+	patch, err := js.StopRecording()
+	assert.True(t, err == nil)
+	println(patch.EncodeToStringOrDefault(""))
+	assert.True(t, replay.ApplyPatch(patch) == nil)
+	assert.True(t, replay.IsSameJSONWith(js))
+}
+
+func TestJson_ApplyOpsErrors(t *testing.T) {
+	js, err := Parse([]byte(`{"a":1,"list":[1,2],"o":{}}`))
+	assert.True(t, err == nil)
+	err = js.ApplyOps([]Op{{Kind: DelOp, Path: []string{"o", "missing"}}})
+	assert.Equal(t, "op 0 (del o.missing): path o.missing doesn't exist", err.Error())
+	err = js.ApplyOps([]Op{{Kind: SetOp, Path: []string{"list", "5"}, Value: 1}})
+	assert.Equal(t, "op 0 (set list.5): path list.5 doesn't exist", err.Error())
+	err = js.ApplyOps([]Op{{Kind: MoveOp, From: []string{"o", "x"}, Path: []string{"b"}}})
+	assert.Equal(t, "op 0 (move o.x to b): path o.x doesn't exist", err.Error())
+
+	// a scalar on the way isn't replaced the way SetPath replaces it
+	err = js.ApplyOps([]Op{{Kind: SetOp, Path: []string{"a", "b"}, Value: 1}})
+	assert.Equal(t, "op 0 (set a.b): can't set b of number", err.Error())
+	err = js.ApplyOps([]Op{{Kind: SetOp, Path: []string{"a", "b", "c"}, Value: 1}})
+	assert.Equal(t, "op 0 (set a.b.c): path a.b doesn't exist", err.Error())
+	assert.Equal(t, `{"a":1,"list":[1,2],"o":{}}`, js.EncodeToStringOrDefault(""))
+}
@@ -0,0 +1,91 @@
+package betterjson
+
+import (
+	"log"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Options configures the behavior of a document and every wrapper derived
+// from it by Get, GetIndex, CheckGet, Select or the path getters
+type Options struct {
+	// PanicOnMust makes Must* methods of an empty Json panic, as they do
+	// without options. when false they return the default argument, or the
+	// zero value, and record the error for LastError
+	PanicOnMust bool
+	// CaseInsensitiveKeys makes Get, CheckGet, GetPath and ContainsKey match
+	// keys like GetFold, for documents from sources sloppy about key casing
+	CaseInsensitiveKeys bool
+	// StrictTraversal makes Get and GetIndex record for LastError the
+	// TraversalError GetStrict and GetIndexStrict would fail with, missing
+	// keys aside. they still return a null Json. meant for test
+	// environments, to catch shape mismatches early
+	StrictTraversal bool
+}
+
+// DefaultOptions is the behavior of documents created without options
+var DefaultOptions = Options{PanicOnMust: true}
+
+// documentSettings are shared by every wrapper of a document, lastError is
+// guarded by mutex so concurrent reads of one document can record errors
+type documentSettings struct {
+	options   Options
+	mutex     sync.Mutex
+	lastError error
+}
+
+// NewJSONObjectWithOptions is NewJSONObject with opts applied
+func NewJSONObjectWithOptions(opts Options) *Json {
+	return NewJSONObject().WithOptions(opts)
+}
+
+// WithOptions applies opts to j and to the wrappers derived from it
+// afterwards, and returns j. wrappers derived before keep their options.
+// documents with options record the errors of Must* methods, both failed
+// conversions and empty values, for LastError:
+//    config := betterjson.NewJSONObjectWithOptions(betterjson.Options{PanicOnMust: false})
+//    port := config.CheckGet("port").MustInt(8080)
+//    if err := config.LastError(); err != nil {
+//        log.Println(err)
+//    }
+func (j *Json) WithOptions(opts Options) *Json {
+	j.settings = &documentSettings{options: opts}
+	return j
+}
+
+// Options returns the options of j's document
+func (j *Json) Options() Options {
+	if j.settings == nil {
+		return DefaultOptions
+	}
+	return j.settings.options
+}
+
+// LastError returns the last error recorded by a Must* method of j's
+// document, nil when there was none or the document has no options
+func (j *Json) LastError() error {
+	if j.settings == nil {
+		return nil
+	}
+	j.settings.mutex.Lock()
+	defer j.settings.mutex.Unlock()
+	return j.settings.lastError
+}
+
+func (j *Json) recordError(err error) {
+	if j.settings != nil {
+		j.settings.mutex.Lock()
+		j.settings.lastError = err
+		j.settings.mutex.Unlock()
+	}
+}
+
+// mustOnEmpty panics for a Must* method called on an empty Json, unless the
+// options of the document say to record the error instead
+func (j *Json) mustOnEmpty(method string) {
+	if j.Options().PanicOnMust {
+		log.Panicf("empty json %s failed", method)
+	}
+	j.recordError(errors.Errorf("empty json %s failed", method))
+}
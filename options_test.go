@@ -0,0 +1,132 @@
+package betterjson
+
+import (
+	"sync"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+// mustCalls calls every Must* method of j with and without a default, and
+// reports whether each result equals the default or zero value
+var mustCalls = map[string]func(j *Json, withDefault bool) bool{
+	"MustArray": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return len(j.MustArray([]interface{}{1})) == 1
+		}
+		return j.MustArray() == nil
+	},
+	"MustMap": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return len(j.MustMap(map[string]interface{}{"a": 1})) == 1
+		}
+		return j.MustMap() == nil
+	},
+	"MustString": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return j.MustString("d") == "d"
+		}
+		return j.MustString() == ""
+	},
+	"MustStringArray": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return len(j.MustStringArray([]string{"d"})) == 1
+		}
+		return j.MustStringArray() == nil
+	},
+	"MustInt": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return j.MustInt(7) == 7
+		}
+		return j.MustInt() == 0
+	},
+	"MustFloat64": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return j.MustFloat64(1.5) == 1.5
+		}
+		return j.MustFloat64() == 0
+	},
+	"MustBool": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return j.MustBool(true)
+		}
+		return !j.MustBool()
+	},
+	"MustInt64": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return j.MustInt64(7) == 7
+		}
+		return j.MustInt64() == 0
+	},
+	"MustUint64": func(j *Json, withDefault bool) bool {
+		if withDefault {
+			return j.MustUint64(7) == 7
+		}
+		return j.MustUint64() == 0
+	},
+}
+
+func TestJson_MustPanicsByDefault(t *testing.T) {
+	for name, call := range mustCalls {
+		empty := NewJSONObject().CheckGet("missing")
+		assert.Panics(t, func() { call(empty, false) }, name)
+		assert.Panics(t, func() { call(empty, true) }, name)
+		mismatched, _ := Parse([]byte(`{"v":{"nested":"object"}}`))
+		if name == "MustMap" {
+			mismatched, _ = Parse([]byte(`{"v":"string"}`))
+		}
+		assert.True(t, call(mismatched.Get("v"), true), name)
+		assert.True(t, mismatched.LastError() == nil, name)
+	}
+	assert.True(t, NewJSONObject().Options().PanicOnMust)
+}
+
+func TestJson_MustWithoutPanic(t *testing.T) {
+	for name, call := range mustCalls {
+		doc := NewJSONObjectWithOptions(Options{PanicOnMust: false})
+		empty := doc.CheckGet("missing")
+		assert.NotPanics(t, func() { assert.True(t, call(empty, false), name) })
+		assert.True(t, doc.LastError() != nil, name)
+		println(doc.LastError().Error())
+		assert.NotPanics(t, func() { assert.True(t, call(empty, true), name) })
+
+		parsed, _ := Parse([]byte(`{"v":{"nested":"object"}}`))
+		if name == "MustMap" {
+			parsed, _ = Parse([]byte(`{"v":"string"}`))
+		}
+		parsed.WithOptions(Options{})
+		assert.True(t, parsed.LastError() == nil, name)
+		assert.True(t, call(parsed.Get("v"), true), name)
+		assert.True(t, parsed.LastError() != nil, name)
+		assert.True(t, parsed.GetDottedPath("v").LastError() != nil, name)
+	}
+}
+
+func TestJson_OptionsInherited(t *testing.T) {
+	doc, _ := Parse([]byte(`{"a":{"b":[{"c":1}]}}`))
+	before := doc.Get("a")
+	doc.WithOptions(Options{PanicOnMust: false})
+	assert.False(t, doc.Get("a").Get("b").GetIndex(0).Options().PanicOnMust)
+	assert.False(t, doc.GetDottedPath("a.missing").Options().PanicOnMust)
+	assert.True(t, before.Options().PanicOnMust)
+	assert.True(t, doc.GetDottedPath("a.b.0.c").MustInt() == 1)
+	assert.True(t, doc.LastError() == nil)
+}
+
+func TestJson_MustWithoutPanicConcurrently(t *testing.T) {
+	doc, _ := Parse([]byte(`{"name":"a","port":"x"}`))
+	doc.WithOptions(Options{})
+	var group sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for n := 0; n < 100; n++ {
+				doc.Get("port").MustInt(1)
+				doc.Get("missing").MustString()
+				doc.LastError()
+			}
+		}()
+	}
+	group.Wait()
+	assert.True(t, doc.LastError() != nil)
+}
@@ -0,0 +1,88 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// OverlayOptions configures OverlayWithReportOptions
+type OverlayOptions struct {
+	// DeleteNulls makes a null in the overlay remove the key instead of
+	// setting it to null
+	DeleteNulls bool
+}
+
+// Override is one change an overlay made. Kind is DiffAdded for a key the
+// document didn't have, DiffRemoved for a key removed by a null with
+// DeleteNulls and DiffChanged otherwise. Old and New are copies, empty on the
+// side that has no value
+type Override struct {
+	Kind DiffKind
+	Path []string
+	Old  *Json
+	New  *Json
+}
+
+// OverlayWithReport is OverlayWithReportOptions with nulls stored as nulls
+func (j *Json) OverlayWithReport(other *Json) (*Json, []Override, error) {
+	return j.OverlayWithReportOptions(other, OverlayOptions{})
+}
+
+// OverlayWithReportOptions deep merges other onto a copy of j and reports
+// every value that changed. objects are merged key by key, anything else in
+// other, arrays included, replaces the value of j as a whole and is reported
+// as one Override. values other sets to what j already has aren't reported.
+// j is left unchanged and an empty other changes nothing:
+//    merged, overrides, err := defaults.OverlayWithReport(userConfig)
+//    for _, override := range overrides {
+//        log.Printf("%s %s", override.Kind, betterjson.JoinDottedPath(override.Path))
+//    }
+func (j *Json) OverlayWithReportOptions(other *Json, opts OverlayOptions) (*Json, []Override, error) {
+	overrides := make([]Override, 0)
+	if j.IsEmpty() {
+		return NewEmpty(), overrides, errors.New("can't overlay empty json")
+	}
+	root := j.value.Interface()
+	overlay, ok := documentRoot(other)
+	if !ok {
+		return wrapRaw(deepCopyRaw(root)), overrides, nil
+	}
+	return wrapRaw(overlayRaw([]string{}, root, overlay, opts, &overrides)), overrides, nil
+}
+
+// overlayRaw returns a copy of base with over merged onto it
+func overlayRaw(branch []string, base interface{}, over interface{}, opts OverlayOptions, overrides *[]Override) interface{} {
+	base, over = unwrapRaw(base), unwrapRaw(over)
+	baseObject, baseIsObject := base.(map[string]interface{})
+	overObject, overIsObject := over.(map[string]interface{})
+	if !baseIsObject || !overIsObject || baseObject == nil || overObject == nil {
+		if digestEqual(base, over) {
+			return deepCopyRaw(base)
+		}
+		*overrides = append(*overrides, newOverride(DiffChanged, branch, base, over))
+		return deepCopyRaw(over)
+	}
+	result := deepCopyRaw(baseObject).(map[string]interface{})
+	for _, key := range sortedKeys(overObject) {
+		item := unwrapRaw(overObject[key])
+		child := append(branch[:len(branch):len(branch)], key)
+		existing, exists := baseObject[key]
+		switch {
+		case item == nil && opts.DeleteNulls:
+			if exists {
+				*overrides = append(*overrides, newOverride(DiffRemoved, child, existing, nil))
+				delete(result, key)
+			}
+		case !exists:
+			*overrides = append(*overrides, newOverride(DiffAdded, child, nil, item))
+			result[key] = deepCopyRaw(item)
+		default:
+			result[key] = overlayRaw(child, existing, item, opts, overrides)
+		}
+	}
+	return result
+}
+
+func newOverride(kind DiffKind, branch []string, old interface{}, new interface{}) Override {
+	difference := newDifference(kind, branch, old, new)
+	return Override{Kind: kind, Path: difference.Path, Old: difference.Old, New: difference.New}
+}
@@ -0,0 +1,55 @@
+package betterjson_test
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/zoowii/betterjson"
+	"github.com/zoowii/betterjson/betterjsontest"
+)
+
+func TestOverlayWithReport(t *testing.T) {
+	defaults, _ := betterjson.Parse([]byte(`{"server":{"host":"localhost","port":80,"tls":{"enabled":false}},"hosts":["a","b"],"debug":false,"name":"svc"}`))
+	overlay, _ := betterjson.Parse([]byte(`{"server":{"port":8080,"tls":{"enabled":true,"cert":"c.pem"}},"hosts":["c"],"name":"svc","debug":null}`))
+	merged, overrides, err := defaults.OverlayWithReport(overlay)
+	assert.True(t, err == nil)
+	betterjsontest.AssertEqualJSON(t, `{"server":{"host":"localhost","port":8080,"tls":{"enabled":true,"cert":"c.pem"}},"hosts":["c"],"debug":null,"name":"svc"}`, merged)
+	assert.Equal(t, 5, len(overrides))
+	assert.Equal(t, betterjson.DiffChanged, overrides[0].Kind)
+	assert.Equal(t, []string{"debug"}, overrides[0].Path)
+	assert.True(t, overrides[0].New.IsNull())
+	// arrays are replaced as a whole
+	assert.Equal(t, []string{"hosts"}, overrides[1].Path)
+	betterjsontest.AssertEqualJSON(t, `["a","b"]`, overrides[1].Old)
+	betterjsontest.AssertEqualJSON(t, `["c"]`, overrides[1].New)
+	assert.Equal(t, []string{"server", "port"}, overrides[2].Path)
+	assert.Equal(t, int64(80), overrides[2].Old.MustInt64())
+	assert.Equal(t, betterjson.DiffAdded, overrides[3].Kind)
+	assert.Equal(t, []string{"server", "tls", "cert"}, overrides[3].Path)
+	assert.True(t, overrides[3].Old.IsEmpty())
+	assert.Equal(t, []string{"server", "tls", "enabled"}, overrides[4].Path)
+	// the inputs are left unchanged
+	betterjsontest.AssertPathEquals(t, defaults, "server.port", 80)
+	merged.GetPath("server", "tls").Set("enabled", false)
+	assert.True(t, overlay.GetPath("server", "tls", "enabled").MustBool())
+}
+
+func TestOverlayWithReportOptions(t *testing.T) {
+	defaults, _ := betterjson.Parse([]byte(`{"a":1,"b":{"c":2},"d":3}`))
+	overlay, _ := betterjson.Parse([]byte(`{"a":null,"b":{"c":null},"e":null,"d":{"x":1}}`))
+	merged, overrides, err := defaults.OverlayWithReportOptions(overlay, betterjson.OverlayOptions{DeleteNulls: true})
+	assert.True(t, err == nil)
+	betterjsontest.AssertEqualJSON(t, `{"b":{},"d":{"x":1}}`, merged)
+	assert.Equal(t, 3, len(overrides))
+	assert.Equal(t, betterjson.DiffRemoved, overrides[0].Kind)
+	assert.Equal(t, int64(1), overrides[0].Old.MustInt64())
+	assert.True(t, overrides[0].New.IsEmpty())
+	assert.Equal(t, []string{"b", "c"}, overrides[1].Path)
+	assert.Equal(t, betterjson.DiffChanged, overrides[2].Kind)
+	assert.Equal(t, []string{"d"}, overrides[2].Path)
+
+	same, overrides, err := defaults.OverlayWithReport(betterjson.NewEmpty())
+	assert.True(t, err == nil && len(overrides) == 0 && same.IsSameJSONWith(defaults))
+	_, _, err = betterjson.NewEmpty().OverlayWithReport(overlay)
+	assert.True(t, err != nil)
+	println(err.Error())
+}
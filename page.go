@@ -0,0 +1,82 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// PageOptions names the members of the envelope of PageWithOptions, the
+// empty ones keep the names of Page
+type PageOptions struct {
+	ItemsKey    string
+	PageKey     string
+	PageSizeKey string
+	TotalKey    string
+	HasMoreKey  string
+}
+
+// Page is PageWithOptions with the default envelope
+//    {"items": [...], "page": 2, "pageSize": 20, "total": 45, "hasMore": true}
+func (j *Json) Page(pageNum, pageSize int) (*Json, error) {
+	return j.PageWithOptions(pageNum, pageSize, PageOptions{})
+}
+
+// PageWithOptions returns page pageNum, counted from 1, of the j array split
+// into pages of pageSize items, in an envelope object with the page, the page
+// size, the total number of items and whether pages follow. the items are
+// copies, and pages after the last one have no items
+func (j *Json) PageWithOptions(pageNum, pageSize int, opts PageOptions) (*Json, error) {
+	if pageNum < 1 {
+		return NewEmpty(), errors.Errorf("page %d is invalid, pages start at 1", pageNum)
+	}
+	if pageSize < 1 {
+		return NewEmpty(), errors.Errorf("page size %d is invalid", pageSize)
+	}
+	array, err := arrayToDraw(j, "page")
+	if err != nil {
+		return NewEmpty(), err
+	}
+	opts = opts.withDefaults()
+	pages := len(array) / pageSize
+	if len(array)%pageSize != 0 {
+		pages++
+	}
+	items := make([]interface{}, 0)
+	// comparing page numbers first keeps the start from overflowing
+	if pageNum <= pages {
+		start := (pageNum - 1) * pageSize
+		end := len(array)
+		if end-start > pageSize {
+			end = start + pageSize
+		}
+		for _, item := range array[start:end] {
+			items = append(items, deepCopyRaw(item))
+		}
+	}
+	hasMore := pageNum < pages
+	return wrapRaw(map[string]interface{}{
+		opts.ItemsKey:    items,
+		opts.PageKey:     pageNum,
+		opts.PageSizeKey: pageSize,
+		opts.TotalKey:    len(array),
+		opts.HasMoreKey:  hasMore,
+	}), nil
+}
+
+func (opts PageOptions) withDefaults() PageOptions {
+	defaults := []struct {
+		key      *string
+		fallback string
+	}{
+		{&opts.ItemsKey, "items"},
+		{&opts.PageKey, "page"},
+		{&opts.PageSizeKey, "pageSize"},
+		{&opts.TotalKey, "total"},
+		{&opts.HasMoreKey, "hasMore"},
+	}
+	for _, field := range defaults {
+		if *field.key == "" {
+			*field.key = field.fallback
+		}
+	}
+	return opts
+}
@@ -0,0 +1,53 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func pageFixture() *Json {
+	js := NewJSONArray()
+	for i := 1; i <= 7; i++ {
+		js.TryAdd(map[string]interface{}{"id": i})
+	}
+	return js
+}
+
+func TestPage(t *testing.T) {
+	js := pageFixture()
+	first, err := js.Page(1, 3)
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"hasMore":true,"items":[{"id":1},{"id":2},{"id":3}],"page":1,"pageSize":3,"total":7}`, first.EncodeToStringOrDefault(""))
+	last, _ := js.Page(3, 3)
+	assert.Equal(t, `{"hasMore":false,"items":[{"id":7}],"page":3,"pageSize":3,"total":7}`, last.EncodeToStringOrDefault(""))
+	beyond, err := js.Page(4, 3)
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"hasMore":false,"items":[],"page":4,"pageSize":3,"total":7}`, beyond.EncodeToStringOrDefault(""))
+	whole, _ := js.Page(1, 100)
+	assert.Equal(t, 7, len(whole.Get("items").MustArray()))
+	assert.True(t, !whole.Get("hasMore").MustBool())
+	farBeyond, _ := js.Page(1<<40, 1<<40)
+	assert.Equal(t, 0, len(farBeyond.Get("items").MustArray()))
+	empty, _ := NewJSONArray().Page(1, 10)
+	assert.Equal(t, `{"hasMore":false,"items":[],"page":1,"pageSize":10,"total":0}`, empty.EncodeToStringOrDefault(""))
+
+	// the items are copies
+	first.Get("items").GetIndex(0).Set("id", 100)
+	assert.Equal(t, int64(1), js.GetIndex(0).Get("id").MustInt64())
+}
+
+func TestPageErrors(t *testing.T) {
+	js := pageFixture()
+	_, err := js.Page(0, 3)
+	assert.Equal(t, "page 0 is invalid, pages start at 1", err.Error())
+	_, err = js.Page(1, 0)
+	assert.Equal(t, "page size 0 is invalid", err.Error())
+	_, err = NewJSONObject().Page(1, 3)
+	assert.Equal(t, "can't page object", err.Error())
+}
+
+func TestPageWithOptions(t *testing.T) {
+	page, err := pageFixture().PageWithOptions(2, 5, PageOptions{ItemsKey: "data", HasMoreKey: "next"})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"data":[{"id":6},{"id":7}],"next":false,"page":2,"pageSize":5,"total":7}`, page.EncodeToStringOrDefault(""))
+}
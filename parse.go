@@ -0,0 +1,313 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// ParseOptions limits what the parse constructors accept. a zero field means
+// no limit
+type ParseOptions struct {
+	// MaxDepth is the deepest container nesting allowed, see DocumentStats.MaxDepth
+	MaxDepth int
+	// MaxStringLen is the longest string value or key allowed, in bytes
+	MaxStringLen int
+	// MaxTotalBytes is the largest input allowed
+	MaxTotalBytes int64
+	// MaxArrayLen is the largest item count allowed in one array
+	MaxArrayLen int
+	// RejectDuplicateKeys fails with a DuplicateKeyError when a key appears
+	// twice in one object, instead of keeping the last value
+	RejectDuplicateKeys bool
+	// InternKeys makes equal object keys share one string, which saves memory
+	// for arrays of many objects with the same keys. the document is the same
+	InternKeys bool
+}
+
+func (opts ParseOptions) isZero() bool {
+	return opts == ParseOptions{}
+}
+
+// LimitError is returned when parsing input exceeds one of the ParseOptions limits
+type LimitError struct {
+	// Limit is the name of the ParseOptions field that was exceeded
+	Limit string
+	Max   int64
+	// Offset is the approximate input byte offset where parsing stopped
+	Offset int64
+	// Line and Column locate Offset like they do for a SyntaxError
+	Line    int
+	Column  int
+	snippet string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("json exceeds %s limit %d near offset %d", e.Limit, e.Max, e.Offset)
+}
+
+// DuplicateKey records a key that appeared more than once in one object
+type DuplicateKey struct {
+	Key string
+	// Path is the branch of the object containing Key
+	Path []string
+	// Offset is the approximate input byte offset of the repeated key
+	Offset int64
+}
+
+// DuplicateKeyError is returned by strict parsing when an object repeats a key
+type DuplicateKeyError struct {
+	DuplicateKey
+	// Line and Column locate Offset like they do for a SyntaxError
+	Line    int
+	Column  int
+	snippet string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q in object at %s near offset %d", e.Key, displayPath(e.Path), e.Offset)
+}
+
+// Parse parses data into a Json. numbers are kept as json.Number like
+// simplejson.NewJson does, so every parse function keeps number literals as
+// they were written: Encode writes 1.50, 1e3 and -0 back unchanged until the
+// value is replaced, while MustFloat64 and the other accessors convert them
+func Parse(data []byte) (*Json, error) {
+	return ParseWithOptions(data, ParseOptions{})
+}
+
+// ParseWithOptions parses data into a Json, aborting as soon as one of the
+// limits in opts is exceeded. malformed input fails with a SyntaxError, and
+// like LimitError and DuplicateKeyError it has the line and column of the
+// failure and a Snippet of data around it
+func ParseWithOptions(data []byte, opts ParseOptions) (*Json, error) {
+	if sink := metrics; sink != nil {
+		start := time.Now()
+		result, err := parseWithOptions(data, opts)
+		observe(sink, "parse", start, len(data), err)
+		return result, err
+	}
+	return parseWithOptions(data, opts)
+}
+
+func parseWithOptions(data []byte, opts ParseOptions) (*Json, error) {
+	if opts.MaxTotalBytes > 0 && int64(len(data)) > opts.MaxTotalBytes {
+		return nil, annotateParseError(&LimitError{Limit: "MaxTotalBytes", Max: opts.MaxTotalBytes, Offset: opts.MaxTotalBytes}, data)
+	}
+	if opts == (ParseOptions{InternKeys: true}) && json.Valid(data) {
+		return parseInterned(data), nil
+	}
+	result, err := parseReader(bytes.NewReader(data), &parser{opts: opts})
+	if err != nil {
+		return nil, annotateParseError(err, data)
+	}
+	return result, nil
+}
+
+// ParseReaderWithOptions is ParseWithOptions reading a single document from r.
+// limits are checked while the input is tokenized, so oversized input is
+// rejected before the whole document is built
+func ParseReaderWithOptions(r io.Reader, opts ParseOptions) (*Json, error) {
+	if sink := metrics; sink != nil {
+		start := time.Now()
+		counter := &countingReader{r: r}
+		result, err := parseReader(counter, &parser{opts: opts})
+		observe(sink, "parse", start, int(counter.n), err)
+		return result, err
+	}
+	return parseReader(r, &parser{opts: opts})
+}
+
+// ParseReportingDuplicates is ParseWithOptions collecting every repeated object
+// key instead of failing, in input order. the last value of a repeated key wins,
+// like encoding/json. opts.RejectDuplicateKeys is ignored
+func ParseReportingDuplicates(data []byte, opts ParseOptions) (*Json, []DuplicateKey, error) {
+	if opts.MaxTotalBytes > 0 && int64(len(data)) > opts.MaxTotalBytes {
+		return nil, nil, annotateParseError(&LimitError{Limit: "MaxTotalBytes", Max: opts.MaxTotalBytes, Offset: opts.MaxTotalBytes}, data)
+	}
+	opts.RejectDuplicateKeys = false
+	p := &parser{opts: opts, collectDuplicates: true, duplicates: make([]DuplicateKey, 0)}
+	result, err := parseReader(bytes.NewReader(data), p)
+	if err != nil {
+		return nil, nil, annotateParseError(err, data)
+	}
+	return result, p.duplicates, nil
+}
+
+func parseReader(r io.Reader, p *parser) (*Json, error) {
+	if p.opts.MaxTotalBytes > 0 {
+		r = &limitedReader{r: r, remaining: p.opts.MaxTotalBytes, max: p.opts.MaxTotalBytes}
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var data interface{}
+	var err error
+	if p.opts.isZero() && !p.collectDuplicates {
+		err = dec.Decode(&data)
+	} else {
+		p.dec = dec
+		data, err = p.parseDocument()
+	}
+	if err != nil {
+		return nil, err
+	}
+	end := dec.InputOffset()
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = &SyntaxError{Offset: end, Err: errors.New("invalid data after top-level value")}
+		}
+		return nil, err
+	}
+	value := simplejson.New()
+	value.SetPath([]string{}, data)
+	return FromNotEmptySimpleJson(value), nil
+}
+
+// limitedReader fails with a LimitError once more than max bytes were read
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	max       int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, &LimitError{Limit: "MaxTotalBytes", Max: l.max, Offset: l.max}
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, &LimitError{Limit: "MaxTotalBytes", Max: l.max, Offset: l.max}
+	}
+	return n, err
+}
+
+// parser builds a document from the decoder's token stream so limits can be
+// enforced before the document is materialized
+type parser struct {
+	dec               *json.Decoder
+	opts              ParseOptions
+	collectDuplicates bool
+	duplicates        []DuplicateKey
+	// branch is the path of the value being parsed, only maintained when
+	// duplicate keys are checked
+	branch []string
+	// keys is the intern table of InternKeys
+	keys map[string]string
+}
+
+func (p *parser) checkDuplicates() bool {
+	return p.opts.RejectDuplicateKeys || p.collectDuplicates
+}
+
+func (p *parser) enter(segment func() string) {
+	if p.checkDuplicates() {
+		p.branch = append(p.branch, segment())
+	}
+}
+
+func (p *parser) leave() {
+	if p.checkDuplicates() {
+		p.branch = p.branch[:len(p.branch)-1]
+	}
+}
+
+func (p *parser) parseDocument() (interface{}, error) {
+	token, err := p.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseValue(token, 0)
+}
+
+func (p *parser) limitError(limit string, max int) error {
+	return &LimitError{Limit: limit, Max: int64(max), Offset: p.dec.InputOffset()}
+}
+
+func (p *parser) parseValue(token json.Token, depth int) (interface{}, error) {
+	switch value := token.(type) {
+	case json.Delim:
+		if p.opts.MaxDepth > 0 && depth+1 > p.opts.MaxDepth {
+			return nil, p.limitError("MaxDepth", p.opts.MaxDepth)
+		}
+		if value == '{' {
+			return p.parseObject(depth + 1)
+		}
+		return p.parseArray(depth + 1)
+	case string:
+		if p.opts.MaxStringLen > 0 && len(value) > p.opts.MaxStringLen {
+			return nil, p.limitError("MaxStringLen", p.opts.MaxStringLen)
+		}
+	}
+	return token, nil
+}
+
+func (p *parser) parseObject(depth int) (interface{}, error) {
+	object := make(map[string]interface{})
+	for {
+		token, err := p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if token == json.Delim('}') {
+			return object, nil
+		}
+		key := token.(string)
+		if p.opts.InternKeys {
+			key = internKey(&p.keys, key)
+		}
+		if p.opts.MaxStringLen > 0 && len(key) > p.opts.MaxStringLen {
+			return nil, p.limitError("MaxStringLen", p.opts.MaxStringLen)
+		}
+		if _, exists := object[key]; exists && p.checkDuplicates() {
+			duplicate := DuplicateKey{Key: key, Path: append([]string{}, p.branch...), Offset: p.dec.InputOffset()}
+			if p.opts.RejectDuplicateKeys {
+				return nil, &DuplicateKeyError{DuplicateKey: duplicate}
+			}
+			p.duplicates = append(p.duplicates, duplicate)
+		}
+		token, err = p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		p.enter(func() string { return key })
+		item, err := p.parseValue(token, depth)
+		p.leave()
+		if err != nil {
+			return nil, err
+		}
+		object[key] = item
+	}
+}
+
+func (p *parser) parseArray(depth int) (interface{}, error) {
+	array := make([]interface{}, 0)
+	for {
+		token, err := p.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if token == json.Delim(']') {
+			return array, nil
+		}
+		if p.opts.MaxArrayLen > 0 && len(array) >= p.opts.MaxArrayLen {
+			return nil, p.limitError("MaxArrayLen", p.opts.MaxArrayLen)
+		}
+		p.enter(func() string { return strconv.Itoa(len(array)) })
+		item, err := p.parseValue(token, depth)
+		p.leave()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+}
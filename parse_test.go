@@ -0,0 +1,209 @@
+package betterjson
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	a, err := Parse([]byte(`{"hello":"world","items":[1,2.5,null,true]}`))
+	assert.True(t, err == nil)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, aStr == `{"hello":"world","items":[1,2.5,null,true]}`)
+	_, err = Parse([]byte(`{"hello":`))
+	assert.True(t, err != nil)
+	_, err = Parse([]byte(`{"hello":1} {}`))
+	assert.True(t, err != nil)
+	b, err := ParseWithOptions([]byte(`{"hello":"world","items":[1,2.5,null,true]}`), ParseOptions{MaxDepth: 2})
+	assert.True(t, err == nil)
+	assert.True(t, b.IsSameJSONWith(a))
+}
+
+func assertLimitError(t *testing.T, err error, limit string) {
+	assert.True(t, err != nil)
+	limitErr, ok := err.(*LimitError)
+	assert.True(t, ok, err)
+	if ok {
+		println(limitErr.Error())
+		assert.True(t, limitErr.Limit == limit)
+	}
+}
+
+func TestParseWithOptionsMaxDepth(t *testing.T) {
+	deep := strings.Repeat("[", 100000) + strings.Repeat("]", 100000)
+	_, err := ParseWithOptions([]byte(deep), ParseOptions{MaxDepth: 64})
+	assertLimitError(t, err, "MaxDepth")
+	assert.True(t, err.(*LimitError).Offset < 100)
+	deepObject := strings.Repeat(`{"a":`, 10) + "1" + strings.Repeat("}", 10)
+	_, err = ParseWithOptions([]byte(deepObject), ParseOptions{MaxDepth: 10})
+	assert.True(t, err == nil)
+	_, err = ParseWithOptions([]byte(deepObject), ParseOptions{MaxDepth: 9})
+	assertLimitError(t, err, "MaxDepth")
+}
+
+func TestParseWithOptionsMaxStringLen(t *testing.T) {
+	long := `{"a":"` + strings.Repeat("x", 1000) + `"}`
+	_, err := ParseWithOptions([]byte(long), ParseOptions{MaxStringLen: 999})
+	assertLimitError(t, err, "MaxStringLen")
+	longKey := `{"` + strings.Repeat("k", 1000) + `":1}`
+	_, err = ParseWithOptions([]byte(longKey), ParseOptions{MaxStringLen: 999})
+	assertLimitError(t, err, "MaxStringLen")
+	_, err = ParseWithOptions([]byte(long), ParseOptions{MaxStringLen: 1000})
+	assert.True(t, err == nil)
+}
+
+func TestParseWithOptionsMaxArrayLen(t *testing.T) {
+	items := "[" + strings.Repeat("0,", 100000) + "0]"
+	_, err := ParseWithOptions([]byte(items), ParseOptions{MaxArrayLen: 1000})
+	assertLimitError(t, err, "MaxArrayLen")
+	assert.True(t, err.(*LimitError).Offset < 3000)
+	_, err = ParseWithOptions([]byte("[[1,2],[3]]"), ParseOptions{MaxArrayLen: 2})
+	assert.True(t, err == nil)
+}
+
+func TestParseWithOptionsMaxTotalBytes(t *testing.T) {
+	items := "[" + strings.Repeat("0,", 100000) + "0]"
+	_, err := ParseWithOptions([]byte(items), ParseOptions{MaxTotalBytes: 1024})
+	assertLimitError(t, err, "MaxTotalBytes")
+	_, err = ParseReaderWithOptions(strings.NewReader(items), ParseOptions{MaxTotalBytes: 1024})
+	assertLimitError(t, err, "MaxTotalBytes")
+	_, err = ParseReaderWithOptions(strings.NewReader(items), ParseOptions{MaxTotalBytes: int64(len(items))})
+	assert.True(t, err == nil)
+}
+
+func TestParseRejectDuplicateKeys(t *testing.T) {
+	_, err := ParseWithOptions([]byte(`{"a":1,"b":2,"a":3}`), ParseOptions{RejectDuplicateKeys: true})
+	assert.True(t, err != nil)
+	dupErr, ok := err.(*DuplicateKeyError)
+	assert.True(t, ok)
+	println(err.Error())
+	assert.True(t, dupErr.Key == "a" && len(dupErr.Path) == 0)
+	_, err = ParseWithOptions([]byte(`{"a":{"b":{"c":1,"c":1}}}`), ParseOptions{RejectDuplicateKeys: true})
+	assert.True(t, err != nil)
+	assert.Equal(t, []string{"a", "b"}, err.(*DuplicateKeyError).Path)
+	_, err = ParseWithOptions([]byte(`{"items":[{"id":1},{"id":2,"id":3}]}`), ParseOptions{RejectDuplicateKeys: true})
+	assert.True(t, err != nil)
+	assert.Equal(t, []string{"items", "1"}, err.(*DuplicateKeyError).Path)
+	assert.True(t, err.Error() == `duplicate key "id" in object at items.1 near offset 31`)
+	_, err = ParseWithOptions([]byte(`{"items":[{"id":1},{"id":2}],"a":{"id":3}}`), ParseOptions{RejectDuplicateKeys: true})
+	assert.True(t, err == nil)
+}
+
+func TestParseReportingDuplicates(t *testing.T) {
+	a, duplicates, err := ParseReportingDuplicates([]byte(`{"a":1,"a":2,"n":{"x":[{"k":1,"k":2}],"y":0,"y":1}}`), ParseOptions{})
+	assert.True(t, err == nil)
+	assert.True(t, len(duplicates) == 3)
+	assert.True(t, duplicates[0].Key == "a" && len(duplicates[0].Path) == 0)
+	assert.True(t, duplicates[1].Key == "k")
+	assert.Equal(t, []string{"n", "x", "0"}, duplicates[1].Path)
+	assert.True(t, duplicates[2].Key == "y")
+	assert.Equal(t, []string{"n"}, duplicates[2].Path)
+	assert.True(t, a.Get("a").MustInt() == 2)
+	assert.True(t, a.GetPath("n", "y").MustInt() == 1)
+	_, duplicates, err = ParseReportingDuplicates([]byte(`{"a":1}`), ParseOptions{})
+	assert.True(t, err == nil)
+	assert.True(t, len(duplicates) == 0)
+}
+
+func TestParseInternKeys(t *testing.T) {
+	texts := []string{
+		`[{"id":1,"status":"ok"},{"id":2,"status":"ok","extra":{"id":3}}]`,
+		` { "a\u0062" : "x\ny" , "ab":[ 1.50 , -0, 1e3, true, false, null ] } `,
+		"{\"k\xff\":\"v\xfe\"}",
+		`{"dup":1,"dup":2}`, `"plain"`, `12`, `[]`, `{}`,
+	}
+	for _, fixture := range encodeFixtures() {
+		encoded, _ := fixture.Encode()
+		texts = append(texts, string(encoded))
+	}
+	for _, opts := range []ParseOptions{{InternKeys: true}, {InternKeys: true, MaxDepth: 100}} {
+		for _, text := range texts {
+			eager, err := Parse([]byte(text))
+			assert.True(t, err == nil, text)
+			interned, err := ParseWithOptions([]byte(text), opts)
+			assert.True(t, err == nil, text)
+			assert.True(t, interned.DigestJSONForEqual() == eager.DigestJSONForEqual(), text)
+		}
+		for _, text := range []string{`{"a":`, `[1,]`, ``, `{} {}`} {
+			_, err := ParseWithOptions([]byte(text), opts)
+			assert.True(t, err != nil, text)
+		}
+		items, _ := ParseWithOptions([]byte(`[{"status":1},{"status":2}]`), opts)
+		keys := make([]string, 0)
+		for _, item := range items.MustArray() {
+			for key := range item.(map[string]interface{}) {
+				keys = append(keys, key)
+			}
+		}
+		assert.True(t, unsafe.StringData(keys[0]) == unsafe.StringData(keys[1]))
+	}
+}
+
+func internBenchmarkInput() []byte {
+	var buffer strings.Builder
+	buffer.WriteString("[")
+	for i := 0; i < 100000; i++ {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(`{"id":` + strconv.Itoa(i) + `,"timestamp":"2024-01-01T00:00:00Z","status":"active","owner_account":"acct"}`)
+	}
+	buffer.WriteString("]")
+	return []byte(buffer.String())
+}
+
+func BenchmarkParse_HomogeneousArray(b *testing.B) {
+	input := internBenchmarkInput()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Parse(input)
+	}
+}
+
+func BenchmarkParseInternKeys_HomogeneousArray(b *testing.B) {
+	input := internBenchmarkInput()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ParseWithOptions(input, ParseOptions{InternKeys: true})
+	}
+}
+
+func TestParsePreservesNumberLiterals(t *testing.T) {
+	literals := []string{"1.50", "1e3", "1E+3", "-2.5e-10", "-0", "-0.0", "0.000", "100", "123456789012345678901234567890.123456789012345678901234567890"}
+	parsers := map[string]func(data []byte) (*Json, error){
+		"Parse":        Parse,
+		"ParseLenient": ParseLenient,
+		"ParseLazy":    ParseLazy,
+		"InternKeys": func(data []byte) (*Json, error) {
+			return ParseWithOptions(data, ParseOptions{InternKeys: true})
+		},
+		"MaxDepth": func(data []byte) (*Json, error) {
+			return ParseWithOptions(data, ParseOptions{MaxDepth: 10})
+		},
+	}
+	for name, parse := range parsers {
+		for _, literal := range literals {
+			text := `{"n":` + literal + `,"list":[` + literal + `],"other":1}`
+			js, err := parse([]byte(text))
+			assert.True(t, err == nil, name, literal)
+			encoded, _ := js.Encode()
+			assert.True(t, string(encoded) == `{"list":[`+literal+`],"n":`+literal+`,"other":1}`, name, literal)
+			js.Set("other", 2)
+			appended, _ := js.AppendEncode(nil)
+			assert.True(t, string(appended) == `{"list":[`+literal+`],"n":`+literal+`,"other":2}`, name, literal)
+		}
+	}
+	js, _ := Parse([]byte(`{"price":1.50,"count":1e3}`))
+	assert.True(t, js.Get("price").MustFloat64() == 1.5)
+	assert.True(t, js.Get("count").MustFloat64() == 1000)
+	copied := NewJSONObject().SetPath([]string{"copy"}, js)
+	encoded, _ := copied.Encode()
+	assert.True(t, string(encoded) == `{"copy":{"count":1e3,"price":1.50}}`)
+	js.Set("price", 1.5)
+	encoded, _ = js.Encode()
+	assert.True(t, string(encoded) == `{"count":1e3,"price":1.5}`)
+}
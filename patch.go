@@ -0,0 +1,272 @@
+package betterjson
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// patchOperation is one RFC 6902 operation with its pointers parsed
+type patchOperation struct {
+	op    string
+	path  []string
+	from  []string
+	value interface{}
+}
+
+// under returns operation with key prepended to its paths, for recording a
+// change of a child in the document containing it
+func (operation patchOperation) under(key string) patchOperation {
+	operation.path = append([]string{key}, operation.path...)
+	if operation.from != nil {
+		operation.from = append([]string{key}, operation.from...)
+	}
+	return operation
+}
+
+func (operation patchOperation) encode() map[string]interface{} {
+	encoded := map[string]interface{}{
+		"op":   operation.op,
+		"path": JSONPointer(operation.path),
+	}
+	switch operation.op {
+	case "add", "replace", "test":
+		encoded["value"] = deepCopyRaw(operation.value)
+	case "move", "copy":
+		encoded["from"] = JSONPointer(operation.from)
+	}
+	return encoded
+}
+
+// ApplyPatch applies an RFC 6902 JSON Patch array to j. supported operations
+// are add, remove, replace, move, copy and test. the patch is applied as a
+// whole: when any operation fails, j is left unchanged and the error names the
+// failing operation
+func (j *Json) ApplyPatch(patch *Json) error {
+	if j.IsEmpty() {
+		return errors.New("empty json can't be patched")
+	}
+	operations, err := parsePatch(patch)
+	if err != nil {
+		return err
+	}
+	root := deepCopyRaw(j.value.Interface())
+	for idx, operation := range operations {
+		if root, err = applyPatchOperation(root, operation); err != nil {
+			return errors.Wrapf(err, "patch operation %d (%s %s)", idx, operation.op, JSONPointer(operation.path))
+		}
+	}
+	j.commitPatch(root, operations)
+	return nil
+}
+
+// commitPatch replaces j's data with root, the result of operations, and
+// records and notifies them
+func (j *Json) commitPatch(root interface{}, operations []patchOperation) {
+	prior := j.prior([]string{})
+	j.replaceData(root)
+	if !prior.observed {
+		return
+	}
+	for _, operation := range operations {
+		if operation.op != "test" {
+			j.recordChange(operation)
+		}
+	}
+	// observers see the whole patch as one change of j
+	if j.hasObservers() {
+		j.notifyChange(setOperation([]string{}, root, prior).changeEvent(prior))
+	}
+}
+
+func parsePatch(patch *Json) ([]patchOperation, error) {
+	items, ok := unwrapRaw(patch.Interface()).([]interface{})
+	if !ok {
+		return nil, errors.New("json patch must be an array of operations")
+	}
+	operations := make([]patchOperation, 0, len(items))
+	for idx, item := range items {
+		object, ok := unwrapRaw(item).(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("patch operation %d is not an object", idx)
+		}
+		op, _ := unwrapRaw(object["op"]).(string)
+		operation := patchOperation{op: op}
+		var err error
+		if operation.path, err = patchPointer(object, "path"); err != nil {
+			return nil, errors.Wrapf(err, "patch operation %d", idx)
+		}
+		switch op {
+		case "add", "replace", "test":
+			value, ok := object["value"]
+			if !ok {
+				return nil, errors.Errorf("patch operation %d (%s) has no value", idx, op)
+			}
+			operation.value = unwrapRaw(value)
+		case "move", "copy":
+			if operation.from, err = patchPointer(object, "from"); err != nil {
+				return nil, errors.Wrapf(err, "patch operation %d", idx)
+			}
+		case "remove":
+		default:
+			return nil, errors.Errorf("patch operation %d has unknown op %q", idx, op)
+		}
+		operations = append(operations, operation)
+	}
+	return operations, nil
+}
+
+func patchPointer(object map[string]interface{}, field string) ([]string, error) {
+	pointer, ok := unwrapRaw(object[field]).(string)
+	if !ok {
+		return nil, errors.Errorf("%s must be a json pointer string", field)
+	}
+	return ParseJSONPointer(pointer)
+}
+
+// applyPatchOperation applies operation to the raw document root and returns
+// the new root. containers along the path are edited in place
+func applyPatchOperation(root interface{}, operation patchOperation) (interface{}, error) {
+	switch operation.op {
+	case "add":
+		return patchAdd(root, operation.path, deepCopyRaw(operation.value))
+	case "remove":
+		return patchRemove(root, operation.path)
+	case "replace":
+		if _, ok := valueAtBranch(root, operation.path); !ok {
+			return nil, errors.New("path doesn't exist")
+		}
+		if len(operation.path) == 0 {
+			return deepCopyRaw(operation.value), nil
+		}
+		value := deepCopyRaw(operation.value)
+		return editAt(root, operation.path, func(container interface{}, key string) (interface{}, error) {
+			switch container := container.(type) {
+			case map[string]interface{}:
+				container[key] = value
+			case []interface{}:
+				idx, _ := strconv.Atoi(key)
+				container[idx] = value
+			}
+			return container, nil
+		})
+	case "move":
+		value, ok := valueAtBranch(root, operation.from)
+		if !ok {
+			return nil, errors.Errorf("from %s doesn't exist", JSONPointer(operation.from))
+		}
+		if len(operation.path) > len(operation.from) && hasPrefix(operation.path, operation.from) {
+			return nil, errors.New("a value can't be moved into itself")
+		}
+		root, err := patchRemove(root, operation.from)
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(root, operation.path, value)
+	case "copy":
+		value, ok := valueAtBranch(root, operation.from)
+		if !ok {
+			return nil, errors.Errorf("from %s doesn't exist", JSONPointer(operation.from))
+		}
+		return patchAdd(root, operation.path, deepCopyRaw(value))
+	case "test":
+		value, ok := valueAtBranch(root, operation.path)
+		if !ok || !rawEqual(value, operation.value) {
+			return nil, errors.New("test failed")
+		}
+		return root, nil
+	}
+	return nil, errors.Errorf("unknown op %q", operation.op)
+}
+
+func hasPrefix(branch []string, prefix []string) bool {
+	if len(prefix) > len(branch) {
+		return false
+	}
+	for idx, segment := range prefix {
+		if branch[idx] != segment {
+			return false
+		}
+	}
+	return true
+}
+
+// editAt calls edit with the container holding the last segment of branch and
+// stores the container it returns back into its parent. branch must not be empty
+func editAt(node interface{}, branch []string, edit func(container interface{}, key string) (interface{}, error)) (interface{}, error) {
+	node = unwrapRaw(node)
+	if len(branch) == 1 {
+		return edit(node, branch[0])
+	}
+	child, ok := childValue(node, branch[0])
+	if !ok {
+		return nil, errors.New("path doesn't exist")
+	}
+	updated, err := editAt(child, branch[1:], edit)
+	if err != nil {
+		return nil, err
+	}
+	switch container := node.(type) {
+	case map[string]interface{}:
+		container[branch[0]] = updated
+	case []interface{}:
+		idx, _ := strconv.Atoi(branch[0])
+		container[idx] = updated
+	}
+	return node, nil
+}
+
+// patchAdd adds value at branch: object members are set, array items are
+// inserted before the index, or appended for the index "-"
+func patchAdd(root interface{}, branch []string, value interface{}) (interface{}, error) {
+	if len(branch) == 0 {
+		return value, nil
+	}
+	return editAt(root, branch, func(container interface{}, key string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			container[key] = value
+			return container, nil
+		case []interface{}:
+			if key == "-" {
+				return append(container, value), nil
+			}
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx > len(container) {
+				return nil, errors.Errorf("index %s out of range of array", key)
+			}
+			result := make([]interface{}, 0, len(container)+1)
+			result = append(result, container[:idx]...)
+			result = append(result, value)
+			return append(result, container[idx:]...), nil
+		}
+		return nil, errors.Errorf("can't add to %s", kindName(container))
+	})
+}
+
+// patchRemove removes the value at branch. removed array items are not
+// overwritten in place, so other wrappers of the array keep their items
+func patchRemove(root interface{}, branch []string) (interface{}, error) {
+	if len(branch) == 0 {
+		return nil, errors.New("the document root can't be removed")
+	}
+	return editAt(root, branch, func(container interface{}, key string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			if _, ok := container[key]; !ok {
+				return nil, errors.New("path doesn't exist")
+			}
+			delete(container, key)
+			return container, nil
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(container) {
+				return nil, errors.Errorf("index %s out of range of array", key)
+			}
+			result := make([]interface{}, 0, len(container)-1)
+			result = append(result, container[:idx]...)
+			return append(result, container[idx+1:]...), nil
+		}
+		return nil, errors.New("path doesn't exist")
+	})
+}
@@ -0,0 +1,54 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_ApplyPatch(t *testing.T) {
+	a, _ := Parse([]byte(`{"a":{"b":[1,2,3]},"c":"d"}`))
+	patch, _ := Parse([]byte(`[
+		{"op":"test","path":"/c","value":"d"},
+		{"op":"add","path":"/a/b/1","value":"inserted"},
+		{"op":"remove","path":"/a/b/0"},
+		{"op":"replace","path":"/c","value":{"e":true}},
+		{"op":"copy","from":"/c","path":"/f"},
+		{"op":"move","from":"/a/b","path":"/g"},
+		{"op":"add","path":"/g/-","value":4}
+	]`))
+	assert.True(t, a.ApplyPatch(patch) == nil)
+	expected, _ := Parse([]byte(`{"a":{},"c":{"e":true},"f":{"e":true},"g":["inserted",2,3,4]}`))
+	assert.True(t, a.IsSameJSONWith(expected))
+}
+
+func TestJson_ApplyPatchIsAtomic(t *testing.T) {
+	a, _ := Parse([]byte(`{"a":1}`))
+	patch, _ := Parse([]byte(`[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":2}]`))
+	err := a.ApplyPatch(patch)
+	assert.True(t, err != nil)
+	println(err.Error())
+	assert.True(t, a.DigestJSONForEqual() == `{"a":1}`)
+
+	for _, invalid := range []string{
+		`{"op":"add"}`,
+		`[{"op":"nope","path":""}]`,
+		`[{"op":"remove","path":"/missing"}]`,
+		`[{"op":"add","path":"a","value":1}]`,
+		`[{"op":"move","from":"/a","path":"/a/b"}]`,
+	} {
+		patch, _ := Parse([]byte(invalid))
+		assert.True(t, a.ApplyPatch(patch) != nil)
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	branch := []string{"a/b", "c~d", "", "0"}
+	pointer := JSONPointer(branch)
+	assert.True(t, pointer == "/a~1b/c~0d//0")
+	parsed, err := ParseJSONPointer(pointer)
+	assert.True(t, err == nil)
+	assert.Equal(t, branch, parsed)
+	assert.True(t, JSONPointer([]string{}) == "")
+	_, err = ParseJSONPointer("/bad~2")
+	assert.True(t, err != nil)
+}
@@ -0,0 +1,248 @@
+package betterjson
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// ParseDottedPath splits a dotted path like "a.b.0.c" into its segments.
+//
+// a backslash escapes the next character, so "a\.b" is the single key "a.b"
+// and "a\\b" is the key `a\b`. the empty string is the empty branch (the root),
+// a lone backslash escaping nothing is the branch of the single key "".
+func ParseDottedPath(path string) []string {
+	if path == "" {
+		return []string{}
+	}
+	branch := make([]string, 0, strings.Count(path, ".")+1)
+	var segment bytes.Buffer
+	escaped := false
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if escaped {
+			segment.WriteByte(c)
+			escaped = false
+			continue
+		}
+		switch c {
+		case '\\':
+			escaped = true
+		case '.':
+			branch = append(branch, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteByte(c)
+		}
+	}
+	branch = append(branch, segment.String())
+	return branch
+}
+
+// JoinDottedPath is the reverse of ParseDottedPath, escaping dots, backslashes
+// and asterisks inside the segments so the result is also a literal glob pattern.
+// the branch of the single key "" is a lone backslash, "" being the root
+func JoinDottedPath(branch []string) string {
+	if len(branch) == 1 && branch[0] == "" {
+		return `\`
+	}
+	var buffer bytes.Buffer
+	for idx, segment := range branch {
+		if idx > 0 {
+			buffer.WriteByte('.')
+		}
+		writeEscapedSegment(&buffer, segment)
+	}
+	return buffer.String()
+}
+
+func writeEscapedSegment(buffer *bytes.Buffer, segment string) {
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		if c == '.' || c == '\\' || c == '*' {
+			buffer.WriteByte('\\')
+		}
+		buffer.WriteByte(c)
+	}
+}
+
+// JSONPointer formats branch as an RFC 6901 JSON Pointer like "/a/0/b",
+// escaping "~" as "~0" and "/" as "~1". the empty branch is ""
+func JSONPointer(branch []string) string {
+	var buffer bytes.Buffer
+	for _, segment := range branch {
+		buffer.WriteByte('/')
+		buffer.WriteString(pointerEscaper.Replace(segment))
+	}
+	return buffer.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// ParseJSONPointer is the reverse of JSONPointer
+func ParseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, errors.Errorf("json pointer %q doesn't start with /", pointer)
+	}
+	branch := strings.Split(pointer[1:], "/")
+	for idx, segment := range branch {
+		for i := 0; i < len(segment); i++ {
+			if segment[i] == '~' && (i+1 == len(segment) || (segment[i+1] != '0' && segment[i+1] != '1')) {
+				return nil, errors.Errorf("json pointer %q has an invalid ~ escape", pointer)
+			}
+		}
+		branch[idx] = strings.Replace(strings.Replace(segment, "~1", "/", -1), "~0", "~", -1)
+	}
+	return branch, nil
+}
+
+// GetDottedPath is GetPath with the branch given in dotted form:
+//
+//   js.GetDottedPath("top_level.dict")
+func (j *Json) GetDottedPath(path string) *Json {
+	item, _ := j.lookupPath(ParseDottedPath(path))
+	return item
+}
+
+// ContainsKeyPath reports whether there is a value at branch, explicit nulls
+// included. segments may be array indexes like GetDottedPath's
+func (j *Json) ContainsKeyPath(branch ...string) bool {
+	_, ok := j.lookupPath(branch)
+	return ok
+}
+
+// HasAllPaths reports whether every one of paths is present in j, like
+// ContainsKeyPath, and returns the dotted forms of the missing ones:
+//    if ok, missing := payload.HasAllPaths(requiredPaths); !ok {
+//        return errors.Errorf("missing %s", strings.Join(missing, ", "))
+//    }
+func (j *Json) HasAllPaths(paths [][]string) (bool, []string) {
+	missing := make([]string, 0)
+	for _, branch := range paths {
+		if !j.ContainsKeyPath(branch...) {
+			missing = append(missing, JoinDottedPath(branch))
+		}
+	}
+	return len(missing) == 0, missing
+}
+
+// HasAnyPath reports whether at least one of paths is present in j and returns
+// the dotted forms of the present ones
+func (j *Json) HasAnyPath(paths [][]string) (bool, []string) {
+	present := make([]string, 0)
+	for _, branch := range paths {
+		if j.ContainsKeyPath(branch...) {
+			present = append(present, JoinDottedPath(branch))
+		}
+	}
+	return len(present) > 0, present
+}
+
+// lookupPath resolves branch without panicking on empty or missing nodes.
+// segments are object keys, or indexes when the current node is an array
+func (j *Json) lookupPath(branch []string) (*Json, bool) {
+	result := NewEmpty()
+	ok := false
+	if !j.IsEmpty() {
+		if j.trace != nil {
+			j.trace.recordBranch(j, j.value.Interface(), branch)
+		}
+		var item interface{}
+		if item, ok = valueAtBranch(j.value.Interface(), branch); ok {
+			result = wrapRaw(item)
+		}
+	}
+	result.settings = j.settings
+	return result, ok
+}
+
+// valueAtBranch is lookupPath on raw data
+func valueAtBranch(node interface{}, branch []string) (interface{}, bool) {
+	for _, segment := range branch {
+		next, ok := childValue(node, segment)
+		if !ok {
+			return nil, false
+		}
+		node = next
+	}
+	return node, true
+}
+
+// childValue returns the item of an object or array node selected by segment
+func childValue(node interface{}, segment string) (interface{}, bool) {
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		item, ok := container[segment]
+		return item, ok
+	case []interface{}:
+		idx, err := strconv.Atoi(segment)
+		if err != nil || idx < 0 || idx >= len(container) {
+			return nil, false
+		}
+		return container[idx], true
+	}
+	return nil, false
+}
+
+// unwrapRaw returns the plain data of nodes that were stored as
+// *simplejson.Json or *Json inside a container
+func unwrapRaw(node interface{}) interface{} {
+	switch wrapped := node.(type) {
+	case *simplejson.Json:
+		if wrapped == nil {
+			return nil
+		}
+		return wrapped.Interface()
+	case *Json:
+		if wrapped == nil || wrapped.IsEmpty() {
+			return nil
+		}
+		return wrapped.value.Interface()
+	case *lazyValue:
+		return wrapped.materialize()
+	}
+	return node
+}
+
+// wrapRaw makes a Json sharing the raw data node
+func wrapRaw(node interface{}) *Json {
+	value := simplejson.New()
+	value.SetPath([]string{}, unwrapRaw(node))
+	return FromNotEmptySimpleJson(value)
+}
+
+// displayPath formats branch for error messages
+func displayPath(branch []string) string {
+	if len(branch) == 0 {
+		return "<root>"
+	}
+	return JoinDottedPath(branch)
+}
+
+// deepCopyRaw copies a raw node so the copy shares no container with it
+func deepCopyRaw(node interface{}) interface{} {
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(container))
+		for key, item := range container {
+			result[key] = deepCopyRaw(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(container))
+		for idx, item := range container {
+			result[idx] = deepCopyRaw(item)
+		}
+		return result
+	case []byte:
+		return append([]byte{}, container...)
+	default:
+		return container
+	}
+}
@@ -0,0 +1,68 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDottedPath(t *testing.T) {
+	assert.Equal(t, []string{}, ParseDottedPath(""))
+	assert.Equal(t, []string{"a", "b", "0"}, ParseDottedPath("a.b.0"))
+	assert.Equal(t, []string{"a.b", "c"}, ParseDottedPath("a\\.b.c"))
+	assert.Equal(t, []string{"a\\b"}, ParseDottedPath("a\\\\b"))
+	assert.Equal(t, []string{"", "a", ""}, ParseDottedPath(".a."))
+}
+
+func TestJoinDottedPath(t *testing.T) {
+	branches := [][]string{{}, {"a"}, {"a.b", "c"}, {"x\\y", "0"}, {""}, {"", ""}, {"", "a"}, {"a", ""}}
+	for _, branch := range branches {
+		path := JoinDottedPath(branch)
+		println(path)
+		assert.Equal(t, branch, ParseDottedPath(path))
+	}
+}
+
+func TestJson_GetDottedPath(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("hi", NewJSONObject().Set("age", 18).Set("items", NewJSONArray().TryAdd(1).TryAdd("China"))).Set("a.b", "dot")
+	assert.True(t, a.GetDottedPath("hi.age").MustInt() == 18)
+	assert.True(t, a.GetDottedPath("hi.items.1").MustString() == "China")
+	assert.True(t, a.GetDottedPath("a\\.b").MustString() == "dot")
+	assert.True(t, a.GetDottedPath("hi.items.5").IsEmpty())
+	assert.True(t, a.GetDottedPath("hi.age.x").IsEmpty())
+	assert.True(t, NewEmpty().GetDottedPath("hi").IsEmpty())
+}
+
+func TestJson_ContainsKeyPath(t *testing.T) {
+	payload, _ := Parse([]byte(`{"event":{"type":"push","commits":[{"id":"a1","author":null}]},"meta":null}`))
+	assert.True(t, payload.ContainsKeyPath("event", "type"))
+	assert.True(t, payload.ContainsKeyPath("event", "commits", "0", "id"))
+	assert.True(t, payload.ContainsKeyPath("event", "commits", "0", "author"))
+	assert.True(t, payload.ContainsKeyPath("meta"))
+	assert.True(t, payload.ContainsKeyPath())
+	assert.True(t, !payload.ContainsKeyPath("event", "commits", "1", "id"))
+	assert.True(t, !payload.ContainsKeyPath("meta", "deeper"))
+	assert.True(t, !payload.ContainsKeyPath("event", "type", "x"))
+	assert.True(t, !NewEmpty().ContainsKeyPath())
+	assert.True(t, !payload.Get("missing").ContainsKeyPath("a"))
+}
+
+func TestJson_HasAllPaths(t *testing.T) {
+	payload, _ := Parse([]byte(`{"event":{"type":"push","commits":[{"id":"a1","author":null}]}}`))
+	required := [][]string{{"event", "type"}, {"event", "commits", "0", "author"}, {"event", "sender"}, {"event", "commits", "1"}}
+	ok, missing := payload.HasAllPaths(required)
+	assert.True(t, !ok)
+	assert.Equal(t, []string{"event.sender", "event.commits.1"}, missing)
+	ok, missing = payload.HasAllPaths(required[:2])
+	assert.True(t, ok && len(missing) == 0)
+	ok, missing = NewEmpty().HasAllPaths(required[:1])
+	assert.True(t, !ok && len(missing) == 1)
+
+	ok, present := payload.HasAnyPath(required)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"event.type", "event.commits.0.author"}, present)
+	ok, present = payload.HasAnyPath(required[2:])
+	assert.True(t, !ok && len(present) == 0)
+	ok, _ = NewEmpty().HasAnyPath(required)
+	assert.True(t, !ok)
+}
@@ -0,0 +1,106 @@
+package betterjson
+
+import (
+	"sync"
+
+	"github.com/bitly/go-simplejson"
+)
+
+var (
+	objectPool = sync.Pool{New: func() interface{} { return simplejson.New() }}
+	arrayPool  = sync.Pool{New: func() interface{} {
+		value := simplejson.New()
+		value.SetPath([]string{}, make([]interface{}, 0, 8))
+		return value
+	}}
+	poolDebug bool
+)
+
+// SetPoolDebug turns the checks of the documents of AcquireObject and
+// AcquireArray on or off. when on, releasing a document twice panics, and
+// released data isn't reused but poisoned: objects are left holding just
+// {"$released":true} and arrays [] so code still using them stands out.
+// meant for tests, not to be changed while documents are in use
+func SetPoolDebug(enabled bool) {
+	poolDebug = enabled
+}
+
+// AcquireObject is NewJSONObject taking the storage of the object from a
+// pool. pass the document to Release when done with it:
+//    response := betterjson.AcquireObject()
+//    defer response.Release()
+//    response.Set("id", id).Set("items", items)
+//    encoded, err := response.AppendEncode(buffer[:0])
+func AcquireObject() *Json {
+	return acquire(&objectPool)
+}
+
+// AcquireArray is NewJSONArray taking the storage of the array from a pool
+func AcquireArray() *Json {
+	return acquire(&arrayPool)
+}
+
+func acquire(pool *sync.Pool) *Json {
+	j := FromNotEmptySimpleJson(pool.Get().(*simplejson.Json))
+	j.pool = pool
+	return j
+}
+
+// Release empties j and returns its storage to the pool of AcquireObject or
+// AcquireArray. Release does nothing for documents that aren't from there.
+// after Release j is empty, calling it again does nothing, or panics with
+// SetPoolDebug. values taken out of j before, with Get, Map, Interface and
+// the like, must not be used afterwards: the root container is reused by the
+// next document acquired
+func (j *Json) Release() {
+	if j.pool == nil {
+		return
+	}
+	if j.released {
+		if poolDebug {
+			panic("betterjson: json released twice")
+		}
+		return
+	}
+	storage := j.value
+	pool := j.pool
+	*j = Json{pool: pool, released: true}
+	if storage == nil {
+		return
+	}
+	if poolDebug {
+		switch data := storage.Interface().(type) {
+		case map[string]interface{}:
+			for key := range data {
+				delete(data, key)
+			}
+			data["$released"] = true
+		case []interface{}:
+			for idx := range data {
+				data[idx] = nil
+			}
+		}
+		storage.SetPath([]string{}, nil)
+		return
+	}
+	if pool == &objectPool {
+		object, ok := storage.Interface().(map[string]interface{})
+		if !ok {
+			object = make(map[string]interface{})
+		}
+		for key := range object {
+			delete(object, key)
+		}
+		storage.SetPath([]string{}, object)
+	} else {
+		array, ok := storage.Interface().([]interface{})
+		if !ok {
+			array = make([]interface{}, 0, 8)
+		}
+		for idx := range array {
+			array[idx] = nil
+		}
+		storage.SetPath([]string{}, array[:0])
+	}
+	pool.Put(storage)
+}
@@ -0,0 +1,75 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	object := AcquireObject()
+	object.Set("id", 1).Set("tags", Arr("a"))
+	assert.Equal(t, `{"id":1,"tags":["a"]}`, object.EncodeToStringOrDefault(""))
+	object.Release()
+	assert.True(t, object.IsEmpty())
+	object.Release()
+
+	reused := AcquireObject()
+	assert.Equal(t, `{}`, reused.EncodeToStringOrDefault(""))
+	reused.Release()
+
+	array := AcquireArray()
+	array.TryAdd(1).TryAdd("x")
+	assert.Equal(t, `[1,"x"]`, array.EncodeToStringOrDefault(""))
+	array.Release()
+	assert.Equal(t, `[]`, AcquireArray().EncodeToStringOrDefault(""))
+
+	// documents that aren't from the pool are left alone
+	parsed, _ := Parse([]byte(`{"a":1}`))
+	parsed.Release()
+	assert.Equal(t, 1, parsed.Get("a").MustInt())
+
+	// an acquired object whose root was replaced still goes back as an object
+	replaced := AcquireObject()
+	replaced.SetValue(5)
+	replaced.Release()
+	assert.Equal(t, `{}`, AcquireObject().EncodeToStringOrDefault(""))
+}
+
+func TestPoolDebug(t *testing.T) {
+	SetPoolDebug(true)
+	defer SetPoolDebug(false)
+	object := AcquireObject()
+	object.Set("secret", "x")
+	data, _ := object.Map()
+	child := object.Get("secret")
+	object.Release()
+	assert.Equal(t, map[string]interface{}{"$released": true}, data)
+	child.SetValue("changed")
+	assert.True(t, object.IsEmpty())
+
+	defer func() {
+		assert.Equal(t, "betterjson: json released twice", recover())
+	}()
+	object.Release()
+}
+
+func BenchmarkBuildEncode(b *testing.B) {
+	buffer := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		response := NewJSONObject()
+		response.Set("id", i).Set("status", "ok").Set("count", 3)
+		buffer, _ = response.AppendEncode(buffer[:0])
+	}
+}
+
+func BenchmarkBuildEncodeRelease(b *testing.B) {
+	buffer := make([]byte, 0, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		response := AcquireObject()
+		response.Set("id", i).Set("status", "ok").Set("count", 3)
+		buffer, _ = response.AppendEncode(buffer[:0])
+		response.Release()
+	}
+}
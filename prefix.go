@@ -0,0 +1,86 @@
+package betterjson
+
+import (
+	"strings"
+)
+
+// WithPrefix returns a new object of the members of the j object whose keys
+// start with prefix, the prefix stripped from the keys:
+//    // config is {"aws_region":"eu","aws_account":"1","gcp_project":"p"}
+//    aws := config.WithPrefix("aws_") // {"account":"1","region":"eu"}
+// the values are copies. it returns an empty Json when j isn't an object
+func (j *Json) WithPrefix(prefix string) *Json {
+	object, ok := j.objectValue()
+	if !ok {
+		return NewEmpty()
+	}
+	result := make(map[string]interface{})
+	for key, item := range object {
+		if strings.HasPrefix(key, prefix) {
+			result[key[len(prefix):]] = deepCopyRaw(item)
+		}
+	}
+	return wrapRaw(result)
+}
+
+// AddPrefix returns a new object of the members of the j object with prefix
+// added to every key. the values are copies. it returns an empty Json when j
+// isn't an object
+func (j *Json) AddPrefix(prefix string) *Json {
+	object, ok := j.objectValue()
+	if !ok {
+		return NewEmpty()
+	}
+	result := make(map[string]interface{}, len(object))
+	for key, item := range object {
+		result[prefix+key] = deepCopyRaw(item)
+	}
+	return wrapRaw(result)
+}
+
+// GroupByPrefix returns a copy of the j object with the keys containing sep
+// grouped into nested objects by the part before the first sep:
+//    // config is {"aws_region":"eu","aws_account":"1","name":"n"}
+//    nested := config.GroupByPrefix("_") // {"aws":{"account":"1","region":"eu"},"name":"n"}
+// keys are kept flat when the group would have an empty name or an empty key,
+// and all the keys of a group are kept flat when j also has a key equal to
+// the group name, so nothing is lost. it returns an empty Json when j isn't
+// an object
+func (j *Json) GroupByPrefix(sep string) *Json {
+	object, ok := j.objectValue()
+	if !ok {
+		return NewEmpty()
+	}
+	result := make(map[string]interface{}, len(object))
+	groups := make(map[string]map[string]interface{})
+	for key, item := range object {
+		group, rest, grouped := groupKey(key, sep)
+		if !grouped {
+			result[key] = deepCopyRaw(item)
+			continue
+		}
+		if _, collides := object[group]; collides {
+			result[key] = deepCopyRaw(item)
+			continue
+		}
+		if groups[group] == nil {
+			groups[group] = make(map[string]interface{})
+		}
+		groups[group][rest] = deepCopyRaw(item)
+	}
+	for group, members := range groups {
+		result[group] = members
+	}
+	return wrapRaw(result)
+}
+
+func groupKey(key string, sep string) (string, string, bool) {
+	if sep == "" {
+		return "", "", false
+	}
+	idx := strings.Index(key, sep)
+	if idx <= 0 || idx+len(sep) == len(key) {
+		return "", "", false
+	}
+	return key[:idx], key[idx+len(sep):], true
+}
@@ -0,0 +1,50 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func prefixedConfig() *Json {
+	js, _ := Parse([]byte(`{"aws_region":"eu","aws_account":"1","aws_tags_team":["a"],"gcp_project":"p","name":"svc","_hidden":1,"trailing_":2}`))
+	return js
+}
+
+func TestWithPrefix(t *testing.T) {
+	js := prefixedConfig()
+	aws := js.WithPrefix("aws_")
+	assert.Equal(t, `{"account":"1","region":"eu","tags_team":["a"]}`, aws.EncodeToStringOrDefault(""))
+	aws.Get("tags_team").TryAdd("b")
+	assert.Equal(t, 1, len(js.Get("aws_tags_team").MustArray()))
+	assert.Equal(t, `{}`, js.WithPrefix("azure_").EncodeToStringOrDefault(""))
+	assert.Equal(t, 7, len(js.WithPrefix("").MustMap()))
+	assert.True(t, Arr(1).WithPrefix("a").IsEmpty())
+}
+
+func TestAddPrefix(t *testing.T) {
+	js := Obj("region", "eu", "account", Obj("id", 1))
+	prefixed := js.AddPrefix("aws_")
+	assert.Equal(t, `{"aws_account":{"id":1},"aws_region":"eu"}`, prefixed.EncodeToStringOrDefault(""))
+	assert.True(t, js.IsSameJSONWith(prefixed.WithPrefix("aws_")))
+	assert.True(t, NewEmpty().AddPrefix("a").IsEmpty())
+}
+
+func TestGroupByPrefix(t *testing.T) {
+	js := prefixedConfig()
+	grouped := js.GroupByPrefix("_")
+	assert.Equal(t, `{"_hidden":1,"aws":{"account":"1","region":"eu","tags_team":["a"]},"gcp":{"project":"p"},"name":"svc","trailing_":2}`, grouped.EncodeToStringOrDefault(""))
+	assert.True(t, js.Get("aws").IsNull())
+
+	dotted := Obj("db.host", "h", "db.port", 5432).GroupByPrefix(".")
+	assert.Equal(t, `{"db":{"host":"h","port":5432}}`, dotted.EncodeToStringOrDefault(""))
+	assert.Equal(t, `{"a_b":1}`, Obj("a_b", 1).GroupByPrefix("").EncodeToStringOrDefault(""))
+}
+
+func TestGroupByPrefixCollision(t *testing.T) {
+	// a key equal to a group name keeps the whole group flat
+	js := Obj("aws", "legacy", "aws_region", "eu", "aws_account", "1", "gcp_project", "p")
+	grouped := js.GroupByPrefix("_")
+	assert.Equal(t, `{"aws":"legacy","aws_account":"1","aws_region":"eu","gcp":{"project":"p"}}`, grouped.EncodeToStringOrDefault(""))
+	js.Del("aws")
+	assert.Equal(t, `{"aws":{"account":"1","region":"eu"},"gcp":{"project":"p"}}`, js.GroupByPrefix("_").EncodeToStringOrDefault(""))
+}
@@ -0,0 +1,270 @@
+package betterjson
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// projection is the paths of EncodeOnly or EncodeExcept and the branch of the
+// value being written
+type projection struct {
+	globs []globPattern
+	path  []string
+	// indexes are the segments of the paths that are array indexes, sorted
+	indexes []int
+}
+
+func newProjection(paths []string) *projection {
+	p := &projection{globs: make([]globPattern, 0, len(paths)), path: make([]string, 0, 8)}
+	seen := make(map[int]bool)
+	for _, path := range paths {
+		glob := parseGlobPattern(path)
+		p.globs = append(p.globs, glob)
+		for _, segment := range glob {
+			if idx, err := strconv.Atoi(segment.text); err == nil && segment.kind == globLiteral && idx >= 0 && strconv.Itoa(idx) == segment.text && !seen[idx] {
+				seen[idx] = true
+				p.indexes = append(p.indexes, idx)
+			}
+		}
+	}
+	sort.Ints(p.indexes)
+	return p
+}
+
+// itemIndexes returns the indexes of the items of the array of length n at
+// the current branch that the paths may select or reach into, nil when a
+// wildcard may do that for any item. the index segments are only formatted
+// for these, which keeps walking past large arrays allocation free
+func (p *projection) itemIndexes(n int) []int {
+	// only wildcards match this segment, unless a path has it literally,
+	// which just disables the shortcut
+	p.path = append(p.path, "\x00")
+	wildcard := p.selects() || p.reaches()
+	p.path = p.path[:len(p.path)-1]
+	if wildcard {
+		return nil
+	}
+	end := sort.SearchInts(p.indexes, n)
+	if end == 0 {
+		return []int{}
+	}
+	return p.indexes[:end:end]
+}
+
+// selects reports whether one of the paths matches the current branch
+func (p *projection) selects() bool {
+	for _, glob := range p.globs {
+		if glob.match(p.path) {
+			return true
+		}
+	}
+	return false
+}
+
+// reaches reports whether one of the paths may match below the current branch
+func (p *projection) reaches() bool {
+	for _, glob := range p.globs {
+		if glob.matchPrefix(p.path) {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeOnly encodes just the values at paths and the objects and arrays
+// leading to them, in one walk without copying the document. paths are
+// dotted glob patterns like PathsMatching's. arrays keep only the items
+// holding a selected value, so their indexes may shift, and when nothing is
+// selected the result is {} or [] for a container and null otherwise:
+//    body, err := order.EncodeOnly([]string{"id", "items.*.sku"})
+func (j *Json) EncodeOnly(paths []string) ([]byte, error) {
+	if j.IsEmpty() {
+		return []byte{}, errors.New("empty json can't be encoded")
+	}
+	encoder := &streamEncoder{}
+	encoder.w = &encoder.buffer
+	root := j.Interface()
+	if _, err := encoder.encodeOnly(root, newProjection(paths)); err != nil {
+		return []byte{}, err
+	}
+	if len(encoder.buffer.b) == 0 {
+		switch container := unwrapRaw(root).(type) {
+		case map[string]interface{}:
+			if container != nil {
+				return []byte("{}"), nil
+			}
+		case []interface{}:
+			if container != nil {
+				return []byte("[]"), nil
+			}
+		}
+		return []byte("null"), nil
+	}
+	return encoder.buffer.b, nil
+}
+
+// EncodeExcept encodes j without the values at paths, the complement of
+// EncodeOnly. array items at the paths are left out, shifting the indexes
+// after them. excluding the whole document is an error
+func (j *Json) EncodeExcept(paths []string) ([]byte, error) {
+	if j.IsEmpty() {
+		return []byte{}, errors.New("empty json can't be encoded")
+	}
+	p := newProjection(paths)
+	if p.selects() {
+		return []byte{}, errors.New("the paths exclude the whole document")
+	}
+	encoder := &streamEncoder{}
+	encoder.w = &encoder.buffer
+	if err := encoder.encodeExcept(j.Interface(), p); err != nil {
+		return []byte{}, err
+	}
+	return encoder.buffer.b, nil
+}
+
+// encodeOnly writes the selected parts of node and reports whether there
+// were any. members and items without any are taken back out of the buffer
+func (e *streamEncoder) encodeOnly(node interface{}, p *projection) (bool, error) {
+	if p.selects() {
+		return true, e.encode(node, len(p.path))
+	}
+	if !p.reaches() {
+		return false, nil
+	}
+	written := 0
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		if container == nil {
+			return false, nil
+		}
+		e.w.WriteByte('{')
+		for _, key := range sortedKeys(container) {
+			mark := len(e.buffer.b)
+			if written > 0 {
+				e.w.WriteByte(',')
+			}
+			if err := e.encodeString(key); err != nil {
+				return false, err
+			}
+			e.w.WriteByte(':')
+			p.path = append(p.path, key)
+			ok, err := e.encodeOnly(container[key], p)
+			p.path = p.path[:len(p.path)-1]
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				written++
+			} else {
+				e.buffer.b = e.buffer.b[:mark]
+			}
+		}
+		e.w.WriteByte('}')
+	case []interface{}:
+		if container == nil {
+			return false, nil
+		}
+		e.w.WriteByte('[')
+		candidates := p.itemIndexes(len(container))
+		for pos := range container {
+			idx := pos
+			if candidates != nil {
+				if pos >= len(candidates) {
+					break
+				}
+				idx = candidates[pos]
+			}
+			item := container[idx]
+			mark := len(e.buffer.b)
+			if written > 0 {
+				e.w.WriteByte(',')
+			}
+			p.path = append(p.path, strconv.Itoa(idx))
+			ok, err := e.encodeOnly(item, p)
+			p.path = p.path[:len(p.path)-1]
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				written++
+			} else {
+				e.buffer.b = e.buffer.b[:mark]
+			}
+		}
+		e.w.WriteByte(']')
+	}
+	return written > 0, nil
+}
+
+// encodeExcept writes node without the excluded parts, subtrees no path reaches
+// into are written by encode as they are
+func (e *streamEncoder) encodeExcept(node interface{}, p *projection) error {
+	if !p.reaches() {
+		return e.encode(node, len(p.path))
+	}
+	written := 0
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		if container == nil {
+			break
+		}
+		e.w.WriteByte('{')
+		for _, key := range sortedKeys(container) {
+			p.path = append(p.path, key)
+			if !p.selects() {
+				if written > 0 {
+					e.w.WriteByte(',')
+				}
+				written++
+				if err := e.encodeString(key); err != nil {
+					return err
+				}
+				e.w.WriteByte(':')
+				if err := e.encodeExcept(container[key], p); err != nil {
+					return err
+				}
+			}
+			p.path = p.path[:len(p.path)-1]
+		}
+		e.w.WriteByte('}')
+		return nil
+	case []interface{}:
+		if container == nil {
+			break
+		}
+		e.w.WriteByte('[')
+		candidates := p.itemIndexes(len(container))
+		for idx, item := range container {
+			if candidates != nil && (len(candidates) == 0 || candidates[0] != idx) {
+				// no path selects or reaches into the item
+				if written > 0 {
+					e.w.WriteByte(',')
+				}
+				written++
+				if err := e.encode(item, len(p.path)+1); err != nil {
+					return err
+				}
+				continue
+			}
+			if candidates != nil {
+				candidates = candidates[1:]
+			}
+			p.path = append(p.path, strconv.Itoa(idx))
+			if !p.selects() {
+				if written > 0 {
+					e.w.WriteByte(',')
+				}
+				written++
+				if err := e.encodeExcept(item, p); err != nil {
+					return err
+				}
+			}
+			p.path = p.path[:len(p.path)-1]
+		}
+		e.w.WriteByte(']')
+		return nil
+	}
+	return e.encode(node, len(p.path))
+}
@@ -0,0 +1,88 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const projectFixture = `{"id":7,"customer":{"name":"Ann","email":"a@x","address":{"city":"Oslo","zip":"0150"}},
+"items":[{"sku":"s1","qty":1,"note":null},{"sku":"s2","qty":2},{"qty":3}],"tags":[],"internal":{"score":0.5}}`
+
+func TestEncodeOnly(t *testing.T) {
+	js, _ := Parse([]byte(projectFixture))
+	cases := []struct {
+		paths    []string
+		expected string
+	}{
+		{[]string{"id"}, `{"id":7}`},
+		{[]string{"customer.address.city", "id"}, `{"customer":{"address":{"city":"Oslo"}},"id":7}`},
+		{[]string{"items.*.sku"}, `{"items":[{"sku":"s1"},{"sku":"s2"}]}`},
+		{[]string{"items.1"}, `{"items":[{"qty":2,"sku":"s2"}]}`},
+		{[]string{"**.city", "internal"}, `{"customer":{"address":{"city":"Oslo"}},"internal":{"score":0.5}}`},
+		{[]string{"items.0.note", "tags"}, `{"items":[{"note":null}],"tags":[]}`},
+		{[]string{"missing", "id.deeper"}, `{}`},
+		{[]string{}, `{}`},
+		{[]string{""}, js.EncodeToStringOrDefault("")},
+	}
+	for _, c := range cases {
+		encoded, err := js.EncodeOnly(c.paths)
+		assert.True(t, err == nil)
+		assert.True(t, json.Valid(encoded))
+		assert.Equal(t, c.expected, string(encoded))
+	}
+	encoded, _ := NewString("x").EncodeOnly([]string{"a"})
+	assert.Equal(t, "null", string(encoded))
+	encoded, _ = NewJSONArrayFrom(1, 2).EncodeOnly([]string{"5"})
+	assert.Equal(t, "[]", string(encoded))
+	_, err := NewEmpty().EncodeOnly([]string{"a"})
+	assert.True(t, err != nil)
+}
+
+func TestEncodeExcept(t *testing.T) {
+	js, _ := Parse([]byte(projectFixture))
+	encoded, err := js.EncodeExcept([]string{"internal", "customer.email", "items.*.note", "items.2"})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"customer":{"address":{"city":"Oslo","zip":"0150"},"name":"Ann"},"id":7,"items":[{"qty":1,"sku":"s1"},{"qty":2,"sku":"s2"}],"tags":[]}`, string(encoded))
+	encoded, _ = js.EncodeExcept([]string{"**.zip", "**.qty", "items.*.sku", "tags", "internal", "customer"})
+	assert.Equal(t, `{"id":7,"items":[{"note":null},{},{}]}`, string(encoded))
+	encoded, _ = js.EncodeExcept([]string{"missing"})
+	assert.Equal(t, js.EncodeToStringOrDefault(""), string(encoded))
+	_, err = js.EncodeExcept([]string{"**"})
+	assert.True(t, err != nil)
+	println(err.Error())
+}
+
+func projectionDocument() *Json {
+	// about 5MB encoded
+	return largeDocument(200000).Set("count", 200000)
+}
+
+func BenchmarkEncodeOnly(b *testing.B) {
+	js := projectionDocument()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		js.EncodeOnly([]string{"count", "items.0"})
+	}
+}
+
+func BenchmarkEncodeOnlyByCopy(b *testing.B) {
+	js := projectionDocument()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		projected := wrapRaw(deepCopyRaw(js.Interface()))
+		projected.Set("items", NewJSONArrayFrom(projected.Get("items").GetIndex(0)))
+		projected.Encode()
+	}
+}
+
+func BenchmarkEncodeExcept(b *testing.B) {
+	js := projectionDocument()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		js.EncodeExcept([]string{"count", "items.0.name"})
+	}
+}
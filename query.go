@@ -0,0 +1,276 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Where keeps the objects of the j array whose fields match every key/value
+// pair of conditions, returning them as a new array of copies:
+//    users.Where(conditions) // conditions is {"user.role":"admin","status":{"$in":["active","new"]}}
+//
+// condition keys are dotted paths into each element. values are compared deep
+// equal with numbers compared by value (1 matches 1.0), except an object of
+// the single key "$in" holding an array, which matches any of the items.
+// elements that aren't objects never match a non-empty conditions object.
+// non-array receivers give an empty Json
+func (j *Json) Where(conditions *Json) *Json {
+	items, err := j.Array()
+	if err != nil {
+		return NewEmpty()
+	}
+	var conditionMap map[string]interface{}
+	if conditions != nil && !conditions.IsEmpty() {
+		conditionMap, _ = unwrapRaw(conditions.value.Interface()).(map[string]interface{})
+	}
+	result := make([]interface{}, 0)
+	for idx, item := range items {
+		if j.trace != nil {
+			for path := range conditionMap {
+				j.recordItem(idx, item, ParseDottedPath(path))
+			}
+		}
+		if matchesConditions(item, conditionMap) {
+			result = append(result, deepCopyRaw(item))
+		}
+	}
+	return wrapRaw(result)
+}
+
+func matchesConditions(item interface{}, conditions map[string]interface{}) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+	if _, isObject := unwrapRaw(item).(map[string]interface{}); !isObject {
+		return false
+	}
+	element := wrapRaw(item)
+	for path, expected := range conditions {
+		actual, ok := element.lookupPath(ParseDottedPath(path))
+		if !ok || !matchesCondition(actual.value.Interface(), unwrapRaw(expected)) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesCondition(actual interface{}, expected interface{}) bool {
+	if operator, ok := expected.(map[string]interface{}); ok && len(operator) == 1 {
+		if candidates, isArray := unwrapRaw(operator["$in"]).([]interface{}); isArray {
+			for _, candidate := range candidates {
+				if rawEqual(actual, candidate) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return rawEqual(actual, expected)
+}
+
+// PluckOptions configures PluckWithOptions
+type PluckOptions struct {
+	// SkipMissing leaves out elements where the path is missing instead of
+	// contributing null
+	SkipMissing bool
+}
+
+// Pluck maps the j array to a new array of the values found at path in each
+// element, null where it's missing:
+//    users.Pluck("profile", "email")
+//
+// the values are copies, so the result shares nothing with j. non-array
+// receivers give an empty Json
+func (j *Json) Pluck(path ...string) *Json {
+	return j.PluckWithOptions(PluckOptions{}, path...)
+}
+
+// PluckWithOptions is Pluck configured by opts
+func (j *Json) PluckWithOptions(opts PluckOptions, path ...string) *Json {
+	items, err := j.Array()
+	if err != nil {
+		return NewEmpty()
+	}
+	result := make([]interface{}, 0, len(items))
+	for idx, item := range items {
+		j.recordItem(idx, item, path)
+		value, ok := wrapRaw(item).lookupPath(path)
+		if !ok {
+			if !opts.SkipMissing {
+				result = append(result, nil)
+			}
+			continue
+		}
+		result = append(result, deepCopyRaw(value.value.Interface()))
+	}
+	return wrapRaw(result)
+}
+
+// SelectMany is Pluck flattening: values at path that are arrays contribute
+// their items, other values contribute themselves and missing paths nothing:
+//    tags := posts.SelectMany("tags")
+//
+// the result is a new array of copies, in the order of j and of the arrays.
+// non-array receivers give an empty Json
+func (j *Json) SelectMany(path ...string) *Json {
+	items, err := j.Array()
+	if err != nil {
+		return NewEmpty()
+	}
+	result := make([]interface{}, 0, len(items))
+	for idx, item := range items {
+		j.recordItem(idx, item, path)
+		value, ok := valueAtBranch(item, path)
+		if !ok {
+			continue
+		}
+		if nested, isArray := unwrapRaw(value).([]interface{}); isArray {
+			for _, nestedItem := range nested {
+				result = append(result, deepCopyRaw(nestedItem))
+			}
+			continue
+		}
+		result = append(result, deepCopyRaw(value))
+	}
+	return wrapRaw(result)
+}
+
+// IndexError is the failure of one array item
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("item %d: %s", e.Index, e.Err.Error())
+}
+
+// IndexErrors collects the failures of several array items
+type IndexErrors []*IndexError
+
+func (errs IndexErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// PluckStrings is Pluck returning the values as strings. items where the path
+// is missing or isn't a string are left "" and reported in the IndexErrors
+// error, which lists every failing index
+func (j *Json) PluckStrings(path ...string) ([]string, error) {
+	result := make([]string, 0)
+	err := j.pluckEach(path, func(idx int, value *Json) error {
+		s, err := value.value.String()
+		if err != nil {
+			return errors.Errorf("%s is not a string", kindName(value.value.Interface()))
+		}
+		result[idx] = s
+		return nil
+	}, func(length int) {
+		result = make([]string, length)
+	})
+	return result, err
+}
+
+// PluckInts is PluckStrings for int values. numbers with a fraction are reported
+func (j *Json) PluckInts(path ...string) ([]int, error) {
+	result := make([]int, 0)
+	err := j.pluckEach(path, func(idx int, value *Json) error {
+		i, err := integerValue(value)
+		result[idx] = int(i)
+		return err
+	}, func(length int) {
+		result = make([]int, length)
+	})
+	return result, err
+}
+
+// integerValue converts a number without a fraction to int64, reporting
+// fractions and numbers out of the int64 range instead of truncating them
+func integerValue(value *Json) (int64, error) {
+	raw := unwrapRaw(value.value.Interface())
+	switch number := raw.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return 0, errors.Errorf("number %s is not an integer", number)
+		}
+		return floatInteger(f, raw)
+	case float64:
+		return floatInteger(number, raw)
+	case float32:
+		return floatInteger(float64(number), raw)
+	case int:
+		return int64(number), nil
+	case int8:
+		return int64(number), nil
+	case int16:
+		return int64(number), nil
+	case int32:
+		return int64(number), nil
+	case int64:
+		return number, nil
+	case uint8:
+		return int64(number), nil
+	case uint16:
+		return int64(number), nil
+	case uint32:
+		return int64(number), nil
+	case uint:
+		return unsignedInteger(uint64(number), raw)
+	case uint64:
+		return unsignedInteger(number, raw)
+	}
+	return 0, errors.Errorf("%s is not a number", kindName(raw))
+}
+
+// floatInteger is f as int64 when it has no fraction and fits
+func floatInteger(f float64, raw interface{}) (int64, error) {
+	// float64(math.MaxInt64) rounds up to 2^63, which doesn't fit
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, errors.Errorf("number %v is not an integer", raw)
+	}
+	return int64(f), nil
+}
+
+// unsignedInteger is u as int64 when it fits
+func unsignedInteger(u uint64, raw interface{}) (int64, error) {
+	if u > math.MaxInt64 {
+		return 0, errors.Errorf("number %v is out of the int64 range", raw)
+	}
+	return int64(u), nil
+}
+
+func (j *Json) pluckEach(path []string, convert func(idx int, value *Json) error, allocate func(length int)) error {
+	items, err := j.Array()
+	if err != nil {
+		return err
+	}
+	allocate(len(items))
+	errs := make(IndexErrors, 0)
+	for idx, item := range items {
+		j.recordItem(idx, item, path)
+		value, ok := wrapRaw(item).lookupPath(path)
+		if !ok {
+			errs = append(errs, &IndexError{Index: idx, Err: errors.Errorf("path %s is missing", displayPath(path))})
+			continue
+		}
+		if err := convert(idx, value); err != nil {
+			errs = append(errs, &IndexError{Index: idx, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package betterjson
+
+import (
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func queryFixture(t *testing.T) *Json {
+	users, err := Parse([]byte(`[
+		{"id":1,"status":"active","user":{"role":"admin","level":3}},
+		{"id":2,"status":"new","user":{"role":"guest","level":1.0}},
+		{"id":3,"status":"banned","user":{"role":"admin","level":1}},
+		"not an object",
+		{"id":4,"status":"active"}
+	]`))
+	assert.True(t, err == nil)
+	return users
+}
+
+func whereIds(result *Json) []int {
+	ids := make([]int, 0)
+	for idx := 0; idx < result.ArrayLength(); idx++ {
+		ids = append(ids, result.GetIndex(idx).Get("id").MustInt())
+	}
+	return ids
+}
+
+func TestJson_Where(t *testing.T) {
+	users := queryFixture(t)
+	assert.Equal(t, []int{1, 3}, whereIds(users.Where(NewJSONObject().Set("user.role", "admin"))))
+	assert.Equal(t, []int{2, 3}, whereIds(users.Where(NewJSONObject().Set("user.level", 1))))
+	assert.Equal(t, []int{1}, whereIds(users.Where(NewJSONObject().Set("user.role", "admin").Set("status", "active"))))
+	in := NewJSONObject().Set("status", NewJSONObject().Set("$in", NewJSONArray().TryAdd("active").TryAdd("new")))
+	assert.Equal(t, []int{1, 2, 4}, whereIds(users.Where(in)))
+	assert.True(t, users.Where(NewJSONObject().Set("status", "gone")).ArrayLength() == 0)
+	assert.True(t, users.Where(NewJSONObject()).ArrayLength() == 5)
+	assert.True(t, users.GetIndex(0).Where(NewJSONObject()).IsEmpty())
+	assert.True(t, NewEmpty().Where(NewJSONObject()).IsEmpty())
+}
+
+func TestJson_WhereFloats(t *testing.T) {
+	products, err := Parse([]byte(`[{"id":1,"price":19.99},{"id":2,"price":0.1},{"id":3,"price":20}]`))
+	assert.True(t, err == nil)
+	assert.Equal(t, []int{1}, whereIds(products.Where(Obj("price", 19.99))))
+	assert.Equal(t, []int{2}, whereIds(products.Where(Obj("price", float32(0.1)))))
+	assert.Equal(t, []int{1, 2}, whereIds(products.Where(Obj("price", Obj("$in", Arr(0.1, 19.99))))))
+
+	patch := Arr(Obj("op", "test", "path", "/1/price", "value", 0.1), Obj("op", "replace", "path", "/1/price", "value", 0.2))
+	assert.True(t, products.ApplyPatch(patch) == nil)
+	assert.Equal(t, "0.2", products.GetIndex(1).Get("price").EncodeToStringOrDefault(""))
+}
+
+func TestJson_WhereCopiesElements(t *testing.T) {
+	users := queryFixture(t)
+	admins := users.Where(NewJSONObject().Set("user.role", "admin"))
+	admins.GetIndex(0).Set("id", 100)
+	assert.True(t, users.GetIndex(0).Get("id").MustInt() == 1)
+}
+
+func TestJson_Pluck(t *testing.T) {
+	users := queryFixture(t)
+	roles := users.Pluck("user", "role")
+	rolesStr, err := roles.EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, rolesStr == `["admin","guest","admin",null,null]`)
+	skipped := users.PluckWithOptions(PluckOptions{SkipMissing: true}, "user", "role")
+	assert.True(t, skipped.EncodeToStringOrDefault("") == `["admin","guest","admin"]`)
+	objects := users.Pluck("user")
+	objects.GetIndex(0).Set("role", "root")
+	assert.True(t, users.GetIndex(0).GetPath("user", "role").MustString() == "admin")
+	assert.True(t, users.GetIndex(0).Pluck("id").IsEmpty())
+}
+
+func TestJson_PluckStrings(t *testing.T) {
+	users := queryFixture(t)
+	statuses, err := users.PluckStrings("status")
+	assert.True(t, err != nil)
+	println(err.Error())
+	assert.Equal(t, []string{"active", "new", "banned", "", "active"}, statuses)
+	indexErrs, ok := err.(IndexErrors)
+	assert.True(t, ok)
+	assert.True(t, len(indexErrs) == 1 && indexErrs[0].Index == 3)
+	_, err = users.PluckStrings("id")
+	assert.True(t, err != nil)
+	assert.True(t, len(err.(IndexErrors)) == 5)
+	emails, err := NewJSONArray().TryAdd(NewJSONObject().Set("email", "a@x.com")).PluckStrings("email")
+	assert.True(t, err == nil)
+	assert.Equal(t, []string{"a@x.com"}, emails)
+}
+
+func TestJson_PluckInts(t *testing.T) {
+	users := queryFixture(t)
+	levels, err := users.PluckInts("user", "level")
+	assert.Equal(t, []int{3, 1, 1, 0, 0}, levels)
+	assert.True(t, err != nil)
+	println(err.Error())
+	assert.True(t, len(err.(IndexErrors)) == 2)
+	ids, err := users.Where(NewJSONObject()).PluckInts("id")
+	assert.True(t, err != nil)
+	assert.Equal(t, []int{1, 2, 3, 0, 4}, ids)
+	_, err = NewJSONObject().PluckInts("id")
+	assert.True(t, err != nil)
+}
+
+func TestJson_PluckIntsGoValues(t *testing.T) {
+	ints, err := NewJSONArray().TryAdd(1.5).TryAdd(2).TryAdd(uint64(math.MaxUint64)).TryAdd(uint64(7)).TryAdd(float64(1 << 63)).PluckInts()
+	assert.True(t, err != nil)
+	println(err.Error())
+	assert.Equal(t, 3, len(err.(IndexErrors)))
+	assert.Equal(t, []int{0, 2, 0, 7, 0}, ints)
+	ints, err = NewJSONArray().TryAdd(-3.0).TryAdd(int64(math.MinInt64)).TryAdd(uint8(4)).PluckInts()
+	assert.True(t, err == nil)
+	assert.Equal(t, []int{-3, math.MinInt64, 4}, ints)
+}
+
+func TestJson_SelectMany(t *testing.T) {
+	posts, _ := Parse([]byte(`[{"tags":["a","b"],"meta":{"refs":[[1],2]}},{"tags":[]},{"tags":"c"},{"id":4},{"tags":["d",{"x":1}],"meta":{"refs":3}},{"tags":null}]`))
+	tags := posts.SelectMany("tags")
+	assert.True(t, tags.DigestJSONForEqual() == `["a","b","c","d",{"x":1},null]`)
+	tags.GetIndex(4).Set("x", 2)
+	assert.True(t, posts.GetDottedPath("4.tags.1.x").MustInt() == 1)
+	assert.True(t, posts.SelectMany("meta", "refs").DigestJSONForEqual() == `[[1],2,3]`)
+	assert.True(t, posts.SelectMany("missing").DigestJSONForEqual() == `[]`)
+
+	nested, _ := Parse([]byte(`[[1,2],3,[[4]]]`))
+	assert.True(t, nested.SelectMany().DigestJSONForEqual() == `[1,2,3,[4]]`)
+	assert.True(t, NewJSONObject().SelectMany("tags").IsEmpty())
+}
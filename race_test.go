@@ -0,0 +1,7 @@
+//go:build race
+
+package betterjson
+
+// raceEnabled is set when the tests run with the race detector, which adds
+// allocations of its own
+const raceEnabled = true
@@ -0,0 +1,77 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// recorder collects the patch operations of a recording started by StartRecording
+type recorder struct {
+	operations []interface{}
+}
+
+// StartRecording starts logging every Set, SetPath, SetValue, Del, DelPath,
+// TryAdd, SetIndex, ApplyPatch and Rollback on j as a JSON Patch, including
+// mutations through wrappers derived from j by Get, GetIndex, CheckGet, Select
+// or ObjectAtPath, whether taken before or after this call. paths are JSON
+// Pointers relative to j. starting again discards the operations recorded so far
+func (j *Json) StartRecording() {
+	j.recorder = &recorder{operations: make([]interface{}, 0)}
+}
+
+// StopRecording ends the recording started by StartRecording and returns the
+// RFC 6902 patch array of the recorded mutations, which ApplyPatch can replay
+// onto a copy of the document as it was when recording started:
+//    js.StartRecording()
+//    js.Get("server").Set("port", 8080)
+//    patch, _ := js.StopRecording() // [{"op":"replace","path":"/server/port","value":8080}]
+func (j *Json) StopRecording() (*Json, error) {
+	if j.recorder == nil {
+		return NewEmpty(), errors.New("json is not recording changes")
+	}
+	patch := wrapRaw(j.recorder.operations)
+	j.recorder = nil
+	return patch, nil
+}
+
+// recordChange adds operation, with paths relative to j, to the recording of
+// j and of every wrapper j was derived from
+func (j *Json) recordChange(operation patchOperation) {
+	for node := j; node != nil; node = node.parent {
+		if node.recorder != nil {
+			node.recorder.operations = append(node.recorder.operations, operation.encode())
+		}
+		if node.parent != nil {
+			operation = operation.under(node.parentKey)
+		}
+	}
+}
+
+// setPathTarget returns the branch of the value SetPath(branch, ...) is about
+// to add or replace in j. SetPath replaces intermediates that aren't objects,
+// so that is the first node it creates or replaces
+func (j *Json) setPathTarget(branch []string) []string {
+	if j.IsEmpty() {
+		return []string{}
+	}
+	current, isObject := j.value.Interface().(map[string]interface{})
+	if len(branch) == 0 || !isObject {
+		return []string{}
+	}
+	for idx, segment := range branch {
+		next, isObject := current[segment].(map[string]interface{})
+		if idx == len(branch)-1 || !isObject {
+			return append([]string{}, branch[:idx+1]...)
+		}
+		current = next
+	}
+	return []string{}
+}
+
+// setOperation adds or replaces value at path, depending on whether prior
+// found a value there
+func setOperation(path []string, value interface{}, prior priorValue) patchOperation {
+	if prior.exists {
+		return patchOperation{op: "replace", path: path, value: value}
+	}
+	return patchOperation{op: "add", path: path, value: value}
+}
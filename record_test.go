@@ -0,0 +1,52 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_RecordingReplaysOntoCopy(t *testing.T) {
+	source := `{"name":"app","server":{"port":80,"hosts":["a","b"]},"tags":["x","y","z"],"old":1}`
+	a, err := Parse([]byte(source))
+	assert.True(t, err == nil)
+	server := a.Get("server")
+	a.StartRecording()
+	a.Set("name", "renamed").Set("added", Obj("k", "v"))
+	server.Set("port", 8080)
+	server.Get("hosts").TryAdd("c")
+	a.SetPath([]string{"deep", "er", "est"}, 1)
+	a.Del("old").Del("missing")
+	a.DelPath("tags", "0")
+	a.Get("tags").SetIndex(0, "Y")
+	a.Get("slash/key").SetValue("tilde~")
+	patch, err := a.StopRecording()
+	assert.True(t, err == nil)
+	encoded, _ := patch.EncodeToString()
+	println(encoded)
+	assert.True(t, patch.ArrayLength() == 9)
+	assert.True(t, patch.GetIndex(2).Get("path").MustString() == "/server/port")
+	assert.True(t, patch.GetIndex(3).Get("path").MustString() == "/server/hosts/-")
+	assert.True(t, patch.GetIndex(4).Get("path").MustString() == "/deep")
+	assert.True(t, patch.GetIndex(8).Get("path").MustString() == "/slash~1key")
+
+	replayed, _ := Parse([]byte(source))
+	assert.True(t, replayed.ApplyPatch(patch) == nil)
+	assert.True(t, replayed.IsSameJSONWith(a))
+	assert.True(t, a.GetDottedPath("server.hosts.2").MustString() == "c")
+
+	_, err = a.StopRecording()
+	assert.True(t, err != nil)
+}
+
+func TestJson_RecordingChild(t *testing.T) {
+	a, _ := Parse([]byte(`{"outer":{"inner":{"v":1}}}`))
+	outer := a.Get("outer")
+	outer.StartRecording()
+	a.StartRecording()
+	outer.Get("inner").Set("v", 2)
+	outerPatch, _ := outer.StopRecording()
+	rootPatch, _ := a.StopRecording()
+	assert.True(t, outerPatch.GetIndex(0).Get("path").MustString() == "/inner/v")
+	assert.True(t, rootPatch.GetIndex(0).Get("path").MustString() == "/outer/inner/v")
+	assert.True(t, rootPatch.GetIndex(0).Get("op").MustString() == "replace")
+}
@@ -0,0 +1,159 @@
+package betterjson
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RefSiblingMode decides what ResolveRefs does with the other members of an
+// object holding a "$ref"
+type RefSiblingMode int
+
+const (
+	// RefSiblingsError fails the resolution
+	RefSiblingsError RefSiblingMode = iota
+	// RefSiblingsMerge sets the other members on the resolved value, which
+	// then must be an object
+	RefSiblingsMerge
+)
+
+// RefOptions configures ResolveRefs
+type RefOptions struct {
+	Siblings RefSiblingMode
+	// MaxDepth is how many references may be expanded inside each other,
+	// 0 is no limit
+	MaxDepth int
+	// External are the documents references like "common.json#/address"
+	// point into, by the part before "#"
+	External map[string]*Json
+}
+
+// refDocument is a document references point into, by its base URI, "" for
+// the one being resolved
+type refDocument struct {
+	base string
+	root interface{}
+}
+
+// ResolveRefs returns a copy of j where every {"$ref": "#/json/pointer"} object
+// is replaced by a copy of the value the JSON Pointer selects, with the
+// references inside that resolved too. "#" is the whole document, and a
+// reference with a base URI before the "#" points into opts.External. cyclic
+// references are an error listing the cycle. j is left untouched:
+//    resolved, err := template.ResolveRefs(betterjson.RefOptions{})
+func (j *Json) ResolveRefs(opts RefOptions) (*Json, error) {
+	if j.IsEmpty() {
+		return NewEmpty(), errors.New("empty json has no references to resolve")
+	}
+	document := refDocument{root: j.value.Interface()}
+	result, err := resolveRefs(document.root, document, []string{}, make([]string, 0), opts)
+	if err != nil {
+		return NewEmpty(), err
+	}
+	return wrapRaw(result), nil
+}
+
+// resolveRefs resolves the references in node of document, branch being the
+// path of node in the result and chain the references being expanded
+func resolveRefs(node interface{}, document refDocument, branch []string, chain []string, opts RefOptions) (interface{}, error) {
+	switch value := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		if value == nil {
+			return nil, nil
+		}
+		if ref, ok := value["$ref"]; ok {
+			return resolveRef(value, unwrapRaw(ref), document, branch, chain, opts)
+		}
+		result := make(map[string]interface{}, len(value))
+		for _, key := range sortedKeys(value) {
+			resolved, err := resolveRefs(value[key], document, append(branch[:len(branch):len(branch)], key), chain, opts)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+		}
+		return result, nil
+	case []interface{}:
+		if value == nil {
+			return nil, nil
+		}
+		result := make([]interface{}, len(value))
+		for idx, item := range value {
+			resolved, err := resolveRefs(item, document, append(branch[:len(branch):len(branch)], strconv.Itoa(idx)), chain, opts)
+			if err != nil {
+				return nil, err
+			}
+			result[idx] = resolved
+		}
+		return result, nil
+	default:
+		return deepCopyRaw(value), nil
+	}
+}
+
+// resolveRef expands the reference object holding ref
+func resolveRef(object map[string]interface{}, ref interface{}, document refDocument, branch []string, chain []string, opts RefOptions) (interface{}, error) {
+	text, ok := ref.(string)
+	if !ok {
+		return nil, errors.Errorf("$ref at %s is %s, not a string", displayPath(branch), kindName(ref))
+	}
+	base, pointer := text, ""
+	if idx := strings.IndexByte(text, '#'); idx >= 0 {
+		base, pointer = text[:idx], text[idx+1:]
+	}
+	target := document
+	if base != "" {
+		external, ok := opts.External[base]
+		if !ok || external.IsEmpty() {
+			return nil, errors.Errorf("$ref %q at %s points into unknown document %q", text, displayPath(branch), base)
+		}
+		target = refDocument{base: base, root: external.value.Interface()}
+	}
+	// the chain names references by their absolute form, so the same target
+	// is recognized whichever document refers to it
+	absolute := target.base + "#" + pointer
+	for idx, expanding := range chain {
+		if expanding == absolute {
+			cycle := append(append([]string{}, chain[idx:]...), absolute)
+			return nil, errors.Errorf("cyclic $ref at %s: %s", displayPath(branch), strings.Join(cycle, " -> "))
+		}
+	}
+	if opts.MaxDepth > 0 && len(chain) >= opts.MaxDepth {
+		return nil, errors.Errorf("$ref %q at %s is nested deeper than %d references", text, displayPath(branch), opts.MaxDepth)
+	}
+	path, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "$ref %q at %s", text, displayPath(branch))
+	}
+	value, ok := valueAtBranch(target.root, path)
+	if !ok {
+		return nil, errors.Errorf("$ref %q at %s doesn't resolve", text, displayPath(branch))
+	}
+	resolved, err := resolveRefs(value, target, branch, append(chain[:len(chain):len(chain)], absolute), opts)
+	if err != nil || len(object) == 1 {
+		return resolved, err
+	}
+	siblings := make([]string, 0, len(object)-1)
+	for _, key := range sortedKeys(object) {
+		if key != "$ref" {
+			siblings = append(siblings, key)
+		}
+	}
+	resolvedObject, isObject := resolved.(map[string]interface{})
+	switch {
+	case opts.Siblings == RefSiblingsError:
+		return nil, errors.Errorf("$ref at %s has sibling keys %s", displayPath(branch), strings.Join(siblings, ", "))
+	case !isObject || resolvedObject == nil:
+		return nil, errors.Errorf("$ref %q at %s resolves to %s, its sibling keys can't be merged", text, displayPath(branch), kindName(resolved))
+	}
+	for _, key := range siblings {
+		sibling, err := resolveRefs(object[key], document, append(branch[:len(branch):len(branch)], key), chain, opts)
+		if err != nil {
+			return nil, err
+		}
+		resolvedObject[key] = sibling
+	}
+	return resolvedObject, nil
+}
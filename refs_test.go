@@ -0,0 +1,61 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRefs(t *testing.T) {
+	template, _ := Parse([]byte(`{"definitions":{"address":{"city":"Oslo","zip":{"$ref":"#/definitions/zip"}},"zip":"0150","list":[1,{"$ref":"#/definitions/zip"}]},
+"home":{"$ref":"#/definitions/address"},"work":[{"$ref":"#/definitions/address"},{"$ref":"#/definitions/list/0"}],"all":{"$ref":"#/definitions/list"}}`))
+	before := template.EncodeToStringOrDefault("")
+	resolved, err := template.ResolveRefs(RefOptions{})
+	assert.True(t, err == nil)
+	expected, _ := Parse([]byte(`{"definitions":{"address":{"city":"Oslo","zip":"0150"},"zip":"0150","list":[1,"0150"]},
+"home":{"city":"Oslo","zip":"0150"},"work":[{"city":"Oslo","zip":"0150"},1],"all":[1,"0150"]}`))
+	assert.True(t, resolved.IsSameJSONWith(expected))
+	assert.Equal(t, before, template.EncodeToStringOrDefault(""))
+	// the expansions are copies
+	resolved.Get("home").Set("city", "Bergen")
+	assert.Equal(t, "Oslo", resolved.GetPath("work").GetIndex(0).Get("city").MustString())
+
+	_, err = template.ResolveRefs(RefOptions{MaxDepth: 1})
+	assert.Equal(t, `$ref "#/definitions/zip" at all.1 is nested deeper than 1 references`, err.Error())
+	missing, _ := Parse([]byte(`{"a":{"$ref":"#/nope"}}`))
+	_, err = missing.ResolveRefs(RefOptions{})
+	assert.Equal(t, `$ref "#/nope" at a doesn't resolve`, err.Error())
+}
+
+func TestResolveRefsSiblings(t *testing.T) {
+	template, _ := Parse([]byte(`{"base":{"host":"h","port":80},"server":{"$ref":"#/base","port":{"$ref":"#/ports/0"}},"ports":[8080]}`))
+	_, err := template.ResolveRefs(RefOptions{})
+	assert.Equal(t, "$ref at server has sibling keys port", err.Error())
+	resolved, err := template.ResolveRefs(RefOptions{Siblings: RefSiblingsMerge})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"host":"h","port":8080}`, resolved.Get("server").EncodeToStringOrDefault(""))
+	assert.Equal(t, int64(80), resolved.GetPath("base", "port").MustInt64())
+
+	scalar, _ := Parse([]byte(`{"a":1,"b":{"$ref":"#/a","c":2}}`))
+	_, err = scalar.ResolveRefs(RefOptions{Siblings: RefSiblingsMerge})
+	assert.Equal(t, `$ref "#/a" at b resolves to number, its sibling keys can't be merged`, err.Error())
+}
+
+func TestResolveRefsCycle(t *testing.T) {
+	template, _ := Parse([]byte(`{"a":{"next":{"$ref":"#/b"}},"b":{"next":{"$ref":"#/a"}}}`))
+	_, err := template.ResolveRefs(RefOptions{})
+	assert.Equal(t, "cyclic $ref at a.next.next.next: #/b -> #/a -> #/b", err.Error())
+	self, _ := Parse([]byte(`{"a":{"$ref":"#"}}`))
+	_, err = self.ResolveRefs(RefOptions{})
+	assert.Equal(t, "cyclic $ref at a.a: # -> #", err.Error())
+}
+
+func TestResolveRefsExternal(t *testing.T) {
+	common, _ := Parse([]byte(`{"address":{"city":"Oslo","country":{"$ref":"#/countries/no"}},"countries":{"no":"Norway"}}`))
+	template, _ := Parse([]byte(`{"home":{"$ref":"common.json#/address"},"whole":{"$ref":"common.json"}}`))
+	resolved, err := template.ResolveRefs(RefOptions{External: map[string]*Json{"common.json": common}})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"city":"Oslo","country":"Norway"}`, resolved.Get("home").EncodeToStringOrDefault(""))
+	assert.Equal(t, "Norway", resolved.GetPath("whole", "countries", "no").MustString())
+	_, err = template.ResolveRefs(RefOptions{})
+	assert.Equal(t, `$ref "common.json#/address" at home points into unknown document "common.json"`, err.Error())
+}
@@ -0,0 +1,102 @@
+package betterjson
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// IndexRangeError is an array index beyond the items of the array
+type IndexRangeError struct {
+	Index  int
+	Length int
+}
+
+func (e *IndexRangeError) Error() string {
+	return fmt.Sprintf("index %d out of range of array of length %d", e.Index, e.Length)
+}
+
+// reorderedArray returns the array of j and checks indexes against it
+func (j *Json) reorderedArray(indexes ...int) ([]interface{}, error) {
+	if j.IsEmpty() {
+		return nil, errors.New("can't reorder empty json")
+	}
+	array, isArray := j.value.Interface().([]interface{})
+	if !isArray {
+		return nil, errors.Errorf("can't reorder %s", kindName(j.value.Interface()))
+	}
+	for _, index := range indexes {
+		if index < 0 || index >= len(array) {
+			return nil, &IndexRangeError{Index: index, Length: len(array)}
+		}
+	}
+	return array, nil
+}
+
+// SwapIndex exchanges the items at a and b of the j array in place. indexes
+// out of range are an IndexRangeError
+func (j *Json) SwapIndex(a, b int) error {
+	array, err := j.reorderedArray(a, b)
+	if err != nil || a == b {
+		return err
+	}
+	pathA, pathB := []string{strconv.Itoa(a)}, []string{strconv.Itoa(b)}
+	priorA, priorB := j.prior(pathA), j.prior(pathB)
+	array[a], array[b] = array[b], array[a]
+	j.changed(patchOperation{op: "replace", path: pathA, value: array[a]}, priorA)
+	j.changed(patchOperation{op: "replace", path: pathB, value: array[b]}, priorB)
+	return nil
+}
+
+// MoveIndex removes the item at from of the j array and inserts it at to,
+// shifting the items between, in place. it is the same as removing the item
+// and then inserting it with InsertAt(to, item), except that to must be an
+// index of an item:
+//    tasks.MoveIndex(4, 0) // the fifth task first
+func (j *Json) MoveIndex(from, to int) error {
+	array, err := j.reorderedArray(from, to)
+	if err != nil || from == to {
+		return err
+	}
+	path := []string{strconv.Itoa(to)}
+	prior := j.prior([]string{"-"})
+	item := array[from]
+	if from < to {
+		copy(array[from:to], array[from+1:to+1])
+	} else {
+		copy(array[to+1:from+1], array[to:from])
+	}
+	array[to] = item
+	j.changed(patchOperation{op: "move", path: path, from: []string{strconv.Itoa(from)}, value: item}, prior)
+	return nil
+}
+
+// RotateArray rotates the items of the j array in place by n positions to the
+// left, or to the right for a negative n, so the item at n comes first. n may
+// exceed the length. it does nothing when j isn't an array
+func (j *Json) RotateArray(n int) *Json {
+	array, err := j.reorderedArray()
+	if err != nil || len(array) < 2 {
+		return j
+	}
+	n %= len(array)
+	if n < 0 {
+		n += len(array)
+	}
+	if n == 0 {
+		return j
+	}
+	prior := j.prior([]string{})
+	reverseItems(array[:n])
+	reverseItems(array[n:])
+	reverseItems(array)
+	j.changed(setOperation([]string{}, array, prior), prior)
+	return j
+}
+
+func reverseItems(items []interface{}) {
+	for a, b := 0, len(items)-1; a < b; a, b = a+1, b-1 {
+		items[a], items[b] = items[b], items[a]
+	}
+}
@@ -0,0 +1,96 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwapIndex(t *testing.T) {
+	js := Obj("list", Arr("a", "b", "c"))
+	list := js.Get("list")
+	assert.True(t, list.SwapIndex(0, 2) == nil)
+	assert.True(t, list.SwapIndex(1, 1) == nil)
+	assert.Equal(t, `{"list":["c","b","a"]}`, js.EncodeToStringOrDefault(""))
+
+	err := list.SwapIndex(0, 3)
+	var rangeError *IndexRangeError
+	assert.True(t, errors.As(err, &rangeError))
+	assert.Equal(t, 3, rangeError.Index)
+	assert.Equal(t, 3, rangeError.Length)
+	assert.Equal(t, "index -1 out of range of array of length 3", list.SwapIndex(-1, 0).Error())
+	assert.Equal(t, "index 0 out of range of array of length 0", NewJSONArray().SwapIndex(0, 0).Error())
+	assert.Equal(t, "can't reorder object", js.SwapIndex(0, 1).Error())
+	assert.True(t, Arr(1).SwapIndex(0, 0) == nil)
+}
+
+func TestMoveIndex(t *testing.T) {
+	js := Arr(0, 1, 2, 3, 4)
+	assert.True(t, js.MoveIndex(0, 4) == nil)
+	assert.Equal(t, `[1,2,3,4,0]`, js.EncodeToStringOrDefault(""))
+	assert.True(t, js.MoveIndex(4, 0) == nil)
+	assert.Equal(t, `[0,1,2,3,4]`, js.EncodeToStringOrDefault(""))
+	assert.True(t, js.MoveIndex(3, 1) == nil)
+	assert.Equal(t, `[0,3,1,2,4]`, js.EncodeToStringOrDefault(""))
+	assert.True(t, js.MoveIndex(2, 2) == nil)
+
+	assert.Equal(t, "index 5 out of range of array of length 5", js.MoveIndex(0, 5).Error())
+	assert.Equal(t, "index 0 out of range of array of length 0", NewJSONArray().MoveIndex(0, 0).Error())
+	assert.True(t, Arr("only").MoveIndex(0, 0) == nil)
+	assert.Equal(t, "can't reorder empty json", NewEmpty().MoveIndex(0, 0).Error())
+}
+
+func TestMoveIndexIsRemoveAndInsert(t *testing.T) {
+	const n = 6
+	for from := 0; from < n; from++ {
+		for to := 0; to < n; to++ {
+			moved := Arr(0, 1, 2, 3, 4, 5)
+			assert.True(t, moved.MoveIndex(from, to) == nil)
+
+			items := moved.MustArray()[:0:0]
+			for i := 0; i < n; i++ {
+				if i != from {
+					items = append(items, i)
+				}
+			}
+			expected := NewJSONArrayFrom(items...)
+			assert.True(t, expected.InsertAtE(to, from) == nil)
+			assert.True(t, expected.IsSameJSONWith(moved), from, to)
+		}
+	}
+}
+
+func TestRotateArray(t *testing.T) {
+	cases := []struct {
+		n        int
+		expected string
+	}{
+		{0, `[1,2,3,4]`},
+		{1, `[2,3,4,1]`},
+		{3, `[4,1,2,3]`},
+		{4, `[1,2,3,4]`},
+		{6, `[3,4,1,2]`},
+		{-1, `[4,1,2,3]`},
+		{-5, `[4,1,2,3]`},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, Arr(1, 2, 3, 4).RotateArray(c.n).EncodeToStringOrDefault(""), c.n)
+	}
+	assert.Equal(t, `[]`, NewJSONArray().RotateArray(3).EncodeToStringOrDefault(""))
+	assert.Equal(t, `[1]`, Arr(1).RotateArray(-3).EncodeToStringOrDefault(""))
+	assert.Equal(t, `{"a":1}`, Obj("a", 1).RotateArray(1).EncodeToStringOrDefault(""))
+}
+
+func TestReorderRecorded(t *testing.T) {
+	js := Obj("list", Arr("a", "b", "c", "d"))
+	js.StartRecording()
+	list := js.Get("list")
+	list.SwapIndex(0, 1)
+	list.MoveIndex(3, 0)
+	list.RotateArray(1)
+	patch, _ := js.StopRecording()
+	replayed := Obj("list", Arr("a", "b", "c", "d"))
+	assert.True(t, replayed.ApplyPatch(patch) == nil)
+	assert.Equal(t, `{"list":["b","a","c","d"]}`, replayed.EncodeToStringOrDefault(""))
+	assert.True(t, js.IsSameJSONWith(replayed))
+}
@@ -0,0 +1,78 @@
+package betterjson
+
+import (
+	"strings"
+)
+
+// ReplaceValue returns a copy of j with every value deep equal to old replaced
+// by a copy of new, and how many were replaced. numbers are compared by value,
+// so 1 matches 1.0 and 1e0 although IsSameJSONWith tells them apart, and a Go
+// float like 0.1 matches the literal 0.1. old and new can be *Json or plain
+// values. when old is an object or array, the matching containers are
+// replaced as a whole. j itself is not changed:
+//    count, promoted := config.ReplaceValue("db.staging.local", "db.prod.local")
+func (j *Json) ReplaceValue(old, new interface{}) (int, *Json) {
+	if j.IsEmpty() {
+		return 0, NewEmpty()
+	}
+	target := unwrapRaw(old)
+	replacement := unwrapRaw(new)
+	count := 0
+	result := replaceRaw(j.value.Interface(), func(node interface{}) (interface{}, bool) {
+		if !rawEqual(node, target) {
+			return nil, false
+		}
+		count++
+		return deepCopyRaw(replacement), true
+	})
+	return count, wrapRaw(result)
+}
+
+// ReplaceStrings is ReplaceValue for string values: values equal to old are
+// replaced by new, or with substring every occurrence of old inside a string
+// value is. the count is of the string values changed. object keys are kept
+func (j *Json) ReplaceStrings(old, new string, substring bool) (int, *Json) {
+	if j.IsEmpty() {
+		return 0, NewEmpty()
+	}
+	count := 0
+	result := replaceRaw(j.value.Interface(), func(node interface{}) (interface{}, bool) {
+		value, ok := node.(string)
+		switch {
+		case !ok:
+			return nil, false
+		case !substring && value == old:
+			count++
+			return new, true
+		case substring && old != "" && strings.Contains(value, old):
+			count++
+			return strings.Replace(value, old, new, -1), true
+		}
+		return nil, false
+	})
+	return count, wrapRaw(result)
+}
+
+// replaceRaw copies node, replacing the nodes replace returns true for
+// instead of descending into them
+func replaceRaw(node interface{}, replace func(node interface{}) (interface{}, bool)) interface{} {
+	node = unwrapRaw(node)
+	if replacement, ok := replace(node); ok {
+		return replacement
+	}
+	switch container := node.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(container))
+		for key, item := range container {
+			result[key] = replaceRaw(item, replace)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(container))
+		for idx, item := range container {
+			result[idx] = replaceRaw(item, replace)
+		}
+		return result
+	}
+	return deepCopyRaw(node)
+}
@@ -0,0 +1,56 @@
+package betterjson_test
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/zoowii/betterjson"
+	"github.com/zoowii/betterjson/betterjsontest"
+)
+
+func TestJson_ReplaceValue(t *testing.T) {
+	source := `{"db":{"host":"staging.local","replicas":["staging.local","other"]},"cache":[{"host":"staging.local"}],"port":1,"weight":1.0,"name":"staging.local.backup"}`
+	config, _ := betterjson.Parse([]byte(source))
+	count, promoted := config.ReplaceValue("staging.local", "prod.local")
+	assert.True(t, count == 3)
+	assert.True(t, promoted.DigestJSONForEqual() == `{"cache":[{"host":"prod.local"}],"db":{"host":"prod.local","replicas":["prod.local","other"]},"name":"staging.local.backup","port":1,"weight":1.0}`)
+	// the receiver is untouched
+	original, _ := betterjson.Parse([]byte(source))
+	assert.True(t, config.IsSameJSONWith(original))
+
+	count, promoted = config.ReplaceValue(1.0, 2)
+	assert.True(t, count == 2)
+	assert.True(t, promoted.Get("port").MustInt() == 2 && promoted.Get("weight").MustInt() == 2)
+
+	host, _ := betterjson.Parse([]byte(`{"host":"staging.local"}`))
+	count, promoted = config.ReplaceValue(host, betterjson.NewJSONObject().Set("host", "prod.local"))
+	assert.True(t, count == 1)
+	betterjsontest.AssertPathEquals(t, promoted, "cache.0.host", "prod.local")
+
+	count, promoted = betterjson.NewEmpty().ReplaceValue(1, 2)
+	assert.True(t, count == 0 && promoted.IsEmpty())
+}
+
+func TestJson_ReplaceValueFloats(t *testing.T) {
+	doc, _ := betterjson.Parse([]byte(`{"a":0.1,"b":[1.5,0.1],"c":0.10,"d":0.3}`))
+	count, replaced := doc.ReplaceValue(0.1, "x")
+	assert.Equal(t, 3, count)
+	assert.Equal(t, `{"a":"x","b":[1.5,"x"],"c":"x","d":0.3}`, replaced.EncodeToStringOrDefault(""))
+	a, b := 0.1, 0.2
+	count, _ = doc.ReplaceValue(a+b, "x")
+	assert.Equal(t, 0, count)
+}
+
+func TestJson_ReplaceStrings(t *testing.T) {
+	config, _ := betterjson.Parse([]byte(`{"url":"https://staging.local/api","hosts":["staging.local","staging.local:8080"],"staging.local":true}`))
+	count, exact := config.ReplaceStrings("staging.local", "prod.local", false)
+	assert.True(t, count == 1)
+	betterjsontest.AssertEqualJSON(t, `{"hosts":["prod.local","staging.local:8080"],"staging.local":true,"url":"https://staging.local/api"}`, exact)
+
+	count, within := config.ReplaceStrings("staging.local", "prod.local", true)
+	assert.True(t, count == 3)
+	betterjsontest.AssertEqualJSON(t, `{"hosts":["prod.local","prod.local:8080"],"staging.local":true,"url":"https://prod.local/api"}`, within)
+	betterjsontest.AssertPathEquals(t, config, "hosts.1", "staging.local:8080")
+
+	count, _ = config.ReplaceStrings("", "x", true)
+	assert.True(t, count == 0)
+}
@@ -0,0 +1,84 @@
+package betterjson
+
+import (
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// SampleArray returns a new array of n items of the j array chosen uniformly
+// without replacement, in the order they were drawn, or of all items shuffled
+// when n exceeds the length. the items are copies. a nil r draws from the
+// default source of math/rand, while a seeded one gives the same sample every
+// time:
+//    preview := feed.Get("items").SampleArray(10, rand.New(rand.NewSource(1)))
+//
+// when j isn't an array or n is negative the result is empty and Err says why,
+// like At
+func (j *Json) SampleArray(n int, r *rand.Rand) *Json {
+	array, err := arrayToDraw(j, "sample")
+	if err != nil {
+		return failedAt(j, err)
+	}
+	if n < 0 {
+		return failedAt(j, errors.Errorf("can't sample %d items", n))
+	}
+	if n > len(array) {
+		n = len(array)
+	}
+	// a partial Fisher-Yates shuffle of the indexes
+	indexes := make([]int, len(array))
+	for idx := range indexes {
+		indexes[idx] = idx
+	}
+	sample := make([]interface{}, n)
+	for idx := 0; idx < n; idx++ {
+		other := idx + randomIntn(r, len(array)-idx)
+		indexes[idx], indexes[other] = indexes[other], indexes[idx]
+		sample[idx] = deepCopyRaw(array[indexes[idx]])
+	}
+	result := wrapRaw(sample)
+	result.settings = j.settings
+	return result
+}
+
+// ShuffleArray shuffles the items of the j array in place and returns j,
+// drawing from r like SampleArray. when j isn't an array the result is empty
+// and Err says why
+func (j *Json) ShuffleArray(r *rand.Rand) *Json {
+	array, err := arrayToDraw(j, "shuffle")
+	if err != nil {
+		return failedAt(j, err)
+	}
+	if len(array) < 2 {
+		return j
+	}
+	prior := j.prior([]string{})
+	swap := func(a, b int) { array[a], array[b] = array[b], array[a] }
+	if r == nil {
+		rand.Shuffle(len(array), swap)
+	} else {
+		r.Shuffle(len(array), swap)
+	}
+	j.changed(setOperation([]string{}, array, prior), prior)
+	return j
+}
+
+func arrayToDraw(j *Json, operation string) ([]interface{}, error) {
+	if j == nil || j.IsEmpty() {
+		return nil, errors.Errorf("can't %s empty json", operation)
+	}
+	array, isArray := j.value.Interface().([]interface{})
+	if !isArray {
+		return nil, errors.Errorf("can't %s %s", operation, kindName(j.value.Interface()))
+	}
+	return array, nil
+}
+
+// randomIntn is r.Intn, or rand.Intn for a nil r
+func randomIntn(r *rand.Rand, n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+	return r.Intn(n)
+}
@@ -0,0 +1,57 @@
+package betterjson
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleArray(t *testing.T) {
+	js := NewJSONArrayFrom(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	sample := js.SampleArray(4, rand.New(rand.NewSource(7)))
+	assert.Equal(t, "[6,4,7,5]", sample.EncodeToStringOrDefault(""))
+	assert.Equal(t, sample.EncodeToStringOrDefault(""), js.SampleArray(4, rand.New(rand.NewSource(7))).EncodeToStringOrDefault(""))
+	assert.Equal(t, 4, len(sample.MustArray()))
+	seen := make(map[int64]bool)
+	for _, item := range sample.MustArray() {
+		value := wrapRaw(item).MustInt64()
+		assert.True(t, !seen[value] && value >= 0 && value < 10)
+		seen[value] = true
+	}
+	assert.Equal(t, "[0,1,2,3,4,5,6,7,8,9]", js.EncodeToStringOrDefault(""))
+
+	all := js.SampleArray(20, rand.New(rand.NewSource(1)))
+	assert.Equal(t, 10, len(all.MustArray()))
+	assert.Equal(t, "[]", js.SampleArray(0, nil).EncodeToStringOrDefault(""))
+	assert.Equal(t, 3, len(js.SampleArray(3, nil).MustArray()))
+
+	// the items are copies
+	objects := NewJSONArrayFrom(map[string]interface{}{"a": 1})
+	objects.SampleArray(1, nil).GetIndex(0).Set("a", 2)
+	assert.Equal(t, `[{"a":1}]`, objects.EncodeToStringOrDefault(""))
+
+	failed := NewJSONObject().SampleArray(1, nil)
+	assert.True(t, failed.IsEmpty())
+	assert.Equal(t, "can't sample object", failed.Err().Error())
+	assert.Equal(t, "can't sample -1 items", js.SampleArray(-1, nil).Err().Error())
+	assert.Equal(t, "can't sample empty json", NewEmpty().SampleArray(1, nil).Err().Error())
+}
+
+func TestSampleArraySeeded(t *testing.T) {
+	js := NewJSONArrayFrom("a", "b", "c", "d", "e")
+	assert.Equal(t, `["a","e","b"]`, js.SampleArray(3, rand.New(rand.NewSource(42))).EncodeToStringOrDefault(""))
+	js.ShuffleArray(rand.New(rand.NewSource(42)))
+	assert.Equal(t, `["c","d","e","a","b"]`, js.EncodeToStringOrDefault(""))
+}
+
+func TestShuffleArray(t *testing.T) {
+	doc, _ := Parse([]byte(`{"list":[1,2,3,4,5,6]}`))
+	list := doc.Get("list")
+	assert.True(t, list.ShuffleArray(rand.New(rand.NewSource(3))) == list)
+	// shuffled in place, so doc sees it
+	assert.Equal(t, list.EncodeToStringOrDefault(""), doc.Get("list").EncodeToStringOrDefault(""))
+	other := NewJSONArrayFrom(1, 2, 3, 4, 5, 6).ShuffleArray(rand.New(rand.NewSource(3)))
+	assert.Equal(t, other.EncodeToStringOrDefault(""), list.EncodeToStringOrDefault(""))
+	assert.Equal(t, 6, len(NewJSONArrayFrom(1, 2, 3, 4, 5, 6).ShuffleArray(nil).MustArray()))
+	assert.Equal(t, "can't shuffle string", NewString("x").ShuffleArray(nil).Err().Error())
+}
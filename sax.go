@@ -0,0 +1,402 @@
+package betterjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Handler receives the tokens of a document from ParseWithHandler in input
+// order. an error returned by a callback stops the parse and is returned by
+// ParseWithHandler as it is
+type Handler interface {
+	OnObjectStart() error
+	OnObjectEnd() error
+	OnArrayStart() error
+	OnArrayEnd() error
+	// OnKey is called with the key before each object member value
+	OnKey(key string) error
+	OnString(s string) error
+	// OnNumber gets the number literal as it was written
+	OnNumber(n json.Number) error
+	OnBool(b bool) error
+	OnNull() error
+}
+
+// ParseWithHandler reads a single document from r and calls h for each of
+// its tokens without building the document, so memory doesn't grow with the
+// input. malformed input fails with a SyntaxError, possibly after h got the
+// tokens before the failure:
+//    err := betterjson.ParseWithHandler(file, handler)
+func ParseWithHandler(r io.Reader, h Handler) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	// objects holds whether each open container is an object, expectKey
+	// whether the next string in the innermost object is a key
+	objects := make([]bool, 0, 16)
+	expectKey := false
+	for {
+		offset := dec.InputOffset()
+		token, err := dec.Token()
+		if err != nil {
+			return handlerSyntaxError(err, offset)
+		}
+		if expectKey {
+			// the decoder only hands out strings or the closing delimiter here
+			if key, ok := token.(string); ok {
+				if err := h.OnKey(key); err != nil {
+					return err
+				}
+				expectKey = false
+				continue
+			}
+		}
+		switch value := token.(type) {
+		case json.Delim:
+			switch value {
+			case '{':
+				err = h.OnObjectStart()
+				objects = append(objects, true)
+			case '[':
+				err = h.OnArrayStart()
+				objects = append(objects, false)
+			case '}':
+				err = h.OnObjectEnd()
+				objects = objects[:len(objects)-1]
+			case ']':
+				err = h.OnArrayEnd()
+				objects = objects[:len(objects)-1]
+			}
+		case string:
+			err = h.OnString(value)
+		case json.Number:
+			err = h.OnNumber(value)
+		case bool:
+			err = h.OnBool(value)
+		case nil:
+			err = h.OnNull()
+		}
+		if err != nil {
+			return err
+		}
+		if len(objects) == 0 {
+			break
+		}
+		// inside an object every value is followed by a key or the end
+		expectKey = objects[len(objects)-1]
+	}
+	end := dec.InputOffset()
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = &SyntaxError{Offset: end, Err: errors.New("invalid data after top-level value")}
+		}
+		return handlerSyntaxError(err, end)
+	}
+	return nil
+}
+
+func handlerSyntaxError(err error, offset int64) error {
+	switch e := err.(type) {
+	case *SyntaxError:
+		return e
+	case *json.SyntaxError:
+		return &SyntaxError{Offset: e.Offset - 1, Err: e}
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == io.ErrUnexpectedEOF {
+		return &SyntaxError{Offset: offset, Err: err}
+	}
+	return err
+}
+
+// RewriteFunc returns the value to write instead of value, the empty Json to
+// leave out the object member or array item
+type RewriteFunc func(path []string, value *Json) *Json
+
+// RewritingHandler is a Handler copying the document to a writer while the
+// values at chosen paths are replaced. only those values are built in memory.
+// the output is compact, with strings escaped and object members in the order
+// of the input, so input written by Encode is copied byte for byte apart from
+// the rewritten values. call Flush after ParseWithHandler, or use RewriteStream
+type RewritingHandler struct {
+	w       *bufio.Writer
+	encoder *streamEncoder
+	rules   []rewriteRule
+	frames  []rewriteFrame
+	path    []string
+	// capture builds the value being rewritten by captureFn
+	capture     *treeBuilder
+	captureFn   RewriteFunc
+	capturePath []string
+}
+
+type rewriteRule struct {
+	glob globPattern
+	fn   RewriteFunc
+}
+
+// rewriteFrame is an open container of the input. seen counts its values so
+// far, written the ones in the output
+type rewriteFrame struct {
+	object  bool
+	key     string
+	seen    int
+	written int
+}
+
+// NewRewritingHandler returns a RewritingHandler writing to w. the keys of
+// rewrites are dotted glob patterns like PathsMatching's, when several match a
+// value the first in sorted order is used. array items keep their input
+// indexes in the paths when items before them are left out
+func NewRewritingHandler(w io.Writer, rewrites map[string]RewriteFunc) *RewritingHandler {
+	h := &RewritingHandler{w: bufio.NewWriter(w), path: make([]string, 0, 16)}
+	h.encoder = &streamEncoder{w: h.w}
+	patterns := make([]string, 0, len(rewrites))
+	for pattern := range rewrites {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		h.rules = append(h.rules, rewriteRule{glob: parseGlobPattern(pattern), fn: rewrites[pattern]})
+	}
+	return h
+}
+
+// RewriteStream copies the document read from r to w with the values at the
+// paths of rewrites replaced, see NewRewritingHandler:
+//    err := betterjson.RewriteStream(in, out, map[string]betterjson.RewriteFunc{
+//        "users.*.password": func(path []string, value *betterjson.Json) *betterjson.Json {
+//            return betterjson.NewString("***")
+//        },
+//    })
+func RewriteStream(r io.Reader, w io.Writer, rewrites map[string]RewriteFunc) error {
+	h := NewRewritingHandler(w, rewrites)
+	err := ParseWithHandler(r, h)
+	if flushErr := h.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// Flush writes the buffered output
+func (h *RewritingHandler) Flush() error {
+	return h.w.Flush()
+}
+
+// startValue is called before every value and reports whether it is
+// captured, because a rule matches it or a value it is part of
+func (h *RewritingHandler) startValue() bool {
+	if h.capture != nil {
+		return true
+	}
+	depth := len(h.frames)
+	if depth > 0 {
+		frame := &h.frames[depth-1]
+		segment := frame.key
+		if !frame.object {
+			segment = strconv.Itoa(frame.seen)
+		}
+		frame.seen++
+		h.path = append(h.path[:depth-1], segment)
+	}
+	for _, rule := range h.rules {
+		if rule.glob.match(h.path[:depth]) {
+			h.capture = &treeBuilder{}
+			h.captureFn = rule.fn
+			h.capturePath = append([]string{}, h.path[:depth]...)
+			return true
+		}
+	}
+	h.writeSeparator()
+	return false
+}
+
+// writeSeparator writes the comma and the key before a value
+func (h *RewritingHandler) writeSeparator() {
+	if len(h.frames) == 0 {
+		return
+	}
+	frame := &h.frames[len(h.frames)-1]
+	if frame.written > 0 {
+		h.w.WriteByte(',')
+	}
+	frame.written++
+	if frame.object {
+		h.encoder.encodeString(frame.key)
+		h.w.WriteByte(':')
+	}
+}
+
+// endCapture writes the rewrite of the captured value once it is complete
+func (h *RewritingHandler) endCapture() error {
+	if !h.capture.complete {
+		return nil
+	}
+	value := h.captureFn(h.capturePath, wrapRaw(h.capture.root))
+	h.capture = nil
+	if value == nil || value.IsEmpty() {
+		if len(h.frames) == 0 {
+			return errors.New("the rewrite of the document root left it empty")
+		}
+		return nil
+	}
+	h.writeSeparator()
+	return h.encoder.encode(value.value.Interface(), len(h.frames))
+}
+
+func (h *RewritingHandler) OnObjectStart() error {
+	if h.startValue() {
+		return h.capture.OnObjectStart()
+	}
+	h.w.WriteByte('{')
+	h.frames = append(h.frames, rewriteFrame{object: true})
+	return nil
+}
+
+func (h *RewritingHandler) OnObjectEnd() error {
+	if h.capture != nil {
+		h.capture.OnObjectEnd()
+		return h.endCapture()
+	}
+	h.frames = h.frames[:len(h.frames)-1]
+	return h.w.WriteByte('}')
+}
+
+func (h *RewritingHandler) OnArrayStart() error {
+	if h.startValue() {
+		return h.capture.OnArrayStart()
+	}
+	h.w.WriteByte('[')
+	h.frames = append(h.frames, rewriteFrame{})
+	return nil
+}
+
+func (h *RewritingHandler) OnArrayEnd() error {
+	if h.capture != nil {
+		h.capture.OnArrayEnd()
+		return h.endCapture()
+	}
+	h.frames = h.frames[:len(h.frames)-1]
+	return h.w.WriteByte(']')
+}
+
+func (h *RewritingHandler) OnKey(key string) error {
+	if h.capture != nil {
+		return h.capture.OnKey(key)
+	}
+	h.frames[len(h.frames)-1].key = key
+	return nil
+}
+
+func (h *RewritingHandler) OnString(s string) error {
+	if h.startValue() {
+		h.capture.OnString(s)
+		return h.endCapture()
+	}
+	return h.encoder.encodeString(s)
+}
+
+func (h *RewritingHandler) OnNumber(n json.Number) error {
+	if h.startValue() {
+		h.capture.OnNumber(n)
+		return h.endCapture()
+	}
+	_, err := h.w.WriteString(string(n))
+	return err
+}
+
+func (h *RewritingHandler) OnBool(b bool) error {
+	if h.startValue() {
+		h.capture.OnBool(b)
+		return h.endCapture()
+	}
+	_, err := h.w.WriteString(strconv.FormatBool(b))
+	return err
+}
+
+func (h *RewritingHandler) OnNull() error {
+	if h.startValue() {
+		h.capture.OnNull()
+		return h.endCapture()
+	}
+	_, err := h.w.WriteString("null")
+	return err
+}
+
+// treeBuilder is a Handler building the raw value of the tokens, like Parse
+type treeBuilder struct {
+	root     interface{}
+	complete bool
+	stack    []builderFrame
+}
+
+type builderFrame struct {
+	object map[string]interface{}
+	array  []interface{}
+	key    string
+}
+
+// add stores a finished value in the innermost open container
+func (b *treeBuilder) add(value interface{}) error {
+	if len(b.stack) == 0 {
+		b.root = value
+		b.complete = true
+		return nil
+	}
+	frame := &b.stack[len(b.stack)-1]
+	if frame.object != nil {
+		frame.object[frame.key] = value
+	} else {
+		frame.array = append(frame.array, value)
+	}
+	return nil
+}
+
+func (b *treeBuilder) OnObjectStart() error {
+	b.stack = append(b.stack, builderFrame{object: make(map[string]interface{})})
+	return nil
+}
+
+func (b *treeBuilder) OnObjectEnd() error {
+	frame := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	return b.add(frame.object)
+}
+
+func (b *treeBuilder) OnArrayStart() error {
+	b.stack = append(b.stack, builderFrame{array: make([]interface{}, 0)})
+	return nil
+}
+
+func (b *treeBuilder) OnArrayEnd() error {
+	frame := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	return b.add(frame.array)
+}
+
+func (b *treeBuilder) OnKey(key string) error {
+	b.stack[len(b.stack)-1].key = key
+	return nil
+}
+
+func (b *treeBuilder) OnString(s string) error {
+	return b.add(s)
+}
+
+func (b *treeBuilder) OnNumber(n json.Number) error {
+	return b.add(n)
+}
+
+func (b *treeBuilder) OnBool(v bool) error {
+	return b.add(v)
+}
+
+func (b *treeBuilder) OnNull() error {
+	return b.add(nil)
+}
@@ -0,0 +1,111 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenRecorder is a Handler listing the tokens it gets
+type tokenRecorder struct {
+	tokens []string
+}
+
+func (r *tokenRecorder) OnObjectStart() error      { r.tokens = append(r.tokens, "{"); return nil }
+func (r *tokenRecorder) OnObjectEnd() error        { r.tokens = append(r.tokens, "}"); return nil }
+func (r *tokenRecorder) OnArrayStart() error       { r.tokens = append(r.tokens, "["); return nil }
+func (r *tokenRecorder) OnArrayEnd() error         { r.tokens = append(r.tokens, "]"); return nil }
+func (r *tokenRecorder) OnKey(key string) error    { r.tokens = append(r.tokens, "key:"+key); return nil }
+func (r *tokenRecorder) OnString(s string) error   { r.tokens = append(r.tokens, "string:"+s); return nil }
+func (r *tokenRecorder) OnNumber(n json.Number) error {
+	r.tokens = append(r.tokens, "number:"+string(n))
+	return nil
+}
+func (r *tokenRecorder) OnBool(b bool) error {
+	r.tokens = append(r.tokens, "bool:"+strconv.FormatBool(b))
+	return nil
+}
+func (r *tokenRecorder) OnNull() error {
+	if len(r.tokens) > 20 {
+		return errors.New("too many tokens")
+	}
+	r.tokens = append(r.tokens, "null")
+	return nil
+}
+
+func TestParseWithHandler(t *testing.T) {
+	recorder := &tokenRecorder{}
+	err := ParseWithHandler(strings.NewReader(`{"a":[1,"x",{"b":null,"c":{}}],"d":true,"e":[],"f":"key"} `), recorder)
+	assert.True(t, err == nil)
+	assert.Equal(t, "{ key:a [ number:1 string:x { key:b null key:c { } } ] key:d bool:true key:e [ ] key:f string:key }", strings.Join(recorder.tokens, " "))
+
+	recorder = &tokenRecorder{}
+	assert.True(t, ParseWithHandler(strings.NewReader(`1.50`), recorder) == nil)
+	assert.Equal(t, []string{"number:1.50"}, recorder.tokens)
+
+	var syntaxErr *SyntaxError
+	err = ParseWithHandler(strings.NewReader(`{"a":[1,}`), &tokenRecorder{})
+	assert.True(t, errors.As(err, &syntaxErr))
+	println(err.Error())
+	err = ParseWithHandler(strings.NewReader(`{"a":1`), &tokenRecorder{})
+	assert.True(t, errors.As(err, &syntaxErr))
+	err = ParseWithHandler(strings.NewReader(`{} {}`), &tokenRecorder{})
+	assert.Equal(t, "invalid data after top-level value at offset 2", err.Error())
+	err = ParseWithHandler(strings.NewReader(`[`+strings.Repeat("null,", 30)+`null]`), &tokenRecorder{})
+	assert.Equal(t, "too many tokens", err.Error())
+}
+
+func TestRewriteStream(t *testing.T) {
+	users := make([]interface{}, 0, 5000)
+	for i := 0; i < 5000; i++ {
+		users = append(users, map[string]interface{}{"id": i, "name": "user <" + strconv.Itoa(i) + ">", "score": 1.5, "auth": map[string]interface{}{"password": "secret" + strconv.Itoa(i), "mfa": i%2 == 0}})
+	}
+	input, _ := wrapRaw(map[string]interface{}{"users": users, "total": 5000, "zeta": []interface{}{}}).Encode()
+	var output bytes.Buffer
+	err := RewriteStream(bytes.NewReader(input), &output, map[string]RewriteFunc{
+		"users.4321.auth.password": func(path []string, value *Json) *Json {
+			assert.Equal(t, []string{"users", "4321", "auth", "password"}, path)
+			assert.Equal(t, "secret4321", value.MustString())
+			return NewString("***")
+		},
+	})
+	assert.True(t, err == nil)
+	expected := bytes.Replace(input, []byte(`"secret4321"`), []byte(`"***"`), 1)
+	assert.True(t, bytes.Equal(expected, output.Bytes()))
+	assert.True(t, !bytes.Equal(input, output.Bytes()))
+}
+
+func TestRewritingHandlerContainers(t *testing.T) {
+	input := `{"a":[{"k":1,"drop":{"x":[1,2]}},{"k":2}],"b":{"c":"v"},"n":1e3}`
+	var output bytes.Buffer
+	err := RewriteStream(strings.NewReader(input), &output, map[string]RewriteFunc{
+		"a.*.drop": func(path []string, value *Json) *Json {
+			assert.Equal(t, `{"x":[1,2]}`, value.EncodeToStringOrDefault(""))
+			return NewEmpty()
+		},
+		"a.1": func(path []string, value *Json) *Json {
+			return value.Set("k", 20)
+		},
+		"b": func(path []string, value *Json) *Json {
+			return NewJSONArrayFrom(value.Get("c"))
+		},
+	})
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"a":[{"k":1},{"k":20}],"b":["v"],"n":1e3}`, output.String())
+
+	output.Reset()
+	err = RewriteStream(strings.NewReader(`[1,2,3]`), &output, map[string]RewriteFunc{
+		"0": func(path []string, value *Json) *Json { return NewEmpty() },
+		"2": func(path []string, value *Json) *Json { return NewInt(30) },
+	})
+	assert.True(t, err == nil)
+	assert.Equal(t, `[2,30]`, output.String())
+	err = RewriteStream(strings.NewReader(`[1]`), &output, map[string]RewriteFunc{
+		"": func(path []string, value *Json) *Json { return NewEmpty() },
+	})
+	assert.True(t, err != nil)
+}
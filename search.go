@@ -0,0 +1,749 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExpressionError is a Search expression that can't be parsed
+type ExpressionError struct {
+	Expression string
+	// Offset is the byte offset of the offending character
+	Offset  int
+	Message string
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("%s at offset %d of %q", e.Message, e.Offset, e.Expression)
+}
+
+// Search evaluates a JMESPath expression on j and returns a copy of the
+// result. the supported subset is identifiers, "quoted identifiers", the
+// current node @, sub-expressions a.b, indexes [0] and [-1], projections
+// [*], .* and the flatten [], filters [?price > `10` && name != 'x'] with the
+// comparators == != < <= > >=, || && ! and parentheses, literals `json` and
+// 'raw strings', pipes and the functions length, keys, values, contains and
+// join. missing data and arguments of the wrong type evaluate to null, only an
+// expression that doesn't parse is an error:
+//    names, err := js.Search("users[?active].name | [0]")
+func (j *Json) Search(expression string) (*Json, error) {
+	node, err := parseSearch(expression)
+	if err != nil {
+		return NewEmpty(), err
+	}
+	var root interface{}
+	if !j.IsEmpty() {
+		root = j.value.Interface()
+		if j.trace != nil {
+			j.trace.recordAll(j, root)
+		}
+	}
+	return wrapRaw(deepCopyRaw(node.eval(root))), nil
+}
+
+type searchTokenKind int
+
+const (
+	tokenEOF searchTokenKind = iota
+	tokenIdentifier
+	tokenQuotedIdentifier
+	tokenNumber
+	tokenLiteral
+	tokenRawString
+	tokenDot
+	tokenStar
+	tokenAt
+	tokenLbracket
+	tokenRbracket
+	tokenFilter
+	tokenFlatten
+	tokenLparen
+	tokenRparen
+	tokenPipe
+	tokenOr
+	tokenAnd
+	tokenNot
+	tokenComparator
+	tokenComma
+)
+
+type searchToken struct {
+	kind   searchTokenKind
+	text   string
+	value  interface{}
+	offset int
+}
+
+// binding powers of the tokens that continue an expression
+var searchBindingPowers = map[searchTokenKind]int{
+	tokenPipe:       1,
+	tokenOr:         2,
+	tokenAnd:        3,
+	tokenComparator: 5,
+	tokenFlatten:    9,
+	tokenStar:       20,
+	tokenFilter:     21,
+	tokenDot:        40,
+	tokenNot:        45,
+	tokenLbracket:   55,
+	tokenLparen:     60,
+}
+
+// projectionStop is the binding power below which a token ends the right
+// side of a projection
+const projectionStop = 10
+
+func lexSearch(expression string) ([]searchToken, error) {
+	tokens := make([]searchToken, 0)
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			for i < len(expression) && isSearchIdentifierByte(expression[i]) {
+				i++
+			}
+			tokens = append(tokens, searchToken{kind: tokenIdentifier, text: expression[start:i], offset: start})
+			continue
+		case c == '-' || '0' <= c && c <= '9':
+			i++
+			for i < len(expression) && '0' <= expression[i] && expression[i] <= '9' {
+				i++
+			}
+			n, err := strconv.Atoi(expression[start:i])
+			if err != nil {
+				return nil, &ExpressionError{expression, start, "invalid number"}
+			}
+			tokens = append(tokens, searchToken{kind: tokenNumber, text: expression[start:i], value: n, offset: start})
+			continue
+		case c == '"':
+			end, err := searchQuotedEnd(expression, start, '"')
+			if err != nil {
+				return nil, err
+			}
+			var name string
+			if json.Unmarshal([]byte(expression[start:end]), &name) != nil {
+				return nil, &ExpressionError{expression, start, "invalid quoted identifier"}
+			}
+			tokens = append(tokens, searchToken{kind: tokenQuotedIdentifier, text: name, offset: start})
+			i = end
+			continue
+		case c == '\'':
+			end, err := searchQuotedEnd(expression, start, '\'')
+			if err != nil {
+				return nil, err
+			}
+			raw := strings.Replace(expression[start+1:end-1], `\'`, `'`, -1)
+			tokens = append(tokens, searchToken{kind: tokenRawString, value: raw, offset: start})
+			i = end
+			continue
+		case c == '`':
+			end, err := searchQuotedEnd(expression, start, '`')
+			if err != nil {
+				return nil, err
+			}
+			dec := json.NewDecoder(strings.NewReader(strings.Replace(expression[start+1:end-1], "\\`", "`", -1)))
+			dec.UseNumber()
+			var value interface{}
+			if dec.Decode(&value) != nil {
+				return nil, &ExpressionError{expression, start, "invalid json literal"}
+			}
+			tokens = append(tokens, searchToken{kind: tokenLiteral, value: value, offset: start})
+			i = end
+			continue
+		}
+		kind, width := searchTokenKind(-1), 1
+		next := byte(0)
+		if i+1 < len(expression) {
+			next = expression[i+1]
+		}
+		switch c {
+		case '.':
+			kind = tokenDot
+		case '*':
+			kind = tokenStar
+		case '@':
+			kind = tokenAt
+		case ']':
+			kind = tokenRbracket
+		case '(':
+			kind = tokenLparen
+		case ')':
+			kind = tokenRparen
+		case ',':
+			kind = tokenComma
+		case '[':
+			kind = tokenLbracket
+			if next == '?' {
+				kind, width = tokenFilter, 2
+			} else if next == ']' {
+				kind, width = tokenFlatten, 2
+			}
+		case '|':
+			kind = tokenPipe
+			if next == '|' {
+				kind, width = tokenOr, 2
+			}
+		case '&':
+			if next == '&' {
+				kind, width = tokenAnd, 2
+			}
+		case '!':
+			kind = tokenNot
+			if next == '=' {
+				kind, width = tokenComparator, 2
+			}
+		case '=':
+			if next == '=' {
+				kind, width = tokenComparator, 2
+			}
+		case '<', '>':
+			kind = tokenComparator
+			if next == '=' {
+				width = 2
+			}
+		}
+		if kind < 0 {
+			r, _ := utf8.DecodeRuneInString(expression[i:])
+			return nil, &ExpressionError{expression, start, fmt.Sprintf("unexpected character %q", r)}
+		}
+		tokens = append(tokens, searchToken{kind: kind, text: expression[i : i+width], offset: start})
+		i += width
+	}
+	return append(tokens, searchToken{kind: tokenEOF, offset: len(expression)}), nil
+}
+
+func isSearchIdentifierByte(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// searchQuotedEnd returns the offset after the closing quote of the quoted
+// token at start. a backslash escapes the next byte
+func searchQuotedEnd(expression string, start int, quote byte) (int, error) {
+	for i := start + 1; i < len(expression); i++ {
+		switch expression[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1, nil
+		}
+	}
+	return 0, &ExpressionError{expression, start, "unclosed " + string(quote)}
+}
+
+// searchParser is a Pratt parser of the token list of lexSearch
+type searchParser struct {
+	expression string
+	tokens     []searchToken
+	pos        int
+}
+
+func parseSearch(expression string) (searchNode, error) {
+	tokens, err := lexSearch(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &searchParser{expression: expression, tokens: tokens}
+	node, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected(p.peek())
+	}
+	return node, nil
+}
+
+func (p *searchParser) peek() searchToken {
+	return p.tokens[p.pos]
+}
+
+func (p *searchParser) lookahead(n int) searchToken {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *searchParser) next() searchToken {
+	token := p.tokens[p.pos]
+	if token.kind != tokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *searchParser) expect(kind searchTokenKind) error {
+	if token := p.next(); token.kind != kind {
+		return p.unexpected(token)
+	}
+	return nil
+}
+
+func (p *searchParser) unexpected(token searchToken) error {
+	if token.kind == tokenEOF {
+		return &ExpressionError{p.expression, token.offset, "unexpected end of expression"}
+	}
+	text := token.text
+	if text == "" {
+		text = p.expression[token.offset:]
+	}
+	return &ExpressionError{p.expression, token.offset, fmt.Sprintf("unexpected %q", text)}
+}
+
+func (p *searchParser) parseExpression(bindingPower int) (searchNode, error) {
+	left, err := p.nud(p.next())
+	if err != nil {
+		return nil, err
+	}
+	for bindingPower < searchBindingPowers[p.peek().kind] {
+		if left, err = p.led(p.next(), left); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *searchParser) nud(token searchToken) (searchNode, error) {
+	switch token.kind {
+	case tokenIdentifier, tokenQuotedIdentifier:
+		return searchField(token.text), nil
+	case tokenLiteral, tokenRawString:
+		return searchLiteral{token.value}, nil
+	case tokenAt:
+		return searchCurrent{}, nil
+	case tokenStar:
+		right, err := p.parseProjectionRight(searchBindingPowers[tokenStar])
+		return searchProjection{searchCurrent{}, right, true}, err
+	case tokenLbracket:
+		return p.parseBracket(searchCurrent{}, token)
+	case tokenFilter:
+		return p.parseFilter(searchCurrent{})
+	case tokenFlatten:
+		right, err := p.parseProjectionRight(searchBindingPowers[tokenFlatten])
+		return searchProjection{searchFlatten{searchCurrent{}}, right, false}, err
+	case tokenNot:
+		operand, err := p.parseExpression(searchBindingPowers[tokenNot])
+		return searchNot{operand}, err
+	case tokenLparen:
+		inner, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(tokenRparen)
+	}
+	return nil, p.unexpected(token)
+}
+
+func (p *searchParser) led(token searchToken, left searchNode) (searchNode, error) {
+	bindingPower := searchBindingPowers[token.kind]
+	switch token.kind {
+	case tokenDot:
+		if p.peek().kind == tokenStar {
+			p.next()
+			right, err := p.parseProjectionRight(searchBindingPowers[tokenStar])
+			return searchProjection{left, right, true}, err
+		}
+		right, err := p.parseDotRight(bindingPower)
+		return searchSubexpression{left, right}, err
+	case tokenLbracket:
+		return p.parseBracket(left, token)
+	case tokenFilter:
+		return p.parseFilter(left)
+	case tokenFlatten:
+		right, err := p.parseProjectionRight(bindingPower)
+		return searchProjection{searchFlatten{left}, right, false}, err
+	case tokenPipe:
+		right, err := p.parseExpression(bindingPower)
+		return searchPipe{left, right}, err
+	case tokenOr, tokenAnd:
+		right, err := p.parseExpression(bindingPower)
+		return searchLogical{token.kind == tokenAnd, left, right}, err
+	case tokenComparator:
+		right, err := p.parseExpression(bindingPower)
+		return searchComparison{token.text, left, right}, err
+	case tokenLparen:
+		if name, ok := left.(searchField); ok {
+			return p.parseFunction(string(name), p.tokens[p.pos-2])
+		}
+	}
+	return nil, p.unexpected(token)
+}
+
+// parseFunction parses the arguments of a call of the function name
+func (p *searchParser) parseFunction(name string, nameToken searchToken) (searchNode, error) {
+	arity, ok := searchFunctionArities[name]
+	if !ok {
+		return nil, &ExpressionError{p.expression, nameToken.offset, fmt.Sprintf("unknown function %s()", name)}
+	}
+	call := searchCall{name: name}
+	for p.peek().kind != tokenRparen {
+		if len(call.args) > 0 {
+			if err := p.expect(tokenComma); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseExpression(0)
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+	}
+	p.next()
+	if len(call.args) != arity {
+		return nil, &ExpressionError{p.expression, nameToken.offset, fmt.Sprintf("%s() takes %d arguments, got %d", name, arity, len(call.args))}
+	}
+	return call, nil
+}
+
+// parseBracket parses the rest of [n] or [*] applied to left
+func (p *searchParser) parseBracket(left searchNode, open searchToken) (searchNode, error) {
+	token := p.next()
+	switch token.kind {
+	case tokenNumber:
+		if err := p.expect(tokenRbracket); err != nil {
+			return nil, err
+		}
+		return searchSubexpression{left, searchIndex(token.value.(int))}, nil
+	case tokenStar:
+		if err := p.expect(tokenRbracket); err != nil {
+			return nil, err
+		}
+		right, err := p.parseProjectionRight(searchBindingPowers[tokenStar])
+		return searchProjection{left, right, false}, err
+	}
+	return nil, p.unexpected(token)
+}
+
+func (p *searchParser) parseFilter(left searchNode) (searchNode, error) {
+	condition, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokenRbracket); err != nil {
+		return nil, err
+	}
+	right, err := p.parseProjectionRight(searchBindingPowers[tokenFilter])
+	return searchFilter{left, condition, right}, err
+}
+
+func (p *searchParser) parseDotRight(bindingPower int) (searchNode, error) {
+	switch p.peek().kind {
+	case tokenIdentifier, tokenQuotedIdentifier:
+		return p.parseExpression(bindingPower)
+	}
+	return nil, p.unexpected(p.peek())
+}
+
+// parseProjectionRight parses what a projection applies to each element
+func (p *searchParser) parseProjectionRight(bindingPower int) (searchNode, error) {
+	token := p.peek()
+	switch {
+	case searchBindingPowers[token.kind] < projectionStop:
+		return searchCurrent{}, nil
+	case token.kind == tokenLbracket || token.kind == tokenFilter:
+		return p.parseExpression(bindingPower)
+	case token.kind == tokenDot:
+		p.next()
+		if p.peek().kind == tokenStar {
+			// a.*.*
+			p.next()
+			right, err := p.parseProjectionRight(searchBindingPowers[tokenStar])
+			return searchProjection{searchCurrent{}, right, true}, err
+		}
+		return p.parseDotRight(bindingPower)
+	}
+	return nil, p.unexpected(token)
+}
+
+// searchNode is a node of a parsed Search expression
+type searchNode interface {
+	eval(node interface{}) interface{}
+}
+
+type searchCurrent struct{}
+
+func (searchCurrent) eval(node interface{}) interface{} {
+	return node
+}
+
+type searchField string
+
+func (field searchField) eval(node interface{}) interface{} {
+	if object, ok := unwrapRaw(node).(map[string]interface{}); ok {
+		return object[string(field)]
+	}
+	return nil
+}
+
+type searchIndex int
+
+func (index searchIndex) eval(node interface{}) interface{} {
+	array, ok := unwrapRaw(node).([]interface{})
+	if !ok {
+		return nil
+	}
+	idx := int(index)
+	if idx < 0 {
+		idx += len(array)
+	}
+	if idx < 0 || idx >= len(array) {
+		return nil
+	}
+	return array[idx]
+}
+
+type searchLiteral struct {
+	value interface{}
+}
+
+func (literal searchLiteral) eval(node interface{}) interface{} {
+	return literal.value
+}
+
+type searchSubexpression struct {
+	left, right searchNode
+}
+
+func (sub searchSubexpression) eval(node interface{}) interface{} {
+	return sub.right.eval(sub.left.eval(node))
+}
+
+type searchPipe struct {
+	left, right searchNode
+}
+
+func (pipe searchPipe) eval(node interface{}) interface{} {
+	return pipe.right.eval(pipe.left.eval(node))
+}
+
+// searchProjection applies right to every item of the array left evaluates
+// to, or to every value of the object when values is set, leaving out nulls
+type searchProjection struct {
+	left, right searchNode
+	values      bool
+}
+
+func (projection searchProjection) eval(node interface{}) interface{} {
+	target := unwrapRaw(projection.left.eval(node))
+	var items []interface{}
+	if projection.values {
+		object, ok := target.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			items = append(items, object[key])
+		}
+	} else {
+		array, ok := target.([]interface{})
+		if !ok {
+			return nil
+		}
+		items = array
+	}
+	return projectItems(items, projection.right)
+}
+
+func projectItems(items []interface{}, right searchNode) []interface{} {
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if value := right.eval(item); unwrapRaw(value) != nil {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+type searchFlatten struct {
+	left searchNode
+}
+
+func (flatten searchFlatten) eval(node interface{}) interface{} {
+	array, ok := unwrapRaw(flatten.left.eval(node)).([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]interface{}, 0, len(array))
+	for _, item := range array {
+		if inner, ok := unwrapRaw(item).([]interface{}); ok {
+			result = append(result, inner...)
+		} else {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+type searchFilter struct {
+	left, condition, right searchNode
+}
+
+func (filter searchFilter) eval(node interface{}) interface{} {
+	array, ok := unwrapRaw(filter.left.eval(node)).([]interface{})
+	if !ok {
+		return nil
+	}
+	kept := make([]interface{}, 0, len(array))
+	for _, item := range array {
+		if searchTruthy(filter.condition.eval(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return projectItems(kept, filter.right)
+}
+
+type searchNot struct {
+	operand searchNode
+}
+
+func (not searchNot) eval(node interface{}) interface{} {
+	return !searchTruthy(not.operand.eval(node))
+}
+
+// searchLogical is && when and is set, || otherwise. like in JMESPath it
+// evaluates to one of its operands
+type searchLogical struct {
+	and         bool
+	left, right searchNode
+}
+
+func (logical searchLogical) eval(node interface{}) interface{} {
+	left := logical.left.eval(node)
+	if searchTruthy(left) != logical.and {
+		return left
+	}
+	return logical.right.eval(node)
+}
+
+type searchComparison struct {
+	op          string
+	left, right searchNode
+}
+
+func (comparison searchComparison) eval(node interface{}) interface{} {
+	left, right := unwrapRaw(comparison.left.eval(node)), unwrapRaw(comparison.right.eval(node))
+	switch comparison.op {
+	case "==":
+		return rawEqual(left, right)
+	case "!=":
+		return !rawEqual(left, right)
+	}
+	leftNumber, leftOk := rawNumber(left)
+	rightNumber, rightOk := rawNumber(right)
+	if !leftOk || !rightOk {
+		return nil
+	}
+	order := leftNumber.Cmp(rightNumber)
+	switch comparison.op {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	}
+	return order >= 0
+}
+
+// searchTruthy is the truth of JMESPath: false, null and empty strings,
+// arrays and objects are false, everything else, 0 included, is true
+func searchTruthy(node interface{}) bool {
+	switch value := unwrapRaw(node).(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case string:
+		return value != ""
+	case []interface{}:
+		return len(value) > 0
+	case map[string]interface{}:
+		return len(value) > 0
+	}
+	return true
+}
+
+var searchFunctionArities = map[string]int{
+	"length":   1,
+	"keys":     1,
+	"values":   1,
+	"contains": 2,
+	"join":     2,
+}
+
+type searchCall struct {
+	name string
+	args []searchNode
+}
+
+func (call searchCall) eval(node interface{}) interface{} {
+	args := make([]interface{}, len(call.args))
+	for idx, arg := range call.args {
+		args[idx] = unwrapRaw(arg.eval(node))
+	}
+	switch call.name {
+	case "length":
+		switch value := args[0].(type) {
+		case string:
+			return json.Number(strconv.Itoa(utf8.RuneCountInString(value)))
+		case []interface{}:
+			return json.Number(strconv.Itoa(len(value)))
+		case map[string]interface{}:
+			return json.Number(strconv.Itoa(len(value)))
+		}
+	case "keys":
+		if object, ok := args[0].(map[string]interface{}); ok {
+			keys := make([]interface{}, 0, len(object))
+			for _, key := range sortedKeys(object) {
+				keys = append(keys, key)
+			}
+			return keys
+		}
+	case "values":
+		if object, ok := args[0].(map[string]interface{}); ok {
+			values := make([]interface{}, 0, len(object))
+			for _, key := range sortedKeys(object) {
+				values = append(values, object[key])
+			}
+			return values
+		}
+	case "contains":
+		switch subject := args[0].(type) {
+		case string:
+			if search, ok := args[1].(string); ok {
+				return strings.Contains(subject, search)
+			}
+			return false
+		case []interface{}:
+			for _, item := range subject {
+				if rawEqual(item, args[1]) {
+					return true
+				}
+			}
+			return false
+		}
+	case "join":
+		glue, ok := args[0].(string)
+		array, isArray := args[1].([]interface{})
+		if !ok || !isArray {
+			return nil
+		}
+		parts := make([]string, 0, len(array))
+		for _, item := range array {
+			part, ok := unwrapRaw(item).(string)
+			if !ok {
+				return nil
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, glue)
+	}
+	return nil
+}
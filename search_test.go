@@ -0,0 +1,170 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+type searchCase struct {
+	expression string
+	result     string
+}
+
+func assertSearchCases(t *testing.T, document string, cases []searchCase) {
+	js, err := Parse([]byte(document))
+	assert.True(t, err == nil)
+	for _, c := range cases {
+		result, err := js.Search(c.expression)
+		assert.True(t, err == nil, c.expression)
+		assert.Equal(t, c.result, result.EncodeToStringOrDefault("?"), c.expression)
+	}
+}
+
+// the cases are taken from the basic, indices, wildcard, filters and pipe
+// suites of the JMESPath compliance tests
+func TestJson_SearchBasic(t *testing.T) {
+	assertSearchCases(t, `{"foo": {"bar": {"baz": "correct"}}}`, []searchCase{
+		{"foo", `{"bar":{"baz":"correct"}}`},
+		{"foo.bar", `{"baz":"correct"}`},
+		{"foo.bar.baz", `"correct"`},
+		{"foo\n.\nbar\n.baz", `"correct"`},
+		{"foo.bar.baz.bad", "null"},
+		{"foo.bar.bad", "null"},
+		{"foo.bad", "null"},
+		{"bad", "null"},
+		{"bad.morebad.morebad", "null"},
+		{`"foo"."bar"`, `{"baz":"correct"}`},
+		{"@.foo.bar", `{"baz":"correct"}`},
+	})
+	assertSearchCases(t, `{"foo": {"bar": ["one", "two", "three"]}}`, []searchCase{
+		{"foo.bar", `["one","two","three"]`},
+		{"foo.bar.baz", "null"},
+	})
+}
+
+func TestJson_SearchIndices(t *testing.T) {
+	assertSearchCases(t, `{"foo": {"bar": ["zero", "one", "two"]}}`, []searchCase{
+		{"foo.bar[0]", `"zero"`},
+		{"foo.bar[1]", `"one"`},
+		{"foo.bar[2]", `"two"`},
+		{"foo.bar[3]", "null"},
+		{"foo.bar[-1]", `"two"`},
+		{"foo.bar[-3]", `"zero"`},
+		{"foo.bar[-4]", "null"},
+		{"foo[0]", "null"},
+	})
+	assertSearchCases(t, `{"foo": [{"bar": ["one", "two"]}, {"bar": ["three", "four"]}, {"bar": ["five"]}]}`, []searchCase{
+		{"foo[0].bar", `["one","two"]`},
+		{"foo[1].bar[0]", `"three"`},
+		{"foo[2].bar[3]", "null"},
+		{"foo[*].bar[0]", `["one","three","five"]`},
+		{"foo[*].bar", `[["one","two"],["three","four"],["five"]]`},
+		{"foo[].bar[]", `["one","two","three","four","five"]`},
+		{"foo[*].bar[]", `["one","two","three","four","five"]`},
+	})
+}
+
+func TestJson_SearchWildcards(t *testing.T) {
+	assertSearchCases(t, `{"foo": {"bar": {"baz": "val"}, "other": {"baz": "val"}, "other2": {"baz": "val"}, "other3": {"notbaz": ["a", "b", "c"]}, "other4": {"notbaz": ["a", "b", "c"]}}}`, []searchCase{
+		{"foo.*.baz", `["val","val","val"]`},
+		{"foo.bar.*", `["val"]`},
+		{"foo.*.notbaz", `[["a","b","c"],["a","b","c"]]`},
+		{"foo.*.notbaz[0]", `["a","a"]`},
+		{"foo.*.notbaz[-1]", `["c","c"]`},
+	})
+	assertSearchCases(t, `{"foo": [{"bar": "one"}, {"bar": "two"}, {"bar": "three"}, {"notbar": "four"}]}`, []searchCase{
+		{"foo[*].bar", `["one","two","three"]`},
+		{"foo[*].notbar", `["four"]`},
+		{"*.bar", "[]"},
+		{"foo[*]", `[{"bar":"one"},{"bar":"two"},{"bar":"three"},{"notbar":"four"}]`},
+		{"foo.bar[*]", "null"},
+	})
+}
+
+func TestJson_SearchFilters(t *testing.T) {
+	assertSearchCases(t, `{"foo": [{"name": "a"}, {"name": "b"}]}`, []searchCase{
+		{"foo[?name == 'a']", `[{"name":"a"}]`},
+		{"*[?name == 'a']", `[[{"name":"a"}]]`},
+		{"foo[?name != 'a'].name", `["b"]`},
+	})
+	assertSearchCases(t, `{"foo": [{"age": 20}, {"age": 25}, {"age": 30}]}`, []searchCase{
+		{"foo[?age > `25`]", `[{"age":30}]`},
+		{"foo[?age >= `25`]", `[{"age":25},{"age":30}]`},
+		{"foo[?age > `30`]", "[]"},
+		{"foo[?age < `25`]", `[{"age":20}]`},
+		{"foo[?age <= `25`]", `[{"age":20},{"age":25}]`},
+		{"foo[?age == `20`].age", `[20]`},
+		{"foo[?age == `20.0`].age", `[20]`},
+		{"foo[?age > 'a']", "[]"},
+	})
+	assertSearchCases(t, `{"foo": [{"a": 1, "b": true, "c": 0}, {"a": 2, "b": false, "c": ""}, {"a": 3}]}`, []searchCase{
+		{"foo[?b].a", `[1]`},
+		{"foo[?!b].a", `[2,3]`},
+		{"foo[?c].a", `[1]`},
+		{"foo[?a == `1` || a == `3`].a", `[1,3]`},
+		{"foo[?a > `1` && !(b)].a", `[2,3]`},
+		{"foo[?b == `true`].a", `[1]`},
+		{"foo[?c == `null`].a", `[3]`},
+	})
+	assertSearchCases(t, `{"reservations": [{"instances": [{"foo": 1, "bar": 2}, {"foo": 1, "bar": 3}, {"foo": 1, "bar": 2}, {"foo": 2, "bar": 1}]}]}`, []searchCase{
+		{"reservations[*].instances[?bar==`1`]", `[[{"bar":1,"foo":2}]]`},
+		{"reservations[].instances[?bar==`1`]", `[[{"bar":1,"foo":2}]]`},
+		{"reservations[].instances[?foo==bar]", `[[]]`},
+		{"reservations[].instances[?foo<bar].bar | [0]", `[2,3,2]`},
+	})
+}
+
+func TestJson_SearchPipes(t *testing.T) {
+	assertSearchCases(t, `{"foo": {"bar": {"baz": "subkey"}, "other": {"baz": "subkey"}, "other2": {"baz": "subkey"}, "other3": {"notbaz": ["a", "b", "c"]}, "other4": {"notbaz": ["d", "e", "f"]}}}`, []searchCase{
+		{"foo.*.baz | [0]", `"subkey"`},
+		{"foo.*.baz | [1]", `"subkey"`},
+		{"foo.*.baz | [3]", "null"},
+		{"foo.bar.* | [0]", `"subkey"`},
+		{"foo.*.notbaz | [*]", `[["a","b","c"],["d","e","f"]]`},
+		{"foo.*.notbaz | [*][0]", `["a","d"]`},
+		{"foo | bar", `{"baz":"subkey"}`},
+		{"foo | bad", "null"},
+		{"foo | other3 | notbaz", `["a","b","c"]`},
+		{"foo.other4 | notbaz[1] | @", `"e"`},
+	})
+}
+
+func TestJson_SearchResultIsACopy(t *testing.T) {
+	js, _ := Parse([]byte(`{"users":[{"name":"a","active":true},{"name":"b","active":false},{"name":"c","active":true}]}`))
+	names, err := js.Search("users[?active].name | [0]")
+	assert.True(t, err == nil)
+	assert.Equal(t, "a", names.MustString())
+	first, _ := js.Search("users[0]")
+	first.Set("name", "changed")
+	assert.Equal(t, "a", js.GetDottedPath("users.0.name").MustString())
+	missing, _ := NewEmpty().Search("a.b")
+	assert.False(t, missing.IsEmpty())
+	assert.True(t, missing.Interface() == nil)
+	literal, _ := js.Search("`{\"k\":[1,2]}`")
+	assert.Equal(t, `{"k":[1,2]}`, literal.EncodeToStringOrDefault(""))
+}
+
+func TestJson_SearchErrors(t *testing.T) {
+	js, _ := Parse([]byte(`{"a":1}`))
+	for expression, expected := range map[string]string{
+		"a.":          `unexpected end of expression at offset 2 of "a."`,
+		"a]":          `unexpected "]" at offset 1 of "a]"`,
+		"a.b$c":       `unexpected character '$' at offset 3 of "a.b$c"`,
+		"a[?b == 'x'": `unexpected end of expression at offset 11 of "a[?b == 'x'"`,
+		"a['x']":      `unexpected "'x']" at offset 2 of "a['x']"`,
+		"`{`":         "invalid json literal at offset 0 of \"`{`\"",
+		"\"unclosed":  `unclosed " at offset 0 of "\"unclosed"`,
+		"a || ":       `unexpected end of expression at offset 5 of "a || "`,
+		"[1:2]":       `unexpected character ':' at offset 2 of "[1:2]"`,
+	} {
+		result, err := js.Search(expression)
+		assert.True(t, err != nil, expression)
+		if err == nil {
+			continue
+		}
+		exprErr, ok := err.(*ExpressionError)
+		assert.True(t, ok)
+		assert.Equal(t, expected, exprErr.Error(), expression)
+		assert.True(t, result.IsEmpty())
+	}
+}
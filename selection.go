@@ -0,0 +1,93 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Select2 returns a document shaped like the selection object, with the
+// values taken from j. each member of the selection is one of
+//    true             the member of the same name
+//    "dotted.path"    the value at that path, relative to the enclosing source
+//    {...}            a nested selection of the member of the same name,
+//                     applied to every item when that member is an array
+// for example
+//    selection, _ := betterjson.Parse([]byte(`{"userName": "user.profile.name", "orders": {"total": "amount", "id": true}}`))
+//    view, err := j.Select2(selection)
+// sources that aren't there become null
+func (j *Json) Select2(selection *Json) (*Json, error) {
+	if j.IsEmpty() {
+		return NewEmpty(), errors.New("can't select from empty json")
+	}
+	if selection.IsEmpty() {
+		return NewEmpty(), errors.New("empty selection")
+	}
+	shape, isObject := unwrapRaw(selection.Interface()).(map[string]interface{})
+	if !isObject {
+		return NewEmpty(), errors.Errorf("selection must be an object, not %s", kindName(selection.Interface()))
+	}
+	result, err := selectShape(j.Interface(), shape, []string{})
+	if err != nil {
+		return NewEmpty(), err
+	}
+	return wrapRaw(result), nil
+}
+
+// selectShape applies the selection shape, found at branch of the whole
+// selection, to the source node
+func selectShape(source interface{}, shape map[string]interface{}, branch []string) (interface{}, error) {
+	switch container := unwrapRaw(source).(type) {
+	case []interface{}:
+		items := make([]interface{}, len(container))
+		for idx, item := range container {
+			selected, err := selectShape(item, shape, branch)
+			if err != nil {
+				return nil, err
+			}
+			items[idx] = selected
+		}
+		return items, nil
+	case map[string]interface{}:
+	default:
+		// a selection below a scalar still has to be valid
+		if _, err := selectShape(map[string]interface{}{}, shape, branch); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	result := make(map[string]interface{}, len(shape))
+	for _, key := range sortedKeys(shape) {
+		memberBranch := append(branch[:len(branch):len(branch)], key)
+		switch spec := unwrapRaw(shape[key]).(type) {
+		case bool:
+			if !spec {
+				return nil, errors.Errorf("selection of %s is false, leave it out instead", displayPath(memberBranch))
+			}
+			result[key] = selectedValue(source, []string{key})
+		case string:
+			result[key] = selectedValue(source, ParseDottedPath(spec))
+		case map[string]interface{}:
+			member, ok := childValue(source, key)
+			if !ok {
+				member = nil
+			}
+			selected, err := selectShape(member, spec, memberBranch)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = selected
+		default:
+			return nil, errors.Errorf("selection of %s must be true, a path or an object, not %s",
+				displayPath(memberBranch), kindName(spec))
+		}
+	}
+	return result, nil
+}
+
+// selectedValue is a copy of the value at branch of node, or null
+func selectedValue(node interface{}, branch []string) interface{} {
+	value, ok := valueAtBranch(node, branch)
+	if !ok {
+		return nil
+	}
+	return deepCopyRaw(value)
+}
@@ -0,0 +1,55 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_Select2(t *testing.T) {
+	js, err := Parse([]byte(`{"user":{"id":7,"profile":{"name":"ann","email":"a@x"}},` +
+		`"orders":[{"id":1,"amount":10,"lines":[{"sku":"a"},{"sku":"b"}]},{"id":2,"amount":20},"bad"]}`))
+	assert.True(t, err == nil)
+	selection, err := Parse([]byte(`{"userName":"user.profile.name","user":{"id":true},` +
+		`"orders":{"total":"amount","id":true,"firstSku":"lines.0.sku","lines":{"sku":true}}}`))
+	assert.True(t, err == nil)
+	view, err := js.Select2(selection)
+	assert.True(t, err == nil)
+	println(view.EncodeToStringOrDefault(""))
+	assert.Equal(t, `{"orders":[{"firstSku":"a","id":1,"lines":[{"sku":"a"},{"sku":"b"}],"total":10},`+
+		`{"firstSku":null,"id":2,"lines":null,"total":20},null],"user":{"id":7},"userName":"ann"}`,
+		view.EncodeToStringOrDefault(""))
+	view.Get("user").Set("id", 8)
+	assert.Equal(t, int64(7), js.GetPath("user", "id").MustInt64())
+}
+
+func TestJson_Select2Missing(t *testing.T) {
+	js, err := Parse([]byte(`{"a":1}`))
+	assert.True(t, err == nil)
+	selection, err := Parse([]byte(`{"x":"no.such.path","b":true,"c":{"d":true}}`))
+	assert.True(t, err == nil)
+	view, err := js.Select2(selection)
+	assert.True(t, err == nil)
+	assert.Equal(t, `{"b":null,"c":null,"x":null}`, view.EncodeToStringOrDefault(""))
+
+	items, err := Parse([]byte(`[{"a":1},{"a":2}]`))
+	assert.True(t, err == nil)
+	aliased, err := Parse([]byte(`{"value":"a"}`))
+	assert.True(t, err == nil)
+	view, err = items.Select2(aliased)
+	assert.True(t, err == nil)
+	assert.Equal(t, `[{"value":1},{"value":2}]`, view.EncodeToStringOrDefault(""))
+}
+
+func TestJson_Select2Invalid(t *testing.T) {
+	js, err := Parse([]byte(`{"a":1}`))
+	assert.True(t, err == nil)
+	for _, text := range []string{`{"a":1}`, `{"a":false}`, `{"c":{"d":[]}}`, `["a"]`} {
+		selection, err := Parse([]byte(text))
+		assert.True(t, err == nil)
+		_, err = js.Select2(selection)
+		assert.True(t, err != nil, text)
+		println(err.Error())
+	}
+	_, err = NewEmpty().Select2(NewJSONObject())
+	assert.True(t, err != nil)
+}
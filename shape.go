@@ -0,0 +1,91 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// EnsureShape creates every part of the skeleton document spec that is missing
+// from j, without overwriting anything that already exists:
+//    js.EnsureShape(spec) // spec is {"meta":{},"items":[],"counts":{"total":0}}
+//
+// objects in spec are descended into, arrays are only ensured to exist (their
+// items are ignored) and scalars are used as defaults. missing and null nodes
+// count as absent. a present scalar is kept whatever its kind, so "8080" stays
+// in place of a default 80. a node that isn't the object or array spec wants
+// is reported as an error naming its path; the whole spec is checked before
+// anything is created, so j is left unchanged on error.
+//
+// after a successful EnsureShape GetPath on any path present in spec is non-empty
+func (j *Json) EnsureShape(spec *Json) error {
+	if j.IsEmpty() {
+		return errors.New("empty json can't ensure shape")
+	}
+	if spec == nil || spec.IsEmpty() {
+		return nil
+	}
+	specValue := unwrapRaw(spec.value.Interface())
+	if err := checkShape(unwrapRaw(j.value.Interface()), specValue, []string{}); err != nil {
+		return err
+	}
+	ensureShape(j, specValue)
+	return nil
+}
+
+// checkShape reports the first node of node, found at branch, that conflicts
+// with spec, without changing anything
+func checkShape(node interface{}, spec interface{}, branch []string) error {
+	switch spec.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return nil
+	}
+	if kindName(node) != kindName(spec) {
+		return errors.Errorf("shape conflict at %s: document has %s, spec wants %s", displayPath(branch), kindName(node), kindName(spec))
+	}
+	specMap, ok := spec.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	nodeMap := node.(map[string]interface{})
+	for _, key := range sortedKeys(specMap) {
+		item := unwrapRaw(nodeMap[key])
+		if item == nil {
+			continue
+		}
+		if err := checkShape(item, unwrapRaw(specMap[key]), append(branch[:len(branch):len(branch)], key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureShape shapes the value of current, already checked against spec by
+// checkShape. the nodes it creates are recorded and observed like Set
+func ensureShape(current *Json, spec interface{}) {
+	specMap, ok := spec.(map[string]interface{})
+	if !ok {
+		return
+	}
+	nodeMap := unwrapRaw(current.value.Interface()).(map[string]interface{})
+	for _, key := range sortedKeys(specMap) {
+		specItem := unwrapRaw(specMap[key])
+		item := unwrapRaw(nodeMap[key])
+		if item == nil {
+			item = newShapeNode(specItem)
+			prior := current.prior([]string{key})
+			nodeMap[key] = item
+			current.changed(setOperation([]string{key}, item, prior), prior)
+		}
+		ensureShape(newChild(current, key, item), specItem)
+	}
+}
+// newShapeNode returns the empty container or the default scalar for spec
+func newShapeNode(spec interface{}) interface{} {
+	switch spec.(type) {
+	case map[string]interface{}:
+		return make(map[string]interface{})
+	case []interface{}:
+		return make([]interface{}, 0)
+	}
+	return spec
+}
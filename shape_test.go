@@ -0,0 +1,83 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/bitly/go-simplejson"
+	"github.com/stretchr/testify/assert"
+)
+
+func shapeSpec(t *testing.T) *Json {
+	spec, err := simplejson.NewJson([]byte("{\"meta\":{\"tags\":[]},\"items\":[],\"counts\":{\"total\":0}}"))
+	assert.True(t, err == nil)
+	return FromNotEmptySimpleJson(spec)
+}
+
+func TestJson_EnsureShapeOnEmptyDocument(t *testing.T) {
+	a := NewJSONObject()
+	err := a.EnsureShape(shapeSpec(t))
+	assert.True(t, err == nil)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	println(aStr)
+	assert.True(t, aStr == "{\"counts\":{\"total\":0},\"items\":[],\"meta\":{\"tags\":[]}}")
+	assert.True(t, !a.GetPath("meta", "tags").IsEmptyOrNull())
+	assert.True(t, a.GetPath("counts", "total").MustInt() == 0)
+}
+
+func TestJson_EnsureShapeOnPartialDocument(t *testing.T) {
+	a := Obj("items", Arr(1), "counts", Obj("total", 5), "meta", nil, "extra", true)
+	err := a.EnsureShape(shapeSpec(t))
+	assert.True(t, err == nil)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	println(aStr)
+	assert.True(t, aStr == "{\"counts\":{\"total\":5},\"extra\":true,\"items\":[1],\"meta\":{\"tags\":[]}}")
+}
+
+func TestJson_EnsureShapeConflict(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("meta", Obj("tags", "a,b"))
+	err := a.EnsureShape(shapeSpec(t))
+	assert.True(t, err != nil)
+	println(err.Error())
+	assert.True(t, err.Error() == "shape conflict at meta.tags: document has string, spec wants array")
+	// counts sorts before meta but isn't created when meta conflicts
+	aStr, _ := a.EncodeToString()
+	assert.True(t, aStr == "{\"meta\":{\"tags\":\"a,b\"}}")
+	b := NewJSONObject()
+	b.Set("counts", "many")
+	err = b.EnsureShape(shapeSpec(t))
+	assert.True(t, err != nil)
+	assert.True(t, err.Error() == "shape conflict at counts: document has string, spec wants object")
+	assert.True(t, !b.ContainsKey("items") && !b.ContainsKey("meta"))
+	err = NewJSONArray().EnsureShape(shapeSpec(t))
+	assert.True(t, err != nil)
+	assert.True(t, NewEmpty().EnsureShape(shapeSpec(t)) != nil)
+}
+
+func TestJson_EnsureShapeKeepsScalars(t *testing.T) {
+	a := Obj("port", "8080", "counts", Obj("total", "many"), "meta", Obj("tags", Arr("a")))
+	spec, _ := Parse([]byte(`{"port":80,"host":"localhost","counts":{"total":0},"meta":"none"}`))
+	err := a.EnsureShape(spec)
+	assert.True(t, err == nil)
+	aStr, _ := a.EncodeToString()
+	println(aStr)
+	assert.True(t, aStr == "{\"counts\":{\"total\":\"many\"},\"host\":\"localhost\",\"meta\":{\"tags\":[\"a\"]},\"port\":\"8080\"}")
+}
+
+func TestJson_EnsureShapeRecorded(t *testing.T) {
+	original := `{"meta":{"a":1}}`
+	doc, _ := Parse([]byte(original))
+	spec, _ := Parse([]byte(`{"meta":{"tags":[]},"counts":{"total":0}}`))
+	paths := make([]string, 0)
+	doc.OnChange(func(event ChangeEvent) { paths = append(paths, JoinDottedPath(event.Path)) })
+	doc.StartRecording()
+	assert.True(t, doc.EnsureShape(spec) == nil)
+	patch, err := doc.StopRecording()
+	assert.True(t, err == nil)
+	assert.Equal(t, []string{"counts", "counts.total", "meta.tags"}, paths)
+
+	copied, _ := Parse([]byte(original))
+	assert.True(t, copied.ApplyPatch(patch) == nil)
+	assert.True(t, copied.IsSameJSONWith(doc))
+}
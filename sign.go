@@ -0,0 +1,80 @@
+package betterjson
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+// SignOptions configures Sign and VerifySignature
+type SignOptions struct {
+	// Field is the dotted path of the signature, "_sig" when empty
+	Field string
+}
+
+func (opts SignOptions) branch() []string {
+	if opts.Field == "" {
+		return []string{"_sig"}
+	}
+	return ParseDottedPath(opts.Field)
+}
+
+// Sign returns a copy of the object j with an HMAC-SHA256 of its canonical
+// form added as hex at opts.Field. the HMAC is taken with "" at opts.Field,
+// so a signature j already has there is left out of it and replaced. the
+// canonical form is the one of DigestJSONForEqual, so key order and
+// whitespace don't change the signature:
+//    signed, err := session.Sign(secret, betterjson.SignOptions{})
+//    ...
+//    received, err := betterjson.Parse(cookie)
+//    ok, err := received.VerifySignature(secret, betterjson.SignOptions{})
+func (j *Json) Sign(key []byte, opts SignOptions) (*Json, error) {
+	if _, ok := unwrapRaw(j.Interface()).(map[string]interface{}); !ok || j.IsEmpty() {
+		return NewEmpty(), errors.New("only json objects can be signed")
+	}
+	branch := opts.branch()
+	signed := wrapRaw(deepCopyRaw(j.value.Interface()))
+	signature, err := signed.signature(key, branch)
+	if err != nil {
+		return NewEmpty(), err
+	}
+	signed.SetPath(branch, hex.EncodeToString(signature))
+	return signed, nil
+}
+
+// VerifySignature reports whether the signature at opts.Field of j is the one
+// Sign makes with key for the rest of j. a missing or malformed signature is
+// an error
+func (j *Json) VerifySignature(key []byte, opts SignOptions) (bool, error) {
+	branch := opts.branch()
+	field, ok := j.lookupPath(branch)
+	if !ok {
+		return false, errors.Errorf("signature %s is missing", displayPath(branch))
+	}
+	text, ok := unwrapRaw(field.Interface()).(string)
+	if !ok {
+		return false, errors.Errorf("signature %s is a %s, not a string", displayPath(branch), kindName(field.Interface()))
+	}
+	claimed, err := hex.DecodeString(text)
+	if err != nil {
+		return false, errors.Wrapf(err, "signature %s is not hex", displayPath(branch))
+	}
+	signature, err := wrapRaw(deepCopyRaw(j.value.Interface())).signature(key, branch)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(claimed, signature), nil
+}
+
+// signature is the HMAC of j with "" at branch, which keeps the signature
+// out of it whether or not j has one already. j is modified
+func (j *Json) signature(key []byte, branch []string) ([]byte, error) {
+	j.SetPath(branch, "")
+	mac := hmac.New(sha256.New, key)
+	if err := j.WriteDigest(mac); err != nil {
+		return nil, errors.Wrap(err, "can't sign json")
+	}
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,54 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_SignAndVerify(t *testing.T) {
+	key := []byte("secret")
+	session, _ := Parse([]byte(`{"user":"ann","roles":["admin"],"exp":1700000000}`))
+	signed, err := session.Sign(key, SignOptions{})
+	assert.True(t, err == nil)
+	println(signed.EncodeToStringOrDefault(""))
+	assert.Equal(t, 64, len(signed.Get("_sig").MustString()))
+	assert.False(t, session.ContainsKey("_sig"))
+
+	ok, err := signed.VerifySignature(key, SignOptions{})
+	assert.True(t, err == nil && ok)
+	ok, _ = signed.VerifySignature([]byte("other"), SignOptions{})
+	assert.False(t, ok)
+
+	// reordered keys and whitespace don't matter
+	reordered, _ := Parse([]byte(`{ "exp": 1700000000, "_sig": "` + signed.Get("_sig").MustString() + `",
+		"roles": [ "admin" ], "user": "ann" }`))
+	ok, _ = reordered.VerifySignature(key, SignOptions{})
+	assert.True(t, ok)
+
+	// signing again replaces the signature with the same one
+	resigned, _ := signed.Sign(key, SignOptions{})
+	assert.Equal(t, signed.Get("_sig").MustString(), resigned.Get("_sig").MustString())
+}
+
+func TestJson_VerifySignatureDetectsChanges(t *testing.T) {
+	key := []byte("secret")
+	doc, _ := Parse([]byte(`{"a":{"b":[1,2]},"c":"x"}`))
+	signed, _ := doc.Sign(key, SignOptions{Field: "meta.signature"})
+	assert.True(t, signed.GetDottedPath("meta.signature").MustString() != "")
+	ok, _ := signed.VerifySignature(key, SignOptions{Field: "meta.signature"})
+	assert.True(t, ok)
+
+	signed.GetDottedPath("a").Get("b").SetIndex(1, 3)
+	ok, err := signed.VerifySignature(key, SignOptions{Field: "meta.signature"})
+	assert.True(t, err == nil)
+	assert.False(t, ok)
+
+	_, err = doc.VerifySignature(key, SignOptions{})
+	assert.Equal(t, "signature _sig is missing", err.Error())
+	_, err = doc.VerifySignature(key, SignOptions{Field: "c"})
+	assert.Equal(t, "signature c is not hex: encoding/hex: invalid byte: U+0078 'x'", err.Error())
+	_, err = doc.VerifySignature(key, SignOptions{Field: "a"})
+	assert.Equal(t, "signature a is a object, not a string", err.Error())
+	_, err = NewJSONArray().Sign(key, SignOptions{})
+	assert.Equal(t, "only json objects can be signed", err.Error())
+}
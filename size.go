@@ -0,0 +1,68 @@
+package betterjson
+
+import (
+	"fmt"
+)
+
+// SizeError is returned by LenAt and ArrayLengthAt when there is no value at
+// Path, or it has no size of the requested kind
+type SizeError struct {
+	Path []string
+	// Missing is set when nothing is at Path
+	Missing bool
+	// Kind is the JSON type found at Path otherwise, like "number"
+	Kind string
+}
+
+func (e *SizeError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("path %s is missing", displayPath(e.Path))
+	}
+	return fmt.Sprintf("%s at %s has no length", e.Kind, displayPath(e.Path))
+}
+
+// LenAt returns the size of the node at branch: the item count of an array,
+// the key count of an object or the length in bytes of a string. segments
+// may be array indexes like GetDottedPath's. other kinds and missing paths
+// fail with a SizeError:
+//    if n, err := js.LenAt("results", "entries"); err == nil && n > 0 {
+//        ...
+//    }
+func (j *Json) LenAt(branch ...string) (int, error) {
+	node, err := j.sizedNode(branch)
+	if err != nil {
+		return 0, err
+	}
+	switch value := node.(type) {
+	case []interface{}:
+		return len(value), nil
+	case map[string]interface{}:
+		return len(value), nil
+	case string:
+		return len(value), nil
+	}
+	return 0, &SizeError{Path: branch, Kind: kindName(node)}
+}
+
+// ArrayLengthAt is LenAt for arrays only, other kinds fail with a SizeError
+func (j *Json) ArrayLengthAt(branch ...string) (int, error) {
+	node, err := j.sizedNode(branch)
+	if err != nil {
+		return 0, err
+	}
+	if array, ok := node.([]interface{}); ok {
+		return len(array), nil
+	}
+	return 0, &SizeError{Path: branch, Kind: kindName(node)}
+}
+
+func (j *Json) sizedNode(branch []string) (interface{}, error) {
+	if j.IsEmpty() {
+		return nil, &SizeError{Path: branch, Missing: true}
+	}
+	node, ok := valueAtBranch(j.value.Interface(), branch)
+	if !ok {
+		return nil, &SizeError{Path: branch, Missing: true}
+	}
+	return unwrapRaw(node), nil
+}
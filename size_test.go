@@ -0,0 +1,50 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_LenAt(t *testing.T) {
+	js, _ := Parse([]byte(`{"results":{"entries":[1,2,3],"empty":[]},"name":"héllo","count":3,"flag":true,"nothing":null,"list":[{"tags":{"a":1,"b":2}}]}`))
+	n, err := js.LenAt("results", "entries")
+	assert.True(t, err == nil && n == 3)
+	n, err = js.LenAt("results")
+	assert.True(t, err == nil && n == 2)
+	n, err = js.LenAt("name")
+	assert.True(t, err == nil && n == 6)
+	n, err = js.LenAt("list", "0", "tags")
+	assert.True(t, err == nil && n == 2)
+	n, err = js.LenAt()
+	assert.True(t, err == nil && n == 6)
+	n, err = js.LenAt("results", "empty")
+	assert.True(t, err == nil && n == 0)
+
+	for _, branch := range [][]string{{"count"}, {"flag"}, {"nothing"}} {
+		_, err = js.LenAt(branch...)
+		sizeErr, ok := err.(*SizeError)
+		assert.True(t, ok && !sizeErr.Missing, branch)
+		println(err.Error())
+	}
+	for _, branch := range [][]string{{"missing"}, {"results", "entries", "9"}, {"nothing", "deeper"}, {"count", "x", "y"}} {
+		_, err = js.LenAt(branch...)
+		sizeErr, ok := err.(*SizeError)
+		assert.True(t, ok && sizeErr.Missing, branch)
+	}
+	_, err = NewEmpty().LenAt("a")
+	assert.True(t, err != nil && err.Error() == "path a is missing")
+	_, err = js.LenAt("count")
+	assert.True(t, err.Error() == "number at count has no length")
+}
+
+func TestJson_ArrayLengthAt(t *testing.T) {
+	js, _ := Parse([]byte(`{"results":{"entries":[1,2,3]},"name":"abc"}`))
+	n, err := js.ArrayLengthAt("results", "entries")
+	assert.True(t, err == nil && n == 3)
+	_, err = js.ArrayLengthAt("results")
+	assert.True(t, err != nil && err.(*SizeError).Kind == "object")
+	_, err = js.ArrayLengthAt("name")
+	assert.True(t, err != nil && err.(*SizeError).Kind == "string")
+	_, err = js.Get("missing").ArrayLengthAt("entries")
+	assert.True(t, err != nil && err.(*SizeError).Missing)
+}
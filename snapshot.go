@@ -0,0 +1,101 @@
+package betterjson
+
+import (
+	"sync/atomic"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// SnapshotID identifies a state saved by Json.Snapshot
+type SnapshotID int
+
+type snapshot struct {
+	id    SnapshotID
+	empty bool
+	data  interface{}
+}
+
+// lastSnapshotID makes snapshot ids unique across documents
+var lastSnapshotID int64
+
+// Snapshot saves a deep copy of the current state of j, which Rollback can
+// restore. snapshots nest like a stack:
+//    id := js.Snapshot()
+//    js.Set("a", 1).Del("b")
+//    if err := validate(js); err != nil {
+//        js.Rollback(id)
+//    } else {
+//        js.DiscardSnapshot(id)
+//    }
+func (j *Json) Snapshot() SnapshotID {
+	saved := snapshot{id: SnapshotID(atomic.AddInt64(&lastSnapshotID, 1)), empty: j.IsEmpty()}
+	if !saved.empty {
+		saved.data = deepCopyRaw(j.value.Interface())
+	}
+	j.snapshots = append(j.snapshots, saved)
+	return saved.id
+}
+
+func (j *Json) snapshotIndex(id SnapshotID) (int, error) {
+	for idx := len(j.snapshots) - 1; idx >= 0; idx-- {
+		if j.snapshots[idx].id == id {
+			return idx, nil
+		}
+	}
+	return -1, errors.Errorf("snapshot %d doesn't exist", id)
+}
+
+// Rollback restores the state saved by Snapshot id, discarding it and every
+// snapshot taken after it. when j is an object its keys are restored in place,
+// so wrappers of j itself keep working; wrappers of nested values taken after
+// the snapshot no longer belong to j's document
+func (j *Json) Rollback(id SnapshotID) error {
+	idx, err := j.snapshotIndex(id)
+	if err != nil {
+		return err
+	}
+	saved := j.snapshots[idx]
+	j.snapshots = j.snapshots[:idx]
+	if saved.empty {
+		j.value = nil
+		return nil
+	}
+	data := deepCopyRaw(saved.data)
+	prior := j.prior([]string{})
+	j.replaceData(data)
+	j.changed(setOperation([]string{}, data, prior), prior)
+	return nil
+}
+
+// replaceData makes data the value of j. when both are objects the keys are
+// replaced in place, so wrappers of j itself keep working
+func (j *Json) replaceData(data interface{}) {
+	if j.IsEmpty() {
+		j.value = simplejson.New()
+	}
+	current, currentIsObject := j.value.Interface().(map[string]interface{})
+	restored, restoredIsObject := data.(map[string]interface{})
+	if currentIsObject && restoredIsObject {
+		for key := range current {
+			delete(current, key)
+		}
+		for key, item := range restored {
+			current[key] = item
+		}
+		return
+	}
+	j.value.SetPath([]string{}, data)
+	j.writeBack()
+}
+
+// DiscardSnapshot drops the state saved by Snapshot id, and every snapshot
+// taken after it, keeping the current state
+func (j *Json) DiscardSnapshot(id SnapshotID) error {
+	idx, err := j.snapshotIndex(id)
+	if err != nil {
+		return err
+	}
+	j.snapshots = j.snapshots[:idx]
+	return nil
+}
@@ -0,0 +1,58 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_SnapshotRollback(t *testing.T) {
+	a := NewJSONObject()
+	a.Set("hello", "world").Set("items", NewJSONArray().TryAdd(1).TryAdd("two")).Set("nested", NewJSONObject().Set("age", 18))
+	before := a.DigestJSONForEqual()
+	id := a.Snapshot()
+	a.Set("hello", "changed").Del("nested").Set("added", true)
+	items, err := a.ArrayAtPath("items")
+	assert.True(t, err == nil)
+	items.TryAdd(3)
+	assert.True(t, a.DigestJSONForEqual() != before)
+	err = a.Rollback(id)
+	assert.True(t, err == nil)
+	assert.True(t, a.DigestJSONForEqual() == before)
+	assert.True(t, a.Rollback(id) != nil)
+}
+
+func TestJson_SnapshotStack(t *testing.T) {
+	a := NewJSONArray().TryAdd(1)
+	outer := a.Snapshot()
+	a.TryAdd(2)
+	afterFirst := a.DigestJSONForEqual()
+	inner := a.Snapshot()
+	a.TryAdd(3)
+	assert.True(t, a.Rollback(inner) == nil)
+	assert.True(t, a.DigestJSONForEqual() == afterFirst)
+	assert.True(t, a.Rollback(outer) == nil)
+	assert.True(t, a.DigestJSONForEqual() == "[1]")
+
+	outer = a.Snapshot()
+	inner = a.Snapshot()
+	a.TryAdd(4)
+	assert.True(t, a.DiscardSnapshot(inner) == nil)
+	assert.True(t, a.Rollback(inner) != nil)
+	assert.True(t, a.Rollback(outer) == nil)
+	assert.True(t, a.DigestJSONForEqual() == "[1]")
+	assert.True(t, a.DiscardSnapshot(outer) != nil)
+}
+
+func TestJson_RollbackKeepsObjectIdentity(t *testing.T) {
+	a := NewJSONObject().Set("a", 1)
+	id := a.Snapshot()
+	a.Set("a", 2).Set("b", 3)
+	alias := FromNotEmptySimpleJson(a.ToSimpleJson())
+	assert.True(t, a.Rollback(id) == nil)
+	assert.True(t, alias.DigestJSONForEqual() == `{"a":1}`)
+	snapshotted := a.Snapshot()
+	a.Get("a")
+	a.Set("nested", NewJSONObject().Set("x", 1))
+	assert.True(t, a.Rollback(snapshotted) == nil)
+	assert.True(t, !a.ContainsKey("nested"))
+}
@@ -0,0 +1,120 @@
+package betterjson
+
+import (
+	"github.com/pkg/errors"
+)
+
+// SplitObject partitions the keys of the object j in sorted order into n
+// sub-objects of about the same size, the first ones one key larger when the
+// keys don't divide evenly. there are fewer parts when j has fewer than n
+// keys, and a single empty one for an empty object. the parts are copies,
+// Combine puts them back together. j that isn't an object has no parts
+func (j *Json) SplitObject(n int) []*Json {
+	parts := make([]*Json, 0)
+	object, ok := j.objectValue()
+	if !ok {
+		return parts
+	}
+	keys := sortedKeys(object)
+	for _, bounds := range splitBounds(len(keys), n) {
+		part := make(map[string]interface{}, bounds[1]-bounds[0])
+		for _, key := range keys[bounds[0]:bounds[1]] {
+			part[key] = deepCopyRaw(object[key])
+		}
+		parts = append(parts, j.splitPart(part))
+	}
+	return parts
+}
+
+// SplitArray is SplitObject for the items of an array, keeping their order
+func (j *Json) SplitArray(n int) []*Json {
+	parts := make([]*Json, 0)
+	if j.IsEmpty() {
+		return parts
+	}
+	array, ok := unwrapRaw(j.value.Interface()).([]interface{})
+	if !ok {
+		return parts
+	}
+	for _, bounds := range splitBounds(len(array), n) {
+		parts = append(parts, j.splitPart(deepCopyRaw(array[bounds[0]:bounds[1]])))
+	}
+	return parts
+}
+
+func (j *Json) splitPart(data interface{}) *Json {
+	part := wrapRaw(data)
+	part.settings = j.settings
+	return part
+}
+
+// splitBounds returns the start and end of n chunks of count elements of about
+// the same size, at least one chunk and no empty ones unless count is 0
+func splitBounds(count int, n int) [][2]int {
+	if n > count {
+		n = count
+	}
+	if n < 1 {
+		n = 1
+	}
+	bounds := make([][2]int, 0, n)
+	size, larger := count/n, count%n
+	start := 0
+	for idx := 0; idx < n; idx++ {
+		end := start + size
+		if idx < larger {
+			end++
+		}
+		bounds = append(bounds, [2]int{start, end})
+		start = end
+	}
+	return bounds
+}
+
+// Combine reverses SplitObject and SplitArray: objects are merged into one,
+// arrays concatenated in order. parts of different kinds, other values, and
+// a key in more than one part are errors
+func Combine(parts []*Json) (*Json, error) {
+	if len(parts) == 0 {
+		return NewEmpty(), errors.New("nothing to combine")
+	}
+	first := parts[0]
+	if first == nil || first.IsEmpty() {
+		return NewEmpty(), errors.New("part 0 is empty")
+	}
+	switch unwrapRaw(first.value.Interface()).(type) {
+	case map[string]interface{}:
+		combined := make(map[string]interface{})
+		for idx, part := range parts {
+			if part == nil {
+				return NewEmpty(), errors.Errorf("part %d is not an object like part 0", idx)
+			}
+			object, ok := part.objectValue()
+			if !ok {
+				return NewEmpty(), errors.Errorf("part %d is not an object like part 0", idx)
+			}
+			for key, item := range object {
+				if _, repeated := combined[key]; repeated {
+					return NewEmpty(), errors.Errorf("key %q is in more than one part", key)
+				}
+				combined[key] = deepCopyRaw(item)
+			}
+		}
+		return first.splitPart(combined), nil
+	case []interface{}:
+		combined := make([]interface{}, 0)
+		for idx, part := range parts {
+			var array []interface{}
+			ok := part != nil && !part.IsEmpty()
+			if ok {
+				array, ok = unwrapRaw(part.value.Interface()).([]interface{})
+			}
+			if !ok {
+				return NewEmpty(), errors.Errorf("part %d is not an array like part 0", idx)
+			}
+			combined = append(combined, deepCopyRaw(array).([]interface{})...)
+		}
+		return first.splitPart(combined), nil
+	}
+	return NewEmpty(), errors.Errorf("can't combine %s parts", kindName(first.value.Interface()))
+}
@@ -0,0 +1,65 @@
+package betterjson
+
+import (
+	"strconv"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func partEncodings(parts []*Json) []string {
+	encoded := make([]string, 0, len(parts))
+	for _, part := range parts {
+		encoded = append(encoded, part.EncodeToStringOrDefault("?"))
+	}
+	return encoded
+}
+
+func TestJson_SplitObject(t *testing.T) {
+	js, _ := Parse([]byte(`{"e":5,"a":1,"d":{"x":4},"b":2,"c":[3]}`))
+	parts := js.SplitObject(2)
+	assert.Equal(t, []string{`{"a":1,"b":2,"c":[3]}`, `{"d":{"x":4},"e":5}`}, partEncodings(parts))
+	assert.Equal(t, []string{`{"a":1,"b":2}`, `{"c":[3],"d":{"x":4}}`, `{"e":5}`}, partEncodings(js.SplitObject(3)))
+	assert.Equal(t, 5, len(js.SplitObject(9)))
+	assert.Equal(t, 1, len(js.SplitObject(0)))
+
+	parts[1].Get("d").Set("x", 0)
+	assert.Equal(t, 4, js.GetDottedPath("d.x").MustInt())
+
+	combined, err := Combine(js.SplitObject(3))
+	assert.True(t, err == nil)
+	assert.True(t, combined.IsSameJSONWith(js))
+
+	empty := NewJSONObject()
+	assert.Equal(t, []string{`{}`}, partEncodings(empty.SplitObject(4)))
+	combined, _ = Combine(empty.SplitObject(4))
+	assert.True(t, combined.IsSameJSONWith(empty))
+	assert.Equal(t, 0, len(NewJSONArray().SplitObject(2)))
+}
+
+func TestJson_SplitArray(t *testing.T) {
+	js := BuildArray(10, func(i int) interface{} { return i })
+	assert.Equal(t, []string{`[0,1,2,3]`, `[4,5,6]`, `[7,8,9]`}, partEncodings(js.SplitArray(3)))
+	assert.Equal(t, 10, len(js.SplitArray(25)))
+	for n := 1; n <= 12; n++ {
+		combined, err := Combine(js.SplitArray(n))
+		assert.True(t, err == nil, strconv.Itoa(n))
+		assert.True(t, combined.IsSameJSONWith(js), strconv.Itoa(n))
+	}
+	assert.Equal(t, []string{`[]`}, partEncodings(NewJSONArray().SplitArray(3)))
+	assert.Equal(t, 0, len(NewJSONObject().SplitArray(2)))
+}
+
+func TestCombineErrors(t *testing.T) {
+	_, err := Combine(nil)
+	assert.Equal(t, "nothing to combine", err.Error())
+	_, err = Combine([]*Json{Obj("a", 1), Obj("a", 2)})
+	assert.Equal(t, `key "a" is in more than one part`, err.Error())
+	_, err = Combine([]*Json{Obj("a", 1), Arr(2)})
+	assert.Equal(t, "part 1 is not an object like part 0", err.Error())
+	_, err = Combine([]*Json{Arr(1), nil})
+	assert.Equal(t, "part 1 is not an array like part 0", err.Error())
+	_, err = Combine([]*Json{NewEmpty()})
+	assert.Equal(t, "part 0 is empty", err.Error())
+	_, err = Combine([]*Json{Arr(1).GetIndex(0)})
+	assert.Equal(t, "can't combine number parts", err.Error())
+}
@@ -0,0 +1,111 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// DocumentStats describes the size and shape of a document
+type DocumentStats struct {
+	// MaxDepth is the deepest container nesting: 0 for a scalar, 1 for {"a":1}
+	MaxDepth int
+	// TotalNodes counts every value, containers and leaves alike
+	TotalNodes int
+	ObjectCount int
+	ArrayCount int
+	// StringBytes is the byte length of all string values, keys excluded
+	StringBytes int
+	LargestArrayLen int
+	// EstimatedSize is the approximate encoded byte size, see Json.EstimatedSize
+	EstimatedSize int
+}
+
+type statsFrame struct {
+	node  interface{}
+	depth int
+}
+
+// Stats computes DocumentStats in a single walk. the walk uses an explicit
+// stack, so arbitrarily deep documents don't grow the goroutine stack
+func (j *Json) Stats() DocumentStats {
+	stats := DocumentStats{}
+	if j.IsEmpty() {
+		return stats
+	}
+	stack := []statsFrame{{node: j.value.Interface(), depth: 0}}
+	for len(stack) > 0 {
+		frame := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		stats.TotalNodes++
+		switch node := unwrapRaw(frame.node).(type) {
+		case map[string]interface{}:
+			stats.ObjectCount++
+			depth := frame.depth + 1
+			if depth > stats.MaxDepth {
+				stats.MaxDepth = depth
+			}
+			stats.EstimatedSize += 2
+			if len(node) > 0 {
+				stats.EstimatedSize += len(node) - 1
+			}
+			for key, item := range node {
+				stats.EstimatedSize += len(key) + 3
+				stack = append(stack, statsFrame{node: item, depth: depth})
+			}
+		case []interface{}:
+			stats.ArrayCount++
+			depth := frame.depth + 1
+			if depth > stats.MaxDepth {
+				stats.MaxDepth = depth
+			}
+			if len(node) > stats.LargestArrayLen {
+				stats.LargestArrayLen = len(node)
+			}
+			stats.EstimatedSize += 2
+			if len(node) > 0 {
+				stats.EstimatedSize += len(node) - 1
+			}
+			for _, item := range node {
+				stack = append(stack, statsFrame{node: item, depth: depth})
+			}
+		case string:
+			stats.StringBytes += len(node)
+			stats.EstimatedSize += len(node) + 2
+		default:
+			stats.EstimatedSize += scalarSize(node)
+		}
+	}
+	return stats
+}
+
+// MaxDepth returns the deepest container nesting of j, see DocumentStats
+func (j *Json) MaxDepth() int {
+	return j.Stats().MaxDepth
+}
+
+// EstimatedSize returns the approximate encoded byte size of j without
+// encoding it. string escaping is not accounted for
+func (j *Json) EstimatedSize() int {
+	return j.Stats().EstimatedSize
+}
+
+// scalarSize is the encoded length of a non-string leaf
+func scalarSize(node interface{}) int {
+	switch value := node.(type) {
+	case nil:
+		return 4
+	case bool:
+		if value {
+			return 4
+		}
+		return 5
+	case json.Number:
+		return len(value)
+	case float64:
+		return len(strconv.FormatFloat(value, 'g', -1, 64))
+	case float32:
+		return len(strconv.FormatFloat(float64(value), 'g', -1, 32))
+	}
+	return len(fmt.Sprint(node))
+}
@@ -0,0 +1,34 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/bitly/go-simplejson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_Stats(t *testing.T) {
+	text := `{"name":"zoowii","tags":["a","bc",null],"meta":{"age":18,"ok":true,"items":[[1,2,3,4],{}]}}`
+	a, err := simplejson.NewJson([]byte(text))
+	assert.True(t, err == nil)
+	stats := FromNotEmptySimpleJson(a).Stats()
+	assert.True(t, stats.MaxDepth == 4)
+	assert.True(t, stats.TotalNodes == 16)
+	assert.True(t, stats.ObjectCount == 3)
+	assert.True(t, stats.ArrayCount == 3)
+	assert.True(t, stats.StringBytes == 9)
+	assert.True(t, stats.LargestArrayLen == 4)
+	assert.True(t, stats.EstimatedSize == len(text))
+	assert.True(t, NewEmpty().Stats() == DocumentStats{})
+	assert.True(t, NewEmpty().SetValue("abc").MaxDepth() == 0)
+}
+
+func TestJson_MaxDepthOfDeepDocument(t *testing.T) {
+	var node interface{} = "leaf"
+	for i := 0; i < 10000; i++ {
+		node = []interface{}{node}
+	}
+	a := NewEmpty().SetValue(node)
+	assert.True(t, a.MaxDepth() == 10000)
+	assert.True(t, a.Stats().TotalNodes == 10001)
+	assert.True(t, a.EstimatedSize() == 20000+6)
+}
@@ -0,0 +1,127 @@
+package betterjson
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNumericOverflow is the cause of errors from strict accessors finding
+	// a number out of the range of the type they return
+	ErrNumericOverflow = errors.New("number out of range")
+	// ErrLossyConversion is the cause of errors from strict accessors finding
+	// a number the type they return can't hold exactly
+	ErrLossyConversion = errors.New("number can't be converted exactly")
+)
+
+// maxExactFloat is 2^53, beyond it not every integer is a float64
+const maxExactFloat = 1 << 53
+
+// Int64Strict is Int64 returning an error instead of a truncated or wrapped
+// value when the number isn't an int64 exactly. use errors.Cause to tell
+// ErrNumericOverflow from ErrLossyConversion and ErrTypeMismatch:
+//    count, err := js.Get("count").Int64Strict()
+// a float64 beyond 2^53 is lossy, it may already have been rounded
+func (j *Json) Int64Strict() (int64, error) {
+	i, err := j.strictInteger("int64")
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, j.strictOverflow("int64")
+	}
+	return i.Int64(), nil
+}
+
+// IntStrict is Int64Strict for int
+func (j *Json) IntStrict() (int, error) {
+	i, err := j.strictInteger("int")
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() || i.Int64() < math.MinInt || i.Int64() > math.MaxInt {
+		return 0, j.strictOverflow("int")
+	}
+	return int(i.Int64()), nil
+}
+
+// Uint64Strict is Int64Strict for uint64, negative numbers overflow
+func (j *Json) Uint64Strict() (uint64, error) {
+	i, err := j.strictInteger("uint64")
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsUint64() {
+		return 0, j.strictOverflow("uint64")
+	}
+	return i.Uint64(), nil
+}
+
+// Float64Strict is Float64 returning ErrNumericOverflow for numbers beyond
+// the range of float64, and ErrLossyConversion for numbers with more digits
+// than float64 holds, like 9007199254740993 or 1e-400. a number is exact
+// when it is the value of the shortest literal of its float64, so 0.1 is
+// exact although its float64 isn't exactly one tenth
+func (j *Json) Float64Strict() (float64, error) {
+	number, err := j.strictNumber("float64")
+	if err != nil {
+		return 0, err
+	}
+	f, exact := number.Float64()
+	if exact {
+		return f, nil
+	}
+	if math.IsInf(f, 0) {
+		return 0, j.strictOverflow("float64")
+	}
+	if shortest, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64)); shortest.Cmp(number) != 0 {
+		return 0, errors.Wrapf(ErrLossyConversion, "%s to float64", j.numberLiteral())
+	}
+	return f, nil
+}
+
+// strictNumber returns the exact value of the number j holds
+func (j *Json) strictNumber(target string) (*big.Rat, error) {
+	if j.IsEmpty() {
+		return nil, errors.Wrap(ErrTypeMismatch, "empty json parse to "+target+" failed")
+	}
+	number, ok := rawNumber(j.value.Interface())
+	if !ok {
+		return nil, errors.Wrap(ErrTypeMismatch, kindName(j.value.Interface())+" parse to "+target+" failed")
+	}
+	return number, nil
+}
+
+// strictInteger returns the integer j holds, an error for fractions and for
+// float64 values beyond 2^53
+func (j *Json) strictInteger(target string) (*big.Int, error) {
+	number, err := j.strictNumber(target)
+	if err != nil {
+		return nil, err
+	}
+	if !number.IsInt() {
+		return nil, errors.Wrapf(ErrLossyConversion, "%s has a fractional part, to %s", j.numberLiteral(), target)
+	}
+	if f, ok := j.value.Interface().(float64); ok && math.Abs(f) > maxExactFloat {
+		return nil, errors.Wrapf(ErrLossyConversion, "float %s is beyond 2^53, to %s", j.numberLiteral(), target)
+	}
+	return number.Num(), nil
+}
+
+func (j *Json) strictOverflow(target string) error {
+	return errors.Wrapf(ErrNumericOverflow, "%s doesn't fit in %s", j.numberLiteral(), target)
+}
+
+func (j *Json) numberLiteral() string {
+	switch value := j.value.Interface().(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32)
+	}
+	return fmt.Sprint(j.value.Interface())
+}
@@ -0,0 +1,125 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func strictCause(err error) string {
+	switch errors.Cause(err) {
+	case nil:
+		return "ok"
+	case ErrNumericOverflow:
+		return "overflow"
+	case ErrLossyConversion:
+		return "lossy"
+	case ErrTypeMismatch:
+		return "type"
+	}
+	return err.Error()
+}
+
+func TestInt64Strict(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected int64
+		cause    string
+	}{
+		{json.Number("9223372036854775807"), math.MaxInt64, "ok"},
+		{json.Number("9223372036854775808"), 0, "overflow"},
+		{json.Number("-9223372036854775808"), math.MinInt64, "ok"},
+		{json.Number("-9223372036854775809"), 0, "overflow"},
+		{json.Number("9007199254740993"), 9007199254740993, "ok"},
+		{json.Number("1e3"), 1000, "ok"},
+		{json.Number("1.5"), 0, "lossy"},
+		{json.Number("2.0"), 2, "ok"},
+		{float64(1 << 53), 1 << 53, "ok"},
+		{float64(-(1 << 53)), -(1 << 53), "ok"},
+		{float64(1<<53 + 2), 0, "lossy"},
+		{1e19, 0, "lossy"},
+		{0.5, 0, "lossy"},
+		{int8(-3), -3, "ok"},
+		{uint64(math.MaxUint64), 0, "overflow"},
+		{"12", 0, "type"},
+		{nil, 0, "type"},
+	}
+	for _, c := range cases {
+		i, err := wrapRaw(c.value).Int64Strict()
+		assert.Equal(t, c.cause, strictCause(err), c.value)
+		assert.Equal(t, c.expected, i, c.value)
+	}
+	_, err := NewEmpty().Int64Strict()
+	assert.Equal(t, "type", strictCause(err))
+	_, err = wrapRaw(json.Number("9223372036854775808")).Int64Strict()
+	assert.Equal(t, "9223372036854775808 doesn't fit in int64: number out of range", err.Error())
+	_, err = wrapRaw(1e19).Int64Strict()
+	assert.Equal(t, "float 1e+19 is beyond 2^53, to int64: number can't be converted exactly", err.Error())
+}
+
+func TestUint64Strict(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected uint64
+		cause    string
+	}{
+		{json.Number("18446744073709551615"), math.MaxUint64, "ok"},
+		{json.Number("18446744073709551616"), 0, "overflow"},
+		{json.Number("0"), 0, "ok"},
+		{json.Number("0.30000000000000001"), 0, "lossy"},
+		{json.Number("-1"), 0, "overflow"},
+		{-1, 0, "overflow"},
+		{float64(-1), 0, "overflow"},
+		{json.Number("-0.5"), 0, "lossy"},
+		{float64(1<<53 - 1), 1<<53 - 1, "ok"},
+		{float64(1<<53 + 2), 0, "lossy"},
+		{uint64(math.MaxUint64), math.MaxUint64, "ok"},
+	}
+	for _, c := range cases {
+		u, err := wrapRaw(c.value).Uint64Strict()
+		assert.Equal(t, c.cause, strictCause(err), c.value)
+		assert.Equal(t, c.expected, u, c.value)
+	}
+}
+
+func TestIntStrict(t *testing.T) {
+	i, err := wrapRaw(json.Number("-42")).IntStrict()
+	assert.True(t, err == nil)
+	assert.Equal(t, -42, i)
+	_, err = wrapRaw(json.Number("9223372036854775808")).IntStrict()
+	assert.Equal(t, "overflow", strictCause(err))
+	_, err = wrapRaw(json.Number("3.25")).IntStrict()
+	assert.Equal(t, "lossy", strictCause(err))
+}
+
+func TestFloat64Strict(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected float64
+		cause    string
+	}{
+		{json.Number("0.1"), 0.1, "ok"},
+		{json.Number("9007199254740992"), 1 << 53, "ok"},
+		{json.Number("9007199254740993"), 0, "lossy"},
+		{json.Number("-9007199254740993"), 0, "lossy"},
+		{json.Number("1.7976931348623157e308"), math.MaxFloat64, "ok"},
+		{json.Number("1e309"), 0, "overflow"},
+		{json.Number("-1e309"), 0, "overflow"},
+		{json.Number("1e-400"), 0, "lossy"},
+		{json.Number("0"), 0, "ok"},
+		{json.Number("0.30000000000000001"), 0, "lossy"},
+		{uint64(math.MaxUint64), 0, "lossy"},
+		{int64(1<<53 + 1), 0, "lossy"},
+		{2.5, 2.5, "ok"},
+		{true, 0, "type"},
+	}
+	for _, c := range cases {
+		f, err := wrapRaw(c.value).Float64Strict()
+		assert.Equal(t, c.cause, strictCause(err), c.value)
+		assert.Equal(t, c.expected, f, c.value)
+	}
+	// the lenient accessors are unchanged
+	println(wrapRaw(1e19).MustInt64())
+}
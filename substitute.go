@@ -0,0 +1,138 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MissingVarMode decides what Substitute does with placeholders whose path is
+// missing from vars
+type MissingVarMode int
+
+const (
+	// MissingVarError fails the substitution
+	MissingVarError MissingVarMode = iota
+	// MissingVarKeep leaves the placeholder text as it is
+	MissingVarKeep
+	// MissingVarEmpty replaces the placeholder with the empty string
+	MissingVarEmpty
+)
+
+// SubstituteOptions configures Substitute
+type SubstituteOptions struct {
+	Missing MissingVarMode
+}
+
+// Substitute returns a copy of j where ${dotted.path} placeholders inside
+// string values are replaced by the value at that path of vars:
+//    tpl.Substitute(vars, SubstituteOptions{}) // "hi ${user.name}" => "hi zoowii"
+//
+// a string that is exactly one placeholder takes the value with its json type,
+// so "${user.age}" becomes the number 18 and may become an object or array.
+// inside longer text strings are inserted as is and other values as their json
+// encoding. write "$${" for a literal "${". an unterminated "${" is kept as text.
+// j is left untouched
+func (j *Json) Substitute(vars *Json, opts SubstituteOptions) (*Json, error) {
+	if j.IsEmpty() {
+		return j, errors.New("empty json can't be substituted")
+	}
+	if vars == nil {
+		vars = NewEmpty()
+	}
+	result, err := substituteNode(j.value.Interface(), vars, opts)
+	if err != nil {
+		return NewEmpty(), err
+	}
+	return wrapRaw(result), nil
+}
+
+func substituteNode(node interface{}, vars *Json, opts SubstituteOptions) (interface{}, error) {
+	switch value := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			substituted, err := substituteNode(item, vars, opts)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = substituted
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for idx, item := range value {
+			substituted, err := substituteNode(item, vars, opts)
+			if err != nil {
+				return nil, err
+			}
+			result[idx] = substituted
+		}
+		return result, nil
+	case string:
+		return substituteString(value, vars, opts)
+	default:
+		return value, nil
+	}
+}
+
+func substituteString(s string, vars *Json, opts SubstituteOptions) (interface{}, error) {
+	if strings.HasPrefix(s, "${") && strings.Index(s, "}") == len(s)-1 {
+		item, ok := vars.lookupPath(ParseDottedPath(s[2 : len(s)-1]))
+		if ok {
+			return deepCopyRaw(item.value.Interface()), nil
+		}
+		return missingVar(s, opts)
+	}
+	var buffer bytes.Buffer
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "$${"):
+			buffer.WriteString("${")
+			i += 3
+		case strings.HasPrefix(s[i:], "${"):
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				buffer.WriteString(s[i:])
+				i = len(s)
+				continue
+			}
+			placeholder := s[i : i+end+1]
+			i += end + 1
+			item, ok := vars.lookupPath(ParseDottedPath(placeholder[2 : len(placeholder)-1]))
+			if !ok {
+				replacement, err := missingVar(placeholder, opts)
+				if err != nil {
+					return nil, err
+				}
+				buffer.WriteString(replacement.(string))
+				continue
+			}
+			if text, isString := item.value.Interface().(string); isString {
+				buffer.WriteString(text)
+				continue
+			}
+			encoded, err := json.Marshal(item.value.Interface())
+			if err != nil {
+				return nil, err
+			}
+			buffer.Write(encoded)
+		default:
+			buffer.WriteByte(s[i])
+			i++
+		}
+	}
+	return buffer.String(), nil
+}
+
+func missingVar(placeholder string, opts SubstituteOptions) (interface{}, error) {
+	switch opts.Missing {
+	case MissingVarKeep:
+		return placeholder, nil
+	case MissingVarEmpty:
+		return "", nil
+	}
+	return nil, errors.Errorf("variable %s is missing", placeholder)
+}
@@ -0,0 +1,55 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func substituteVars() *Json {
+	return Obj("user", Obj("name", "zoowii", "age", 18, "vip", true, "tags", Arr("a", "b")), "site", "example.com")
+}
+
+func TestJson_SubstituteText(t *testing.T) {
+	tpl := NewJSONObject()
+	tpl.Set("title", "Hi ${user.name}, welcome to ${site}!").Set("info", "age=${user.age} vip=${user.vip} tags=${user.tags}").Set("literal", "cost: $${price} for ${user.name}").Set("open", "tail ${user.name")
+	result, err := tpl.Substitute(substituteVars(), SubstituteOptions{})
+	assert.True(t, err == nil)
+	resultStr, err := result.EncodeToString()
+	assert.True(t, err == nil)
+	println(resultStr)
+	assert.True(t, result.Get("title").MustString() == "Hi zoowii, welcome to example.com!")
+	assert.True(t, result.Get("info").MustString() == `age=18 vip=true tags=["a","b"]`)
+	assert.True(t, result.Get("literal").MustString() == "cost: ${price} for zoowii")
+	assert.True(t, result.Get("open").MustString() == "tail ${user.name")
+	assert.True(t, tpl.Get("title").MustString() == "Hi ${user.name}, welcome to ${site}!")
+}
+
+func TestJson_SubstituteTypedValues(t *testing.T) {
+	tpl := Obj("age", "${user.age}", "vip", "${user.vip}", "items", Arr("${user.tags}", 1, "${user.name}"))
+	vars := substituteVars()
+	result, err := tpl.Substitute(vars, SubstituteOptions{})
+	assert.True(t, err == nil)
+	resultStr, err := result.EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, resultStr == `{"age":18,"items":[["a","b"],1,"zoowii"],"vip":true}`)
+	result.GetPath("items").GetIndex(0).TryAdd("c")
+	assert.True(t, vars.GetPath("user", "tags").ArrayLength() == 2)
+}
+
+func TestJson_SubstituteMissing(t *testing.T) {
+	tpl := NewJSONObject()
+	tpl.Set("whole", "${nope}").Set("text", "x=${user.nope}.")
+	_, err := tpl.Substitute(substituteVars(), SubstituteOptions{})
+	assert.True(t, err != nil)
+	println(err.Error())
+	kept, err := tpl.Substitute(substituteVars(), SubstituteOptions{Missing: MissingVarKeep})
+	assert.True(t, err == nil)
+	assert.True(t, kept.Get("whole").MustString() == "${nope}")
+	assert.True(t, kept.Get("text").MustString() == "x=${user.nope}.")
+	emptied, err := tpl.Substitute(substituteVars(), SubstituteOptions{Missing: MissingVarEmpty})
+	assert.True(t, err == nil)
+	assert.True(t, emptied.Get("whole").MustString() == "")
+	assert.True(t, emptied.Get("text").MustString() == "x=.")
+	_, err = NewEmpty().Substitute(substituteVars(), SubstituteOptions{})
+	assert.True(t, err != nil)
+}
@@ -0,0 +1,94 @@
+package betterjson
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ChildKeysAt returns the sorted keys of the object at branch, or the indexes
+// of the array there as strings. segments may be array indexes like
+// GetDottedPath's
+func (j *Json) ChildKeysAt(branch ...string) ([]string, error) {
+	node, ok := j.lookupPath(branch)
+	if !ok {
+		return nil, errors.Errorf("path %s doesn't exist", displayPath(branch))
+	}
+	switch container := unwrapRaw(node.value.Interface()).(type) {
+	case map[string]interface{}:
+		return sortedKeys(container), nil
+	case []interface{}:
+		indexes := make([]string, len(container))
+		for idx := range container {
+			indexes[idx] = strconv.Itoa(idx)
+		}
+		return indexes, nil
+	}
+	return nil, errors.Errorf("%s at %s has no child keys", kindName(node.value.Interface()), displayPath(branch))
+}
+
+// SuggestPaths returns up to limit of the leaf paths of Paths that start with
+// prefix, in document order: object keys sorted, array items by index. a limit
+// of 0 or less returns all of them. only the parts of j that may hold such
+// paths are walked, so completing a prefix is fast in large documents:
+//    js.SuggestPaths("users.1.", 10) // [users.1.email users.1.name ...]
+func (j *Json) SuggestPaths(prefix string, limit int) []string {
+	suggestions := make([]string, 0)
+	if j.IsEmpty() {
+		return suggestions
+	}
+	s := &suggester{prefix: prefix, limit: limit, suggestions: suggestions}
+	s.walk(j.value.Interface(), "", true)
+	return s.suggestions
+}
+
+type suggester struct {
+	prefix      string
+	limit       int
+	suggestions []string
+}
+
+func (s *suggester) full() bool {
+	return s.limit > 0 && len(s.suggestions) >= s.limit
+}
+
+// walk visits node at the dotted path, returning false once enough
+// suggestions are found
+func (s *suggester) walk(node interface{}, path string, root bool) bool {
+	switch container := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(container) {
+			if !s.visit(container[key], s.childPath(path, JoinDottedPath([]string{key}), root)) {
+				return false
+			}
+		}
+	case []interface{}:
+		for idx, item := range container {
+			if !s.visit(item, s.childPath(path, strconv.Itoa(idx), root)) {
+				return false
+			}
+		}
+	default:
+		if !root && strings.HasPrefix(path, s.prefix) {
+			s.suggestions = append(s.suggestions, path)
+		}
+	}
+	return !s.full()
+}
+
+// visit walks a child unless neither it nor its descendants can start with
+// the prefix
+func (s *suggester) visit(node interface{}, path string) bool {
+	if !strings.HasPrefix(path, s.prefix) && !strings.HasPrefix(s.prefix, path) {
+		return true
+	}
+	return s.walk(node, path, false)
+}
+
+func (s *suggester) childPath(path string, segment string, root bool) string {
+	if root {
+		return segment
+	}
+	return path + "." + segment
+}
@@ -0,0 +1,74 @@
+package betterjson
+
+import (
+	"strconv"
+	"testing"
+	"time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_ChildKeysAt(t *testing.T) {
+	js, _ := Parse([]byte(`{"users":[{"name":"a","email":"e"},{"id":2}],"b.c":1,"empty":{}}`))
+	keys, err := js.ChildKeysAt()
+	assert.True(t, err == nil)
+	assert.Equal(t, []string{"b.c", "empty", "users"}, keys)
+	keys, _ = js.ChildKeysAt("users")
+	assert.Equal(t, []string{"0", "1"}, keys)
+	keys, _ = js.ChildKeysAt("users", "0")
+	assert.Equal(t, []string{"email", "name"}, keys)
+	keys, _ = js.ChildKeysAt("empty")
+	assert.Equal(t, []string{}, keys)
+
+	_, err = js.ChildKeysAt("users", "2")
+	assert.Equal(t, "path users.2 doesn't exist", err.Error())
+	_, err = js.ChildKeysAt("b.c")
+	println(err.Error())
+	assert.Equal(t, `number at b\.c has no child keys`, err.Error())
+}
+
+func TestJson_SuggestPaths(t *testing.T) {
+	js, _ := Parse([]byte(`{"users":[{"name":"a","email":"e"},{"id":2}],"user":"x","user.x":1,"usage":{"cpu":1},"a":{"b":1},"ab":2}`))
+	assert.Equal(t, []string{"a.b", "ab", "usage.cpu", "user", `user\.x`, "users.0.email", "users.0.name", "users.1.id"}, js.SuggestPaths("", 0))
+	assert.Equal(t, []string{"usage.cpu", "user", `user\.x`, "users.0.email", "users.0.name", "users.1.id"}, js.SuggestPaths("us", 0))
+	assert.Equal(t, []string{"users.0.email", "users.0.name", "users.1.id"}, js.SuggestPaths("users", 0))
+	assert.Equal(t, []string{"users.0.email", "users.0.name", "users.1.id"}, js.SuggestPaths("users.", 0))
+	assert.Equal(t, []string{"users.1.id"}, js.SuggestPaths("users.1", 0))
+	assert.Equal(t, []string{"users.0.email"}, js.SuggestPaths("users.0.e", 0))
+	assert.Equal(t, []string{"a.b"}, js.SuggestPaths("a.", 0))
+	assert.Equal(t, []string{"a.b", "ab"}, js.SuggestPaths("a", 0))
+	assert.Equal(t, []string{"usage.cpu", "user"}, js.SuggestPaths("us", 2))
+	assert.Equal(t, []string{}, js.SuggestPaths("users.2", 0))
+	assert.Equal(t, []string{}, NewEmpty().SuggestPaths("", 0))
+	assert.Equal(t, []string{}, Arr(1).GetIndex(0).SuggestPaths("", 0))
+}
+
+func suggestFixture() *Json {
+	js := NewJSONObject()
+	for i := 0; i < 500; i++ {
+		items := NewJSONArray()
+		for k := 0; k < 20; k++ {
+			items.TryAdd(Obj("id", k, "name", "n", "tags", Arr("a", "b", "c")))
+		}
+		js.Set("group"+strconv.Itoa(i), items)
+	}
+	return js
+}
+
+func TestJson_SuggestPathsLargeDocument(t *testing.T) {
+	js := suggestFixture()
+	assert.Equal(t, 50000, len(js.Paths()))
+	start := time.Now()
+	suggestions := js.SuggestPaths("group42.1", 5)
+	elapsed := time.Since(start)
+	println(elapsed.String())
+	assert.Equal(t, []string{"group42.1.id", "group42.1.name", "group42.1.tags.0", "group42.1.tags.1", "group42.1.tags.2"}, suggestions)
+	assert.True(t, elapsed < 50*time.Millisecond)
+}
+
+func BenchmarkJson_SuggestPaths(b *testing.B) {
+	js := suggestFixture()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		js.SuggestPaths("group42.1", 10)
+	}
+}
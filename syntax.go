@@ -0,0 +1,122 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetRadius is how many bytes of input Snippet shows on each side of the
+// failure point
+const snippetRadius = 40
+
+// SyntaxError is malformed json input, located in it when the parse
+// function had the whole input
+type SyntaxError struct {
+	// Line and Column are 1-based, Column counts bytes. both are 0 when the
+	// input wasn't available, like for ParseReaderWithOptions
+	Line   int
+	Column int
+	// Offset is the input byte offset of the failure
+	Offset int64
+	// Err is the error of encoding/json
+	Err     error
+	snippet string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s at offset %d", e.Err.Error(), e.Offset)
+	}
+	return fmt.Sprintf("%s at line %d column %d", e.Err.Error(), e.Line, e.Column)
+}
+
+// Cause returns the error of encoding/json, for errors.Cause
+func (e *SyntaxError) Cause() error {
+	return e.Err
+}
+
+// Unwrap is Cause for errors.Is and errors.As
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// Snippet shows the input around the failure with a caret under it:
+//    {"id": 1, "name": x}
+//                      ^
+func (e *SyntaxError) Snippet() string {
+	return e.snippet
+}
+
+// Snippet is SyntaxError.Snippet for the point where the limit was exceeded
+func (e *LimitError) Snippet() string {
+	return e.snippet
+}
+
+// Snippet is SyntaxError.Snippet for the repeated key
+func (e *DuplicateKeyError) Snippet() string {
+	return e.snippet
+}
+
+// annotateParseError adds the line, column and snippet of data to the errors
+// of parsing it
+func annotateParseError(err error, data []byte) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *json.SyntaxError:
+		offset := e.Offset - 1
+		if offset < 0 {
+			offset = 0
+		}
+		syntaxErr := &SyntaxError{Offset: offset, Err: e}
+		syntaxErr.Line, syntaxErr.Column, syntaxErr.snippet = locate(data, offset)
+		return syntaxErr
+	case *SyntaxError:
+		e.Line, e.Column, e.snippet = locate(data, e.Offset)
+	case *LimitError:
+		e.Line, e.Column, e.snippet = locate(data, e.Offset)
+	case *DuplicateKeyError:
+		e.Line, e.Column, e.snippet = locate(data, e.Offset)
+	}
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		syntaxErr := &SyntaxError{Offset: int64(len(data)), Err: err}
+		syntaxErr.Line, syntaxErr.Column, syntaxErr.snippet = locate(data, syntaxErr.Offset)
+		return syntaxErr
+	}
+	return err
+}
+
+// locate returns the line and column of offset in data, and the snippet
+// of its line around it
+func locate(data []byte, offset int64) (int, int, string) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := 1 + strings.Count(string(before), "\n")
+	lineStart := strings.LastIndexByte(string(before), '\n') + 1
+	column := int(offset) - lineStart + 1
+	lineEnd := len(data)
+	if idx := strings.IndexByte(string(data[offset:]), '\n'); idx >= 0 {
+		lineEnd = int(offset) + idx
+	}
+	start, end := int(offset)-snippetRadius, int(offset)+snippetRadius
+	if start < lineStart {
+		start = lineStart
+	}
+	if end > lineEnd {
+		end = lineEnd
+	}
+	for start < int(offset) && !utf8.RuneStart(data[start]) {
+		start++
+	}
+	for end < lineEnd && !utf8.RuneStart(data[end]) {
+		end++
+	}
+	excerpt := strings.Replace(strings.TrimRight(string(data[start:end]), "\r"), "\t", " ", -1)
+	caret := strings.Repeat(" ", utf8.RuneCount(data[start:offset])) + "^"
+	return line, column, excerpt + "\n" + caret
+}
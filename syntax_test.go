@@ -0,0 +1,102 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertSyntaxError(t *testing.T, err error) *SyntaxError {
+	assert.True(t, err != nil)
+	syntaxErr, ok := err.(*SyntaxError)
+	assert.True(t, ok, "expected *SyntaxError, got %T", err)
+	return syntaxErr
+}
+
+func TestSyntaxErrorFirstLine(t *testing.T) {
+	_, err := Parse([]byte(`{"id": 1, "name": x}`))
+	syntaxErr := assertSyntaxError(t, err)
+	println(syntaxErr.Error())
+	println(syntaxErr.Snippet())
+	assert.Equal(t, 1, syntaxErr.Line)
+	assert.Equal(t, 19, syntaxErr.Column)
+	assert.Equal(t, int64(18), syntaxErr.Offset)
+	assert.Equal(t, "{\"id\": 1, \"name\": x}\n                  ^", syntaxErr.Snippet())
+	_, isJSONErr := errors.Cause(err).(*json.SyntaxError)
+	assert.True(t, isJSONErr)
+}
+
+func TestSyntaxErrorMiddleLine(t *testing.T) {
+	data := "{\n  \"a\": [1,\n    2 x],\n  \"b\": true\n}"
+	_, err := Parse([]byte(data))
+	syntaxErr := assertSyntaxError(t, err)
+	println(syntaxErr.Snippet())
+	assert.Equal(t, 3, syntaxErr.Line)
+	assert.Equal(t, 7, syntaxErr.Column)
+	assert.Equal(t, "    2 x],\n      ^", syntaxErr.Snippet())
+	assert.True(t, strings.HasSuffix(syntaxErr.Error(), "at line 3 column 7"))
+}
+
+func TestSyntaxErrorLongLine(t *testing.T) {
+	items := make([]string, 0, 500)
+	for i := 0; i < 500; i++ {
+		items = append(items, `"item"`)
+	}
+	data := `{"items":[` + strings.Join(items, ",") + `,}`
+	_, err := Parse([]byte(data))
+	syntaxErr := assertSyntaxError(t, err)
+	println(syntaxErr.Snippet())
+	assert.Equal(t, 1, syntaxErr.Line)
+	assert.Equal(t, len(data), syntaxErr.Column)
+	lines := strings.Split(syntaxErr.Snippet(), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Equal(t, data[len(data)-1-snippetRadius:], lines[0])
+	assert.Equal(t, strings.Repeat(" ", snippetRadius)+"^", lines[1])
+}
+
+func TestSyntaxErrorTruncatedAndTrailing(t *testing.T) {
+	_, err := Parse([]byte("{\n\"a\": [1, 2"))
+	syntaxErr := assertSyntaxError(t, err)
+	assert.Equal(t, 2, syntaxErr.Line)
+	assert.Equal(t, 11, syntaxErr.Column)
+
+	_, err = Parse([]byte(`{"a":1} {"b":2}`))
+	syntaxErr = assertSyntaxError(t, err)
+	assert.Equal(t, 1, syntaxErr.Line)
+	assert.Equal(t, 8, syntaxErr.Column)
+	assert.True(t, strings.HasPrefix(syntaxErr.Error(), "invalid data after top-level value"))
+
+	_, err = ParseReaderWithOptions(strings.NewReader(`{"a":1} {"b":2}`), ParseOptions{})
+	syntaxErr = assertSyntaxError(t, err)
+	assert.Equal(t, 0, syntaxErr.Line)
+	assert.True(t, strings.HasSuffix(syntaxErr.Error(), "at offset 7"))
+}
+
+func TestSyntaxErrorMultibyteSnippet(t *testing.T) {
+	_, err := Parse([]byte(`{"名前": "値", "x": ?}`))
+	syntaxErr := assertSyntaxError(t, err)
+	assert.Equal(t, "{\"名前\": \"値\", \"x\": ?}\n                 ^", syntaxErr.Snippet())
+}
+
+func TestLimitAndDuplicateErrorsAreLocated(t *testing.T) {
+	data := "{\n  \"items\": [\n    [[[[1]]]]\n  ]\n}"
+	_, err := ParseWithOptions([]byte(data), ParseOptions{MaxDepth: 3})
+	limitErr, ok := err.(*LimitError)
+	assert.True(t, ok)
+	println(limitErr.Error())
+	println(limitErr.Snippet())
+	assert.Equal(t, 3, limitErr.Line)
+	assert.True(t, limitErr.Column > 0)
+	assert.True(t, strings.HasPrefix(limitErr.Snippet(), "    [[[[1]]]]\n"))
+
+	data = "{\n  \"id\": 1,\n  \"id\": 2\n}"
+	_, err = ParseWithOptions([]byte(data), ParseOptions{RejectDuplicateKeys: true})
+	duplicateErr, ok := err.(*DuplicateKeyError)
+	assert.True(t, ok)
+	println(duplicateErr.Error())
+	println(duplicateErr.Snippet())
+	assert.Equal(t, 3, duplicateErr.Line)
+	assert.True(t, strings.HasPrefix(duplicateErr.Snippet(), "  \"id\": 2\n"))
+}
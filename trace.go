@@ -0,0 +1,132 @@
+package betterjson
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// AccessTrace collects the paths read through a wrapper returned by Traced
+type AccessTrace struct {
+	root   *Json
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+// Traced returns a wrapper of j's data that records the dotted path of every
+// value read through it, or through wrappers derived from it by Get, GetPath,
+// GetIndex, CheckGet, Select, Children, MapChildren or Walk, into the returned
+// AccessTrace. path reads like GetDottedPath, StringAt and Pluck record the
+// values on the path, Search records the whole document it searches. j
+// itself isn't traced. running code against a traced fixture
+// shows the fields it never reads:
+//    traced, trace := fixture.Traced()
+//    handler(traced)
+//    log.Println(trace.Paths())
+func (j *Json) Traced() (*Json, *AccessTrace) {
+	traced := NewEmpty()
+	if !j.IsEmpty() {
+		traced = FromNotEmptySimpleJson(j.value)
+	}
+	traced.settings = j.settings
+	traced.lazy = j.lazy
+	trace := &AccessTrace{root: traced, counts: make(map[string]int)}
+	traced.trace = trace
+	return traced, trace
+}
+
+// record adds the path of j relative to the traced wrapper
+func (trace *AccessTrace) record(j *Json) {
+	branch := trace.branchOf(j)
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	trace.counts[JoinDottedPath(branch)]++
+}
+
+// branchOf is the branch of j relative to the traced wrapper
+func (trace *AccessTrace) branchOf(j *Json) []string {
+	branch := make([]string, 0, 8)
+	for node := j; node != nil && node != trace.root; node = node.parent {
+		branch = append(branch, node.parentKey)
+	}
+	for left, right := 0, len(branch)-1; left < right; left, right = left+1, right-1 {
+		branch[left], branch[right] = branch[right], branch[left]
+	}
+	return branch
+}
+
+// recordBranch adds the paths read by following branch from node, j's data,
+// up to the first one missing
+func (trace *AccessTrace) recordBranch(j *Json, node interface{}, branch []string) {
+	trace.recordFrom(trace.branchOf(j), node, branch)
+}
+
+func (trace *AccessTrace) recordFrom(current []string, node interface{}, branch []string) {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	for _, segment := range branch {
+		current = append(current, segment)
+		trace.counts[JoinDottedPath(current)]++
+		next, ok := childValue(node, segment)
+		if !ok {
+			return
+		}
+		node = next
+	}
+}
+
+// recordAll adds the paths of node, j's data, and of everything below it, for
+// reads like Search that may look at any of them
+func (trace *AccessTrace) recordAll(j *Json, node interface{}) {
+	branch := trace.branchOf(j)
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	var visit func(node interface{}, branch []string)
+	visit = func(node interface{}, branch []string) {
+		if len(branch) > 0 {
+			trace.counts[JoinDottedPath(branch)]++
+		}
+		for _, segment := range childSegments(unwrapRaw(node), nil) {
+			child, _ := childValue(node, segment)
+			visit(child, append(branch[:len(branch):len(branch)], segment))
+		}
+	}
+	visit(node, branch)
+}
+
+// recordItem records following path in item idx of the j array
+func (j *Json) recordItem(idx int, item interface{}, path []string) {
+	if j.trace == nil {
+		return
+	}
+	itemBranch := append(j.trace.branchOf(j), strconv.Itoa(idx))
+	j.trace.mutex.Lock()
+	j.trace.counts[JoinDottedPath(itemBranch)]++
+	j.trace.mutex.Unlock()
+	j.trace.recordFrom(itemBranch, item, path)
+}
+
+// Paths returns the sorted dotted paths read so far, missing keys included.
+// reading a value also reads its containers, so they are listed too
+func (trace *AccessTrace) Paths() []string {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	paths := make([]string, 0, len(trace.counts))
+	for path := range trace.counts {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ToJson returns an object mapping the paths read so far to how often each
+// one was read
+func (trace *AccessTrace) ToJson() *Json {
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+	result := NewJSONObject()
+	for path, count := range trace.counts {
+		result.Set(path, count)
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraced(t *testing.T) {
+	fixture, _ := Parse([]byte(`{"id":1,"name":"a","legacy":"x","owner":{"team":"core","email":"e"},"items":[{"sku":"s1","price":1},{"sku":"s2","price":2}]}`))
+	traced, trace := fixture.Traced()
+	assert.True(t, traced.IsSameJSONWith(fixture))
+
+	assert.Equal(t, int64(1), traced.Get("id").MustInt64())
+	assert.Equal(t, "core", traced.GetPath("owner", "team").MustString())
+	items, _ := traced.Get("items").Children()
+	for _, item := range items {
+		item.Get("sku").MustString()
+	}
+	traced.Get("items").GetIndex(1).Get("price").MustInt64()
+	traced.Get("missing")
+	assert.Equal(t, []string{"id", "items", "items.0", "items.0.sku", "items.1", "items.1.price", "items.1.sku", "missing", "owner", "owner.team"}, trace.Paths())
+	assert.Equal(t, int64(2), trace.ToJson().Get("items").MustInt64())
+	assert.Equal(t, int64(2), trace.ToJson().Get("items.1").MustInt64())
+	println(trace.ToJson().EncodeToStringOrDefault(""))
+
+	// mutations through the traced wrapper change the document
+	traced.Get("owner").Set("team", "infra")
+	assert.Equal(t, "infra", fixture.GetPath("owner", "team").MustString())
+}
+
+func TestTracedLeavesOtherWrappersAlone(t *testing.T) {
+	fixture, _ := Parse([]byte(`{"a":{"b":1}}`))
+	_, trace := fixture.Traced()
+	fixture.Get("a").Get("b")
+	assert.True(t, fixture.Get("a").trace == nil)
+	assert.Equal(t, 0, len(trace.Paths()))
+
+	_, trace = NewEmpty().Traced()
+	assert.Equal(t, "{}", trace.ToJson().EncodeToStringOrDefault(""))
+}
+
+func tracedPathsFixture() *Json {
+	fixture, _ := Parse([]byte(`{"a":{"b":1},"c":"s","d":2,"e":true,"f":{"g":1.5},"h":[{"id":1,"x":0},{"id":2}],"unread":1}`))
+	return fixture
+}
+
+func TestTracedPathReads(t *testing.T) {
+	traced, trace := tracedPathsFixture().Traced()
+	traced.GetDottedPath("a.b")
+	traced.ContainsKeyPath("a", "missing", "deeper")
+	traced.TypeAt("d")
+	traced.Coalesce([]string{"nope"}, []string{"e"})
+	assert.Equal(t, []string{"a", "a.b", "a.missing", "d", "e", "nope"}, trace.Paths())
+}
+
+func TestTracedGetterReads(t *testing.T) {
+	traced, trace := tracedPathsFixture().Traced()
+	assert.Equal(t, "s", traced.StringAt("", "c"))
+	assert.Equal(t, 2, traced.IntAt(0, "d"))
+	assert.Equal(t, true, traced.BoolAt(false, "e"))
+	assert.Equal(t, 1.5, traced.Get("f").Float64At(0, "g"))
+	assert.Equal(t, []string{"c", "d", "e", "f", "f.g"}, trace.Paths())
+}
+
+func TestTracedQueryReads(t *testing.T) {
+	traced, trace := tracedPathsFixture().Traced()
+	traced.Get("h").Pluck("id")
+	assert.Equal(t, []string{"h", "h.0", "h.0.id", "h.1", "h.1.id"}, trace.Paths())
+
+	traced, trace = tracedPathsFixture().Traced()
+	ids, err := traced.Get("h").PluckInts("id")
+	assert.True(t, err == nil)
+	assert.Equal(t, []int{1, 2}, ids)
+	traced.Get("h").SelectMany("x")
+	assert.Equal(t, []string{"h", "h.0", "h.0.id", "h.0.x", "h.1", "h.1.id", "h.1.x"}, trace.Paths())
+
+	traced, trace = tracedPathsFixture().Traced()
+	traced.Get("h").Where(NewJSONObject().Set("x", 0))
+	assert.Equal(t, []string{"h", "h.0", "h.0.x", "h.1", "h.1.x"}, trace.Paths())
+}
+
+func TestTracedSearchReads(t *testing.T) {
+	traced, trace := tracedPathsFixture().Traced()
+	result, err := traced.Get("f").Search("g")
+	assert.True(t, err == nil)
+	assert.Equal(t, 1.5, result.MustFloat64())
+	assert.Equal(t, []string{"f", "f.g"}, trace.Paths())
+	_, err = traced.Search("h[0].id")
+	assert.True(t, err == nil)
+	// Search may read anything below its receiver
+	assert.Equal(t, []string{"a", "a.b", "c", "d", "e", "f", "f.g", "h", "h.0", "h.0.id", "h.0.x", "h.1", "h.1.id", "unread"}, trace.Paths())
+}
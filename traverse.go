@@ -0,0 +1,152 @@
+package betterjson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TraversalProblem is the reason of a TraversalError
+type TraversalProblem string
+
+const (
+	// TraversalNotObject is a key looked up in a value that isn't an object
+	TraversalNotObject TraversalProblem = "not an object"
+	// TraversalNotArray is an index looked up in a value that isn't an array
+	TraversalNotArray TraversalProblem = "not an array"
+	// TraversalOutOfRange is an index beyond the items of an array
+	TraversalOutOfRange TraversalProblem = "index out of range"
+	// TraversalMissingKey is a key an object doesn't have
+	TraversalMissingKey TraversalProblem = "missing key"
+)
+
+// TraversalError is a strict lookup that failed because the document doesn't
+// have the shape the caller expected
+type TraversalError struct {
+	// Path is the branch, from the root of the document, of the value the
+	// lookup of Segment failed on
+	Path    []string
+	Segment string
+	Problem TraversalProblem
+	// Kind is the kind of the value at Path
+	Kind Kind
+}
+
+func (e *TraversalError) Error() string {
+	switch e.Problem {
+	case TraversalOutOfRange:
+		return fmt.Sprintf("index %s out of range of array at %s", e.Segment, displayPath(e.Path))
+	case TraversalMissingKey:
+		return fmt.Sprintf("key %q is missing in object at %s", e.Segment, displayPath(e.Path))
+	case TraversalNotArray:
+		return fmt.Sprintf("can't get index %s of %s at %s", e.Segment, e.Kind, displayPath(e.Path))
+	}
+	return fmt.Sprintf("can't get key %q of %s at %s", e.Segment, e.Kind, displayPath(e.Path))
+}
+
+// GetStrict is Get failing with a TraversalError, instead of returning a
+// null Json, when j isn't an object or doesn't have key:
+//    server, err := js.GetStrict("server")
+func (j *Json) GetStrict(key string) (*Json, error) {
+	if err := j.keyProblem(key, true); err != nil {
+		return NewEmpty(), err
+	}
+	return j.Get(key), nil
+}
+
+// GetIndexStrict is GetIndex failing with a TraversalError when j isn't an
+// array or index is out of range
+func (j *Json) GetIndexStrict(index int) (*Json, error) {
+	if err := j.indexProblem(index); err != nil {
+		return NewEmpty(), err
+	}
+	return j.GetIndex(index), nil
+}
+
+// GetPathStrict resolves branch like GetDottedPath, segments are keys of
+// objects or indexes of arrays, failing with a TraversalError at the first
+// segment that doesn't fit the document
+func (j *Json) GetPathStrict(branch ...string) (*Json, error) {
+	current := j
+	for _, segment := range branch {
+		var err error
+		if _, isArray := current.arrayValue(); isArray {
+			index, ok := arrayIndexSegment(segment)
+			if !ok {
+				return NewEmpty(), current.traversalError(segment, TraversalNotObject)
+			}
+			current, err = current.GetIndexStrict(index)
+		} else {
+			current, err = current.GetStrict(segment)
+		}
+		if err != nil {
+			return NewEmpty(), err
+		}
+	}
+	return current, nil
+}
+
+// keyProblem returns the TraversalError of looking up key in j, if any.
+// a missing key is one only when missing is set
+func (j *Json) keyProblem(key string, missing bool) error {
+	object, ok := j.objectValue()
+	if !ok {
+		return j.traversalError(key, TraversalNotObject)
+	}
+	if _, ok := object[j.documentKey(key)]; !ok && missing {
+		return j.traversalError(key, TraversalMissingKey)
+	}
+	return nil
+}
+
+func (j *Json) indexProblem(index int) error {
+	segment := strconv.Itoa(index)
+	array, ok := j.arrayValue()
+	if !ok {
+		return j.traversalError(segment, TraversalNotArray)
+	}
+	if index < 0 || index >= len(array) {
+		return j.traversalError(segment, TraversalOutOfRange)
+	}
+	return nil
+}
+
+func (j *Json) arrayValue() ([]interface{}, bool) {
+	if j.IsEmpty() {
+		return nil, false
+	}
+	array, ok := j.value.Interface().([]interface{})
+	return array, ok
+}
+
+func (j *Json) traversalError(segment string, problem TraversalProblem) error {
+	kind := KindMissing
+	if !j.IsEmpty() {
+		kind = Kind(kindName(j.value.Interface()))
+	}
+	return &TraversalError{Path: j.documentPath(), Segment: segment, Problem: problem, Kind: kind}
+}
+
+// documentPath is the branch of j from the root of the document it was
+// derived from
+func (j *Json) documentPath() []string {
+	path := make([]string, 0)
+	for node := j; node.parent != nil; node = node.parent {
+		path = append(path, node.parentKey)
+	}
+	for a, b := 0, len(path)-1; a < b; a, b = a+1, b-1 {
+		path[a], path[b] = path[b], path[a]
+	}
+	return path
+}
+
+// strictTraversal reports whether the document has the StrictTraversal option
+func (j *Json) strictTraversal() bool {
+	return j.settings != nil && j.settings.options.StrictTraversal
+}
+
+// recordTraversal records a problem of a lenient lookup for LastError
+func (j *Json) recordTraversal(problem error) {
+	if problem != nil {
+		j.recordError(problem)
+	}
+}
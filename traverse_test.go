@@ -0,0 +1,83 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func strictDocument() *Json {
+	js, _ := Parse([]byte(`{"name":"svc","ports":[80,443],"db":{"host":"h","replicas":[{"host":"r1"}]}}`))
+	return js
+}
+
+func TestGetStrict(t *testing.T) {
+	js := strictDocument()
+	db, err := js.GetStrict("db")
+	assert.True(t, err == nil)
+	host, err := db.GetStrict("host")
+	assert.True(t, err == nil)
+	assert.Equal(t, "h", host.MustString())
+
+	_, err = js.Get("name").GetStrict("first")
+	var traversal *TraversalError
+	assert.True(t, errors.As(err, &traversal))
+	assert.Equal(t, TraversalNotObject, traversal.Problem)
+	assert.Equal(t, KindString, traversal.Kind)
+	assert.Equal(t, []string{"name"}, traversal.Path)
+	assert.Equal(t, `can't get key "first" of string at name`, err.Error())
+
+	_, err = js.Get("ports").GetStrict("first")
+	assert.Equal(t, `can't get key "first" of array at ports`, err.Error())
+	_, err = db.GetStrict("port")
+	assert.Equal(t, `key "port" is missing in object at db`, err.Error())
+	_, err = NewEmpty().GetStrict("a")
+	assert.Equal(t, `can't get key "a" of missing at <root>`, err.Error())
+}
+
+func TestGetIndexStrict(t *testing.T) {
+	js := strictDocument()
+	port, err := js.Get("ports").GetIndexStrict(1)
+	assert.True(t, err == nil)
+	assert.Equal(t, int64(443), port.MustInt64())
+
+	_, err = js.Get("ports").GetIndexStrict(2)
+	assert.Equal(t, "index 2 out of range of array at ports", err.Error())
+	_, err = js.Get("ports").GetIndexStrict(-1)
+	assert.Equal(t, TraversalOutOfRange, err.(*TraversalError).Problem)
+	_, err = js.Get("db").GetIndexStrict(0)
+	assert.Equal(t, "can't get index 0 of object at db", err.Error())
+	_, err = js.Get("db").Get("host").GetIndexStrict(0)
+	assert.Equal(t, KindString, err.(*TraversalError).Kind)
+	assert.Equal(t, []string{"db", "host"}, err.(*TraversalError).Path)
+}
+
+func TestGetPathStrict(t *testing.T) {
+	js := strictDocument()
+	host, err := js.GetPathStrict("db", "replicas", "0", "host")
+	assert.True(t, err == nil)
+	assert.Equal(t, "r1", host.MustString())
+	_, err = js.GetPathStrict("db", "replicas", "1", "host")
+	assert.Equal(t, "index 1 out of range of array at db.replicas", err.Error())
+	_, err = js.GetPathStrict("db", "replicas", "first")
+	assert.Equal(t, `can't get key "first" of array at db.replicas`, err.Error())
+	_, err = js.GetPathStrict("db", "host", "name")
+	assert.Equal(t, `can't get key "name" of string at db.host`, err.Error())
+	root, err := js.GetPathStrict()
+	assert.True(t, err == nil && root == js)
+}
+
+func TestStrictTraversalOption(t *testing.T) {
+	js := strictDocument()
+	js.Get("name").Get("first")
+	assert.True(t, js.LastError() == nil)
+
+	js.WithOptions(Options{PanicOnMust: true, StrictTraversal: true})
+	assert.True(t, js.Get("name").Get("first").IsNull())
+	assert.Equal(t, `can't get key "first" of string at name`, js.LastError().Error())
+	js.Get("ports").GetIndex(5)
+	assert.Equal(t, "index 5 out of range of array at ports", js.LastError().Error())
+	// missing keys aren't shape mismatches
+	js.GetPath("db", "port")
+	assert.Equal(t, "index 5 out of range of array at ports", js.LastError().Error())
+}
@@ -0,0 +1,70 @@
+package betterjson
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// TruncateOptions limits what Truncated keeps. a zero field means no limit
+type TruncateOptions struct {
+	// MaxStringLen is the longest string value kept whole, in bytes. longer
+	// strings are cut and end with "…(+N bytes)"
+	MaxStringLen int
+	// MaxArrayLen is the most items kept of an array. a longer array keeps
+	// its first MaxArrayLen items followed by the string "…(+N items)"
+	MaxArrayLen int
+	// MaxDepth is the deepest container nesting kept, see DocumentStats.MaxDepth.
+	// deeper containers are replaced by the string "[truncated]"
+	MaxDepth int
+}
+
+// Truncated returns a copy of j shrunk to the limits of opts, for logging
+// payloads of unknown size. j itself is not changed, and a document within
+// the limits comes back equal to it
+func (j *Json) Truncated(opts TruncateOptions) *Json {
+	if j.IsEmpty() {
+		return NewEmpty()
+	}
+	return wrapRaw(truncateRaw(j.value.Interface(), 0, opts))
+}
+
+func truncateRaw(node interface{}, depth int, opts TruncateOptions) interface{} {
+	switch value := unwrapRaw(node).(type) {
+	case string:
+		if opts.MaxStringLen <= 0 || len(value) <= opts.MaxStringLen {
+			return value
+		}
+		cut := opts.MaxStringLen
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		return fmt.Sprintf("%s…(+%d bytes)", value[:cut], len(value)-cut)
+	case map[string]interface{}:
+		if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+			return "[truncated]"
+		}
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[key] = truncateRaw(item, depth+1, opts)
+		}
+		return result
+	case []interface{}:
+		if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+			return "[truncated]"
+		}
+		kept := value
+		if opts.MaxArrayLen > 0 && len(value) > opts.MaxArrayLen {
+			kept = value[:opts.MaxArrayLen]
+		}
+		result := make([]interface{}, 0, len(kept)+1)
+		for _, item := range kept {
+			result = append(result, truncateRaw(item, depth+1, opts))
+		}
+		if len(kept) < len(value) {
+			result = append(result, fmt.Sprintf("…(+%d items)", len(value)-len(kept)))
+		}
+		return result
+	default:
+		return deepCopyRaw(value)
+	}
+}
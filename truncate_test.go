@@ -0,0 +1,52 @@
+package betterjson
+
+import (
+	"strings"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func truncateFixture() *Json {
+	doc, _ := Parse([]byte(`{"name":"abcdefghij","city":"日本語","items":[1,2,3,4,5],"deep":{"er":{"est":{"value":1}}}}`))
+	return doc
+}
+
+func TestJson_Truncated(t *testing.T) {
+	doc := truncateFixture()
+	before := doc.DigestJSONForEqual()
+
+	strs := doc.Truncated(TruncateOptions{MaxStringLen: 4})
+	assert.True(t, strs.Get("name").MustString() == "abcd…(+6 bytes)")
+	assert.True(t, strs.Get("city").MustString() == "日…(+6 bytes)")
+	assert.True(t, strs.Get("items").ArrayLength() == 5)
+
+	arrays := doc.Truncated(TruncateOptions{MaxArrayLen: 2})
+	assert.True(t, arrays.Get("items").EncodeToStringOrDefault("") == `[1,2,"…(+3 items)"]`)
+	assert.True(t, arrays.Get("name").MustString() == "abcdefghij")
+
+	depth := doc.Truncated(TruncateOptions{MaxDepth: 2})
+	assert.True(t, depth.DigestJSONForEqual() == `{"city":"日本語","deep":{"er":"[truncated]"},"items":[1,2,3,4,5],"name":"abcdefghij"}`)
+	assert.True(t, depth.MaxDepth() <= 2)
+
+	combined := doc.Truncated(TruncateOptions{MaxStringLen: 3, MaxArrayLen: 1, MaxDepth: 1})
+	encoded, err := combined.EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, encoded == `{"city":"日…(+6 bytes)","deep":"[truncated]","items":"[truncated]","name":"abc…(+7 bytes)"}`)
+	_, err = Parse([]byte(encoded))
+	assert.True(t, err == nil)
+
+	assert.True(t, doc.DigestJSONForEqual() == before)
+}
+
+func TestJson_TruncatedWithinLimits(t *testing.T) {
+	doc := truncateFixture()
+	for _, opts := range []TruncateOptions{{}, {MaxStringLen: 100, MaxArrayLen: 5, MaxDepth: 4}} {
+		truncated := doc.Truncated(opts)
+		assert.True(t, truncated.IsSameJSONWith(doc))
+		truncated.Get("items").TryAdd(6)
+		assert.True(t, doc.Get("items").ArrayLength() == 5)
+	}
+	long := NewJSONArrayFrom(strings.Repeat("x", 10))
+	assert.True(t, long.Truncated(TruncateOptions{MaxStringLen: 10}).IsSameJSONWith(long))
+	assert.True(t, NewEmpty().Truncated(TruncateOptions{}).IsEmpty())
+}
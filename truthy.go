@@ -0,0 +1,54 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// IsTruthy reports whether j counts as true in a feature flag style check.
+// these are falsy, everything else is truthy:
+//    empty Json, null, false, 0 (of any number type, 0.0 and -0 included),
+//    NaN, "", [] and {}
+// note that the strings "false" and "0" are truthy
+func (j *Json) IsTruthy() bool {
+	if j.IsEmpty() {
+		return false
+	}
+	switch value := unwrapRaw(j.value.Interface()).(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case string:
+		return value != ""
+	case map[string]interface{}:
+		return len(value) > 0
+	case []interface{}:
+		return len(value) > 0
+	case float64:
+		return value != 0 && !math.IsNaN(value)
+	case float32:
+		return value != 0 && !math.IsNaN(float64(value))
+	case json.Number:
+		// an empty json.Number encodes as 0
+		number, ok := rawNumber(value)
+		return ok && number.Sign() != 0
+	}
+	if number, ok := rawNumber(j.value.Interface()); ok {
+		return number.Sign() != 0
+	}
+	return true
+}
+
+// IsFalsy is the opposite of IsTruthy
+func (j *Json) IsFalsy() bool {
+	return !j.IsTruthy()
+}
+
+// GetTruthy reports whether the value at key is truthy, false when key is missing:
+//    if flags.GetTruthy("new_checkout") {
+//        ...
+//    }
+func (j *Json) GetTruthy(key string) bool {
+	return j.CheckGet(key).IsTruthy()
+}
@@ -0,0 +1,38 @@
+package betterjson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJson_IsTruthy(t *testing.T) {
+	falsy := []interface{}{nil, false, "", 0, int8(0), int16(0), int32(0), int64(0), uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		0.0, float32(0), math.Copysign(0, -1), math.NaN(), json.Number("0"), json.Number("0.0"), json.Number("-0"), json.Number("0e10"), json.Number(""),
+		[]interface{}{}, map[string]interface{}{}}
+	for _, value := range falsy {
+		js := NewJSONObject().SetValue(value)
+		assert.True(t, !js.IsTruthy(), value)
+		assert.True(t, js.IsFalsy(), value)
+	}
+	truthy := []interface{}{true, "false", "0", " ", 1, int8(-1), uint64(7), 0.5, float32(-2), math.Inf(1), json.Number("1"), json.Number("1e-9"), json.Number("-3"),
+		[]interface{}{nil}, map[string]interface{}{"": false}}
+	for _, value := range truthy {
+		js := NewJSONObject().SetValue(value)
+		assert.True(t, js.IsTruthy(), value)
+		assert.True(t, !js.IsFalsy(), value)
+	}
+	assert.True(t, !NewEmpty().IsTruthy() && NewEmpty().IsFalsy())
+}
+
+func TestJson_GetTruthy(t *testing.T) {
+	flags, _ := Parse([]byte(`{"a":true,"b":"false","c":0,"d":1,"e":null,"f":[],"g":{"x":0},"h":0.0}`))
+	result := map[string]bool{}
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "missing"} {
+		result[key] = flags.GetTruthy(key)
+	}
+	assert.Equal(t, map[string]bool{"a": true, "b": true, "c": false, "d": true, "e": false, "f": false, "g": true, "h": false, "missing": false}, result)
+	assert.True(t, !NewEmpty().GetTruthy("a"))
+	assert.True(t, !flags.Get("missing").GetTruthy("a"))
+}
@@ -0,0 +1,129 @@
+package betterjson
+
+import (
+	"sort"
+)
+
+// Kind is the json type of a value
+type Kind string
+
+// the Kind names are those of error messages like "string at a.b is not an object"
+const (
+	KindNull   Kind = "null"
+	KindBool   Kind = "bool"
+	KindNumber Kind = "number"
+	KindString Kind = "string"
+	KindArray  Kind = "array"
+	KindObject Kind = "object"
+	// KindUnknown is a value of a Go type with no json counterpart
+	KindUnknown Kind = "unknown"
+	// KindMissing is the Kind of a path without a value
+	KindMissing Kind = "missing"
+)
+
+// TypeAt returns the Kind of the value at branch, KindMissing when there is
+// none. segments may be array indexes like GetDottedPath's
+func (j *Json) TypeAt(branch ...string) Kind {
+	item, ok := j.lookupPath(branch)
+	if !ok {
+		return KindMissing
+	}
+	return Kind(kindName(item.value.Interface()))
+}
+
+// TypeReport counts the kinds of the values found at each path of a corpus
+// of documents, see AnalyzeTypes
+type TypeReport struct {
+	Documents int
+	paths     map[string]map[Kind]int
+}
+
+// AnalyzeTypes counts the kinds of the values at every path of docs, for
+// finding paths whose type drifts between documents. paths are dotted, with
+// the indexes of arrays collapsed to "[]", so "items.[].price" counts the
+// prices of all items. the roots of the documents are not counted
+func AnalyzeTypes(docs []*Json) TypeReport {
+	report := TypeReport{paths: make(map[string]map[Kind]int)}
+	for _, doc := range docs {
+		if doc == nil || doc.IsEmpty() {
+			continue
+		}
+		report.Documents++
+		report.analyze([]string{}, doc.Interface())
+	}
+	return report
+}
+
+func (report TypeReport) analyze(branch []string, node interface{}) {
+	if len(branch) > 0 {
+		path := JoinDottedPath(branch)
+		kinds := report.paths[path]
+		if kinds == nil {
+			kinds = make(map[Kind]int)
+			report.paths[path] = kinds
+		}
+		kinds[Kind(kindName(node))]++
+	}
+	switch value := unwrapRaw(node).(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			report.analyze(append(branch[:len(branch):len(branch)], key), item)
+		}
+	case []interface{}:
+		for _, item := range value {
+			report.analyze(append(branch[:len(branch):len(branch)], "[]"), item)
+		}
+	}
+}
+
+// Paths returns the paths of the report in sorted order
+func (report TypeReport) Paths() []string {
+	paths := make([]string, 0, len(report.paths))
+	for path := range report.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Kinds returns how many values of each kind were seen at path
+func (report TypeReport) Kinds(path string) map[Kind]int {
+	kinds := make(map[Kind]int, len(report.paths[path]))
+	for kind, count := range report.paths[path] {
+		kinds[kind] = count
+	}
+	return kinds
+}
+
+// Conflicts returns the sorted paths where values of more than one kind were
+// seen. null doesn't conflict with the other kinds, it only makes the path
+// nullable
+func (report TypeReport) Conflicts() []string {
+	conflicts := make([]string, 0)
+	for _, path := range report.Paths() {
+		kinds := 0
+		for kind := range report.paths[path] {
+			if kind != KindNull {
+				kinds++
+			}
+		}
+		if kinds > 1 {
+			conflicts = append(conflicts, path)
+		}
+	}
+	return conflicts
+}
+
+// JSON returns the report as a document like
+//    {"documents":2,"paths":{"id":{"number":1,"string":1},"tags":{"array":2}}}
+func (report TypeReport) JSON() *Json {
+	paths := make(map[string]interface{}, len(report.paths))
+	for path, kinds := range report.paths {
+		counts := make(map[string]interface{}, len(kinds))
+		for kind, count := range kinds {
+			counts[string(kind)] = count
+		}
+		paths[path] = counts
+	}
+	return wrapRaw(map[string]interface{}{"documents": report.Documents, "paths": paths})
+}
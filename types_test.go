@@ -0,0 +1,46 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeAt(t *testing.T) {
+	js, _ := Parse([]byte(`{"a":{"b":[1,"x",null,true,{}]}}`))
+	assert.Equal(t, KindObject, js.TypeAt())
+	assert.Equal(t, KindArray, js.TypeAt("a", "b"))
+	assert.Equal(t, KindNumber, js.TypeAt("a", "b", "0"))
+	assert.Equal(t, KindString, js.TypeAt("a", "b", "1"))
+	assert.Equal(t, KindNull, js.TypeAt("a", "b", "2"))
+	assert.Equal(t, KindBool, js.TypeAt("a", "b", "3"))
+	assert.Equal(t, KindObject, js.TypeAt("a", "b", "4"))
+	assert.Equal(t, KindMissing, js.TypeAt("a", "b", "5"))
+	assert.Equal(t, KindMissing, js.TypeAt("nope"))
+	assert.Equal(t, KindMissing, NewEmpty().TypeAt())
+}
+
+func TestAnalyzeTypes(t *testing.T) {
+	corpus := make([]*Json, 0)
+	for _, text := range []string{
+		`{"id":1,"name":"a","tags":["x"],"price":{"amount":1.5}}`,
+		`{"id":"2","name":"b","tags":[],"price":{"amount":"1.50"}}`,
+		`{"id":3,"name":null,"tags":["y",7],"price":null}`,
+	} {
+		js, _ := Parse([]byte(text))
+		corpus = append(corpus, js)
+	}
+	corpus = append(corpus, NewEmpty())
+	report := AnalyzeTypes(corpus)
+	assert.Equal(t, 3, report.Documents)
+	assert.Equal(t, []string{"id", "name", "price", "price.amount", "tags", "tags.[]"}, report.Paths())
+	assert.Equal(t, map[Kind]int{KindNumber: 2, KindString: 1}, report.Kinds("id"))
+	assert.Equal(t, map[Kind]int{KindString: 2, KindNull: 1}, report.Kinds("name"))
+	assert.Equal(t, map[Kind]int{KindString: 2, KindNumber: 1}, report.Kinds("tags.[]"))
+	assert.Equal(t, 0, len(report.Kinds("missing")))
+	assert.Equal(t, []string{"id", "price.amount", "tags.[]"}, report.Conflicts())
+
+	encoded := report.JSON().EncodeToStringOrDefault("")
+	println(encoded)
+	assert.Equal(t, int64(2), report.JSON().GetPath("paths", "price").Get("object").MustInt64())
+	assert.Equal(t, int64(3), report.JSON().Get("documents").MustInt64())
+}
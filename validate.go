@@ -0,0 +1,181 @@
+package betterjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// StringValidationOptions configures ValidateStrings and SanitizeStrings
+type StringValidationOptions struct {
+	// MaxLength is the longest string allowed, in bytes. 0 is no limit
+	MaxLength int
+	// AllowWhitespaceControls accepts tab, line feed and carriage return,
+	// the other control characters are still issues
+	AllowWhitespaceControls bool
+	// EscapeControls makes SanitizeStrings replace control characters by
+	// their \uXXXX escape as text instead of removing them
+	EscapeControls bool
+	// Keys checks object keys as well as string values
+	Keys bool
+}
+
+// StringProblem is the kind of a StringIssue
+type StringProblem string
+
+const (
+	// StringInvalidUTF8 is a byte sequence that isn't UTF-8
+	StringInvalidUTF8 StringProblem = "invalid utf-8"
+	// StringControlChar is a C0 or C1 control character, or DEL
+	StringControlChar StringProblem = "control character"
+	// StringTooLong is a string longer than MaxLength
+	StringTooLong StringProblem = "too long"
+)
+
+// StringIssue is one problem ValidateStrings found
+type StringIssue struct {
+	// Path is the branch of the string, or of the member whose key it is
+	Path    []string
+	Key     bool
+	Problem StringProblem
+	// Offset is the byte offset of the problem in the string, MaxLength for
+	// StringTooLong
+	Offset int
+}
+
+func (issue StringIssue) String() string {
+	subject := "string"
+	if issue.Key {
+		subject = "key"
+	}
+	return fmt.Sprintf("%s at %s: %s at byte %d", subject, displayPath(issue.Path), issue.Problem, issue.Offset)
+}
+
+// ValidateStrings reports invalid UTF-8, control characters and overlong
+// strings in j, in document order. []byte values are binary data and are not
+// checked:
+//    for _, issue := range payload.ValidateStrings(betterjson.StringValidationOptions{AllowWhitespaceControls: true}) {
+//        log.Println(issue)
+//    }
+func (j *Json) ValidateStrings(opts StringValidationOptions) []StringIssue {
+	issues := make([]StringIssue, 0)
+	if j.IsEmpty() {
+		return issues
+	}
+	validateRaw([]string{}, j.Interface(), opts, &issues)
+	return issues
+}
+
+func validateRaw(branch []string, node interface{}, opts StringValidationOptions, issues *[]StringIssue) {
+	switch value := unwrapRaw(node).(type) {
+	case string:
+		validateString(branch, false, value, opts, issues)
+	case map[string]interface{}:
+		for _, key := range sortedKeys(value) {
+			child := append(branch[:len(branch):len(branch)], key)
+			if opts.Keys {
+				validateString(child, true, key, opts, issues)
+			}
+			validateRaw(child, value[key], opts, issues)
+		}
+	case []interface{}:
+		for idx, item := range value {
+			validateRaw(append(branch[:len(branch):len(branch)], strconv.Itoa(idx)), item, opts, issues)
+		}
+	}
+}
+
+func validateString(branch []string, key bool, s string, opts StringValidationOptions, issues *[]StringIssue) {
+	report := func(problem StringProblem, offset int) {
+		*issues = append(*issues, StringIssue{Path: append([]string{}, branch...), Key: key, Problem: problem, Offset: offset})
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			report(StringInvalidUTF8, i)
+		case isControl(r, opts):
+			report(StringControlChar, i)
+		}
+		i += size
+	}
+	if opts.MaxLength > 0 && len(s) > opts.MaxLength {
+		report(StringTooLong, opts.MaxLength)
+	}
+}
+
+func isControl(r rune, opts StringValidationOptions) bool {
+	if opts.AllowWhitespaceControls && (r == '\t' || r == '\n' || r == '\r') {
+		return false
+	}
+	return r < 0x20 || (0x7f <= r && r < 0xa0)
+}
+
+// SanitizeStrings returns a copy of j with the issues ValidateStrings finds
+// fixed: invalid UTF-8 sequences become U+FFFD, control characters are removed
+// or escaped, and overlong strings are cut at a character boundary. keys that
+// become equal keep the value of the first in sorted order
+func (j *Json) SanitizeStrings(opts StringValidationOptions) *Json {
+	if j.IsEmpty() {
+		return NewEmpty()
+	}
+	result := wrapRaw(sanitizeRaw(j.Interface(), opts))
+	result.settings = j.settings
+	return result
+}
+
+func sanitizeRaw(node interface{}, opts StringValidationOptions) interface{} {
+	switch value := unwrapRaw(node).(type) {
+	case string:
+		return sanitizeString(value, opts)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for _, key := range sortedKeys(value) {
+			sanitizedKey := key
+			if opts.Keys {
+				sanitizedKey = sanitizeString(key, opts)
+			}
+			if _, ok := result[sanitizedKey]; !ok {
+				result[sanitizedKey] = sanitizeRaw(value[key], opts)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for idx, item := range value {
+			result[idx] = sanitizeRaw(item, opts)
+		}
+		return result
+	default:
+		return deepCopyRaw(value)
+	}
+}
+
+func sanitizeString(s string, opts StringValidationOptions) string {
+	var builder strings.Builder
+	builder.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == utf8.RuneError && size == 1:
+			builder.WriteRune(utf8.RuneError)
+		case isControl(r, opts):
+			if opts.EscapeControls {
+				fmt.Fprintf(&builder, "\\u%04x", r)
+			}
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	sanitized := builder.String()
+	if opts.MaxLength > 0 && len(sanitized) > opts.MaxLength {
+		cut := opts.MaxLength
+		for cut > 0 && !utf8.RuneStart(sanitized[cut]) {
+			cut--
+		}
+		sanitized = sanitized[:cut]
+	}
+	return sanitized
+}
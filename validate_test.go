@@ -0,0 +1,71 @@
+package betterjson
+
+import (
+	"testing"
+	"unicode/utf8"
+	"github.com/stretchr/testify/assert"
+)
+
+func corruptedDocument() *Json {
+	return Obj(
+		"ok", "plain text\twith tab",
+		"bad", string([]byte{'a', 0xff, 'b', 0xc3}),
+		"list", Arr("fine", string([]byte{'x', 0x1b, '[', '3', '1', 'm', 0x7f}), string([]byte{0xc2, 0x85})),
+		"nested", Obj(string([]byte{'k', 0x00}), "v"),
+		"blob", []byte{0xff, 0x00},
+		"long", "abcdéfgh",
+	)
+}
+
+func TestValidateStrings(t *testing.T) {
+	js := corruptedDocument()
+	issues := js.ValidateStrings(StringValidationOptions{MaxLength: 5, AllowWhitespaceControls: true, Keys: true})
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	assert.Equal(t, []string{
+		"string at bad: invalid utf-8 at byte 1",
+		"string at bad: invalid utf-8 at byte 3",
+		"string at list.1: control character at byte 1",
+		"string at list.1: control character at byte 6",
+		"string at list.1: too long at byte 5",
+		"string at list.2: control character at byte 0",
+		"string at long: too long at byte 5",
+		"key at nested: too long at byte 5",
+		"key at nested.k\x00: control character at byte 1",
+		"string at ok: too long at byte 5",
+	}, lines)
+	assert.Equal(t, []string{"nested", "k\x00"}, issues[8].Path)
+	assert.True(t, issues[8].Key)
+
+	strict := js.Get("ok").ValidateStrings(StringValidationOptions{})
+	assert.Equal(t, 1, len(strict))
+	assert.Equal(t, StringControlChar, strict[0].Problem)
+	assert.Equal(t, 10, strict[0].Offset)
+	assert.Equal(t, 0, len(Obj("a", "ok", "b", Arr(1, nil)).ValidateStrings(StringValidationOptions{MaxLength: 2, Keys: true})))
+	assert.Equal(t, 0, len(NewEmpty().ValidateStrings(StringValidationOptions{})))
+}
+
+func TestSanitizeStrings(t *testing.T) {
+	js := corruptedDocument()
+	before := js.EncodeToStringOrDefault("")
+	sanitized := js.SanitizeStrings(StringValidationOptions{AllowWhitespaceControls: true, Keys: true})
+	assert.Equal(t, "a�b�", sanitized.Get("bad").MustString())
+	assert.Equal(t, "x[31m", sanitized.Get("list").GetIndex(1).MustString())
+	assert.Equal(t, "", sanitized.Get("list").GetIndex(2).MustString())
+	assert.Equal(t, "plain text\twith tab", sanitized.Get("ok").MustString())
+	assert.Equal(t, "v", sanitized.GetPath("nested", "k").MustString())
+	assert.Equal(t, 0, len(sanitized.ValidateStrings(StringValidationOptions{AllowWhitespaceControls: true, Keys: true})))
+	assert.Equal(t, before, js.EncodeToStringOrDefault(""))
+
+	escaped := js.SanitizeStrings(StringValidationOptions{EscapeControls: true})
+	assert.Equal(t, `x\u001b[31m\u007f`, escaped.Get("list").GetIndex(1).MustString())
+	assert.Equal(t, `plain text\u0009with tab`, escaped.Get("ok").MustString())
+	cut := js.SanitizeStrings(StringValidationOptions{MaxLength: 5})
+	assert.Equal(t, "plain", cut.Get("ok").MustString())
+	assert.Equal(t, "abcd", cut.Get("long").MustString())
+	assert.True(t, utf8.ValidString(escaped.Get("bad").MustString()))
+	assert.True(t, escaped.GetPath("nested", "k\x00").MustString() == "v")
+	assert.True(t, NewEmpty().SanitizeStrings(StringValidationOptions{}).IsEmpty())
+}
@@ -0,0 +1,145 @@
+package betterjson
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// SkipSubtree returned by the fn of Walk or WalkBoth skips the children of
+// the current path
+var SkipSubtree = errors.New("skip subtree")
+
+// Walk calls fn for every value of j, containers included, starting with
+// the root: the children of an object in sorted key order, the ones of an
+// array by index. the values are linked to j, so fn may modify them. an error
+// of fn other than SkipSubtree stops the walk and is returned
+func (j *Json) Walk(fn func(path []string, value *Json) error) error {
+	return j.WalkCtx(context.Background(), fn)
+}
+
+// WalkCtx is Walk giving up with a CanceledError once ctx is done
+func (j *Json) WalkCtx(ctx context.Context, fn func(path []string, value *Json) error) error {
+	if j.IsEmpty() {
+		return nil
+	}
+	return walk([]string{}, j, fn, newProgress(ctx, "walk"))
+}
+
+func walk(path []string, value *Json, fn func(path []string, value *Json) error, p *progress) error {
+	if err := p.visit(path); err != nil {
+		return err
+	}
+	if err := fn(append([]string{}, path...), value); err != nil {
+		if err == SkipSubtree {
+			return nil
+		}
+		return err
+	}
+	node := wrappedValue(value)
+	for _, segment := range childSegments(node, nil) {
+		if err := walk(append(path[:len(path):len(path)], segment), childWrapper(value, node, segment), fn, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkBoth calls fn for every path of a or b, starting with the root. av and
+// bv hold the values at path and are empty on the side that doesn't have it.
+// the children of a path are walked when av or bv is an object or an array:
+// object keys in sorted order, array items by index, and the ones of a first
+// when the two are of different kinds. the values are linked to their
+// documents, so fn may modify them. an error of fn other than SkipSubtree
+// stops the walk and is returned:
+//    betterjson.WalkBoth(old, new, func(path []string, av, bv *betterjson.Json) error {
+//        if note := av.CheckGet("note"); !note.IsEmpty() && !bv.IsEmpty() && !bv.ContainsKey("note") {
+//            bv.Set("note", note)
+//        }
+//        return nil
+//    })
+func WalkBoth(a, b *Json, fn func(path []string, av, bv *Json) error) error {
+	return WalkBothCtx(context.Background(), a, b, fn)
+}
+
+// WalkBothCtx is WalkBoth giving up with a CanceledError once ctx is done
+func WalkBothCtx(ctx context.Context, a, b *Json, fn func(path []string, av, bv *Json) error) error {
+	if a == nil {
+		a = NewEmpty()
+	}
+	if b == nil {
+		b = NewEmpty()
+	}
+	if a.IsEmpty() && b.IsEmpty() {
+		return nil
+	}
+	return walkBoth([]string{}, a, b, fn, newProgress(ctx, "walk"))
+}
+
+func walkBoth(path []string, av, bv *Json, fn func(path []string, av, bv *Json) error, p *progress) error {
+	if err := p.visit(path); err != nil {
+		return err
+	}
+	if err := fn(append([]string{}, path...), av, bv); err != nil {
+		if err == SkipSubtree {
+			return nil
+		}
+		return err
+	}
+	aNode, bNode := wrappedValue(av), wrappedValue(bv)
+	segments := childSegments(aNode, nil)
+	segments = childSegments(bNode, segments)
+	_, aObject := aNode.(map[string]interface{})
+	_, bObject := bNode.(map[string]interface{})
+	if aObject && bObject {
+		// the keys of both objects, merged into one sorted list
+		sort.Strings(segments)
+	}
+	for _, segment := range segments {
+		aChild, bChild := childWrapper(av, aNode, segment), childWrapper(bv, bNode, segment)
+		if err := walkBoth(append(path[:len(path):len(path)], segment), aChild, bChild, fn, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func wrappedValue(j *Json) interface{} {
+	if j.IsEmpty() {
+		return nil
+	}
+	return unwrapRaw(j.value.Interface())
+}
+
+// childSegments appends the child segments of node missing from segments
+func childSegments(node interface{}, segments []string) []string {
+	seen := make(map[string]bool, len(segments))
+	for _, segment := range segments {
+		seen[segment] = true
+	}
+	switch container := node.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(container) {
+			if !seen[key] {
+				segments = append(segments, key)
+			}
+		}
+	case []interface{}:
+		for idx := range container {
+			if segment := strconv.Itoa(idx); !seen[segment] {
+				segments = append(segments, segment)
+			}
+		}
+	}
+	return segments
+}
+
+func childWrapper(parent *Json, node interface{}, segment string) *Json {
+	item, ok := childValue(node, segment)
+	if !ok {
+		return NewEmpty()
+	}
+	return newChild(parent, segment, item)
+}
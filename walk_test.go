@@ -0,0 +1,94 @@
+package betterjson
+
+import (
+	"strings"
+	"testing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type walkVisit struct {
+	path string
+	a    string
+	b    string
+}
+
+func walkBothVisits(t *testing.T, a, b *Json, skip string) []walkVisit {
+	visits := make([]walkVisit, 0)
+	err := WalkBoth(a, b, func(path []string, av, bv *Json) error {
+		visits = append(visits, walkVisit{displayPath(path), av.EncodeToStringOrDefault("-"), bv.EncodeToStringOrDefault("-")})
+		if skip != "" && JoinDottedPath(path) == skip {
+			return SkipSubtree
+		}
+		return nil
+	})
+	assert.True(t, err == nil)
+	return visits
+}
+
+func TestWalkBothUnion(t *testing.T) {
+	a, _ := Parse([]byte(`{"keep":1,"old":{"x":1},"list":[1,2]}`))
+	b, _ := Parse([]byte(`{"keep":1,"new":true,"list":[1]}`))
+	visits := walkBothVisits(t, a, b, "")
+	for _, visit := range visits {
+		println(visit.path, visit.a, visit.b)
+	}
+	assert.Equal(t, []walkVisit{
+		{"<root>", `{"keep":1,"list":[1,2],"old":{"x":1}}`, `{"keep":1,"list":[1],"new":true}`},
+		{"keep", "1", "1"},
+		{"list", "[1,2]", "[1]"},
+		{"list.0", "1", "1"},
+		{"list.1", "2", "-"},
+		{"new", "-", "true"},
+		{"old", `{"x":1}`, "-"},
+		{"old.x", "1", "-"},
+	}, visits)
+}
+
+func TestWalkBothDifferentKinds(t *testing.T) {
+	a, _ := Parse([]byte(`{"v":{"1":"one","k":"x"},"s":"text"}`))
+	b, _ := Parse([]byte(`{"v":["zero","uno"],"s":{"deep":1}}`))
+	visits := walkBothVisits(t, a, b, "")
+	assert.Equal(t, []walkVisit{
+		{"<root>", `{"s":"text","v":{"1":"one","k":"x"}}`, `{"s":{"deep":1},"v":["zero","uno"]}`},
+		{"s", `"text"`, `{"deep":1}`},
+		{"s.deep", "-", "1"},
+		{"v", `{"1":"one","k":"x"}`, `["zero","uno"]`},
+		{"v.1", `"one"`, `"uno"`},
+		{"v.k", `"x"`, "-"},
+		{"v.0", "-", `"zero"`},
+	}, visits)
+}
+
+func TestWalkBothSkipAndErrors(t *testing.T) {
+	a, _ := Parse([]byte(`{"skip":{"x":1},"y":2}`))
+	visits := walkBothVisits(t, a, NewEmpty(), "skip")
+	assert.Equal(t, 3, len(visits))
+	assert.Equal(t, "y", visits[2].path)
+	assert.Equal(t, 4, len(walkBothVisits(t, a, a, "")))
+
+	stop := errors.New("stop")
+	count := 0
+	err := WalkBoth(a, nil, func(path []string, av, bv *Json) error {
+		count++
+		if strings.Join(path, ".") == "skip" {
+			return stop
+		}
+		return nil
+	})
+	assert.True(t, err == stop)
+	assert.Equal(t, 2, count)
+	assert.True(t, WalkBoth(nil, NewEmpty(), nil) == nil)
+}
+
+func TestWalkBothCopiesAnnotations(t *testing.T) {
+	old, _ := Parse([]byte(`{"users":[{"id":1,"note":"vip"},{"id":2}]}`))
+	updated, _ := Parse([]byte(`{"users":[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3}]}`))
+	WalkBoth(old, updated, func(path []string, av, bv *Json) error {
+		if note := av.CheckGet("note"); !note.IsEmpty() && !bv.IsEmpty() && !bv.ContainsKey("note") {
+			bv.Set("note", note)
+		}
+		return nil
+	})
+	assert.Equal(t, `{"users":[{"id":1,"name":"a","note":"vip"},{"id":2,"name":"b"},{"id":3}]}`, updated.EncodeToStringOrDefault(""))
+}
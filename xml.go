@@ -0,0 +1,277 @@
+package betterjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/pkg/errors"
+)
+
+// XML conversion follows these rules, in both directions:
+//   - the document becomes an object with the root element name as only key:
+//     <order id="1"/> is {"order": {"@id": "1"}}
+//   - an element without attributes and child elements is its text content as
+//     a string, or null when it has no text at all
+//   - any other element is an object. attributes are keys with AttrPrefix
+//     prepended, child elements are keys of their name and the text content,
+//     when there is any, is under TextKey
+//   - repeated sibling elements of one name become an array in document order,
+//     so a single element and a one item array both encode as one element
+//   - text is trimmed; the text pieces of mixed content are concatenated, so
+//     their position relative to child elements is lost
+//   - namespace prefixes are kept as part of the name ("soap:Body", "@xmlns:soap")
+//   - all values read from xml are strings, no number or bool detection is done
+//
+// ToXML writes attributes, then text, then children sorted by key, one element
+// per array item. scalars are written with their json text ("1", "true"), and
+// null and empty strings produce self-closing elements
+
+// XMLOptions configures the key names used for attributes and text.
+// empty fields use the defaults "@" and "#text"
+type XMLOptions struct {
+	AttrPrefix string
+	TextKey    string
+}
+
+func (opts XMLOptions) withDefaults() XMLOptions {
+	if opts.AttrPrefix == "" {
+		opts.AttrPrefix = "@"
+	}
+	if opts.TextKey == "" {
+		opts.TextKey = "#text"
+	}
+	return opts
+}
+
+type xmlElement struct {
+	name     string
+	object   map[string]interface{}
+	text     bytes.Buffer
+	children int
+}
+
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// FromXML converts an xml document to Json, see the mapping rules above
+func FromXML(data []byte, opts XMLOptions) (*Json, error) {
+	opts = opts.withDefaults()
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	stack := make([]*xmlElement, 0)
+	var root interface{}
+	rootName := ""
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 && rootName != "" {
+				return nil, errors.Errorf("xml has more than one root element, found %s after %s", xmlName(token.Name), rootName)
+			}
+			element := &xmlElement{name: xmlName(token.Name), object: make(map[string]interface{})}
+			for _, attr := range token.Attr {
+				element.object[opts.AttrPrefix+xmlName(attr.Name)] = attr.Value
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			if len(stack) == 0 || stack[len(stack)-1].name != xmlName(token.Name) {
+				return nil, errors.Errorf("unexpected xml end element %s at offset %d", xmlName(token.Name), decoder.InputOffset())
+			}
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			value := element.value(opts)
+			if len(stack) == 0 {
+				root = value
+				rootName = element.name
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.children++
+			if existing, ok := parent.object[element.name]; ok {
+				if items, isArray := existing.([]interface{}); isArray {
+					parent.object[element.name] = append(items, value)
+				} else {
+					parent.object[element.name] = []interface{}{existing, value}
+				}
+			} else {
+				parent.object[element.name] = value
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(token)
+			}
+		}
+	}
+	if len(stack) > 0 {
+		return nil, errors.Errorf("xml element %s is not closed", stack[len(stack)-1].name)
+	}
+	if rootName == "" {
+		return nil, errors.New("xml has no root element")
+	}
+	value := simplejson.New()
+	value.Set(rootName, root)
+	return FromNotEmptySimpleJson(value), nil
+}
+
+func (element *xmlElement) value(opts XMLOptions) interface{} {
+	text := strings.TrimSpace(element.text.String())
+	if len(element.object) == 0 {
+		if text == "" && element.children == 0 {
+			return nil
+		}
+		return text
+	}
+	if text != "" {
+		element.object[opts.TextKey] = text
+	}
+	return element.object
+}
+
+// ToXML converts j to an xml document whose root element is rootName with
+// j as content, see the mapping rules above. with an empty rootName j must be
+// an object with a single key, the shape FromXML returns
+func (j *Json) ToXML(rootName string, opts XMLOptions) ([]byte, error) {
+	if j.IsEmpty() {
+		return []byte{}, errors.New("empty json can't be converted to xml")
+	}
+	opts = opts.withDefaults()
+	content := unwrapRaw(j.value.Interface())
+	if rootName == "" {
+		object, ok := content.(map[string]interface{})
+		if !ok || len(object) != 1 {
+			return nil, errors.New("xml root name is required unless json is an object with a single key")
+		}
+		for key, item := range object {
+			rootName, content = key, unwrapRaw(item)
+		}
+	}
+	if _, isArray := content.([]interface{}); isArray {
+		return nil, errors.New("json array can't be the xml root element")
+	}
+	var buffer bytes.Buffer
+	if err := writeXMLElement(&buffer, rootName, content, opts); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if unicode.IsLetter(c) || c == '_' || c == ':' {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(c) || c == '-' || c == '.') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func xmlScalarText(node interface{}) (string, error) {
+	switch value := node.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	}
+	encoded, err := json.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func writeXMLElement(buffer *bytes.Buffer, name string, node interface{}, opts XMLOptions) error {
+	if !isXMLName(name) {
+		return errors.Errorf("%q is not a valid xml element name", name)
+	}
+	object, isObject := node.(map[string]interface{})
+	if !isObject {
+		text, err := xmlScalarText(node)
+		if err != nil {
+			return err
+		}
+		if text == "" {
+			fmt.Fprintf(buffer, "<%s/>", name)
+			return nil
+		}
+		fmt.Fprintf(buffer, "<%s>", name)
+		xml.EscapeText(buffer, []byte(text))
+		fmt.Fprintf(buffer, "</%s>", name)
+		return nil
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(buffer, "<%s", name)
+	for _, key := range keys {
+		if !strings.HasPrefix(key, opts.AttrPrefix) {
+			continue
+		}
+		attrName := strings.TrimPrefix(key, opts.AttrPrefix)
+		if !isXMLName(attrName) {
+			return errors.Errorf("%q is not a valid xml attribute name", attrName)
+		}
+		text, err := xmlScalarText(unwrapRaw(object[key]))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buffer, " %s=\"", attrName)
+		xml.EscapeText(buffer, []byte(text))
+		buffer.WriteByte('"')
+	}
+	text := ""
+	if textNode, ok := object[opts.TextKey]; ok {
+		var err error
+		if text, err = xmlScalarText(unwrapRaw(textNode)); err != nil {
+			return err
+		}
+	}
+	children := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != opts.TextKey && !strings.HasPrefix(key, opts.AttrPrefix) {
+			children = append(children, key)
+		}
+	}
+	if text == "" && len(children) == 0 {
+		buffer.WriteString("/>")
+		return nil
+	}
+	buffer.WriteByte('>')
+	xml.EscapeText(buffer, []byte(text))
+	for _, key := range children {
+		items, isArray := unwrapRaw(object[key]).([]interface{})
+		if !isArray {
+			items = []interface{}{object[key]}
+		}
+		for _, item := range items {
+			if err := writeXMLElement(buffer, key, unwrapRaw(item), opts); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Fprintf(buffer, "</%s>", name)
+	return nil
+}
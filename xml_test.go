@@ -0,0 +1,73 @@
+package betterjson
+
+import (
+	"testing"
+	"github.com/stretchr/testify/assert"
+)
+
+const xmlFixture = `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <order id="42" status="new">
+      <item sku="a-1">Apple</item>
+      <item sku="b-2">Banana &amp; co</item>
+      <note>first <b>bold</b> last</note>
+      <empty/>
+      <title>Fruit</title>
+    </order>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestFromXML(t *testing.T) {
+	a, err := FromXML([]byte(xmlFixture), XMLOptions{})
+	assert.True(t, err == nil, err)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	println(aStr)
+	order := a.GetPath("soap:Envelope", "soap:Body", "order")
+	assert.True(t, order.Get("@id").MustString() == "42")
+	assert.True(t, order.Get("item").ArrayLength() == 2)
+	assert.True(t, order.Get("item").GetIndex(1).Get("#text").MustString() == "Banana & co")
+	assert.True(t, order.Get("note").Get("#text").MustString() == "first  last")
+	assert.True(t, order.Get("note").Get("b").MustString() == "bold")
+	assert.True(t, order.Get("empty").IsNullJson())
+	assert.True(t, order.Get("title").MustString() == "Fruit")
+	assert.True(t, a.GetPath("soap:Envelope", "@xmlns:soap").MustString() == "http://schemas.xmlsoap.org/soap/envelope/")
+}
+
+func TestFromXMLOptions(t *testing.T) {
+	a, err := FromXML([]byte(`<a x="1">text<b>2</b></a>`), XMLOptions{AttrPrefix: "_", TextKey: "$t"})
+	assert.True(t, err == nil)
+	aStr, err := a.EncodeToString()
+	assert.True(t, err == nil)
+	assert.True(t, aStr == `{"a":{"$t":"text","_x":"1","b":"2"}}`)
+	for _, invalid := range []string{"", "<a>", "<a></b>", "<a/><b/>", "text only"} {
+		_, err = FromXML([]byte(invalid), XMLOptions{})
+		assert.True(t, err != nil, invalid)
+	}
+}
+
+func TestJson_ToXML(t *testing.T) {
+	a := Obj("@id", 42, "item", Arr(Obj("@sku", "a-1", "#text", "Apple"), "Pear & <co>"), "paid", false, "note", nil)
+	encoded, err := a.ToXML("order", XMLOptions{})
+	assert.True(t, err == nil)
+	println(string(encoded))
+	assert.True(t, string(encoded) == `<order id="42"><item sku="a-1">Apple</item><item>Pear &amp; &lt;co&gt;</item><note/><paid>false</paid></order>`)
+	_, err = a.ToXML("", XMLOptions{})
+	assert.True(t, err != nil)
+	_, err = NewJSONObject().Set("bad key", 1).ToXML("root", XMLOptions{})
+	assert.True(t, err != nil)
+	_, err = NewJSONArray().ToXML("root", XMLOptions{})
+	assert.True(t, err != nil)
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	a, err := FromXML([]byte(xmlFixture), XMLOptions{})
+	assert.True(t, err == nil)
+	encoded, err := a.ToXML("", XMLOptions{})
+	assert.True(t, err == nil)
+	println(string(encoded))
+	b, err := FromXML(encoded, XMLOptions{})
+	assert.True(t, err == nil)
+	assert.True(t, b.IsSameJSONWith(a))
+}